@@ -0,0 +1,164 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains code related to gateway transport compression.
+
+package discordgo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// GatewayCompression is the transport compression used for a gateway
+// connection.
+// https://discord.com/developers/docs/topics/gateway#transport-compression
+type GatewayCompression string
+
+// Valid GatewayCompression values
+const (
+	// GatewayCompressionNone disables transport compression. Payloads may
+	// still be compressed individually, see Session.Compress.
+	GatewayCompressionNone GatewayCompression = ""
+	// GatewayCompressionZlibStream compresses the whole connection with a
+	// single zlib context, flushed after every payload.
+	GatewayCompressionZlibStream GatewayCompression = "zlib-stream"
+	// GatewayCompressionZstdStream compresses the whole connection with a
+	// single zstd frame, flushed after every payload. It decompresses faster
+	// than zlib-stream.
+	GatewayCompressionZstdStream GatewayCompression = "zstd-stream"
+)
+
+// A gatewayDecompressor decompresses the messages of a gateway connection
+// which uses transport compression.
+type gatewayDecompressor interface {
+	// Decompress returns the payload a websocket message completes, or nil
+	// if the payload continues in the next message. The returned slice is
+	// only valid until the next call.
+	Decompress(message []byte) ([]byte, error)
+}
+
+// newGatewayDecompressor returns a decompressor for a new connection using
+// the given compression, or nil if the connection is not compressed.
+func newGatewayDecompressor(compression GatewayCompression) (gatewayDecompressor, error) {
+	switch compression {
+	case GatewayCompressionNone:
+		return nil, nil
+	case GatewayCompressionZlibStream:
+		return &zlibStream{}, nil
+	case GatewayCompressionZstdStream:
+		return &zstdStream{}, nil
+	}
+	return nil, fmt.Errorf("unknown gateway compression %q", compression)
+}
+
+// readStream reads the data src holds from a decompressor into out, and
+// returns the extended buffer. The decompressor must return at most
+// maxRead bytes it decoded from src in a single Read, and must not read
+// from src unless it has nothing left to return: reading into a buffer with
+// more room than that means everything is returned right as src runs out,
+// without asking it for more data, which would break the stream.
+func readStream(r io.Reader, src *bytes.Reader, out []byte, maxRead int) ([]byte, error) {
+	out = out[:0]
+	for src.Len() > 0 {
+		if cap(out)-len(out) <= maxRead {
+			grown := make([]byte, len(out), 2*cap(out)+maxRead+1)
+			copy(grown, out)
+			out = grown
+		}
+
+		n, err := r.Read(out[len(out):cap(out)])
+		out = out[:len(out)+n]
+		if err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// zlibSuffix is the Z_SYNC_FLUSH marker which ends every zlib-stream payload.
+var zlibSuffix = []byte{0x00, 0x00, 0xff, 0xff}
+
+// zlibFlushSize is the largest amount of data the inflater returns from a
+// single Read, the size of the deflate window.
+const zlibFlushSize = 1 << 15
+
+// zlibStream decompresses a zlib-stream gateway connection. A zlibStream is
+// created for each connection, and reuses its buffers between payloads.
+type zlibStream struct {
+	in  []byte
+	out []byte
+	src bytes.Reader
+	r   io.ReadCloser
+}
+
+// Decompress appends a websocket message to the stream. It returns the
+// decompressed payload once the message ends with a Z_SYNC_FLUSH.
+func (z *zlibStream) Decompress(message []byte) ([]byte, error) {
+	z.in = append(z.in, message...)
+	if !bytes.HasSuffix(z.in, zlibSuffix) {
+		return nil, nil
+	}
+
+	z.src.Reset(z.in)
+	z.in = z.in[:0]
+
+	if z.r == nil {
+		r, err := zlib.NewReader(&z.src)
+		if err != nil {
+			return nil, err
+		}
+		z.r = r
+	}
+
+	// The inflater returns at most a window of data per Read, and only reads
+	// more input once it has returned everything it decoded.
+	var err error
+	z.out, err = readStream(z.r, &z.src, z.out, zlibFlushSize)
+	if err != nil {
+		return nil, err
+	}
+	return z.out, nil
+}
+
+// zstdBlockSize is the largest amount of data a zstd block decompresses to.
+const zstdBlockSize = 128 << 10
+
+// zstdStream decompresses a zstd-stream gateway connection. Every message
+// holds a whole payload. A zstdStream is created for each connection, and
+// reuses its buffers between payloads.
+type zstdStream struct {
+	out []byte
+	src bytes.Reader
+	d   *zstd.Decoder
+}
+
+// Decompress returns the payload held in a websocket message.
+func (z *zstdStream) Decompress(message []byte) ([]byte, error) {
+	z.src.Reset(message)
+
+	if z.d == nil {
+		// A single goroutine decodes synchronously, a block per Read, and only
+		// reads more input once it has returned everything it decoded.
+		d, err := zstd.NewReader(&z.src, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		z.d = d
+	}
+
+	var err error
+	z.out, err = readStream(z.d, &z.src, z.out, zstdBlockSize)
+	if err != nil {
+		return nil, err
+	}
+	return z.out, nil
+}
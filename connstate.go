@@ -0,0 +1,126 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the state machine of the gateway connection of a
+// Session.
+
+package discordgo
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gorilla/websocket"
+)
+
+// ConnectionState is the state of the gateway connection of a Session.
+//
+// A Session starts Disconnected. Open moves it to Connecting, then to
+// Identifying or Resuming once the gateway said Hello, and to Ready once the
+// READY or RESUMED event was received. When the connection is lost it moves to
+// Reconnecting, back through Connecting, or to Disconnected when it can not be
+// reopened. Close moves it to Closed.
+type ConnectionState int32
+
+// Connection states of a Session.
+const (
+	// ConnectionStateDisconnected is the state of a Session which was not
+	// opened, or whose connection was lost and will not be reopened.
+	ConnectionStateDisconnected ConnectionState = iota
+
+	// ConnectionStateConnecting is the state of a Session connecting to the
+	// gateway.
+	ConnectionStateConnecting
+
+	// ConnectionStateIdentifying is the state of a Session which identified a
+	// new gateway session, and waits for its READY event.
+	ConnectionStateIdentifying
+
+	// ConnectionStateResuming is the state of a Session which is resuming its
+	// gateway session, and waits for its RESUMED event.
+	ConnectionStateResuming
+
+	// ConnectionStateReady is the state of a Session receiving events.
+	ConnectionStateReady
+
+	// ConnectionStateReconnecting is the state of a Session whose connection
+	// was lost, and is about to be reopened.
+	ConnectionStateReconnecting
+
+	// ConnectionStateClosed is the state of a Session closed by Close or
+	// CloseWithCode.
+	ConnectionStateClosed
+)
+
+// String returns the name of the state.
+func (c ConnectionState) String() string {
+	switch c {
+	case ConnectionStateDisconnected:
+		return "Disconnected"
+	case ConnectionStateConnecting:
+		return "Connecting"
+	case ConnectionStateIdentifying:
+		return "Identifying"
+	case ConnectionStateResuming:
+		return "Resuming"
+	case ConnectionStateReady:
+		return "Ready"
+	case ConnectionStateReconnecting:
+		return "Reconnecting"
+	case ConnectionStateClosed:
+		return "Closed"
+	}
+	return "ConnectionState(" + strconv.Itoa(int(c)) + ")"
+}
+
+// Causes of state changes requested by the gateway, or detected by the
+// Session, rather than errors of the connection itself.
+var (
+	// ErrGatewayReconnect is the cause of reconnecting when the gateway
+	// requested it with an Op 7 Reconnect.
+	ErrGatewayReconnect = errors.New("gateway requested a reconnect")
+
+	// ErrGatewayInvalidSession is the cause of reconnecting or identifying
+	// again when the gateway invalidated the session with an Op 9.
+	ErrGatewayInvalidSession = errors.New("gateway invalidated the session")
+
+	// ErrHeartbeatACKTimeout is the cause of reconnecting when the gateway
+	// did not acknowledge heartbeats for FailedHeartbeatAcks intervals.
+	ErrHeartbeatACKTimeout = errors.New("gateway did not acknowledge heartbeats")
+)
+
+// Status returns the state of the gateway connection.
+func (s *Session) Status() ConnectionState {
+	return ConnectionState(s.status.Load())
+}
+
+// setStatus moves the gateway connection to state, and emits a StateChange
+// event when it changed. cause is the error which caused the change, if any.
+func (s *Session) setStatus(state ConnectionState, cause error) {
+	old := ConnectionState(s.status.Swap(int32(state)))
+	if old == state {
+		return
+	}
+
+	s.log(LogInformational, "connection state changed from %s to %s", old, state)
+	s.handleEvent(stateChangeEventType, &StateChange{
+		Old:       old,
+		New:       state,
+		CloseCode: gatewayCloseCode(cause),
+		Err:       cause,
+	})
+}
+
+// gatewayCloseCode returns the code of the close frame err was caused by, or
+// zero.
+func gatewayCloseCode(err error) int {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		return closeErr.Code
+	}
+	return 0
+}
@@ -0,0 +1,690 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the DAVE protocol of voice connections, which
+// negotiates the keys of the end-to-end encryption of voice frames in an
+// MLS group of the members of the voice channel.
+
+package discordgo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gorilla/websocket"
+
+	"github.com/lb-selfbot/discordgo/internal/mls"
+)
+
+// daveProtocolVersion is the highest supported DAVE protocol version,
+// advertised when identifying to voice servers.
+const daveProtocolVersion = 1
+
+// daveExporterLabel is the label of the secrets exported from the MLS group
+// for the key ratchets of senders.
+const daveExporterLabel = "Discord Secure Frames v0"
+
+// DAVE voice opcodes.
+const (
+	voiceOpClientsConnect           = 11
+	voiceOpClientDisconnect         = 13
+	voiceOpDAVEPrepareTransition    = 21
+	voiceOpDAVEExecuteTransition    = 22
+	voiceOpDAVETransitionReady      = 23
+	voiceOpDAVEPrepareEpoch         = 24
+	voiceOpDAVEExternalSender       = 25
+	voiceOpDAVEKeyPackage           = 26
+	voiceOpDAVEProposals            = 27
+	voiceOpDAVECommitWelcome        = 28
+	voiceOpDAVEAnnounceCommit       = 29
+	voiceOpDAVEWelcome              = 30
+	voiceOpDAVEInvalidCommitWelcome = 31
+)
+
+// DAVE proposals operation types.
+const (
+	daveProposalsAppend = 0
+	daveProposalsRevoke = 1
+)
+
+// ErrDAVEUnrecognizedUser is returned when an MLS group of a voice channel
+// would have members which are not connected to it.
+var ErrDAVEUnrecognizedUser = errors.New("dave: unrecognized user in group")
+
+// A daveTransition is a change of protocol version or of keys, executed
+// once every member of the voice channel is ready.
+type daveTransition struct {
+	protocolVersion int
+
+	// ratchet is the key ratchet of the client in the new epoch, if the
+	// transition follows a commit or welcome.
+	ratchet *daveKeyRatchet
+}
+
+// A daveSession is the DAVE state of a voice connection.
+type daveSession struct {
+	sync.Mutex
+
+	userID    uint64
+	channelID uint64
+
+	// protocolVersion is the current DAVE protocol version, 0 when only
+	// the transport is encrypted.
+	protocolVersion int
+
+	externalSender *mls.ExternalSender
+	keyPackage     *mls.KeyPackagePrivate
+
+	// group is the MLS group of the voice channel, once joined, and
+	// pendingGroup the group of the client alone, which commits the
+	// proposals adding the other members.
+	group        *mls.Group
+	pendingGroup *mls.Group
+
+	// recognized are the users connected to the voice channel, which can
+	// be members of the group.
+	recognized map[uint64]bool
+
+	transitions map[uint16]*daveTransition
+
+	encryptor  *daveEncryptor
+	decryptors map[uint64]*daveDecryptor
+	ssrcs      map[uint32]uint64
+
+	// passthroughUntil is the time until which unencrypted frames are
+	// accepted after upgrading from protocol version 0.
+	passthroughUntil time.Time
+}
+
+// newDAVESession returns the DAVE session of a voice connection to a
+// channel.
+func newDAVESession(userID, channelID string) *daveSession {
+	d := &daveSession{
+		recognized:  map[uint64]bool{},
+		transitions: map[uint16]*daveTransition{},
+		decryptors:  map[uint64]*daveDecryptor{},
+		ssrcs:       map[uint32]uint64{},
+	}
+	d.userID, _ = strconv.ParseUint(userID, 10, 64)
+	d.channelID, _ = strconv.ParseUint(channelID, 10, 64)
+	return d
+}
+
+// bigEndianID returns the encoding of an ID in group IDs and credentials.
+func bigEndianID(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+// reset drops the MLS group, and returns a new key package to send to the
+// voice server. d must be locked.
+func (d *daveSession) reset() ([]byte, error) {
+	d.group = nil
+	d.pendingGroup = nil
+	d.encryptor = nil
+	clear(d.transitions)
+
+	key, err := mls.GenerateSignatureKey()
+	if err != nil {
+		return nil, err
+	}
+	d.keyPackage = mls.NewKeyPackage(mls.Credential{Identity: bigEndianID(d.userID)}, key)
+	return d.keyPackage.KeyPackage.Marshal(), nil
+}
+
+// proposalGroup returns the group handling proposals, the pending group
+// being created with the external sender when the client is not a member
+// yet. d must be locked.
+func (d *daveSession) proposalGroup() (*mls.Group, error) {
+	if d.group != nil {
+		return d.group, nil
+	}
+	if d.pendingGroup == nil {
+		if d.externalSender == nil || d.keyPackage == nil {
+			return nil, errors.New("dave: proposals received before external sender")
+		}
+		d.pendingGroup = mls.NewGroup(bigEndianID(d.channelID), d.keyPackage, []*mls.ExternalSender{d.externalSender})
+	}
+	return d.pendingGroup, nil
+}
+
+// handleProposals appends or revokes proposals, and returns the commit of the
+// remaining proposals, followed by the Welcome of the added members. d must be
+// locked.
+func (d *daveSession) handleProposals(payload []byte) ([]byte, error) {
+	if len(payload) < 1 {
+		return nil, errors.New("dave: empty proposals")
+	}
+	g, err := d.proposalGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	// The proposals are a vector of MLS messages or proposal refs.
+	r := payload[1:]
+	vector, ok := readMLSVector(&r)
+	if !ok || len(r) > 0 {
+		return nil, mls.ErrDecode
+	}
+
+	switch payload[0] {
+	case daveProposalsAppend:
+		messages, err := mls.UnmarshalMessages(vector)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range messages {
+			p, err := g.HandleProposal(m)
+			if err != nil {
+				return nil, err
+			}
+			if p.Add != nil && !d.recognizedIdentity(p.Add.Credential().Identity) {
+				g.RevokeProposal(m.ProposalRef())
+				return nil, ErrDAVEUnrecognizedUser
+			}
+		}
+	case daveProposalsRevoke:
+		for len(vector) > 0 {
+			ref, ok := readMLSVector(&vector)
+			if !ok {
+				return nil, mls.ErrDecode
+			}
+			g.RevokeProposal(ref)
+		}
+	default:
+		return nil, fmt.Errorf("dave: unknown proposals operation %d", payload[0])
+	}
+
+	commit, welcome, err := g.Commit()
+	if err != nil {
+		return nil, err
+	}
+	data := commit.Marshal()
+	if welcome != nil {
+		data = append(data, welcome.Marshal()...)
+	}
+	return data, nil
+}
+
+// recognizedIdentity reports whether the identity of a credential is a user
+// connected to the voice channel. d must be locked.
+func (d *daveSession) recognizedIdentity(identity []byte) bool {
+	if len(identity) != 8 {
+		return false
+	}
+	id := binary.BigEndian.Uint64(identity)
+	return id == d.userID || d.recognized[id]
+}
+
+// handleCommit processes the commit announced for a transition, and reports
+// whether the client is a member of the group. d must be locked.
+func (d *daveSession) handleCommit(transitionID uint16, data []byte) (bool, error) {
+	m, err := mls.UnmarshalMessage(data)
+	if err != nil {
+		return false, err
+	}
+
+	g := d.group
+	if g == nil {
+		// Only the commit of the client adds it to the group, otherwise it
+		// waits for a Welcome.
+		if g = d.pendingGroup; g == nil || g.HandleCommit(m) != nil {
+			return false, nil
+		}
+		d.group, d.pendingGroup = g, nil
+	} else if err := g.HandleCommit(m); err != nil {
+		return false, err
+	}
+
+	return true, d.prepareEpoch(transitionID)
+}
+
+// handleWelcome joins the group of a Welcome message for a transition, and
+// reports whether the client joined it. d must be locked.
+func (d *daveSession) handleWelcome(transitionID uint16, data []byte) (bool, error) {
+	if d.group != nil {
+		return false, nil
+	}
+	if d.keyPackage == nil {
+		return false, errors.New("dave: welcome received without key package")
+	}
+
+	welcome, err := mls.UnmarshalWelcome(data)
+	if err != nil {
+		return false, err
+	}
+	g, err := mls.JoinGroup(welcome, d.keyPackage)
+	if err != nil {
+		return false, err
+	}
+	d.group, d.pendingGroup = g, nil
+
+	return true, d.prepareEpoch(transitionID)
+}
+
+// prepareEpoch derives the key ratchets of the members of the group in its
+// new epoch. The decryptors use them right away, while the encryptor uses
+// its own once the transition is executed. d must be locked.
+func (d *daveSession) prepareEpoch(transitionID uint16) error {
+	now := time.Now()
+	members := map[uint64]bool{}
+	for _, c := range d.group.Members() {
+		if !d.recognizedIdentity(c.Identity) {
+			return ErrDAVEUnrecognizedUser
+		}
+		members[binary.BigEndian.Uint64(c.Identity)] = true
+	}
+
+	for id := range members {
+		if id == d.userID {
+			continue
+		}
+		dec := d.decryptors[id]
+		if dec == nil {
+			dec = &daveDecryptor{}
+			d.decryptors[id] = dec
+		}
+		dec.addRatchet(d.ratchet(id), now)
+	}
+	for id := range d.decryptors {
+		if !members[id] {
+			delete(d.decryptors, id)
+		}
+	}
+
+	t := d.transitions[transitionID]
+	if t == nil {
+		t = &daveTransition{protocolVersion: d.protocolVersion}
+		d.transitions[transitionID] = t
+	}
+	t.ratchet = d.ratchet(d.userID)
+	if transitionID == 0 {
+		d.executeTransition(0)
+	}
+	return nil
+}
+
+// ratchet returns the key ratchet of a member of the group. d must be
+// locked.
+func (d *daveSession) ratchet(userID uint64) *daveKeyRatchet {
+	return newDAVEKeyRatchet(d.group.Export(daveExporterLabel, binary.LittleEndian.AppendUint64(nil, userID), 16))
+}
+
+// prepareTransition prepares a change of protocol version. d must be locked.
+func (d *daveSession) prepareTransition(transitionID uint16, protocolVersion int) {
+	d.transitions[transitionID] = &daveTransition{protocolVersion: protocolVersion}
+	if transitionID == 0 {
+		d.executeTransition(0)
+	}
+}
+
+// executeTransition executes a prepared transition. d must be locked.
+func (d *daveSession) executeTransition(transitionID uint16) {
+	t := d.transitions[transitionID]
+	if t == nil {
+		return
+	}
+	delete(d.transitions, transitionID)
+
+	if d.protocolVersion == 0 && t.protocolVersion > 0 {
+		d.passthroughUntil = time.Now().Add(daveKeyExpiry)
+	}
+	d.protocolVersion = t.protocolVersion
+	if t.ratchet != nil {
+		d.encryptor = &daveEncryptor{ratchet: t.ratchet}
+	}
+}
+
+// encrypt returns an opus frame to send, encrypted when the protocol version
+// is not 0 and the client is a member of the group.
+func (d *daveSession) encrypt(opus []byte) []byte {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.protocolVersion == 0 || d.encryptor == nil || string(opus) == string(opusSilenceFrame) {
+		return opus
+	}
+	return d.encryptor.encrypt(opus)
+}
+
+// decrypt returns a received opus frame, decrypted with the keys of the
+// user of its SSRC. Unencrypted frames are only accepted with protocol
+// version 0, or shortly after a transition from it.
+func (d *daveSession) decrypt(ssrc uint32, frame []byte) ([]byte, bool) {
+	d.Lock()
+	defer d.Unlock()
+
+	if string(frame) == string(opusSilenceFrame) {
+		return frame, true
+	}
+
+	now := time.Now()
+	if !isDAVEFrame(frame) {
+		return frame, d.protocolVersion == 0 || now.Before(d.passthroughUntil)
+	}
+
+	dec := d.decryptors[d.ssrcs[ssrc]]
+	if dec == nil {
+		return nil, false
+	}
+	return dec.decrypt(frame, now)
+}
+
+// readMLSVector reads a vector with a variable length integer length, as
+// encoded by MLS, from the start of b.
+func readMLSVector(b *[]byte) ([]byte, bool) {
+	data := *b
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	var n, size int
+	switch data[0] >> 6 {
+	case 0:
+		n, size = int(data[0]), 1
+	case 1:
+		if len(data) < 2 {
+			return nil, false
+		}
+		n, size = int(binary.BigEndian.Uint16(data)&0x3FFF), 2
+	case 2:
+		if len(data) < 4 {
+			return nil, false
+		}
+		n, size = int(binary.BigEndian.Uint32(data)&0x3FFFFFFF), 4
+	default:
+		return nil, false
+	}
+	if len(data) < size+n {
+		return nil, false
+	}
+	*b = data[size+n:]
+	return data[size : size+n], true
+}
+
+// ------------------------------------------------------------------------------------------------
+// Code related to the DAVE messages of the voice websocket
+// ------------------------------------------------------------------------------------------------
+
+type voiceDAVETransition struct {
+	TransitionID    uint16 `json:"transition_id"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+type voiceDAVEEpoch struct {
+	Epoch           uint64 `json:"epoch"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+type voiceDAVETransitionOp struct {
+	Op   int                 `json:"op"` // 23 or 31
+	Data voiceDAVETransition `json:"d"`
+}
+
+type voiceClientsConnect struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+type voiceClientDisconnect struct {
+	UserID string `json:"user_id"`
+}
+
+// isDAVEEvent reports whether a JSON message of the voice websocket is a DAVE
+// message.
+func isDAVEEvent(message []byte) bool {
+	var e struct {
+		Operation int `json:"op"`
+	}
+	if err := json.Unmarshal(message, &e); err != nil {
+		return false
+	}
+
+	switch e.Operation {
+	case voiceOpClientsConnect, voiceOpClientDisconnect, voiceOpDAVEPrepareTransition, voiceOpDAVEExecuteTransition, voiceOpDAVEPrepareEpoch:
+		return true
+	}
+	return false
+}
+
+// DAVEProtocolVersion returns the DAVE protocol version of the voice
+// connection, 0 when its voice frames are not end-to-end encrypted.
+func (v *VoiceConnection) DAVEProtocolVersion() int {
+	v.RLock()
+	d := v.dave
+	v.RUnlock()
+	if d == nil {
+		return 0
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return d.protocolVersion
+}
+
+// writeDAVE sends a binary DAVE message on the voice websocket.
+func (v *VoiceConnection) writeDAVE(wsConn *websocket.Conn, op int, payload []byte) error {
+	v.wsMutex.Lock()
+	defer v.wsMutex.Unlock()
+	return wsConn.WriteMessage(websocket.BinaryMessage, append([]byte{byte(op)}, payload...))
+}
+
+// writeDAVETransition sends a transition ready or invalid commit message.
+func (v *VoiceConnection) writeDAVETransition(wsConn *websocket.Conn, op int, transitionID uint16) error {
+	v.wsMutex.Lock()
+	defer v.wsMutex.Unlock()
+	return wsConn.WriteJSON(voiceDAVETransitionOp{op, voiceDAVETransition{TransitionID: transitionID}})
+}
+
+// daveSession returns the websocket and DAVE session of the voice
+// connection.
+func (v *VoiceConnection) daveSession() (*websocket.Conn, *daveSession) {
+	v.RLock()
+	defer v.RUnlock()
+	return v.wsConn, v.dave
+}
+
+// daveReset resets the DAVE session, and sends a new key package. When it
+// follows an invalid commit or welcome, the transition is reported invalid
+// first.
+func (v *VoiceConnection) daveReset(wsConn *websocket.Conn, d *daveSession, invalid *uint16) {
+	if invalid != nil {
+		if err := v.writeDAVETransition(wsConn, voiceOpDAVEInvalidCommitWelcome, *invalid); err != nil {
+			v.log(LogError, "error sending DAVE invalid commit, %s", err)
+			return
+		}
+	}
+
+	d.Lock()
+	keyPackage, err := d.reset()
+	d.Unlock()
+	if err != nil {
+		v.log(LogError, "error creating DAVE key package, %s", err)
+		return
+	}
+	if err := v.writeDAVE(wsConn, voiceOpDAVEKeyPackage, keyPackage); err != nil {
+		v.log(LogError, "error sending DAVE key package, %s", err)
+	}
+}
+
+// onDAVEBinary handles the binary DAVE messages of the voice websocket, which
+// start with a sequence number and an opcode. They are handled in order, as
+// each depends on the previous ones.
+func (v *VoiceConnection) onDAVEBinary(message []byte) {
+	if len(message) < 3 {
+		v.log(LogError, "DAVE message too short, %x", message)
+		return
+	}
+	seq, op, payload := int(binary.BigEndian.Uint16(message)), int(message[2]), message[3:]
+
+	v.Lock()
+	if seq > v.seqAck {
+		v.seqAck = seq
+	}
+	v.Unlock()
+
+	wsConn, d := v.daveSession()
+	if d == nil || wsConn == nil {
+		return
+	}
+
+	v.log(LogDebug, "received DAVE opcode %d, %d bytes", op, len(payload))
+
+	switch op {
+	case voiceOpDAVEExternalSender:
+		sender, err := mls.UnmarshalExternalSender(payload)
+		if err != nil {
+			v.log(LogError, "DAVE external sender unmarshal error, %s", err)
+			return
+		}
+		d.Lock()
+		d.externalSender = sender
+		d.Unlock()
+
+	case voiceOpDAVEProposals:
+		d.Lock()
+		commit, err := d.handleProposals(payload)
+		d.Unlock()
+		if err != nil {
+			v.log(LogError, "error handling DAVE proposals, %s", err)
+			return
+		}
+		if err := v.writeDAVE(wsConn, voiceOpDAVECommitWelcome, commit); err != nil {
+			v.log(LogError, "error sending DAVE commit, %s", err)
+		}
+
+	case voiceOpDAVEAnnounceCommit, voiceOpDAVEWelcome:
+		if len(payload) < 2 {
+			v.log(LogError, "DAVE opcode %d too short", op)
+			return
+		}
+		transitionID := binary.BigEndian.Uint16(payload)
+
+		d.Lock()
+		var member bool
+		var err error
+		if op == voiceOpDAVEAnnounceCommit {
+			member, err = d.handleCommit(transitionID, payload[2:])
+		} else {
+			member, err = d.handleWelcome(transitionID, payload[2:])
+		}
+		d.Unlock()
+		if err != nil {
+			v.log(LogError, "invalid DAVE commit or welcome for transition %d, %s", transitionID, err)
+			v.daveReset(wsConn, d, &transitionID)
+			return
+		}
+
+		if member && transitionID != 0 {
+			if err := v.writeDAVETransition(wsConn, voiceOpDAVETransitionReady, transitionID); err != nil {
+				v.log(LogError, "error sending DAVE transition ready, %s", err)
+			}
+		}
+
+	default:
+		v.log(LogDebug, "unknown DAVE opcode, %d", op)
+	}
+}
+
+// onDAVEEvent handles the JSON DAVE messages of the voice websocket.
+func (v *VoiceConnection) onDAVEEvent(e *voiceEvent) {
+	wsConn, d := v.daveSession()
+	if d == nil || wsConn == nil {
+		return
+	}
+
+	switch e.Operation {
+	case voiceOpClientsConnect:
+		var data voiceClientsConnect
+		if err := json.Unmarshal(e.RawData, &data); err != nil {
+			v.log(LogError, "OP11 unmarshall error, %s, %s", err, string(e.RawData))
+			return
+		}
+		d.Lock()
+		for _, id := range data.UserIDs {
+			if u, err := strconv.ParseUint(id, 10, 64); err == nil {
+				d.recognized[u] = true
+			}
+		}
+		d.Unlock()
+
+	case voiceOpClientDisconnect:
+		var data voiceClientDisconnect
+		if err := json.Unmarshal(e.RawData, &data); err != nil {
+			v.log(LogError, "OP13 unmarshall error, %s, %s", err, string(e.RawData))
+			return
+		}
+		if u, err := strconv.ParseUint(data.UserID, 10, 64); err == nil {
+			d.Lock()
+			delete(d.recognized, u)
+			d.Unlock()
+		}
+
+	case voiceOpDAVEPrepareTransition:
+		var data voiceDAVETransition
+		if err := json.Unmarshal(e.RawData, &data); err != nil {
+			v.log(LogError, "OP21 unmarshall error, %s, %s", err, string(e.RawData))
+			return
+		}
+		d.Lock()
+		d.prepareTransition(data.TransitionID, data.ProtocolVersion)
+		d.Unlock()
+		if data.TransitionID != 0 {
+			if err := v.writeDAVETransition(wsConn, voiceOpDAVETransitionReady, data.TransitionID); err != nil {
+				v.log(LogError, "error sending DAVE transition ready, %s", err)
+			}
+		}
+
+	case voiceOpDAVEExecuteTransition:
+		var data voiceDAVETransition
+		if err := json.Unmarshal(e.RawData, &data); err != nil {
+			v.log(LogError, "OP22 unmarshall error, %s, %s", err, string(e.RawData))
+			return
+		}
+		d.Lock()
+		d.executeTransition(data.TransitionID)
+		d.Unlock()
+		v.log(LogInformational, "executed DAVE transition %d", data.TransitionID)
+
+	case voiceOpDAVEPrepareEpoch:
+		var data voiceDAVEEpoch
+		if err := json.Unmarshal(e.RawData, &data); err != nil {
+			v.log(LogError, "OP24 unmarshall error, %s, %s", err, string(e.RawData))
+			return
+		}
+
+		// Epoch 1 is a new group, which the client joins with a new key
+		// package.
+		if data.Epoch == 1 {
+			d.Lock()
+			d.protocolVersion = data.ProtocolVersion
+			d.Unlock()
+			v.daveReset(wsConn, d, nil)
+		}
+	}
+}
+
+// onDAVESessionDescription starts the DAVE session with the protocol version
+// selected by the voice server, 0 falling back to transport encryption only.
+func (v *VoiceConnection) onDAVESessionDescription(protocolVersion int) {
+	wsConn, d := v.daveSession()
+	if d == nil || wsConn == nil {
+		return
+	}
+
+	d.Lock()
+	d.protocolVersion = protocolVersion
+	d.Unlock()
+
+	if protocolVersion > 0 {
+		v.log(LogInformational, "using DAVE protocol version %d", protocolVersion)
+		v.daveReset(wsConn, d, nil)
+	}
+}
@@ -0,0 +1,160 @@
+package discordgo
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/lb-selfbot/discordgo/internal/mls"
+)
+
+// daveTestServer is the external sender of the DAVE sessions of the tests.
+type daveTestServer struct {
+	key    *mls.SignatureKey
+	sender *mls.ExternalSender
+}
+
+func newDAVETestServer(t *testing.T) *daveTestServer {
+	key, err := mls.GenerateSignatureKey()
+	if err != nil {
+		t.Fatalf("GenerateSignatureKey returned error: %v", err)
+	}
+	return &daveTestServer{key, &mls.ExternalSender{SignatureKey: key.PublicKey(), Credential: mls.Credential{Identity: []byte("server")}}}
+}
+
+// join returns the DAVE session of a user connected to the channel 10, and
+// its key package.
+func (s *daveTestServer) join(t *testing.T, userID string) (*daveSession, []byte) {
+	d := newDAVESession(userID, "10")
+	d.protocolVersion = 1
+	d.externalSender = s.sender
+	keyPackage, err := d.reset()
+	if err != nil {
+		t.Fatalf("reset returned error: %v", err)
+	}
+	return d, keyPackage
+}
+
+// addProposals returns the proposals payload adding the clients of key
+// packages to the group of the channel 10 at an epoch.
+func (s *daveTestServer) addProposals(t *testing.T, epoch uint64, keyPackages ...[]byte) []byte {
+	var messages []byte
+	for _, data := range keyPackages {
+		kp, err := mls.UnmarshalKeyPackage(data)
+		if err != nil {
+			t.Fatalf("UnmarshalKeyPackage returned error: %v", err)
+		}
+		messages = append(messages, mls.NewExternalProposal(bigEndianID(10), epoch, 0, s.key, mls.AddProposal(kp)).Marshal()...)
+	}
+	return mls.AppendVector([]byte{daveProposalsAppend}, messages)
+}
+
+func TestDAVESession(t *testing.T) {
+	server := newDAVETestServer(t)
+	alice, _ := server.join(t, "1")
+	bob, bobKeyPackage := server.join(t, "2")
+	alice.recognized[2] = true
+	bob.recognized[1] = true
+
+	// Alice commits the addition of Bob, who joins with the Welcome.
+	data, err := alice.handleProposals(server.addProposals(t, 0, bobKeyPackage))
+	if err != nil {
+		t.Fatalf("handleProposals returned error: %v", err)
+	}
+	commit, welcome, err := mls.ReadMessage(data)
+	if err != nil || len(welcome) == 0 {
+		t.Fatalf("expected a commit and a welcome, got %v", err)
+	}
+
+	if member, err := alice.handleCommit(1, commit.Marshal()); !member || err != nil {
+		t.Fatalf("expected alice to be a member, got %t %v", member, err)
+	}
+	if member, err := bob.handleWelcome(1, welcome); !member || err != nil {
+		t.Fatalf("expected bob to join, got %t %v", member, err)
+	}
+
+	// Frames are only encrypted once the transition is executed.
+	opus := []byte("opus frame")
+	if frame := alice.encrypt(opus); !bytes.Equal(frame, opus) {
+		t.Errorf("expected unencrypted frame before the transition, got %x", frame)
+	}
+	alice.executeTransition(1)
+	bob.executeTransition(1)
+
+	alice.ssrcs[200] = 2
+	bob.ssrcs[100] = 1
+	frame := alice.encrypt(opus)
+	if !isDAVEFrame(frame) {
+		t.Fatalf("expected encrypted frame, got %x", frame)
+	}
+	if got, ok := bob.decrypt(100, frame); !ok || !bytes.Equal(got, opus) {
+		t.Errorf("expected bob to decrypt the frame of alice, got %x", got)
+	}
+	if got, ok := alice.decrypt(200, bob.encrypt(opus)); !ok || !bytes.Equal(got, opus) {
+		t.Errorf("expected alice to decrypt the frame of bob, got %x", got)
+	}
+	if _, ok := alice.decrypt(100, bob.encrypt(opus)); ok {
+		t.Error("expected frame of another sender to be rejected")
+	}
+
+	// Unencrypted frames are rejected, except silence.
+	if _, ok := bob.decrypt(100, opus); ok {
+		t.Error("expected unencrypted frame to be rejected")
+	}
+	if got, ok := bob.decrypt(100, alice.encrypt(opusSilenceFrame)); !ok || !bytes.Equal(got, opusSilenceFrame) {
+		t.Errorf("expected silence frame to be passed through, got %x", got)
+	}
+
+	// Only the users connected to the channel can be added.
+	_, carolKeyPackage := server.join(t, "3")
+	if _, err := alice.handleProposals(server.addProposals(t, 1, carolKeyPackage)); !errors.Is(err, ErrDAVEUnrecognizedUser) {
+		t.Errorf("expected ErrDAVEUnrecognizedUser, got %v", err)
+	}
+
+	// Falling back to protocol version 0 disables the end-to-end encryption.
+	for _, d := range []*daveSession{alice, bob} {
+		d.prepareTransition(2, 0)
+		d.executeTransition(2)
+	}
+	if frame := alice.encrypt(opus); !bytes.Equal(frame, opus) {
+		t.Errorf("expected unencrypted frame, got %x", frame)
+	}
+	if got, ok := bob.decrypt(100, opus); !ok || !bytes.Equal(got, opus) {
+		t.Errorf("expected unencrypted frame to be accepted, got %x", got)
+	}
+}
+
+func TestDAVESessionConcurrentCommits(t *testing.T) {
+	server := newDAVETestServer(t)
+	alice, aliceKeyPackage := server.join(t, "1")
+	bob, bobKeyPackage := server.join(t, "2")
+	alice.recognized[2] = true
+	bob.recognized[1] = true
+
+	// Both clients commit, and the commit of Bob is announced: Alice joins
+	// with its Welcome.
+	if _, err := alice.handleProposals(server.addProposals(t, 0, bobKeyPackage)); err != nil {
+		t.Fatalf("handleProposals returned error: %v", err)
+	}
+	data, err := bob.handleProposals(server.addProposals(t, 0, aliceKeyPackage))
+	if err != nil {
+		t.Fatalf("handleProposals returned error: %v", err)
+	}
+	commit, welcome, _ := mls.ReadMessage(data)
+
+	if member, err := alice.handleCommit(0, commit.Marshal()); member || err != nil {
+		t.Fatalf("expected alice to wait for the welcome, got %t %v", member, err)
+	}
+	if member, err := bob.handleCommit(0, commit.Marshal()); !member || err != nil {
+		t.Fatalf("expected bob to be a member, got %t %v", member, err)
+	}
+	if member, err := alice.handleWelcome(0, welcome); !member || err != nil {
+		t.Fatalf("expected alice to join, got %t %v", member, err)
+	}
+
+	// Transition 0 is executed right away.
+	bob.ssrcs[100] = 1
+	if got, ok := bob.decrypt(100, alice.encrypt([]byte("opus"))); !ok || string(got) != "opus" {
+		t.Errorf("expected bob to decrypt the frame of alice, got %x", got)
+	}
+}
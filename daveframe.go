@@ -0,0 +1,257 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the DAVE frame encryption of voice connections, which
+// encrypts every opus frame with the key of its sender before the transport
+// encryption of its RTP packet.
+
+package discordgo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"time"
+
+	"github.com/lb-selfbot/discordgo/internal/mls"
+)
+
+// DAVE frame constants.
+const (
+	daveTagSize = 8
+
+	// daveSupplementalSize is the size of the tag, supplemental size byte
+	// and magic marker at the end of encrypted frames, without the nonce.
+	daveSupplementalSize = daveTagSize + 1 + 2
+
+	// daveGenerationShift is the shift of the truncated nonce of a frame
+	// giving the generation of its key.
+	daveGenerationShift = 24
+
+	// daveMaxGenerationGap is the number of generations a sender can skip.
+	daveMaxGenerationGap = 250
+
+	// daveKeyExpiry is how long the keys of a previous epoch, and
+	// unencrypted frames after a transition, are accepted.
+	daveKeyExpiry = 10 * time.Second
+)
+
+// daveMagicMarker ends encrypted frames.
+var daveMagicMarker = []byte{0xFA, 0xFA}
+
+// opusSilenceFrame is sent unencrypted.
+var opusSilenceFrame = []byte{0xF8, 0xFF, 0xFE}
+
+// daveKey is the AES-128-GCM key of a generation of a sender.
+type daveKey struct {
+	block cipher.Block
+	aead  cipher.AEAD
+}
+
+func newDAVEKey(key []byte) *daveKey {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic("dave: " + err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic("dave: " + err.Error())
+	}
+	return &daveKey{block, aead}
+}
+
+// daveNonce returns the GCM nonce of a truncated frame nonce.
+func daveNonce(nonce uint32) []byte {
+	n := make([]byte, 12)
+	binary.LittleEndian.PutUint32(n[8:], nonce)
+	return n
+}
+
+// seal returns the ciphertext and truncated tag of a frame.
+func (k *daveKey) seal(dst, plaintext []byte, nonce uint32) []byte {
+	sealed := k.aead.Seal(dst, daveNonce(nonce), plaintext, nil)
+	return sealed[:len(sealed)-k.aead.Overhead()+daveTagSize]
+}
+
+// open decrypts the ciphertext of a frame, and verifies its truncated tag.
+// GCM tags shorter than 12 bytes are not supported by crypto/cipher, so the
+// ciphertext is decrypted with the counter mode of GCM, and sealed again to
+// compare the tags.
+func (k *daveKey) open(ciphertext, tag []byte, nonce uint32) ([]byte, bool) {
+	counter := daveNonce(nonce)
+	counter = append(counter[:12:12], 0, 0, 0, 2)
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(k.block, counter).XORKeyStream(plaintext, ciphertext)
+
+	sealed := k.seal(nil, plaintext, nonce)
+	if subtle.ConstantTimeCompare(sealed[len(plaintext):], tag) != 1 {
+		return nil, false
+	}
+	return plaintext, true
+}
+
+// daveKeyRatchet derives the keys of the generations of a sender, from the
+// secret exported from the MLS group for it.
+type daveKeyRatchet struct {
+	secret []byte
+	next   uint32
+	keys   map[uint32]*daveKey
+}
+
+func newDAVEKeyRatchet(secret []byte) *daveKeyRatchet {
+	return &daveKeyRatchet{secret: secret, keys: map[uint32]*daveKey{}}
+}
+
+// key returns the key of a generation, and commit which keeps it. The
+// generation of a received frame comes from its nonce, which is not
+// authenticated, so the keys of new generations are derived without changing
+// the ratchet: commit must only be called once the key authenticated a frame.
+// It then keeps the keys derived, and drops those older than the two previous
+// generations.
+func (r *daveKeyRatchet) key(generation uint32) (key *daveKey, commit func(), ok bool) {
+	if generation < r.next {
+		key, ok = r.keys[generation]
+		return key, func() {}, ok
+	}
+	if generation-r.next > daveMaxGenerationGap {
+		return nil, nil, false
+	}
+
+	keys := make([]*daveKey, 0, generation-r.next+1)
+	secret := r.secret
+	for g := r.next; g <= generation; g++ {
+		var context [4]byte
+		binary.BigEndian.PutUint32(context[:], g)
+		keys = append(keys, newDAVEKey(mls.ExpandWithLabel(secret, "key", context[:], 16)))
+		secret = mls.ExpandWithLabel(secret, "secret", context[:], 32)
+	}
+
+	commit = func() {
+		for _, k := range keys {
+			r.keys[r.next] = k
+			r.next++
+		}
+		r.secret = secret
+		for g := range r.keys {
+			if g+2 < generation {
+				delete(r.keys, g)
+			}
+		}
+	}
+	return keys[len(keys)-1], commit, true
+}
+
+// daveEncryptor encrypts the frames sent by the client.
+type daveEncryptor struct {
+	ratchet *daveKeyRatchet
+	nonce   uint32
+}
+
+// encrypt returns an encrypted opus frame.
+func (e *daveEncryptor) encrypt(opus []byte) []byte {
+	nonce := e.nonce
+	e.nonce++
+
+	// The ratchet can always derive the next generation, and the keys of
+	// the client are trusted.
+	key, commit, _ := e.ratchet.key(nonce >> daveGenerationShift)
+	commit()
+	frame := key.seal(make([]byte, 0, len(opus)+daveSupplementalSize+5), opus, nonce)
+	size := len(frame)
+	frame = appendULEB128(frame, uint64(nonce))
+	frame = append(frame, byte(len(frame)-size+daveSupplementalSize))
+	return append(frame, daveMagicMarker...)
+}
+
+// daveDecryptor decrypts the frames of a sender. The ratchets of previous
+// epochs are kept for a while, for the frames sent before a transition.
+type daveDecryptor struct {
+	ratchets []*daveKeyRatchet
+	expiry   []time.Time
+}
+
+// addRatchet adds the ratchet of a new epoch, and expires the others.
+func (d *daveDecryptor) addRatchet(r *daveKeyRatchet, now time.Time) {
+	for i := range d.expiry {
+		if d.expiry[i].IsZero() {
+			d.expiry[i] = now.Add(daveKeyExpiry)
+		}
+	}
+	d.ratchets = append([]*daveKeyRatchet{r}, d.ratchets...)
+	d.expiry = append([]time.Time{{}}, d.expiry...)
+}
+
+// decrypt decrypts an encrypted frame, with the newest key which can.
+func (d *daveDecryptor) decrypt(frame []byte, now time.Time) ([]byte, bool) {
+	ciphertext, tag, nonce, ok := parseDAVEFrame(frame)
+	if !ok {
+		return nil, false
+	}
+
+	for i := 0; i < len(d.ratchets); i++ {
+		if !d.expiry[i].IsZero() && now.After(d.expiry[i]) {
+			d.ratchets, d.expiry = d.ratchets[:i], d.expiry[:i]
+			break
+		}
+		if key, commit, ok := d.ratchets[i].key(nonce >> daveGenerationShift); ok {
+			if opus, ok := key.open(ciphertext, tag, nonce); ok {
+				commit()
+				return opus, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// isDAVEFrame reports whether a frame ends with the marker of encrypted
+// frames.
+func isDAVEFrame(frame []byte) bool {
+	return bytes.HasSuffix(frame, daveMagicMarker)
+}
+
+// parseDAVEFrame returns the ciphertext, truncated tag and nonce of an
+// encrypted opus frame, which has no unencrypted ranges.
+func parseDAVEFrame(frame []byte) (ciphertext, tag []byte, nonce uint32, ok bool) {
+	if !isDAVEFrame(frame) || len(frame) < daveSupplementalSize+1 {
+		return nil, nil, 0, false
+	}
+	size := int(frame[len(frame)-3])
+	if size < daveSupplementalSize+1 || size > len(frame) {
+		return nil, nil, 0, false
+	}
+
+	supplemental := frame[len(frame)-size : len(frame)-3]
+	n, read := readULEB128(supplemental[daveTagSize:])
+	if read == 0 || daveTagSize+read != len(supplemental) || n > 0xFFFFFFFF {
+		return nil, nil, 0, false
+	}
+	return frame[:len(frame)-size], supplemental[:daveTagSize], uint32(n), true
+}
+
+// appendULEB128 appends the unsigned LEB128 encoding of v to b.
+func appendULEB128(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// readULEB128 decodes an unsigned LEB128 value, and returns it with the
+// number of bytes read, 0 when it is malformed.
+func readULEB128(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7F) << (7 * i)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
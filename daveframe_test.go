@@ -0,0 +1,156 @@
+package discordgo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestULEB128(t *testing.T) {
+	tests := []struct {
+		value   uint64
+		encoded string
+	}{
+		{0, "00"},
+		{127, "7f"},
+		{128, "8001"},
+		{624485, "e58e26"},
+		{0xFFFFFFFF, "ffffffff0f"},
+	}
+
+	for _, test := range tests {
+		if b := hex.EncodeToString(appendULEB128(nil, test.value)); b != test.encoded {
+			t.Errorf("expected %d to encode to %s, got %s", test.value, test.encoded, b)
+		}
+		b, _ := hex.DecodeString(test.encoded)
+		if v, n := readULEB128(b); v != test.value || n != len(b) {
+			t.Errorf("expected %s to decode to %d, got %d (%d bytes)", test.encoded, test.value, v, n)
+		}
+	}
+
+	if _, n := readULEB128([]byte{0x80, 0x80}); n != 0 {
+		t.Error("expected truncated value to be rejected")
+	}
+}
+
+// daveTestSecret is the exported secret of the frame vectors.
+var daveTestSecret = []byte("0123456789abcdef")
+
+func TestDAVEFrameVectors(t *testing.T) {
+	// Regression vectors of frames encrypted with the key ratchet of
+	// daveTestSecret, at the given nonces.
+	tests := []struct {
+		nonce uint32
+		opus  string
+		frame string
+	}{
+		{0, "opus frame", "15320977328090fccdc68b5310965505c1b7000cfafa"},
+		{1<<daveGenerationShift | 5, "opus frame", "e50588027616c3d45724a0d9384cdafcc305858080080ffafa"},
+	}
+
+	for _, test := range tests {
+		e := &daveEncryptor{ratchet: newDAVEKeyRatchet(daveTestSecret), nonce: test.nonce}
+		frame := e.encrypt([]byte(test.opus))
+		if got := hex.EncodeToString(frame); got != test.frame {
+			t.Errorf("nonce %d: expected frame %s, got %s", test.nonce, test.frame, got)
+		}
+
+		d := &daveDecryptor{}
+		d.addRatchet(newDAVEKeyRatchet(daveTestSecret), time.Now())
+		want, _ := hex.DecodeString(test.frame)
+		if opus, ok := d.decrypt(want, time.Now()); !ok || string(opus) != test.opus {
+			t.Errorf("nonce %d: expected frame to decrypt to %q, got %q", test.nonce, test.opus, opus)
+		}
+	}
+}
+
+func TestDAVEFrame(t *testing.T) {
+	e := &daveEncryptor{ratchet: newDAVEKeyRatchet(daveTestSecret)}
+	d := &daveDecryptor{}
+	now := time.Now()
+	d.addRatchet(newDAVEKeyRatchet(daveTestSecret), now)
+
+	opus := bytes.Repeat([]byte{1, 2, 3}, 100)
+	frame := e.encrypt(opus)
+	if !isDAVEFrame(frame) || len(frame) != len(opus)+daveSupplementalSize+1 {
+		t.Fatalf("unexpected frame %x", frame)
+	}
+	if got, ok := d.decrypt(frame, now); !ok || !bytes.Equal(got, opus) {
+		t.Fatalf("expected frame to decrypt, got %x", got)
+	}
+
+	// Tampered ciphertexts, tags and nonces are rejected.
+	for _, i := range []int{0, len(opus), len(opus) + daveTagSize} {
+		tampered := bytes.Clone(frame)
+		tampered[i] ^= 1
+		if _, ok := d.decrypt(tampered, now); ok {
+			t.Errorf("expected frame tampered at %d to be rejected", i)
+		}
+	}
+	if _, ok := d.decrypt(frame[1:], now); ok {
+		t.Error("expected truncated frame to be rejected")
+	}
+
+	// A forged frame of a later generation does not drop the keys of the
+	// current one.
+	forged := (&daveEncryptor{ratchet: newDAVEKeyRatchet([]byte("fedcba9876543210")), nonce: 10 << daveGenerationShift}).encrypt(opus)
+	if _, ok := d.decrypt(forged, now); ok {
+		t.Error("expected forged frame to be rejected")
+	}
+	if got, ok := d.decrypt(frame, now); !ok || !bytes.Equal(got, opus) {
+		t.Error("expected frame to decrypt after a forged frame")
+	}
+
+	// Frames of the previous epoch are decrypted until its keys expire.
+	d.addRatchet(newDAVEKeyRatchet([]byte("fedcba9876543210")), now)
+	if _, ok := d.decrypt(e.encrypt(opus), now.Add(daveKeyExpiry/2)); !ok {
+		t.Error("expected frame of previous epoch to decrypt")
+	}
+	if _, ok := d.decrypt(e.encrypt(opus), now.Add(2*daveKeyExpiry)); ok {
+		t.Error("expected frame of expired epoch to be rejected")
+	}
+}
+
+func TestDAVEKeyRatchet(t *testing.T) {
+	r := newDAVEKeyRatchet(daveTestSecret)
+	use := func(generation uint32) (*daveKey, bool) {
+		k, commit, ok := r.key(generation)
+		if ok {
+			commit()
+		}
+		return k, ok
+	}
+	k0, _ := use(0)
+
+	// Older generations are kept until two newer ones are used.
+	use(2)
+	if k, ok := use(0); !ok || k != k0 {
+		t.Error("expected generation 0 to be kept")
+	}
+
+	// Keys which are not committed, as they did not authenticate a frame,
+	// do not advance the ratchet.
+	if _, _, ok := r.key(10); !ok {
+		t.Error("expected generation 10 to be derived")
+	}
+	if k, ok := use(0); !ok || k != k0 {
+		t.Error("expected generation 0 to be kept after an uncommitted key")
+	}
+
+	use(10)
+	if _, ok := use(0); ok {
+		t.Error("expected generation 0 to be dropped")
+	}
+	if _, _, ok := r.key(11 + daveMaxGenerationGap + 1); ok {
+		t.Error("expected too distant generation to be rejected")
+	}
+
+	// The key of a generation does not depend on the previous ones used.
+	k3, _, _ := newDAVEKeyRatchet(daveTestSecret).key(3)
+	r = newDAVEKeyRatchet(daveTestSecret)
+	use(1)
+	if k, ok := use(3); !ok || !bytes.Equal(k.seal(nil, nil, 0), k3.seal(nil, nil, 0)) {
+		t.Error("expected the same key for generation 3")
+	}
+}
@@ -191,6 +191,7 @@ func New(token string) (s *Session, err error) {
 		Token:                       token,
 		State:                       NewState(),
 		Ratelimiter:                 NewRatelimiter(),
+		RESTMiddleware:              append([]RESTMiddleware(nil), DefaultRESTMiddleware...),
 		StateEnabled:                true,
 		ShouldSubscribeGuilds:       true,
 		MaxGuildSubscriptionMembers: 100,
@@ -3,10 +3,13 @@ package discordgo
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/lb-selfbot/discordgo/recorder"
 )
 
 // ////////////////////////////////////////////////////////////////////////////
@@ -21,6 +24,7 @@ var (
 	envChannel      = os.Getenv("DG_CHANNEL")       // Channel ID to use for tests
 	envVoiceChannel = os.Getenv("DG_VOICE_CHANNEL") // Channel ID to use for tests
 	envAdmin        = os.Getenv("DG_ADMIN")         // User ID of admin user to use for tests
+	envRecord       = os.Getenv("DG_RECORD")        // Record REST fixtures instead of replaying them
 )
 
 func TestMain(m *testing.M) {
@@ -44,6 +48,45 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+// newReplaySession returns a session which replays the REST interactions in
+// testdata/fixtures/<test name>.json. If DG_RECORD is set, the interactions
+// are instead recorded from Discord using DGU_TOKEN and saved to that file.
+//
+// The fixtures in the tree are synthetic: they were written by hand from the
+// samples of the Discord documentation, not recorded, and only some of their
+// requests carry headers. TestReplayRoundTrip covers the recorded format.
+func newReplaySession(t *testing.T) *Session {
+	path := filepath.Join("testdata", "fixtures", t.Name()+".json")
+
+	token := ""
+	mode := recorder.ModeReplay
+	if envRecord != "" {
+		if envOAuth2Token == "" {
+			t.Skip("Skipping recording, DGU_TOKEN not set")
+		}
+		token = envOAuth2Token
+		mode = recorder.ModeRecord
+	}
+
+	s, err := New(token)
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	rec, err := recorder.New(path, mode, s.Client)
+	if err != nil {
+		t.Fatalf("recorder.New returned error: %+v", err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("error saving fixture %s, %s", path, err)
+		}
+	})
+
+	s.Client = rec
+	return s
+}
+
 //////////////////////////////////////////////////////////////////////////////
 /////////////////////////////////////////////////////////////// START OF TESTS
 
@@ -0,0 +1,416 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the DAVE protocol of the fake voice server: the MLS
+// messages relayed between the voice client and the other members of its
+// voice channel, which are simulated by the Server, and the end-to-end
+// encryption of their frames.
+
+package discordgotest
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"slices"
+	"strconv"
+
+	"github.com/lb-selfbot/discordgo/internal/mls"
+)
+
+// daveExporterLabel is the label of the secrets exported from the MLS group
+// for the frame keys of members.
+const daveExporterLabel = "Discord Secure Frames v0"
+
+// daveMagicMarker ends encrypted frames.
+var daveMagicMarker = []byte{0xFA, 0xFA}
+
+type voiceDAVETransitionData struct {
+	TransitionID    uint16 `json:"transition_id"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+type voiceDAVEEpochData struct {
+	Epoch           uint64 `json:"epoch"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+type voiceClientsConnectData struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// A davePeer is another member of a voice channel, simulated by the Server.
+// It only uses the first generation of the keys of an epoch, which is enough
+// for the frames of tests.
+type davePeer struct {
+	userID     string
+	ssrc       uint32
+	keyPackage *mls.KeyPackagePrivate
+	group      *mls.Group
+
+	// nonce is the nonce of the next frame sent in the epoch, and seq the
+	// sequence number of the next RTP packet.
+	nonce uint32
+	seq   uint16
+
+	// clientKeys are the keys of the frames of the voice client in the
+	// current and previous epochs.
+	clientKeys []cipher.Block
+
+	// opus are the frames of the voice client, decrypted.
+	opus [][]byte
+}
+
+// newDAVEPeer returns a peer with a new key package.
+func newDAVEPeer(userID string, ssrc uint32) *davePeer {
+	p := &davePeer{userID: userID, ssrc: ssrc}
+	p.reset()
+	return p
+}
+
+// reset leaves the group, and creates a new key package.
+func (p *davePeer) reset() {
+	key, err := mls.GenerateSignatureKey()
+	if err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+	p.keyPackage = mls.NewKeyPackage(mls.Credential{Identity: daveID(p.userID)}, key)
+	p.group = nil
+}
+
+// startEpoch derives the keys of the new epoch of the group.
+func (p *davePeer) startEpoch(clientID string) {
+	p.nonce = 0
+	p.clientKeys = append([]cipher.Block{daveFrameKey(p.group, clientID)}, p.clientKeys...)
+	if len(p.clientKeys) > 2 {
+		p.clientKeys = p.clientKeys[:2]
+	}
+}
+
+// seal encrypts a frame sent by the peer, once it is a member of the group.
+func (p *davePeer) seal(opus []byte) []byte {
+	if p.group == nil {
+		return opus
+	}
+	nonce := p.nonce
+	p.nonce++
+
+	frame := sealDAVEFrame(daveFrameKey(p.group, p.userID), opus, nonce)
+	size := len(frame)
+	for n := nonce; ; n >>= 7 {
+		if n < 0x80 {
+			frame = append(frame, byte(n))
+			break
+		}
+		frame = append(frame, byte(n)|0x80)
+	}
+	frame = append(frame, byte(len(frame)-size+8+1+2))
+	return append(frame, daveMagicMarker...)
+}
+
+// open decrypts a frame sent by the voice client.
+func (p *davePeer) open(frame []byte) ([]byte, bool) {
+	if !bytes.HasSuffix(frame, daveMagicMarker) || len(frame) < 12 {
+		return nil, false
+	}
+	size := int(frame[len(frame)-3])
+	if size < 12 || size > len(frame) {
+		return nil, false
+	}
+
+	ciphertext, tag := frame[:len(frame)-size], frame[len(frame)-size:len(frame)-size+8]
+	var nonce uint32
+	for i, b := range frame[len(frame)-size+8 : len(frame)-3] {
+		nonce |= uint32(b&0x7F) << (7 * i)
+	}
+
+	for _, key := range p.clientKeys {
+		if opus, ok := openDAVEFrame(key, ciphertext, tag, nonce); ok {
+			return opus, true
+		}
+	}
+	return nil, false
+}
+
+// daveID returns the encoding of an ID in group IDs and credentials.
+func daveID(id string) []byte {
+	n, _ := strconv.ParseUint(id, 10, 64)
+	return binary.BigEndian.AppendUint64(nil, n)
+}
+
+// daveFrameKey returns the key of the first generation of the frames of a
+// user in the current epoch of a group.
+func daveFrameKey(g *mls.Group, userID string) cipher.Block {
+	n, _ := strconv.ParseUint(userID, 10, 64)
+	secret := g.Export(daveExporterLabel, binary.LittleEndian.AppendUint64(nil, n), 16)
+	block, err := aes.NewCipher(mls.ExpandWithLabel(secret, "key", []byte{0, 0, 0, 0}, 16))
+	if err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+	return block
+}
+
+// daveFrameNonce returns the GCM nonce of a truncated frame nonce.
+func daveFrameNonce(nonce uint32) []byte {
+	n := make([]byte, 12)
+	binary.LittleEndian.PutUint32(n[8:], nonce)
+	return n
+}
+
+// sealDAVEFrame returns the ciphertext of a frame, followed by its tag
+// truncated to 8 bytes.
+func sealDAVEFrame(key cipher.Block, opus []byte, nonce uint32) []byte {
+	aead, _ := cipher.NewGCM(key)
+	sealed := aead.Seal(nil, daveFrameNonce(nonce), opus, nil)
+	return sealed[:len(opus)+8]
+}
+
+// openDAVEFrame decrypts the ciphertext of a frame with the counter mode of
+// GCM, and checks its truncated tag.
+func openDAVEFrame(key cipher.Block, ciphertext, tag []byte, nonce uint32) ([]byte, bool) {
+	opus := make([]byte, len(ciphertext))
+	cipher.NewCTR(key, append(daveFrameNonce(nonce), 0, 0, 0, 2)).XORKeyStream(opus, ciphertext)
+
+	sealed := sealDAVEFrame(key, opus, nonce)
+	return opus, subtle.ConstantTimeCompare(sealed[len(opus):], tag) == 1
+}
+
+// sendVoiceBinary sends a binary DAVE message with the next sequence number
+// of a voice session. s must be locked.
+func (s *Server) sendVoiceBinary(vs *voiceSession, op int, payload []byte) {
+	vs.seq++
+	data := binary.BigEndian.AppendUint16(nil, uint16(vs.seq))
+	data = append(append(data, byte(op)), payload...)
+	vs.history = append(vs.history, sentVoiceMessage{vs.seq, data, true})
+
+	if vs.conn != nil {
+		vs.conn.sendBinary(data)
+	}
+}
+
+// nextDAVETransition returns the ID of the next transition of a voice
+// session, which is never 0. s must be locked.
+func (s *Server) nextDAVETransition(vs *voiceSession) uint16 {
+	vs.daveTransition++
+	if vs.daveTransition == 0 {
+		vs.daveTransition++
+	}
+	return vs.daveTransition
+}
+
+// handleDAVEBinary handles a binary DAVE message sent by a voice client. s
+// must be locked.
+func (s *Server) handleDAVEBinary(vs *voiceSession, op int, payload []byte) {
+	switch op {
+	case 26:
+		kp, err := mls.UnmarshalKeyPackage(payload)
+		if err != nil {
+			return
+		}
+		vs.daveKeyPackage = kp
+		if len(vs.davePeers) > 0 {
+			s.welcomeDAVEClient(vs)
+		}
+
+	case 28:
+		commit, welcome, err := mls.ReadMessage(payload)
+		if err != nil || commit.Epoch() != vs.daveEpoch {
+			return
+		}
+		s.announceDAVECommit(vs, commit, welcome)
+	}
+}
+
+// proposeDAVEPeers sends the proposals adding peers to the group of the voice
+// client, which commits them. s must be locked.
+func (s *Server) proposeDAVEPeers(vs *voiceSession, peers ...*davePeer) {
+	var messages []byte
+	for _, p := range peers {
+		m := mls.NewExternalProposal(daveID(vs.channelID), vs.daveEpoch, 0, s.daveKey, mls.AddProposal(p.keyPackage.KeyPackage))
+		for _, member := range vs.davePeers {
+			if member.group != nil {
+				member.group.HandleProposal(m)
+			}
+		}
+		messages = append(messages, m.Marshal()...)
+	}
+	s.sendVoiceBinary(vs, 27, mls.AppendVector([]byte{0}, messages))
+}
+
+// announceDAVECommit announces the commit of the voice client, which the
+// peers process or join with its Welcome. s must be locked.
+func (s *Server) announceDAVECommit(vs *voiceSession, commit *mls.Message, welcome []byte) {
+	w, _ := mls.UnmarshalWelcome(welcome)
+	for _, p := range vs.davePeers {
+		if p.group != nil {
+			p.group.HandleCommit(commit)
+		} else if w != nil {
+			p.group, _ = mls.JoinGroup(w, p.keyPackage)
+		}
+	}
+	vs.daveEpoch = commit.Epoch() + 1
+	s.startDAVEEpoch(vs)
+
+	data := binary.BigEndian.AppendUint16(nil, s.nextDAVETransition(vs))
+	s.sendVoiceBinary(vs, 29, append(data, commit.Marshal()...))
+}
+
+// welcomeDAVEClient makes the first peer commit the addition of the voice
+// client, and of the peers which are not members yet, to its group. s must
+// be locked.
+func (s *Server) welcomeDAVEClient(vs *voiceSession) {
+	committer := vs.davePeers[0]
+	if committer.group == nil {
+		sender := &mls.ExternalSender{SignatureKey: s.daveKey.PublicKey(), Credential: mls.Credential{Identity: []byte("discordgotest")}}
+		committer.group = mls.NewGroup(daveID(vs.channelID), committer.keyPackage, []*mls.ExternalSender{sender})
+	}
+
+	adds := []*mls.KeyPackage{vs.daveKeyPackage}
+	for _, p := range vs.davePeers {
+		if p.group == nil {
+			adds = append(adds, p.keyPackage.KeyPackage)
+		}
+	}
+	for _, kp := range adds {
+		m := mls.NewExternalProposal(daveID(vs.channelID), committer.group.Epoch(), 0, s.daveKey, mls.AddProposal(kp))
+		for _, p := range vs.davePeers {
+			if p.group != nil {
+				p.group.HandleProposal(m)
+			}
+		}
+	}
+
+	commit, welcome, err := committer.group.Commit()
+	if err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+	for _, p := range vs.davePeers {
+		if p.group != nil {
+			p.group.HandleCommit(commit)
+		} else {
+			p.group, _ = mls.JoinGroup(welcome, p.keyPackage)
+		}
+	}
+	vs.daveKeyPackage = nil
+	vs.daveEpoch = committer.group.Epoch()
+	s.startDAVEEpoch(vs)
+
+	data := binary.BigEndian.AppendUint16(nil, s.nextDAVETransition(vs))
+	s.sendVoiceBinary(vs, 30, append(data, welcome.Marshal()...))
+}
+
+// startDAVEEpoch derives the keys of the peers in the new epoch of the
+// group. s must be locked.
+func (s *Server) startDAVEEpoch(vs *voiceSession) {
+	for _, p := range vs.davePeers {
+		if p.group != nil {
+			p.startEpoch(s.User.ID)
+		}
+	}
+}
+
+// AddVoicePeer adds a simulated user to the voice channel of the voice
+// client of a guild, speaking with the given SSRC. When DAVE is used, the
+// voice client is asked to add the user to its MLS group.
+func (s *Server) AddVoicePeer(guildID, userID string, ssrc uint32) {
+	s.Lock()
+	defer s.Unlock()
+
+	vs, ok := s.voiceSessions[guildID]
+	if !ok {
+		return
+	}
+	p := newDAVEPeer(userID, ssrc)
+	vs.davePeers = append(vs.davePeers, p)
+
+	s.sendVoice(vs, 11, voiceClientsConnectData{[]string{userID}})
+	s.sendVoice(vs, 5, voiceSpeakingData{userID, ssrc, true})
+	if vs.daveVersion > 0 && (vs.daveKeyPackage != nil || vs.daveEpoch > 0) {
+		s.proposeDAVEPeers(vs, p)
+	}
+}
+
+// VoicePeerSend sends an opus frame of a simulated user to the voice client
+// of a guild, encrypted with its DAVE keys once it is a member of the MLS
+// group.
+func (s *Server) VoicePeerSend(guildID, userID string, opus []byte) {
+	s.Lock()
+	defer s.Unlock()
+
+	vs, ok := s.voiceSessions[guildID]
+	if !ok || vs.udpAddr == nil {
+		return
+	}
+	i := slices.IndexFunc(vs.davePeers, func(p *davePeer) bool { return p.userID == userID })
+	if i < 0 {
+		return
+	}
+	p := vs.davePeers[i]
+
+	if vs.daveVersion > 0 {
+		opus = p.seal(opus)
+	}
+	header := make([]byte, 12)
+	header[0], header[1] = 0x80, 0x78
+	binary.BigEndian.PutUint16(header[2:], p.seq)
+	binary.BigEndian.PutUint32(header[4:], uint32(p.seq)*960)
+	binary.BigEndian.PutUint32(header[8:], p.ssrc)
+	p.seq++
+	vs.peerNonce++
+
+	s.voiceUDP.WriteToUDP(sealVoicePacket(vs.mode, &vs.key, header, opus, vs.peerNonce), vs.udpAddr)
+}
+
+// VoicePeerOpus returns the opus frames of the voice client of a guild
+// decrypted by a simulated user, in the order they were received.
+func (s *Server) VoicePeerOpus(guildID, userID string) [][]byte {
+	s.Lock()
+	defer s.Unlock()
+
+	if vs, ok := s.voiceSessions[guildID]; ok {
+		for _, p := range vs.davePeers {
+			if p.userID == userID {
+				return slices.Clone(p.opus)
+			}
+		}
+	}
+	return nil
+}
+
+// ResetDAVE sends an Op 24 DAVE Prepare Epoch for a new MLS group to the
+// voice client of a guild, which the first simulated user creates and adds
+// the client to, once it sent a new key package.
+func (s *Server) ResetDAVE(guildID string) {
+	s.Lock()
+	defer s.Unlock()
+
+	vs, ok := s.voiceSessions[guildID]
+	if !ok {
+		return
+	}
+	for _, p := range vs.davePeers {
+		p.reset()
+	}
+	vs.daveKeyPackage = nil
+	vs.daveEpoch = 0
+	s.sendVoice(vs, 24, voiceDAVEEpochData{1, vs.daveVersion})
+}
+
+// DowngradeDAVE sends an Op 21 DAVE Prepare Transition to protocol version
+// 0 to the voice client of a guild, which then only encrypts its frames with
+// the transport encryption.
+func (s *Server) DowngradeDAVE(guildID string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if vs, ok := s.voiceSessions[guildID]; ok && vs.daveVersion > 0 {
+		vs.daveVersion = 0
+		s.sendVoice(vs, 21, voiceDAVETransitionData{s.nextDAVETransition(vs), 0})
+	}
+}
@@ -0,0 +1,510 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the fake gateway websocket of the Server.
+
+package discordgotest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/klauspost/compress/zstd"
+	"github.com/lb-selfbot/discordgo"
+)
+
+// Gateway close codes sent by the Server.
+const (
+	CloseAuthenticationFailed = discordgo.GatewayCloseAuthenticationFailed
+	CloseAlreadyAuthenticated = discordgo.GatewayCloseAlreadyAuthenticated
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// A gatewaySession is a gateway session created by an Identify, which can be
+// resumed by later connections.
+type gatewaySession struct {
+	id      string
+	seq     int64
+	history []sentEvent
+	conn    *conn
+}
+
+// A sentEvent is an encoded dispatch payload kept to be replayed on resume.
+type sentEvent struct {
+	seq  int64
+	data []byte
+}
+
+// A frame is a queued websocket message.
+type frame struct {
+	messageType int
+	data        []byte
+}
+
+// A conn is a client connection to the gateway. Writes are queued and sent
+// in order by a dedicated goroutine, so the Server never blocks on a client.
+type conn struct {
+	ws *websocket.Conn
+
+	// session is guarded by the Server lock.
+	session *gatewaySession
+
+	// resumeURL is set for connections made to the resume URL, which is the
+	// only one sessions can be resumed on.
+	resumeURL bool
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	queue    []frame
+	compress bool
+	closing  bool
+
+	// etf is set for clients which asked for the ETF encoding.
+	etf bool
+
+	// stream compresses the whole connection, when transport compression
+	// was requested.
+	stream    streamWriter
+	streamBuf bytes.Buffer
+}
+
+// A streamWriter compresses a connection, and flushes after every payload.
+type streamWriter interface {
+	io.Writer
+	Flush() error
+}
+
+func newConn(ws *websocket.Conn, encoding, compression string) *conn {
+	c := &conn{ws: ws, etf: discordgo.GatewayEncoding(encoding) == discordgo.GatewayEncodingETF}
+	switch discordgo.GatewayCompression(compression) {
+	case discordgo.GatewayCompressionZlibStream:
+		c.stream = zlib.NewWriter(&c.streamBuf)
+	case discordgo.GatewayCompressionZstdStream:
+		c.stream, _ = zstd.NewWriter(&c.streamBuf, zstd.WithEncoderConcurrency(1))
+	}
+	c.cond = sync.NewCond(&c.mu)
+	go c.writeLoop()
+	return c
+}
+
+func (c *conn) writeLoop() {
+	defer c.ws.Close()
+
+	for {
+		c.mu.Lock()
+		for len(c.queue) == 0 && !c.closing {
+			c.cond.Wait()
+		}
+		if len(c.queue) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		f := c.queue[0]
+		c.queue = c.queue[1:]
+		c.mu.Unlock()
+
+		if err := c.ws.WriteMessage(f.messageType, f.data); err != nil || f.messageType == websocket.CloseMessage {
+			return
+		}
+	}
+}
+
+// send queues an encoded payload, compressing it if the client asked for it.
+func (c *conn) send(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closing {
+		return
+	}
+
+	f := frame{websocket.TextMessage, data}
+	if c.etf {
+		data = etfPayload(data)
+		f = frame{websocket.BinaryMessage, data}
+	}
+	if c.stream != nil {
+		c.stream.Write(data)
+		c.stream.Flush()
+		f = frame{websocket.BinaryMessage, bytes.Clone(c.streamBuf.Bytes())}
+		c.streamBuf.Reset()
+	} else if c.compress {
+		var b bytes.Buffer
+		z := zlib.NewWriter(&b)
+		z.Write(data)
+		z.Close()
+		f = frame{websocket.BinaryMessage, b.Bytes()}
+	}
+	c.queue = append(c.queue, f)
+	c.cond.Signal()
+}
+
+// sendBinary queues a binary message, which is never compressed.
+func (c *conn) sendBinary(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closing {
+		c.queue = append(c.queue, frame{websocket.BinaryMessage, data})
+		c.cond.Signal()
+	}
+}
+
+// sendOp queues a payload with the given opcode and data.
+func (c *conn) sendOp(op int, data any) {
+	c.send(mustMarshal(&discordgo.Event{Operation: op, RawData: mustMarshal(data)}))
+}
+
+// closeWithCode queues a close frame and closes the connection once it is
+// sent.
+func (c *conn) closeWithCode(code int, text string) {
+	c.mu.Lock()
+	if !c.closing {
+		c.queue = append(c.queue, frame{websocket.CloseMessage, websocket.FormatCloseMessage(code, text)})
+	}
+	c.mu.Unlock()
+
+	c.close()
+}
+
+// close closes the connection once all queued messages are sent.
+func (c *conn) close() {
+	c.mu.Lock()
+	c.closing = true
+	c.cond.Signal()
+	c.mu.Unlock()
+}
+
+type helloData struct {
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+}
+
+type identifyData struct {
+	Token    string `json:"token"`
+	Compress bool   `json:"compress"`
+}
+
+type resumeData struct {
+	Token     string `json:"token"`
+	SessionID string `json:"session_id"`
+	Sequence  int64  `json:"seq"`
+}
+
+type readyData struct {
+	Version           int                              `json:"v"`
+	SessionID         string                           `json:"session_id"`
+	ResumeGatewayURL  string                           `json:"resume_gateway_url"`
+	User              *discordgo.User                  `json:"user"`
+	Guilds            []*discordgo.Guild               `json:"guilds"`
+	PrivateChannels   []*discordgo.Channel             `json:"private_channels"`
+	Relationships     []*discordgo.Relationship        `json:"relationships"`
+	ReadState         *discordgo.ReadStateData         `json:"read_state"`
+	UserGuildSettings *discordgo.UserGuildSettingsData `json:"user_guild_settings"`
+	UserSettingsProto string                           `json:"user_settings_proto"`
+}
+
+// handleGateway upgrades the request to a websocket and serves the gateway
+// protocol until the connection is closed.
+func (s *Server) handleGateway(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := newConn(ws, r.URL.Query().Get("encoding"), r.URL.Query().Get("compress"))
+	c.resumeURL = r.URL.Path == resumePath
+
+	s.Lock()
+	s.conns[c] = true
+	s.Unlock()
+
+	defer func() {
+		s.Lock()
+		delete(s.conns, c)
+		if c.session != nil && c.session.conn == c {
+			c.session.conn = nil
+		}
+		s.Unlock()
+
+		c.close()
+	}()
+
+	c.sendOp(10, helloData{s.HeartbeatInterval.Milliseconds()})
+
+	for {
+		_, message, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var e *discordgo.Event
+		if c.etf {
+			err = discordgo.UnmarshalETF(message, &e)
+		} else {
+			err = discordgo.Unmarshal(message, &e)
+		}
+		if err != nil {
+			c.closeWithCode(4002, "Error while decoding payload.")
+			return
+		}
+
+		s.Lock()
+		s.received = append(s.received, e)
+		ok := s.handlePayload(c, e)
+		s.Unlock()
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// handlePayload handles a payload sent by a client. It returns false when the
+// connection was closed. s must be locked.
+func (s *Server) handlePayload(c *conn, e *discordgo.Event) bool {
+	switch e.Operation {
+	case 1:
+		c.sendOp(11, nil)
+
+	case 2:
+		var d identifyData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		if s.Token != "" && d.Token != s.Token {
+			c.closeWithCode(CloseAuthenticationFailed, "Authentication failed.")
+			return false
+		}
+		if c.session != nil {
+			c.closeWithCode(CloseAlreadyAuthenticated, "Already authenticated.")
+			return false
+		}
+
+		c.mu.Lock()
+		c.compress = d.Compress
+		c.mu.Unlock()
+
+		session := &gatewaySession{id: s.nextID(), conn: c}
+		s.sessions[session.id] = session
+		c.session = session
+
+		s.dispatchTo(session, "READY", mustMarshal(s.ready(session)))
+
+	case 6:
+		var d resumeData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		if s.Token != "" && d.Token != s.Token {
+			c.closeWithCode(CloseAuthenticationFailed, "Authentication failed.")
+			return false
+		}
+
+		session, ok := s.sessions[d.SessionID]
+		if !ok || !c.resumeURL {
+			c.sendOp(9, false)
+			return true
+		}
+
+		session.conn = c
+		c.session = session
+
+		for _, sent := range session.history {
+			if sent.seq > d.Sequence {
+				c.send(sent.data)
+			}
+		}
+		s.dispatchTo(session, "RESUMED", mustMarshal(struct{}{}))
+
+	case 4:
+		var d voiceStateData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		s.voiceStateUpdate(c, &d)
+	}
+
+	return true
+}
+
+// ready returns the READY payload for a session. s must be locked.
+func (s *Server) ready(session *gatewaySession) *readyData {
+	r := &readyData{
+		Version:           9,
+		SessionID:         session.id,
+		ResumeGatewayURL:  s.GatewayURL() + resumePath,
+		User:              s.User,
+		Guilds:            []*discordgo.Guild{},
+		PrivateChannels:   s.guildChannels(""),
+		Relationships:     []*discordgo.Relationship{},
+		ReadState:         &discordgo.ReadStateData{Entries: []*discordgo.ReadState{}},
+		UserGuildSettings: &discordgo.UserGuildSettingsData{},
+	}
+
+	for _, g := range s.guilds {
+		r.Guilds = append(r.Guilds, s.guildWithChannels(g))
+	}
+
+	return r
+}
+
+// Dispatch sends a dispatch event with the given type and data to every
+// session. Events sent while a session is disconnected are replayed when it
+// resumes.
+func (s *Server) Dispatch(eventType string, data any) error {
+	raw, err := discordgo.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.dispatchRaw(eventType, raw)
+	return nil
+}
+
+// dispatch sends a dispatch event to every session. s must be locked.
+func (s *Server) dispatch(eventType string, data any) {
+	s.dispatchRaw(eventType, mustMarshal(data))
+}
+
+// dispatchRaw sends an encoded dispatch event to every session. s must be
+// locked.
+func (s *Server) dispatchRaw(eventType string, raw []byte) {
+	for _, session := range s.sessions {
+		s.dispatchTo(session, eventType, raw)
+	}
+}
+
+// dispatchTo sends an encoded dispatch event to a single session. s must be
+// locked.
+func (s *Server) dispatchTo(session *gatewaySession, eventType string, raw []byte) {
+	session.seq++
+
+	data := mustMarshal(&discordgo.Event{
+		Operation: 0,
+		Sequence:  session.seq,
+		Type:      eventType,
+		RawData:   raw,
+	})
+	session.history = append(session.history, sentEvent{session.seq, data})
+
+	if session.conn != nil {
+		session.conn.send(data)
+	}
+}
+
+// Reconnect sends an Op 7 Reconnect to every connected client.
+func (s *Server) Reconnect() {
+	s.Lock()
+	defer s.Unlock()
+
+	for c := range s.conns {
+		c.sendOp(7, nil)
+	}
+}
+
+// RequestHeartbeat sends an Op 1 Heartbeat to every connected client.
+func (s *Server) RequestHeartbeat() {
+	s.Lock()
+	defer s.Unlock()
+
+	for c := range s.conns {
+		c.sendOp(1, nil)
+	}
+}
+
+// InvalidateSession sends an Op 9 Invalid Session to every connected client.
+// When resumable is false the sessions are discarded, so clients must
+// identify again.
+func (s *Server) InvalidateSession(resumable bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	for c := range s.conns {
+		c.sendOp(9, resumable)
+
+		if !resumable && c.session != nil {
+			delete(s.sessions, c.session.id)
+			c.session = nil
+		}
+	}
+}
+
+// Disconnect closes every client connection with the given close code. The
+// sessions are kept, so clients may resume them.
+func (s *Server) Disconnect(code int) {
+	s.Lock()
+	defer s.Unlock()
+
+	for c := range s.conns {
+		c.closeWithCode(code, "")
+	}
+}
+
+// Connections returns the number of open gateway connections.
+func (s *Server) Connections() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return len(s.conns)
+}
+
+// etfPayload encodes a JSON payload as ETF, with snowflakes as integers, as
+// Discord sends it.
+func etfPayload(data []byte) []byte {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+
+	b, err := discordgo.MarshalETF(snowflakesToInts(v))
+	if err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+	return b
+}
+
+// snowflakesToInts replaces the snowflakes in a decoded JSON payload with
+// integers.
+func snowflakesToInts(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = snowflakesToInts(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = snowflakesToInts(e)
+		}
+	case string:
+		if len(v) >= 17 {
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return v
+}
+
+// mustMarshal encodes v as JSON, and panics if it can not be encoded.
+func mustMarshal(v any) []byte {
+	b, err := discordgo.Marshal(v)
+	if err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+	return b
+}
@@ -0,0 +1,551 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the in-memory REST API of the Server, and the HTTP
+// client used to route discordgo requests to it.
+
+package discordgotest
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+	tls_client "github.com/bogdanfinn/tls-client"
+	"github.com/lb-selfbot/discordgo"
+)
+
+// client is a tls_client.HttpClient which sends requests for
+// discordgo.EndpointDiscord to the Server instead.
+type client struct {
+	tls_client.HttpClient
+	base *url.URL
+}
+
+// Client returns an HTTP client, suitable for Session.Client, which routes
+// every request made to discordgo.EndpointDiscord to the Server. Requests to
+// other hosts are sent as usual.
+func (s *Server) Client() (tls_client.HttpClient, error) {
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := tls_client.NewHttpClient(tls_client.NewNoopLogger())
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{c, base}, nil
+}
+
+// Do rewrites requests for discordgo.EndpointDiscord and sends them.
+func (c *client) Do(req *fhttp.Request) (*fhttp.Response, error) {
+	if strings.HasPrefix(req.URL.String(), discordgo.EndpointDiscord) {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = c.base.Scheme
+		req.URL.Host = c.base.Host
+		req.Host = ""
+	}
+	return c.HttpClient.Do(req)
+}
+
+// Get issues a GET to the specified URL.
+func (c *client) Get(url string) (*fhttp.Response, error) {
+	req, err := fhttp.NewRequest(fhttp.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Head issues a HEAD to the specified URL.
+func (c *client) Head(url string) (*fhttp.Response, error) {
+	req, err := fhttp.NewRequest(fhttp.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post issues a POST to the specified URL.
+func (c *client) Post(url, contentType string, body io.Reader) (*fhttp.Response, error) {
+	req, err := fhttp.NewRequest(fhttp.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// registerRoutes registers the REST API handlers on mux.
+func (s *Server) registerRoutes(mux *http.ServeMux) {
+	api := "/api/v" + discordgo.APIVersion + "/"
+
+	routes := map[string]http.HandlerFunc{
+		"GET gateway":                                      s.getGateway,
+		"GET gateway/bot":                                  s.getGatewayBot,
+		"GET users/@me":                                    s.getCurrentUser,
+		"GET users/@me/guilds":                             s.getCurrentUserGuilds,
+		"GET guilds/{guildID}":                             s.getGuild,
+		"GET guilds/{guildID}/channels":                    s.getGuildChannels,
+		"POST guilds/{guildID}/channels":                   s.createGuildChannel,
+		"GET channels/{channelID}":                         s.getChannel,
+		"PATCH channels/{channelID}":                       s.editChannel,
+		"DELETE channels/{channelID}":                      s.deleteChannel,
+		"POST channels/{channelID}/typing":                 s.triggerTyping,
+		"GET channels/{channelID}/messages":                s.getMessages,
+		"POST channels/{channelID}/messages":               s.createMessage,
+		"GET channels/{channelID}/messages/{messageID}":    s.getMessage,
+		"PATCH channels/{channelID}/messages/{messageID}":  s.editMessage,
+		"DELETE channels/{channelID}/messages/{messageID}": s.deleteMessage,
+	}
+
+	for pattern, handler := range routes {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.Handle(method+" "+api+path, s.authorize(handler))
+	}
+
+	mux.HandleFunc(api, func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, 0, "404: Not Found")
+	})
+}
+
+// authorize rejects requests without the expected token, when Server.Token
+// is set.
+func (s *Server) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Token != "" && r.Header.Get("Authorization") != s.Token {
+			writeError(w, http.StatusUnauthorized, 0, "401: Unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(mustMarshal(v))
+}
+
+func writeError(w http.ResponseWriter, status int, code discordgo.ErrorCode, message string) {
+	writeJSON(w, status, &discordgo.APIErrorMessage{Code: code, Message: message})
+}
+
+func (s *Server) getGateway(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"url": s.GatewayURL()})
+}
+
+func (s *Server) getGatewayBot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, &discordgo.GatewayBotResponse{URL: s.GatewayURL(), Shards: 1})
+}
+
+func (s *Server) getCurrentUser(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	writeJSON(w, http.StatusOK, s.User)
+}
+
+func (s *Server) getCurrentUserGuilds(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	guilds := []*discordgo.UserGuild{}
+	for _, g := range s.guilds {
+		guilds = append(guilds, &discordgo.UserGuild{
+			ID:    g.ID,
+			Name:  g.Name,
+			Icon:  g.Icon,
+			Owner: g.OwnerID == s.User.ID,
+		})
+	}
+	writeJSON(w, http.StatusOK, guilds)
+}
+
+func (s *Server) getGuild(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	g, ok := s.guilds[r.PathValue("guildID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.guildWithChannels(g))
+}
+
+func (s *Server) getGuildChannels(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	guildID := r.PathValue("guildID")
+	if _, ok := s.guilds[guildID]; !ok {
+		writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.guildChannels(guildID))
+}
+
+func (s *Server) createGuildChannel(w http.ResponseWriter, r *http.Request) {
+	var c discordgo.Channel
+	if !decodeBody(w, r, &c) {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	guildID := r.PathValue("guildID")
+	if _, ok := s.guilds[guildID]; !ok {
+		writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+		return
+	}
+
+	c.ID = ""
+	c.GuildID = guildID
+	s.storeChannel(&c)
+	s.dispatch("CHANNEL_CREATE", &c)
+
+	writeJSON(w, http.StatusOK, &c)
+}
+
+func (s *Server) getChannel(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	c, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) editChannel(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	c, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+
+	// Channels are replaced rather than modified, so the values returned by
+	// Server.Channel are never changed.
+	edited := *c
+	if err = discordgo.Unmarshal(body, &edited); err != nil {
+		writeError(w, http.StatusBadRequest, discordgo.ErrCodeInvalidFormBody, "Invalid Form Body")
+		return
+	}
+	edited.ID = c.ID
+	edited.GuildID = c.GuildID
+	s.channels[c.ID] = &edited
+	s.dispatch("CHANNEL_UPDATE", &edited)
+
+	writeJSON(w, http.StatusOK, &edited)
+}
+
+func (s *Server) deleteChannel(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	c, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.channels, c.ID)
+	delete(s.messages, c.ID)
+	s.dispatch("CHANNEL_DELETE", c)
+
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) triggerTyping(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.channel(w, r); !ok {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getMessages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit := 50
+	if q.Has("limit") {
+		var err error
+		limit, err = strconv.Atoi(q.Get("limit"))
+		if err != nil || limit < 1 || limit > 100 {
+			writeError(w, http.StatusBadRequest, discordgo.ErrCodeInvalidFormBody, "Invalid Form Body")
+			return
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	c, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+	messages := s.messages[c.ID]
+
+	// Messages are stored oldest first, and returned newest first.
+	var page []*discordgo.Message
+	switch {
+	case q.Has("after"):
+		after := q.Get("after")
+		for _, m := range messages {
+			if snowflakeLess(after, m.ID) && len(page) < limit {
+				page = append([]*discordgo.Message{m}, page...)
+			}
+		}
+	case q.Has("around"):
+		around := q.Get("around")
+		i := 0
+		for i < len(messages) && snowflakeLess(messages[i].ID, around) {
+			i++
+		}
+		start := max(i-limit/2, 0)
+		end := min(start+limit, len(messages))
+		for _, m := range messages[start:end] {
+			page = append([]*discordgo.Message{m}, page...)
+		}
+	default:
+		before := q.Get("before")
+		for i := len(messages) - 1; i >= 0 && len(page) < limit; i-- {
+			if before == "" || snowflakeLess(messages[i].ID, before) {
+				page = append(page, messages[i])
+			}
+		}
+	}
+
+	if page == nil {
+		page = []*discordgo.Message{}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// messageCreateData is the body of a message create request. It mirrors
+// discordgo.MessageSend, which can not be decoded because of its components.
+type messageCreateData struct {
+	Content   string                      `json:"content"`
+	Embeds    []*discordgo.MessageEmbed   `json:"embeds"`
+	TTS       bool                        `json:"tts"`
+	Reference *discordgo.MessageReference `json:"message_reference"`
+	Flags     discordgo.MessageFlags      `json:"flags"`
+
+	Nonce        string `json:"nonce"`
+	EnforceNonce bool   `json:"enforce_nonce"`
+}
+
+func (s *Server) createMessage(w http.ResponseWriter, r *http.Request) {
+	var data messageCreateData
+	var attachments []*discordgo.MessageAttachment
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			writeError(w, http.StatusBadRequest, discordgo.ErrCodeInvalidFormBody, "Invalid Form Body")
+			return
+		}
+		if payload := r.FormValue("payload_json"); payload != "" {
+			if err := discordgo.Unmarshal([]byte(payload), &data); err != nil {
+				writeError(w, http.StatusBadRequest, discordgo.ErrCodeInvalidFormBody, "Invalid Form Body")
+				return
+			}
+		}
+		for _, files := range r.MultipartForm.File {
+			for _, f := range files {
+				attachments = append(attachments, &discordgo.MessageAttachment{
+					Filename:    f.Filename,
+					ContentType: f.Header.Get("Content-Type"),
+					Size:        int(f.Size),
+				})
+			}
+		}
+	} else if !decodeBody(w, r, &data) {
+		return
+	}
+
+	if data.Content == "" && len(data.Embeds) == 0 && len(attachments) == 0 {
+		writeError(w, http.StatusBadRequest, discordgo.ErrCodeCannotSendEmptyMessage, "Cannot send an empty message")
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	c, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+
+	// A message whose nonce is enforced is only created once.
+	if data.EnforceNonce && data.Nonce != "" {
+		for _, m := range s.messages[c.ID] {
+			if m.Nonce == data.Nonce && m.Author != nil && m.Author.ID == s.User.ID {
+				writeJSON(w, http.StatusOK, m)
+				return
+			}
+		}
+	}
+
+	for _, a := range attachments {
+		a.ID = s.nextID()
+		a.URL = s.URL + "/attachments/" + c.ID + "/" + a.ID + "/" + a.Filename
+		a.ProxyURL = a.URL
+	}
+
+	m := &discordgo.Message{
+		ChannelID:        c.ID,
+		Content:          data.Content,
+		Embeds:           data.Embeds,
+		TTS:              data.TTS,
+		Author:           s.User,
+		Attachments:      attachments,
+		MessageReference: data.Reference,
+		Flags:            data.Flags,
+		Nonce:            data.Nonce,
+	}
+	if m.MessageReference != nil {
+		m.Type = discordgo.MessageTypeReply
+	}
+	s.storeMessage(m)
+	s.dispatch("MESSAGE_CREATE", m)
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) getMessage(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	_, m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// messageEditData is the body of a message edit request.
+type messageEditData struct {
+	Content *string                    `json:"content"`
+	Embeds  *[]*discordgo.MessageEmbed `json:"embeds"`
+	Flags   *discordgo.MessageFlags    `json:"flags"`
+}
+
+func (s *Server) editMessage(w http.ResponseWriter, r *http.Request) {
+	var data messageEditData
+	if !decodeBody(w, r, &data) {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	i, m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	if m.Author == nil || m.Author.ID != s.User.ID {
+		writeError(w, http.StatusForbidden, discordgo.ErrCodeCannotEditFromAnotherUser, "Cannot edit a message authored by another user")
+		return
+	}
+
+	// Messages are replaced rather than modified, so the values returned by
+	// Server.Messages are never changed.
+	edited := *m
+	if data.Content != nil {
+		edited.Content = *data.Content
+	}
+	if data.Embeds != nil {
+		edited.Embeds = *data.Embeds
+	}
+	if data.Flags != nil {
+		edited.Flags = *data.Flags
+	}
+	now := time.Now().UTC()
+	edited.EditedTimestamp = &now
+
+	s.messages[m.ChannelID][i] = &edited
+	s.dispatch("MESSAGE_UPDATE", &edited)
+
+	writeJSON(w, http.StatusOK, &edited)
+}
+
+func (s *Server) deleteMessage(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	i, m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+
+	messages := s.messages[m.ChannelID]
+	s.messages[m.ChannelID] = append(messages[:i:i], messages[i+1:]...)
+	s.dispatch("MESSAGE_DELETE", &discordgo.Message{ID: m.ID, ChannelID: m.ChannelID, GuildID: m.GuildID})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// channel returns the channel of the request, or writes an Unknown Channel
+// error. s must be locked.
+func (s *Server) channel(w http.ResponseWriter, r *http.Request) (*discordgo.Channel, bool) {
+	c, ok := s.channels[r.PathValue("channelID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownChannel, "Unknown Channel")
+	}
+	return c, ok
+}
+
+// message returns the message of the request and its index within the
+// channel, or writes an Unknown Channel or Unknown Message error. s must be
+// locked.
+func (s *Server) message(w http.ResponseWriter, r *http.Request) (int, *discordgo.Message, bool) {
+	c, ok := s.channel(w, r)
+	if !ok {
+		return 0, nil, false
+	}
+
+	messageID := r.PathValue("messageID")
+	for i, m := range s.messages[c.ID] {
+		if m.ID == messageID {
+			return i, m, true
+		}
+	}
+
+	writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownMessage, "Unknown Message")
+	return 0, nil, false
+}
+
+// decodeBody decodes the JSON body of r into v, or writes an Invalid Form Body
+// error.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = discordgo.Unmarshal(body, v)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, discordgo.ErrCodeInvalidFormBody, "Invalid Form Body")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,333 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package discordgotest provides an in-process fake Discord server for
+// integration tests. It implements the gateway websocket (Hello, Identify,
+// Ready, Resume, Reconnect and Invalid Session flows), with both the JSON and
+// ETF encodings, an in-memory subset of the REST API for guilds, channels
+// and messages, and a voice server which voice connections join.
+//
+//	srv := discordgotest.NewServer()
+//	defer srv.Close()
+//
+//	s, err := srv.NewSession("token")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) { ... })
+//	if err = s.Open(); err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	srv.AddMessage(&discordgo.Message{ChannelID: "1", Content: "!ping"})
+package discordgotest
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lb-selfbot/discordgo"
+	"github.com/lb-selfbot/discordgo/internal/mls"
+)
+
+// resumePath is the path of the resume gateway URL sent in READY events.
+const resumePath = "/resume"
+
+// discordEpoch is the Discord epoch in milliseconds, used to generate IDs.
+const discordEpoch = 1420070400000
+
+// DefaultHeartbeatInterval is the heartbeat interval sent in the Hello
+// payload when Server.HeartbeatInterval is not set.
+const DefaultHeartbeatInterval = 41250 * time.Millisecond
+
+// A Server is a fake Discord server listening on a local address.
+type Server struct {
+	// URL is the base URL of the server, e.g. http://127.0.0.1:1234
+	URL string
+
+	// User is the user that sessions identify as.
+	User *discordgo.User
+
+	// Token, when not empty, must be sent by clients in the Authorization
+	// header and in Identify/Resume payloads.
+	Token string
+
+	// HeartbeatInterval is the interval sent to clients in the Hello payload.
+	HeartbeatInterval time.Duration
+
+	// VoiceHeartbeatInterval is the interval sent to voice clients in the
+	// voice Hello payload.
+	VoiceHeartbeatInterval time.Duration
+
+	// VoiceModes are the voice encryption modes sent to voice clients in the
+	// voice Ready payload.
+	VoiceModes []string
+
+	// DAVEProtocolVersion is the highest DAVE protocol version of the voice
+	// server. When it is 0, voice frames are only encrypted by the
+	// transport.
+	DAVEProtocolVersion int
+
+	srv      *httptest.Server
+	voiceSrv *httptest.Server
+	voiceUDP *net.UDPConn
+
+	// daveKey signs the proposals of the voice server, the external
+	// sender of the MLS groups of voice channels.
+	daveKey *mls.SignatureKey
+
+	sync.Mutex
+	lastID   int64
+	guilds   map[string]*discordgo.Guild
+	channels map[string]*discordgo.Channel
+	messages map[string][]*discordgo.Message
+	sessions map[string]*gatewaySession
+	conns    map[*conn]bool
+	received []*discordgo.Event
+
+	lastSSRC      uint32
+	voiceSessions map[string]*voiceSession
+	voiceConns    map[*voiceConn]bool
+	voiceReceived []*discordgo.Event
+}
+
+// NewServer starts and returns a new Server. The caller should call Close
+// when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		HeartbeatInterval:      DefaultHeartbeatInterval,
+		VoiceHeartbeatInterval: DefaultVoiceHeartbeatInterval,
+		VoiceModes:             DefaultVoiceModes,
+		guilds:                 make(map[string]*discordgo.Guild),
+		channels:               make(map[string]*discordgo.Channel),
+		messages:               make(map[string][]*discordgo.Message),
+		sessions:               make(map[string]*gatewaySession),
+		conns:                  make(map[*conn]bool),
+		voiceSessions:          make(map[string]*voiceSession),
+		voiceConns:             make(map[*voiceConn]bool),
+	}
+	s.User = &discordgo.User{
+		ID:            s.nextID(),
+		Username:      "discordgotest",
+		Discriminator: "0",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleGateway)
+	mux.HandleFunc("GET "+resumePath, s.handleGateway)
+	s.registerRoutes(mux)
+
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+	s.startVoice()
+
+	return s
+}
+
+// Close closes all gateway connections and shuts down the server.
+func (s *Server) Close() {
+	s.Lock()
+	for c := range s.conns {
+		c.close()
+	}
+	s.Unlock()
+
+	s.srv.Close()
+	s.closeVoice()
+}
+
+// GatewayURL returns the websocket URL of the gateway.
+func (s *Server) GatewayURL() string {
+	return "ws" + s.URL[len("http"):]
+}
+
+// NewSession returns a new discordgo Session whose REST requests, gateway
+// and voice connections are served by s.
+func (s *Server) NewSession(token string) (*discordgo.Session, error) {
+	session, err := discordgo.New(token)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Client, err = s.Client()
+	if err != nil {
+		return nil, err
+	}
+	session.Dialer = s.voiceDialer()
+
+	return session, nil
+}
+
+// nextID returns a new unique snowflake. s must be locked, unless s is not yet
+// shared.
+func (s *Server) nextID() string {
+	id := (time.Now().UnixMilli() - discordEpoch) << 22
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	s.lastID = id
+	return strconv.FormatInt(id, 10)
+}
+
+// Received returns all gateway payloads received from clients with the given
+// opcode. If op is negative, all payloads are returned.
+func (s *Server) Received(op int) []*discordgo.Event {
+	s.Lock()
+	defer s.Unlock()
+
+	var events []*discordgo.Event
+	for _, e := range s.received {
+		if op < 0 || e.Operation == op {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// Guild returns the guild with the given ID, including its channels.
+func (s *Server) Guild(guildID string) (*discordgo.Guild, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, false
+	}
+	return s.guildWithChannels(g), true
+}
+
+// Channel returns the channel with the given ID.
+func (s *Server) Channel(channelID string) (*discordgo.Channel, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	c, ok := s.channels[channelID]
+	return c, ok
+}
+
+// Messages returns the messages of a channel, oldest first.
+func (s *Server) Messages(channelID string) []*discordgo.Message {
+	s.Lock()
+	defer s.Unlock()
+
+	return append([]*discordgo.Message(nil), s.messages[channelID]...)
+}
+
+// AddGuild adds a guild, and the channels within it, and dispatches a
+// GUILD_CREATE event. The ID of the guild and its channels are generated when
+// they are empty.
+func (s *Server) AddGuild(g *discordgo.Guild) *discordgo.Guild {
+	s.Lock()
+	defer s.Unlock()
+
+	guild := *g
+	if guild.ID == "" {
+		guild.ID = s.nextID()
+	}
+	guild.Channels = nil
+	s.guilds[guild.ID] = &guild
+
+	for _, c := range g.Channels {
+		channel := *c
+		channel.GuildID = guild.ID
+		s.storeChannel(&channel)
+	}
+
+	created := s.guildWithChannels(&guild)
+	s.dispatch("GUILD_CREATE", created)
+	return created
+}
+
+// AddChannel adds a channel and dispatches a CHANNEL_CREATE event. The ID of
+// the channel is generated when it is empty.
+func (s *Server) AddChannel(c *discordgo.Channel) *discordgo.Channel {
+	s.Lock()
+	defer s.Unlock()
+
+	channel := *c
+	s.storeChannel(&channel)
+	s.dispatch("CHANNEL_CREATE", &channel)
+	return &channel
+}
+
+// storeChannel stores c, generating its ID when empty. s must be locked.
+func (s *Server) storeChannel(c *discordgo.Channel) {
+	if c.ID == "" {
+		c.ID = s.nextID()
+	}
+	s.channels[c.ID] = c
+}
+
+// AddMessage adds a message, as if it was sent by another user, and
+// dispatches a MESSAGE_CREATE event. The ID, timestamp and guild ID are
+// filled in when empty, and a default author is used when none is set.
+func (s *Server) AddMessage(m *discordgo.Message) *discordgo.Message {
+	s.Lock()
+	defer s.Unlock()
+
+	message := *m
+	if message.Author == nil {
+		message.Author = &discordgo.User{ID: s.nextID(), Username: "user", Discriminator: "0"}
+	}
+	s.storeMessage(&message)
+	s.dispatch("MESSAGE_CREATE", &message)
+	return &message
+}
+
+// storeMessage stores m, filling in its ID, timestamp and guild ID when they
+// are empty. s must be locked.
+func (s *Server) storeMessage(m *discordgo.Message) {
+	if m.ID == "" {
+		m.ID = s.nextID()
+	}
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now().UTC()
+	}
+	if c, ok := s.channels[m.ChannelID]; ok && m.GuildID == "" {
+		m.GuildID = c.GuildID
+	}
+	s.messages[m.ChannelID] = append(s.messages[m.ChannelID], m)
+}
+
+// guildWithChannels returns a copy of g with its channels populated, ordered
+// by position. s must be locked.
+func (s *Server) guildWithChannels(g *discordgo.Guild) *discordgo.Guild {
+	guild := *g
+	guild.Channels = s.guildChannels(g.ID)
+	return &guild
+}
+
+// guildChannels returns the channels of a guild ordered by position. s must
+// be locked.
+func (s *Server) guildChannels(guildID string) []*discordgo.Channel {
+	channels := []*discordgo.Channel{}
+	for _, c := range s.channels {
+		if c.GuildID == guildID {
+			channels = append(channels, c)
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		if channels[i].Position != channels[j].Position {
+			return channels[i].Position < channels[j].Position
+		}
+		return snowflakeLess(channels[i].ID, channels[j].ID)
+	})
+	return channels
+}
+
+// snowflakeLess reports whether the snowflake a is lower than b.
+func snowflakeLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
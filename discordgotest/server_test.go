@@ -0,0 +1,750 @@
+package discordgotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lb-selfbot/discordgo"
+)
+
+// open returns an open session connected to srv, which is closed when the
+// test finishes.
+func open(t *testing.T, srv *Server) *discordgo.Session {
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+// receive waits for a value on c.
+func receive[T any](t *testing.T, c <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-c:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	panic("unreachable")
+}
+
+func TestOpenReady(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Token = "token"
+
+	guild := srv.AddGuild(&discordgo.Guild{
+		Name:     "test",
+		Channels: []*discordgo.Channel{{Name: "general", Type: discordgo.ChannelTypeGuildText}},
+	})
+
+	ready := make(chan *discordgo.Ready, 1)
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) { ready <- r })
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer s.Close()
+
+	r := receive(t, ready)
+	if r.User.ID != srv.User.ID {
+		t.Errorf("expected user %s, got %s", srv.User.ID, r.User.ID)
+	}
+
+	c, err := s.State.Channel(guild.Channels[0].ID)
+	if err != nil {
+		t.Fatalf("State.Channel returned error: %+v", err)
+	}
+	if c.Name != "general" || c.GuildID != guild.ID {
+		t.Errorf("unexpected channel in state %+v", c)
+	}
+
+	if n := len(srv.Received(2)); n != 1 {
+		t.Errorf("expected 1 identify, got %d", n)
+	}
+}
+
+func TestAuthenticationFailed(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Token = "token"
+
+	s, err := srv.NewSession("wrong")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+
+	_, err = s.User("@me")
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response.StatusCode != 401 {
+		t.Errorf("expected a 401 RESTError, got %v", err)
+	}
+}
+
+func TestMessages(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(&discordgo.Channel{Type: discordgo.ChannelTypeDM})
+	s := open(t, srv)
+
+	created := make(chan *discordgo.MessageCreate, 2)
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) { created <- m })
+
+	srv.AddMessage(&discordgo.Message{ChannelID: channel.ID, Content: "!ping"})
+	if m := receive(t, created); m.Content != "!ping" {
+		t.Errorf("expected content %q, got %q", "!ping", m.Content)
+	}
+
+	sent, err := s.ChannelMessageSend(channel.ID, "pong")
+	if err != nil {
+		t.Fatalf("ChannelMessageSend returned error: %+v", err)
+	}
+	if m := receive(t, created); m.ID != sent.ID || m.Author.ID != srv.User.ID {
+		t.Errorf("unexpected MESSAGE_CREATE %+v", m.Message)
+	}
+
+	if _, err = s.ChannelMessageEdit(channel.ID, sent.ID, "edited"); err != nil {
+		t.Fatalf("ChannelMessageEdit returned error: %+v", err)
+	}
+
+	messages, err := s.ChannelMessages(channel.ID, 10, "", "", "")
+	if err != nil {
+		t.Fatalf("ChannelMessages returned error: %+v", err)
+	}
+	if len(messages) != 2 || messages[0].Content != "edited" || messages[1].Content != "!ping" {
+		t.Errorf("unexpected messages %+v", messages)
+	}
+
+	if err = s.ChannelMessageDelete(channel.ID, sent.ID); err != nil {
+		t.Fatalf("ChannelMessageDelete returned error: %+v", err)
+	}
+	if _, err = s.ChannelMessage(channel.ID, sent.ID); !errors.Is(err, discordgo.ErrCodeUnknownMessage) {
+		t.Errorf("expected %v, got %v", discordgo.ErrCodeUnknownMessage, err)
+	}
+	if _, err = s.ChannelMessageSend(channel.ID, ""); !errors.Is(err, discordgo.ErrCodeCannotSendEmptyMessage) {
+		t.Errorf("expected %v, got %v", discordgo.ErrCodeCannotSendEmptyMessage, err)
+	}
+}
+
+func TestReconnectResume(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s := open(t, srv)
+
+	resumed := make(chan *discordgo.Resumed, 1)
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Resumed) { resumed <- r })
+	connected := make(chan *discordgo.Connect, 1)
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.Connect) { connected <- c })
+
+	srv.Reconnect()
+	receive(t, resumed)
+
+	// The Server only resumes sessions on the resume URL.
+	if c := receive(t, connected); !c.Resumed {
+		t.Error("expected the connection to resume the session")
+	}
+
+	if n := len(srv.Received(6)); n != 1 {
+		t.Errorf("expected 1 resume, got %d", n)
+	}
+	if n := len(srv.Received(2)); n != 1 {
+		t.Errorf("expected 1 identify, got %d", n)
+	}
+}
+
+func TestInvalidSession(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s := open(t, srv)
+
+	ready := make(chan *discordgo.Ready, 1)
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) { ready <- r })
+
+	srv.InvalidateSession(false)
+	receive(t, ready)
+
+	if n := len(srv.Received(2)); n != 2 {
+		t.Errorf("expected 2 identifies, got %d", n)
+	}
+}
+
+func TestGatewayCompression(t *testing.T) {
+	for _, compression := range []discordgo.GatewayCompression{
+		discordgo.GatewayCompressionZlibStream,
+		discordgo.GatewayCompressionZstdStream,
+	} {
+		t.Run(string(compression), func(t *testing.T) {
+			srv := NewServer()
+			defer srv.Close()
+
+			channel := srv.AddChannel(&discordgo.Channel{Type: discordgo.ChannelTypeDM})
+
+			s, err := srv.NewSession("token")
+			if err != nil {
+				t.Fatalf("NewSession returned error: %+v", err)
+			}
+			s.ShouldSubscribeGuilds = false
+			s.GatewayCompression = compression
+
+			created := make(chan *discordgo.MessageCreate, 1)
+			s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) { created <- m })
+
+			if err = s.Open(); err != nil {
+				t.Fatalf("Open returned error: %+v", err)
+			}
+			defer s.Close()
+
+			content := strings.Repeat("compress me ", 10000)
+			srv.AddMessage(&discordgo.Message{ChannelID: channel.ID, Content: content})
+			if m := receive(t, created); m.Content != content {
+				t.Errorf("unexpected content of length %d", len(m.Content))
+			}
+		})
+	}
+}
+
+func TestGatewayEncodingETF(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Token = "token"
+
+	guild := srv.AddGuild(&discordgo.Guild{
+		Name:     "test",
+		Channels: []*discordgo.Channel{{Name: "general", Type: discordgo.ChannelTypeGuildText}},
+	})
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	s.GatewayEncoding = discordgo.GatewayEncodingETF
+	s.GatewayCompression = discordgo.GatewayCompressionZlibStream
+
+	created := make(chan *discordgo.MessageCreate, 1)
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) { created <- m })
+	resumed := make(chan *discordgo.Resumed, 1)
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Resumed) { resumed <- r })
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer s.Close()
+
+	// Snowflakes are sent as integers, and decoded into the string fields.
+	c, err := s.State.Channel(guild.Channels[0].ID)
+	if err != nil {
+		t.Fatalf("State.Channel returned error: %+v", err)
+	}
+	if c.Name != "general" || c.GuildID != guild.ID {
+		t.Errorf("unexpected channel in state %+v", c)
+	}
+
+	m := srv.AddMessage(&discordgo.Message{ChannelID: c.ID, Content: "etf"})
+	if got := receive(t, created); got.ID != m.ID || got.ChannelID != c.ID || got.Content != "etf" {
+		t.Errorf("unexpected MESSAGE_CREATE %+v", got.Message)
+	}
+
+	// Payloads the session sends are ETF too.
+	srv.Reconnect()
+	receive(t, resumed)
+
+	resumes := srv.Received(6)
+	if len(resumes) != 1 || !strings.Contains(string(resumes[0].RawData), `"token":"token"`) {
+		t.Errorf("unexpected resumes %+v", resumes)
+	}
+}
+
+func TestInvalidSessionResumable(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s := open(t, srv)
+
+	resumed := make(chan *discordgo.Resumed, 1)
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Resumed) { resumed <- r })
+
+	srv.InvalidateSession(true)
+	receive(t, resumed)
+
+	if n := len(srv.Received(2)); n != 1 {
+		t.Errorf("expected 1 identify, got %d", n)
+	}
+}
+
+func TestCloseCodes(t *testing.T) {
+	for _, tc := range []struct {
+		code       int
+		identifies int
+		resumes    int
+	}{
+		{discordgo.GatewayCloseUnknownError, 1, 1},
+		{discordgo.GatewayCloseSessionTimedOut, 2, 0},
+		{discordgo.GatewayCloseAuthenticationFailed, 1, 0},
+		{discordgo.GatewayCloseDisallowedIntents, 1, 0},
+	} {
+		t.Run(strconv.Itoa(tc.code), func(t *testing.T) {
+			srv := NewServer()
+			defer srv.Close()
+
+			s := open(t, srv)
+
+			connected := make(chan *discordgo.Connect, 1)
+			s.AddHandler(func(s *discordgo.Session, c *discordgo.Connect) { connected <- c })
+			disconnected := make(chan *discordgo.Disconnect, 1)
+			s.AddHandler(func(s *discordgo.Session, d *discordgo.Disconnect) { disconnected <- d })
+
+			srv.Disconnect(tc.code)
+			receive(t, disconnected)
+
+			if tc.identifies+tc.resumes == 1 {
+				// The session must not reconnect.
+				select {
+				case <-connected:
+					t.Fatal("expected no reconnect")
+				case <-time.After(2 * time.Second):
+				}
+			} else if c := receive(t, connected); c.Resumed != (tc.resumes > 0) {
+				t.Errorf("expected Resumed %v, got %v", tc.resumes > 0, c.Resumed)
+			}
+
+			if n := len(srv.Received(2)); n != tc.identifies {
+				t.Errorf("expected %d identifies, got %d", tc.identifies, n)
+			}
+			if n := len(srv.Received(6)); n != tc.resumes {
+				t.Errorf("expected %d resumes, got %d", tc.resumes, n)
+			}
+		})
+	}
+}
+
+func TestCloseEndsSession(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s := open(t, srv)
+
+	ready := make(chan *discordgo.Ready, 1)
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) { ready <- r })
+
+	// A normal closure ends the session, so it is not resumed.
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close returned error: %+v", err)
+	}
+	if err := s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	receive(t, ready)
+
+	if n := len(srv.Received(6)); n != 0 {
+		t.Errorf("expected no resumes, got %d", n)
+	}
+}
+
+// receiveStateChange waits for a StateChange from old to new on c.
+func receiveStateChange(t *testing.T, c <-chan *discordgo.StateChange, old, new discordgo.ConnectionState) *discordgo.StateChange {
+	t.Helper()
+
+	sc := receive(t, c)
+	if sc.Old != old || sc.New != new {
+		t.Fatalf("expected state change from %s to %s, got %s to %s", old, new, sc.Old, sc.New)
+	}
+	return sc
+}
+
+func TestStateChanges(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	// Handlers run in order, so the changes are received in order.
+	s.SyncEvents = true
+
+	changes := make(chan *discordgo.StateChange, 16)
+	s.AddHandler(func(s *discordgo.Session, sc *discordgo.StateChange) { changes <- sc })
+
+	if st := s.Status(); st != discordgo.ConnectionStateDisconnected {
+		t.Errorf("expected a new session to be Disconnected, got %s", st)
+	}
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer s.Close()
+
+	receiveStateChange(t, changes, discordgo.ConnectionStateDisconnected, discordgo.ConnectionStateConnecting)
+	receiveStateChange(t, changes, discordgo.ConnectionStateConnecting, discordgo.ConnectionStateIdentifying)
+	receiveStateChange(t, changes, discordgo.ConnectionStateIdentifying, discordgo.ConnectionStateReady)
+	if st := s.Status(); st != discordgo.ConnectionStateReady {
+		t.Errorf("expected an open session to be Ready, got %s", st)
+	}
+
+	srv.Reconnect()
+	sc := receiveStateChange(t, changes, discordgo.ConnectionStateReady, discordgo.ConnectionStateReconnecting)
+	if !errors.Is(sc.Err, discordgo.ErrGatewayReconnect) {
+		t.Errorf("expected cause %v, got %v", discordgo.ErrGatewayReconnect, sc.Err)
+	}
+	receiveStateChange(t, changes, discordgo.ConnectionStateReconnecting, discordgo.ConnectionStateConnecting)
+	receiveStateChange(t, changes, discordgo.ConnectionStateConnecting, discordgo.ConnectionStateResuming)
+	receiveStateChange(t, changes, discordgo.ConnectionStateResuming, discordgo.ConnectionStateReady)
+
+	srv.Disconnect(discordgo.GatewayCloseAuthenticationFailed)
+	sc = receiveStateChange(t, changes, discordgo.ConnectionStateReady, discordgo.ConnectionStateDisconnected)
+	if sc.CloseCode != discordgo.GatewayCloseAuthenticationFailed {
+		t.Errorf("expected close code %d, got %d", discordgo.GatewayCloseAuthenticationFailed, sc.CloseCode)
+	}
+
+	if err = s.Close(); err != nil {
+		t.Fatalf("Close returned error: %+v", err)
+	}
+	receiveStateChange(t, changes, discordgo.ConnectionStateDisconnected, discordgo.ConnectionStateClosed)
+}
+
+func TestStateChangeResumeFailed(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	s.SyncEvents = true
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer s.Close()
+
+	changes := make(chan *discordgo.StateChange, 16)
+	s.AddHandler(func(s *discordgo.Session, sc *discordgo.StateChange) { changes <- sc })
+
+	// The session is identified again on the same connection.
+	srv.InvalidateSession(false)
+	sc := receiveStateChange(t, changes, discordgo.ConnectionStateReady, discordgo.ConnectionStateIdentifying)
+	if !errors.Is(sc.Err, discordgo.ErrGatewayInvalidSession) {
+		t.Errorf("expected cause %v, got %v", discordgo.ErrGatewayInvalidSession, sc.Err)
+	}
+	receiveStateChange(t, changes, discordgo.ConnectionStateIdentifying, discordgo.ConnectionStateReady)
+}
+
+func TestReconnectGiveUp(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	s.ReconnectPolicy = discordgo.ExponentialBackoff{Base: 10 * time.Millisecond, Max: 10 * time.Millisecond, MaxAttempts: 2}
+
+	failed := make(chan *discordgo.ReconnectFailed, 1)
+	s.AddHandler(func(s *discordgo.Session, f *discordgo.ReconnectFailed) { failed <- f })
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer s.Close()
+
+	// Reconnecting fails while the server is down.
+	srv.Close()
+
+	f := receive(t, failed)
+	if f.Attempts != 2 || f.Err == nil {
+		t.Errorf("expected 2 failed attempts with an error, got %d and %v", f.Attempts, f.Err)
+	}
+	if st := s.Status(); st != discordgo.ConnectionStateDisconnected {
+		t.Errorf("expected the session to be Disconnected, got %s", st)
+	}
+}
+
+// slowReconnect is a ReconnectPolicy which waits longer than tests run.
+type slowReconnect struct{}
+
+func (slowReconnect) Backoff(attempt int, err error) (time.Duration, bool) {
+	return time.Hour, true
+}
+
+func TestReconnectContext(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	s.ReconnectPolicy = slowReconnect{}
+	s.ReconnectContext = ctx
+
+	failed := make(chan *discordgo.ReconnectFailed, 1)
+	s.AddHandler(func(s *discordgo.Session, f *discordgo.ReconnectFailed) { failed <- f })
+	changes := make(chan *discordgo.StateChange, 16)
+	s.AddHandler(func(s *discordgo.Session, sc *discordgo.StateChange) { changes <- sc })
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer s.Close()
+
+	srv.Reconnect()
+	for sc := receive(t, changes); sc.New != discordgo.ConnectionStateReconnecting; sc = receive(t, changes) {
+	}
+	cancel()
+
+	f := receive(t, failed)
+	if f.Attempts != 0 || !errors.Is(f.Err, context.Canceled) {
+		t.Errorf("expected no attempts and %v, got %d and %v", context.Canceled, f.Attempts, f.Err)
+	}
+}
+
+func TestCloseStopsReconnecting(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	s.ReconnectPolicy = slowReconnect{}
+
+	failed := make(chan *discordgo.ReconnectFailed, 1)
+	s.AddHandler(func(s *discordgo.Session, f *discordgo.ReconnectFailed) { failed <- f })
+	disconnected := make(chan *discordgo.Disconnect, 1)
+	s.AddHandler(func(s *discordgo.Session, d *discordgo.Disconnect) { disconnected <- d })
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+
+	srv.Reconnect()
+	receive(t, disconnected)
+	if err = s.Close(); err != nil {
+		t.Fatalf("Close returned error: %+v", err)
+	}
+
+	// Closing is not a failure to reconnect.
+	select {
+	case f := <-failed:
+		t.Errorf("expected reconnecting to stop silently, got %+v", f)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if st := s.Status(); st != discordgo.ConnectionStateClosed {
+		t.Errorf("expected the session to be Closed, got %s", st)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		close(started)
+		<-release
+	})
+
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+
+	srv.AddMessage(&discordgo.Message{ChannelID: "1", Content: "hello"})
+	receive(t, started)
+
+	// The handler is still running.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err = s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	close(release)
+	if err = s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %+v", err)
+	}
+	if st := s.Status(); st != discordgo.ConnectionStateClosed {
+		t.Errorf("expected the session to be Closed, got %s", st)
+	}
+	if n := srv.Connections(); n != 0 {
+		t.Errorf("expected no gateway connections, got %d", n)
+	}
+}
+
+func TestMessageSendConfirm(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(&discordgo.Channel{Type: discordgo.ChannelTypeDM})
+	s := open(t, srv)
+
+	send := &discordgo.MessageSend{Content: "hello", Confirm: true}
+	m, err := s.ChannelMessageSendComplex(channel.ID, send)
+	if err != nil {
+		t.Fatalf("ChannelMessageSendComplex returned error: %+v", err)
+	}
+	if m.Nonce == "" || m.Content != "hello" {
+		t.Errorf("unexpected message %+v", m)
+	}
+	if send.Nonce != "" {
+		t.Errorf("expected the nonce not to be set on the MessageSend, got %q", send.Nonce)
+	}
+
+	// Sending it again generates another nonce.
+	again, err := s.ChannelMessageSendComplex(channel.ID, send)
+	if err != nil {
+		t.Fatalf("ChannelMessageSendComplex returned error: %+v", err)
+	}
+	if again.Nonce == "" || again.Nonce == m.Nonce {
+		t.Errorf("expected a new nonce, got %q", again.Nonce)
+	}
+
+	messages, err := s.ChannelMessages(channel.ID, 10, "", "", "")
+	if err != nil {
+		t.Fatalf("ChannelMessages returned error: %+v", err)
+	}
+	if len(messages) != 2 || messages[1].ID != m.ID || messages[1].Nonce != m.Nonce {
+		t.Errorf("unexpected messages %+v", messages)
+	}
+}
+
+func TestMessageSendEnforceNonce(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(&discordgo.Channel{Type: discordgo.ChannelTypeDM})
+	s := open(t, srv)
+
+	// The first response is lost after the message was created, as if a
+	// proxy failed, so the request is retried.
+	var failed bool
+	s.RESTMiddleware = append(s.RESTMiddleware, func(s *discordgo.Session, req *discordgo.RESTRequest, next discordgo.RESTHandler) (*discordgo.RESTResponse, error) {
+		resp, err := next(req)
+		if err != nil || failed || req.Request.Method != http.MethodPost {
+			return resp, err
+		}
+		failed = true
+
+		resp.Response.StatusCode = http.StatusBadGateway
+		resp.Response.Status = "502 Bad Gateway"
+		return resp, nil
+	})
+
+	m, err := s.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{Content: "hello", EnforceNonce: true})
+	if err != nil {
+		t.Fatalf("ChannelMessageSendComplex returned error: %+v", err)
+	}
+	if !failed {
+		t.Fatal("the request did not fail")
+	}
+
+	messages, err := s.ChannelMessages(channel.ID, 10, "", "", "")
+	if err != nil {
+		t.Fatalf("ChannelMessages returned error: %+v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != m.ID {
+		t.Errorf("expected the message to be created once, got %+v", messages)
+	}
+}
+
+func TestStateSnapshotResume(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(&discordgo.Channel{Type: discordgo.ChannelTypeDM})
+
+	s, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	s.ShouldSubscribeGuilds = false
+	created := make(chan *discordgo.MessageCreate, 1)
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) { created <- m })
+	if err = s.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+
+	srv.AddMessage(&discordgo.Message{ChannelID: channel.ID, Content: "before"})
+	receive(t, created)
+
+	var snapshot bytes.Buffer
+	if err = s.State.Snapshot(&snapshot); err != nil {
+		t.Fatalf("Snapshot returned error: %+v", err)
+	}
+	s.CloseWithCode(websocket.CloseServiceRestart)
+
+	// The message sent while no process is connected is replayed.
+	srv.AddMessage(&discordgo.Message{ChannelID: channel.ID, Content: "missed"})
+
+	restarted, err := srv.NewSession("token")
+	if err != nil {
+		t.Fatalf("NewSession returned error: %+v", err)
+	}
+	restarted.ShouldSubscribeGuilds = false
+	if err = restarted.State.Restore(&snapshot); err != nil {
+		t.Fatalf("Restore returned error: %+v", err)
+	}
+	if _, err = restarted.State.Channel(channel.ID); err != nil {
+		t.Errorf("expected the restored state to hold the channel, got %v", err)
+	}
+
+	missed := make(chan *discordgo.MessageCreate, 2)
+	restarted.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) { missed <- m })
+	resumed := make(chan *discordgo.Resumed, 1)
+	restarted.AddHandler(func(s *discordgo.Session, r *discordgo.Resumed) { resumed <- r })
+	if err = restarted.Open(); err != nil {
+		t.Fatalf("Open returned error: %+v", err)
+	}
+	defer restarted.Close()
+
+	if m := receive(t, missed); m.Content != "missed" {
+		t.Errorf("expected the missed message, got %q", m.Content)
+	}
+	receive(t, resumed)
+
+	if n := len(srv.Received(2)); n != 1 {
+		t.Errorf("expected 1 identify, got %d", n)
+	}
+	if n := len(srv.Received(6)); n != 1 {
+		t.Errorf("expected 1 resume, got %d", n)
+	}
+}
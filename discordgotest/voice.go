@@ -0,0 +1,583 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the fake voice server of the Server: the voice gateway
+// websocket, version 8, and the UDP socket which receives the audio.
+
+package discordgotest
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lb-selfbot/discordgo"
+	"github.com/lb-selfbot/discordgo/internal/mls"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// DefaultVoiceHeartbeatInterval is the heartbeat interval sent in the voice
+// Hello payload when Server.VoiceHeartbeatInterval is not set.
+const DefaultVoiceHeartbeatInterval = 13750 * time.Millisecond
+
+// DefaultVoiceModes are the voice encryption modes sent in the voice Ready
+// payload when Server.VoiceModes is not set.
+var DefaultVoiceModes = []string{
+	discordgo.VoiceModeAES256GCMRTPSize,
+	discordgo.VoiceModeXChaCha20Poly1305RTPSize,
+}
+
+// A voiceSession is the voice session of a guild, created by a voice state
+// update which joins one of its channels, and identified or resumed by voice
+// connections.
+type voiceSession struct {
+	id        string
+	token     string
+	guildID   string
+	channelID string
+	ssrc      uint32
+
+	mode string
+	key  [32]byte
+
+	seq     int
+	history []sentVoiceMessage
+	conn    *voiceConn
+
+	// opus are the opus frames received on the UDP socket, from udpAddr.
+	opus    [][]byte
+	udpAddr *net.UDPAddr
+
+	// daveVersion is the DAVE protocol version of the session, and
+	// daveKeyPackage the last key package of the client, until it is added
+	// to the MLS group of the voice channel. daveEpoch is the epoch of the
+	// group, 0 until the client is a member.
+	daveVersion    int
+	daveKeyPackage *mls.KeyPackage
+	daveEpoch      uint64
+	daveTransition uint16
+
+	// davePeers are the other users of the voice channel, simulated by
+	// the Server, and peerNonce the nonce of the next packet they send.
+	davePeers []*davePeer
+	peerNonce uint32
+}
+
+// A sentVoiceMessage is an encoded voice payload, or binary DAVE message,
+// kept to be replayed on resume.
+type sentVoiceMessage struct {
+	seq    int
+	data   []byte
+	binary bool
+}
+
+// A voiceConn is a client connection to the voice gateway.
+type voiceConn struct {
+	*conn
+
+	// session is guarded by the Server lock.
+	session *voiceSession
+}
+
+// voicePayload is a payload of the voice gateway. Seq is set for the
+// payloads which are replayed on resume.
+type voicePayload struct {
+	Operation int             `json:"op"`
+	Seq       *int            `json:"seq,omitempty"`
+	RawData   json.RawMessage `json:"d"`
+}
+
+type voiceStateData struct {
+	GuildID   string  `json:"guild_id"`
+	ChannelID *string `json:"channel_id"`
+}
+
+type voiceIdentifyData struct {
+	ServerID  string `json:"server_id"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	Token     string `json:"token"`
+
+	MaxDAVEProtocolVersion int `json:"max_dave_protocol_version"`
+}
+
+type voiceResumeData struct {
+	ServerID  string `json:"server_id"`
+	SessionID string `json:"session_id"`
+	Token     string `json:"token"`
+	SeqAck    int    `json:"seq_ack"`
+}
+
+type voiceSelectProtocolData struct {
+	Data struct {
+		Mode string `json:"mode"`
+	} `json:"data"`
+}
+
+type voiceHeartbeatData struct {
+	Nonce  int64 `json:"t"`
+	SeqAck int   `json:"seq_ack"`
+}
+
+type voiceReadyData struct {
+	SSRC  uint32   `json:"ssrc"`
+	IP    string   `json:"ip"`
+	Port  int      `json:"port"`
+	Modes []string `json:"modes"`
+}
+
+type voiceSessionDescriptionData struct {
+	Mode                string   `json:"mode"`
+	SecretKey           [32]byte `json:"secret_key"`
+	DAVEProtocolVersion int      `json:"dave_protocol_version"`
+}
+
+type voiceSpeakingData struct {
+	UserID   string `json:"user_id"`
+	SSRC     uint32 `json:"ssrc"`
+	Speaking bool   `json:"speaking"`
+}
+
+// startVoice starts the voice gateway and the UDP socket of the voice server.
+func (s *Server) startVoice() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleVoice)
+	s.voiceSrv = httptest.NewTLSServer(mux)
+
+	var err error
+	s.voiceUDP, err = net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+	go s.voiceUDPLoop()
+
+	if s.daveKey, err = mls.GenerateSignatureKey(); err != nil {
+		panic("discordgotest: " + err.Error())
+	}
+}
+
+// closeVoice shuts down the voice server.
+func (s *Server) closeVoice() {
+	s.Lock()
+	for c := range s.voiceConns {
+		c.close()
+	}
+	s.Unlock()
+
+	s.voiceSrv.Close()
+	s.voiceUDP.Close()
+}
+
+// voiceEndpoint returns the endpoint of the voice server sent in voice server
+// updates.
+func (s *Server) voiceEndpoint() string {
+	return strings.TrimPrefix(s.voiceSrv.URL, "https://")
+}
+
+// voiceStateUpdate handles a voice state update sent on the gateway
+// connection c, which joins or leaves a voice channel. s must be locked.
+func (s *Server) voiceStateUpdate(c *conn, d *voiceStateData) {
+	if c.session == nil {
+		return
+	}
+
+	if d.ChannelID == nil {
+		delete(s.voiceSessions, d.GuildID)
+		s.dispatchTo(c.session, "VOICE_STATE_UPDATE", mustMarshal(&discordgo.VoiceState{
+			GuildID: d.GuildID,
+			UserID:  s.User.ID,
+		}))
+		return
+	}
+
+	// Like Discord, the ID of the voice session is the ID of the gateway
+	// session, and only its token changes when joining again.
+	s.lastSSRC++
+	vs := &voiceSession{
+		id:        c.session.id,
+		token:     s.nextID(),
+		guildID:   d.GuildID,
+		channelID: *d.ChannelID,
+		ssrc:      s.lastSSRC,
+	}
+	s.voiceSessions[d.GuildID] = vs
+
+	s.dispatchTo(c.session, "VOICE_STATE_UPDATE", mustMarshal(&discordgo.VoiceState{
+		GuildID:   vs.guildID,
+		ChannelID: vs.channelID,
+		UserID:    s.User.ID,
+		SessionID: vs.id,
+	}))
+	s.dispatchTo(c.session, "VOICE_SERVER_UPDATE", mustMarshal(&discordgo.VoiceServerUpdate{
+		Token:    vs.token,
+		GuildID:  vs.guildID,
+		Endpoint: s.voiceEndpoint(),
+	}))
+}
+
+// handleVoice upgrades the request to a websocket and serves the voice
+// gateway protocol until the connection is closed.
+func (s *Server) handleVoice(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &voiceConn{conn: newConn(ws, "", "")}
+
+	s.Lock()
+	s.voiceConns[c] = true
+	s.Unlock()
+
+	defer func() {
+		s.Lock()
+		delete(s.voiceConns, c)
+		if c.session != nil && c.session.conn == c {
+			c.session.conn = nil
+		}
+		s.Unlock()
+
+		c.close()
+	}()
+
+	c.sendOp(8, struct {
+		HeartbeatInterval float64 `json:"heartbeat_interval"`
+	}{float64(s.VoiceHeartbeatInterval) / float64(time.Millisecond)})
+
+	for {
+		messageType, message, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		// Binary DAVE messages start with their opcode.
+		var e *discordgo.Event
+		if messageType == websocket.BinaryMessage && len(message) > 0 {
+			e = &discordgo.Event{Operation: int(message[0]), RawData: message[1:]}
+		} else if err = discordgo.Unmarshal(message, &e); err != nil || messageType != websocket.TextMessage {
+			c.closeWithCode(discordgo.VoiceCloseDecodeError, "Failed to decode payload.")
+			return
+		}
+
+		s.Lock()
+		s.voiceReceived = append(s.voiceReceived, e)
+		ok := true
+		if messageType == websocket.BinaryMessage {
+			if c.session != nil && c.session.daveVersion > 0 {
+				s.handleDAVEBinary(c.session, e.Operation, e.RawData)
+			}
+		} else {
+			ok = s.handleVoicePayload(c, e)
+		}
+		s.Unlock()
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// handleVoicePayload handles a payload sent by a voice client. It returns
+// false when the connection was closed. s must be locked.
+func (s *Server) handleVoicePayload(c *voiceConn, e *discordgo.Event) bool {
+	switch e.Operation {
+	case 0:
+		var d voiceIdentifyData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		vs, ok := s.voiceSessions[d.ServerID]
+		if !ok || vs.id != d.SessionID || vs.token != d.Token {
+			c.closeWithCode(discordgo.VoiceCloseAuthenticationFailed, "Authentication failed.")
+			return false
+		}
+
+		vs.seq = 0
+		vs.history = nil
+		vs.conn = c
+		c.session = vs
+		vs.daveVersion = min(s.DAVEProtocolVersion, d.MaxDAVEProtocolVersion)
+
+		port := s.voiceUDP.LocalAddr().(*net.UDPAddr).Port
+		s.sendVoice(vs, 2, voiceReadyData{vs.ssrc, "127.0.0.1", port, s.VoiceModes})
+
+	case 1:
+		var d voiceSelectProtocolData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		vs := c.session
+		if vs == nil {
+			c.closeWithCode(discordgo.VoiceCloseNotAuthenticated, "Not authenticated.")
+			return false
+		}
+		if !slices.Contains(s.VoiceModes, d.Data.Mode) {
+			c.closeWithCode(discordgo.VoiceCloseUnknownEncryptionMode, "Unknown encryption mode.")
+			return false
+		}
+
+		vs.mode = d.Data.Mode
+		rand.Read(vs.key[:])
+		s.sendVoice(vs, 4, voiceSessionDescriptionData{vs.mode, vs.key, vs.daveVersion})
+		if vs.daveVersion > 0 {
+			sender := &mls.ExternalSender{SignatureKey: s.daveKey.PublicKey(), Credential: mls.Credential{Identity: []byte("discordgotest")}}
+			s.sendVoiceBinary(vs, 25, sender.Marshal())
+		}
+
+	case 3:
+		var d voiceHeartbeatData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		c.sendOp(6, struct {
+			Nonce int64 `json:"t"`
+		}{d.Nonce})
+
+	case 7:
+		var d voiceResumeData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		vs, ok := s.voiceSessions[d.ServerID]
+		if !ok || vs.id != d.SessionID || vs.token != d.Token {
+			c.closeWithCode(discordgo.VoiceCloseSessionNoLongerValid, "Session no longer valid.")
+			return false
+		}
+
+		vs.conn = c
+		c.session = vs
+
+		for _, sent := range vs.history {
+			if sent.seq <= d.SeqAck {
+				continue
+			}
+			if sent.binary {
+				c.sendBinary(sent.data)
+			} else {
+				c.send(sent.data)
+			}
+		}
+		c.sendOp(9, nil)
+
+	case 23:
+		var d voiceDAVETransitionData
+		discordgo.Unmarshal(e.RawData, &d)
+
+		// The other members are always ready.
+		if vs := c.session; vs != nil {
+			s.sendVoice(vs, 22, voiceDAVETransitionData{TransitionID: d.TransitionID})
+		}
+	}
+
+	return true
+}
+
+// sendVoice sends a voice payload with the next sequence number of a voice
+// session. Payloads sent while the session is disconnected are replayed when
+// it resumes. s must be locked.
+func (s *Server) sendVoice(vs *voiceSession, op int, data any) {
+	vs.seq++
+	seq := vs.seq
+
+	raw := mustMarshal(&voicePayload{Operation: op, Seq: &seq, RawData: mustMarshal(data)})
+	vs.history = append(vs.history, sentVoiceMessage{seq, raw, false})
+
+	if vs.conn != nil {
+		vs.conn.send(raw)
+	}
+}
+
+// VoiceSpeaking sends an Op 5 Speaking of another user to the voice client of
+// a guild.
+func (s *Server) VoiceSpeaking(guildID, userID string, ssrc uint32, speaking bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if vs, ok := s.voiceSessions[guildID]; ok {
+		s.sendVoice(vs, 5, voiceSpeakingData{userID, ssrc, speaking})
+	}
+}
+
+// DisconnectVoice closes every voice client connection with the given close
+// code. The voice sessions are kept, so clients may resume them, unless the
+// code is discordgo.VoiceCloseSessionNoLongerValid or
+// discordgo.VoiceCloseDisconnected.
+func (s *Server) DisconnectVoice(code int) {
+	s.Lock()
+	defer s.Unlock()
+
+	for c := range s.voiceConns {
+		c.closeWithCode(code, "")
+
+		if vs := c.session; vs != nil {
+			if vs.conn == c {
+				vs.conn = nil
+			}
+			if code == discordgo.VoiceCloseSessionNoLongerValid || code == discordgo.VoiceCloseDisconnected {
+				delete(s.voiceSessions, vs.guildID)
+			}
+		}
+	}
+}
+
+// VoiceConnections returns the number of open voice gateway connections.
+func (s *Server) VoiceConnections() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return len(s.voiceConns)
+}
+
+// VoiceReceived returns all voice gateway payloads received from clients with
+// the given opcode. If op is negative, all payloads are returned.
+func (s *Server) VoiceReceived(op int) []*discordgo.Event {
+	s.Lock()
+	defer s.Unlock()
+
+	var events []*discordgo.Event
+	for _, e := range s.voiceReceived {
+		if op < 0 || e.Operation == op {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// VoiceOpus returns the opus frames received from the voice client of a
+// guild, decrypted, in the order they were received.
+func (s *Server) VoiceOpus(guildID string) [][]byte {
+	s.Lock()
+	defer s.Unlock()
+
+	if vs, ok := s.voiceSessions[guildID]; ok {
+		return slices.Clone(vs.opus)
+	}
+	return nil
+}
+
+// voiceUDPLoop serves the UDP socket of the voice server: it answers IP
+// discovery requests, and decrypts the audio packets.
+func (s *Server) voiceUDPLoop() {
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := s.voiceUDP.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		packet := buf[:n]
+
+		// IP discovery requests, answered with the address of the client.
+		if n == 74 && binary.BigEndian.Uint16(packet) == 1 {
+			resp := make([]byte, 74)
+			binary.BigEndian.PutUint16(resp, 2)
+			binary.BigEndian.PutUint16(resp[2:], 70)
+			copy(resp[4:8], packet[4:8])
+			copy(resp[8:], addr.IP.String())
+			binary.BigEndian.PutUint16(resp[72:], uint16(addr.Port))
+			s.voiceUDP.WriteToUDP(resp, addr)
+
+			ssrc := binary.BigEndian.Uint32(packet[4:8])
+			s.Lock()
+			for _, vs := range s.voiceSessions {
+				if vs.ssrc == ssrc {
+					vs.udpAddr = addr
+				}
+			}
+			s.Unlock()
+			continue
+		}
+
+		// Anything else but RTP packets is a keep alive.
+		if n < 12 || packet[0]&0xC0 != 0x80 {
+			continue
+		}
+
+		ssrc := binary.BigEndian.Uint32(packet[8:12])
+		s.Lock()
+		for _, vs := range s.voiceSessions {
+			if vs.ssrc == ssrc {
+				opus, ok := openVoicePacket(vs.mode, &vs.key, packet)
+				if !ok {
+					continue
+				}
+				vs.opus = append(vs.opus, opus)
+				for _, p := range vs.davePeers {
+					if frame, ok := p.open(opus); ok {
+						p.opus = append(p.opus, frame)
+					}
+				}
+			}
+		}
+		s.Unlock()
+	}
+}
+
+// openVoicePacket decrypts an RTP packet sent with a voice encryption mode,
+// without CSRCs nor header extension, and returns its payload.
+func openVoicePacket(mode string, key *[32]byte, packet []byte) ([]byte, bool) {
+	header := packet[:12]
+
+	var aead cipher.AEAD
+	switch mode {
+	case discordgo.VoiceModeAES256GCMRTPSize:
+		block, _ := aes.NewCipher(key[:])
+		aead, _ = cipher.NewGCM(block)
+	case discordgo.VoiceModeXChaCha20Poly1305RTPSize:
+		aead, _ = chacha20poly1305.NewX(key[:])
+	case discordgo.VoiceModeXSalsa20Poly1305:
+		var nonce [24]byte
+		copy(nonce[:], header)
+		return secretbox.Open(nil, packet[12:], &nonce, key)
+	default:
+		return nil, false
+	}
+
+	if len(packet) < 12+aead.Overhead()+4 {
+		return nil, false
+	}
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, packet[len(packet)-4:])
+
+	opus, err := aead.Open(nil, nonce, packet[12:len(packet)-4], header)
+	return opus, err == nil
+}
+
+// sealVoicePacket encrypts the payload of an RTP packet with a voice
+// encryption mode.
+func sealVoicePacket(mode string, key *[32]byte, header, opus []byte, nonce uint32) []byte {
+	var aead cipher.AEAD
+	switch mode {
+	case discordgo.VoiceModeAES256GCMRTPSize:
+		block, _ := aes.NewCipher(key[:])
+		aead, _ = cipher.NewGCM(block)
+	case discordgo.VoiceModeXChaCha20Poly1305RTPSize:
+		aead, _ = chacha20poly1305.NewX(key[:])
+	default:
+		var n [24]byte
+		copy(n[:], header)
+		return secretbox.Seal(slices.Clone(header), opus, &n, key)
+	}
+
+	n := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint32(n, nonce)
+	packet := aead.Seal(slices.Clone(header), n, opus, header)
+	return append(packet, n[:4]...)
+}
+
+// voiceDialer returns a websocket dialer which trusts the certificate of the
+// voice server.
+func (s *Server) voiceDialer() *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = s.voiceSrv.Client().Transport.(*http.Transport).TLSClientConfig
+	return &dialer
+}
@@ -0,0 +1,405 @@
+package discordgotest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lb-selfbot/discordgo"
+)
+
+// joinVoice returns a session connected to srv, and its voice connection to
+// a new voice channel.
+func joinVoice(t *testing.T, srv *Server) (*discordgo.Session, *discordgo.VoiceConnection) {
+	guild := srv.AddGuild(&discordgo.Guild{
+		Name:     "test",
+		Channels: []*discordgo.Channel{{Name: "voice", Type: discordgo.ChannelTypeGuildVoice}},
+	})
+
+	s := open(t, srv)
+	s.ReconnectPolicy = discordgo.ExponentialBackoff{Base: 10 * time.Millisecond}
+
+	vc, err := s.ChannelVoiceJoin(guild.ID, guild.Channels[0].ID, false, false)
+	if err != nil {
+		t.Fatalf("ChannelVoiceJoin returned error: %+v", err)
+	}
+	return s, vc
+}
+
+// eventually waits for cond to be true.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(10 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// sendOpus sends an opus frame, and waits for the server to receive it.
+func sendOpus(t *testing.T, srv *Server, vc *discordgo.VoiceConnection, opus []byte) {
+	t.Helper()
+
+	vc.OpusSend <- opus
+	eventually(t, "opus frame", func() bool {
+		frames := srv.VoiceOpus(vc.GuildID)
+		return len(frames) > 0 && bytes.Equal(frames[len(frames)-1], opus)
+	})
+}
+
+func TestVoiceJoin(t *testing.T) {
+	for _, mode := range []string{discordgo.VoiceModeAES256GCMRTPSize, discordgo.VoiceModeXChaCha20Poly1305RTPSize} {
+		t.Run(mode, func(t *testing.T) {
+			srv := NewServer()
+			defer srv.Close()
+			srv.VoiceModes = []string{"xsalsa20_poly1305_lite", mode}
+			srv.VoiceHeartbeatInterval = 20 * time.Millisecond
+
+			_, vc := joinVoice(t, srv)
+			defer vc.Disconnect()
+
+			selected := srv.VoiceReceived(1)
+			if len(selected) != 1 || !bytes.Contains(selected[0].RawData, []byte(`"mode":"`+mode+`"`)) {
+				t.Fatalf("expected mode %s to be selected, got %+v", mode, selected)
+			}
+
+			sendOpus(t, srv, vc, []byte("first frame"))
+			sendOpus(t, srv, vc, []byte("second frame"))
+
+			// Ready and Session Description are acknowledged.
+			eventually(t, "heartbeat with seq_ack", func() bool {
+				for _, e := range srv.VoiceReceived(3) {
+					if bytes.Contains(e.RawData, []byte(`"seq_ack":2`)) {
+						return true
+					}
+				}
+				return false
+			})
+		})
+	}
+}
+
+func TestVoiceResume(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, vc := joinVoice(t, srv)
+	defer vc.Disconnect()
+
+	speaking := make(chan *discordgo.VoiceSpeakingUpdate, 2)
+	vc.AddHandler(func(vc *discordgo.VoiceConnection, vs *discordgo.VoiceSpeakingUpdate) { speaking <- vs })
+
+	srv.VoiceSpeaking(vc.GuildID, "100", 100, true)
+	if vs := receive(t, speaking); vs.UserID != "100" {
+		t.Fatalf("unexpected speaking update %+v", vs)
+	}
+
+	// The update sent while the client is disconnected is replayed.
+	srv.DisconnectVoice(discordgo.VoiceCloseServerCrashed)
+	srv.VoiceSpeaking(vc.GuildID, "101", 101, true)
+	if vs := receive(t, speaking); vs.UserID != "101" {
+		t.Fatalf("unexpected speaking update %+v", vs)
+	}
+
+	resumes := srv.VoiceReceived(7)
+	if len(resumes) != 1 || !bytes.Contains(resumes[0].RawData, []byte(`"seq_ack":3`)) {
+		t.Fatalf("expected a resume acknowledging 3 messages, got %+v", resumes)
+	}
+	if n := len(srv.VoiceReceived(0)); n != 1 {
+		t.Errorf("expected 1 voice identify, got %d", n)
+	}
+	if n := len(srv.Received(4)); n != 1 {
+		t.Errorf("expected 1 voice state update, got %d", n)
+	}
+
+	// The UDP connection is kept.
+	sendOpus(t, srv, vc, []byte("frame"))
+
+	select {
+	case vs := <-speaking:
+		t.Errorf("unexpected speaking update %+v", vs)
+	default:
+	}
+	if s.VoiceConnections[vc.GuildID] != vc {
+		t.Error("expected the voice connection to be kept")
+	}
+}
+
+func TestVoiceSessionNoLongerValid(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, vc := joinVoice(t, srv)
+	defer vc.Disconnect()
+
+	// The voice channel is joined again, instead of resuming.
+	srv.DisconnectVoice(discordgo.VoiceCloseSessionNoLongerValid)
+	eventually(t, "voice identify", func() bool { return len(srv.VoiceReceived(0)) == 2 })
+
+	if n := len(srv.VoiceReceived(7)); n != 0 {
+		t.Errorf("expected no voice resume, got %d", n)
+	}
+	if n := len(srv.Received(4)); n != 2 {
+		t.Errorf("expected 2 voice state updates, got %d", n)
+	}
+
+	eventually(t, "voice ready", func() bool {
+		vc.RLock()
+		defer vc.RUnlock()
+		return vc.Ready
+	})
+	sendOpus(t, srv, vc, []byte("frame"))
+
+	s.RLock()
+	defer s.RUnlock()
+	if s.VoiceConnections[vc.GuildID] != vc {
+		t.Error("expected the voice connection to be kept")
+	}
+}
+
+// noReconnect is a ReconnectPolicy which gives up at once.
+type noReconnect struct{}
+
+func (noReconnect) Backoff(attempt int, err error) (time.Duration, bool) {
+	return 0, false
+}
+
+func TestVoiceReconnectFailed(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, vc := joinVoice(t, srv)
+	defer vc.Disconnect()
+	s.ReconnectPolicy = noReconnect{}
+
+	failed := make(chan *discordgo.VoiceReconnectFailed, 1)
+	s.AddHandler(func(s *discordgo.Session, f *discordgo.VoiceReconnectFailed) { failed <- f })
+
+	srv.DisconnectVoice(discordgo.VoiceCloseSessionNoLongerValid)
+
+	f := receive(t, failed)
+	if f.GuildID != vc.GuildID || f.ChannelID != vc.ChannelID || f.Attempts != 0 {
+		t.Errorf("expected no attempts to join channel %s, got %+v", vc.ChannelID, f)
+	}
+}
+
+func TestVoiceDisconnected(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	s, vc := joinVoice(t, srv)
+
+	// The voice connection is closed, without resuming nor joining again.
+	srv.DisconnectVoice(discordgo.VoiceCloseDisconnected)
+	eventually(t, "voice connection removal", func() bool {
+		s.RLock()
+		defer s.RUnlock()
+		return s.VoiceConnections[vc.GuildID] == nil
+	})
+
+	if n := len(srv.VoiceReceived(7)); n != 0 {
+		t.Errorf("expected no voice resume, got %d", n)
+	}
+	if n := len(srv.VoiceReceived(0)); n != 1 {
+		t.Errorf("expected 1 voice identify, got %d", n)
+	}
+	if n := len(srv.Received(4)); n != 1 {
+		t.Errorf("expected 1 voice state update, got %d", n)
+	}
+}
+
+// exchangeDAVEOpus sends opus frames between the voice client and a peer
+// until they decrypt each other's frames.
+func exchangeDAVEOpus(t *testing.T, srv *Server, vc *discordgo.VoiceConnection, userID string, ssrc uint32, opus []byte) {
+	t.Helper()
+
+	eventually(t, "frame decrypted by the peer", func() bool {
+		vc.OpusSend <- opus
+		time.Sleep(20 * time.Millisecond)
+		frames := srv.VoicePeerOpus(vc.GuildID, userID)
+		return len(frames) > 0 && bytes.Equal(frames[len(frames)-1], opus)
+	})
+
+	// The server only sees the encrypted frames.
+	frames := srv.VoiceOpus(vc.GuildID)
+	if last := frames[len(frames)-1]; bytes.Contains(last, opus) || !bytes.HasSuffix(last, []byte{0xFA, 0xFA}) {
+		t.Errorf("expected an encrypted frame, got %x", last)
+	}
+
+	eventually(t, "frame decrypted by the client", func() bool {
+		srv.VoicePeerSend(vc.GuildID, userID, opus)
+		select {
+		case p := <-vc.OpusRecv:
+			return p.SSRC == ssrc && bytes.Equal(p.Opus, opus)
+		case <-time.After(20 * time.Millisecond):
+			return false
+		}
+	})
+}
+
+func TestVoiceDAVE(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.DAVEProtocolVersion = 1
+
+	_, vc := joinVoice(t, srv)
+	defer vc.Disconnect()
+
+	identify := srv.VoiceReceived(0)
+	if !bytes.Contains(identify[0].RawData, []byte(`"max_dave_protocol_version":1`)) {
+		t.Fatalf("expected the DAVE protocol version in the identify, got %s", identify[0].RawData)
+	}
+	eventually(t, "key package", func() bool { return len(srv.VoiceReceived(26)) == 1 })
+	if v := vc.DAVEProtocolVersion(); v != 1 {
+		t.Fatalf("expected DAVE protocol version 1, got %d", v)
+	}
+
+	// The client commits the addition of the peer, which joins with the
+	// Welcome.
+	srv.AddVoicePeer(vc.GuildID, "100", 100)
+	eventually(t, "commit", func() bool { return len(srv.VoiceReceived(28)) == 1 })
+	eventually(t, "transition ready", func() bool { return len(srv.VoiceReceived(23)) == 1 })
+	exchangeDAVEOpus(t, srv, vc, "100", 100, []byte("first frame"))
+
+	// In a new group, the peer commits the addition of the client, which
+	// joins with the Welcome.
+	srv.ResetDAVE(vc.GuildID)
+	eventually(t, "new key package", func() bool { return len(srv.VoiceReceived(26)) == 2 })
+	eventually(t, "transition ready", func() bool { return len(srv.VoiceReceived(23)) == 2 })
+	exchangeDAVEOpus(t, srv, vc, "100", 100, []byte("second frame"))
+
+	// A second peer is added by the client, in the group of the first.
+	srv.AddVoicePeer(vc.GuildID, "101", 101)
+	eventually(t, "transition ready", func() bool { return len(srv.VoiceReceived(23)) == 3 })
+	exchangeDAVEOpus(t, srv, vc, "101", 101, []byte("third frame"))
+	exchangeDAVEOpus(t, srv, vc, "100", 100, []byte("fourth frame"))
+
+	if n := len(srv.VoiceReceived(31)); n != 0 {
+		t.Errorf("expected no invalid commit, got %d", n)
+	}
+
+	// The frames are only encrypted by the transport after a downgrade.
+	srv.DowngradeDAVE(vc.GuildID)
+	eventually(t, "downgrade", func() bool { return vc.DAVEProtocolVersion() == 0 })
+	sendOpus(t, srv, vc, []byte("fifth frame"))
+}
+
+func TestVoiceDAVEUnsupported(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	_, vc := joinVoice(t, srv)
+	defer vc.Disconnect()
+
+	// Without DAVE, the frames are only encrypted by the transport.
+	sendOpus(t, srv, vc, []byte("frame"))
+	if v := vc.DAVEProtocolVersion(); v != 0 {
+		t.Errorf("expected DAVE protocol version 0, got %d", v)
+	}
+	if n := len(srv.VoiceReceived(26)); n != 0 {
+		t.Errorf("expected no key package, got %d", n)
+	}
+}
+
+func TestVoicePlayOggOpus(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	_, vc := joinVoice(t, srv)
+	defer vc.Disconnect()
+	eventually(t, "voice ready", func() bool {
+		vc.RLock()
+		defer vc.RUnlock()
+		return vc.Ready
+	})
+
+	// Ten packets of 60 ms.
+	var b bytes.Buffer
+	w, _ := discordgo.NewOggOpusWriter(&b, 2)
+	for i := 0; i < 10; i++ {
+		w.WritePacket([]byte{0x18, byte(i)})
+	}
+	w.Close()
+
+	start := time.Now()
+	if err := vc.PlayOggOpus(context.Background(), &b); err != nil {
+		t.Fatalf("PlayOggOpus returned error: %+v", err)
+	}
+	eventually(t, "opus frames", func() bool { return len(srv.VoiceOpus(vc.GuildID)) == 10 })
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("expected the packets to be sent in 540ms, took %s", elapsed)
+	}
+	for i, frame := range srv.VoiceOpus(vc.GuildID) {
+		if !bytes.Equal(frame, []byte{0x18, byte(i)}) {
+			t.Errorf("frame %d: unexpected %x", i, frame)
+		}
+	}
+}
+
+// bufferCloser is a buffer which can be closed.
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error { return nil }
+
+func TestVoiceRecordOggOpus(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	_, vc := joinVoice(t, srv)
+	defer vc.Disconnect()
+	srv.AddVoicePeer(vc.GuildID, "100", 100)
+
+	var mu sync.Mutex
+	streams := map[uint32]*bufferCloser{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- vc.RecordOggOpus(ctx, func(ssrc uint32) (io.WriteCloser, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			streams[ssrc] = &bufferCloser{}
+			return streams[ssrc], nil
+		})
+	}()
+
+	eventually(t, "voice UDP connection", func() bool {
+		srv.VoicePeerSend(vc.GuildID, "100", []byte{0xFC, 0})
+		mu.Lock()
+		defer mu.Unlock()
+		return streams[100] != nil
+	})
+	for i := 1; i <= 5; i++ {
+		srv.VoicePeerSend(vc.GuildID, "100", []byte{0xFC, byte(i)})
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := receive(t, done); err != nil {
+		t.Fatalf("RecordOggOpus returned error: %+v", err)
+	}
+
+	r, err := discordgo.NewOggOpusReader(&streams[100].Buffer)
+	if err != nil {
+		t.Fatalf("NewOggOpusReader returned error: %+v", err)
+	}
+	var last []byte
+	for {
+		packet, err := r.ReadPacket()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ReadPacket returned error: %+v", err)
+		}
+		last = packet
+	}
+	if !bytes.Equal(last, []byte{0xFC, 5}) {
+		t.Errorf("expected the last packet to be recorded, got %x", last)
+	}
+}
@@ -0,0 +1,222 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the Dispatcher, which runs event handlers on a bounded
+// pool of workers.
+
+package discordgo
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what a Dispatcher does with an event when the queue
+// of its worker is full.
+type OverflowPolicy int
+
+// Overflow policies of a Dispatcher.
+const (
+	// OverflowBlock waits for room in the queue. The gateway connection is
+	// not read meanwhile, so events are never lost, but a slow handler
+	// delays all events.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest drops the event.
+	OverflowDropNewest
+
+	// OverflowDropOldest drops the oldest event in the queue to make room
+	// for the event.
+	OverflowDropOldest
+)
+
+// DefaultDispatcherQueueSize is the queue size of the workers of a
+// Dispatcher, when NewDispatcher is not given one.
+const DefaultDispatcherQueueSize = 1024
+
+// A Dispatcher runs the event handlers of a Session on a fixed pool of
+// workers, instead of a goroutine per handler per event. Events with the same
+// key, such as the events of a channel, are run by the same worker in the
+// order they were received, so a MESSAGE_UPDATE is never handled before its
+// MESSAGE_CREATE.
+//
+// A Dispatcher is used by setting Session.Dispatcher, and may be shared by
+// several sessions. Its exported fields must not be changed once it is in
+// use.
+//
+// With OverflowBlock, an event handler must not wait for another event with
+// the same key, which would wait in the queue of the same worker.
+type Dispatcher struct {
+	// Overflow decides what happens to an event when the queue of its
+	// worker is full. It defaults to OverflowBlock.
+	Overflow OverflowPolicy
+
+	// Key returns the key of an event. Events with the same key are handled
+	// in order, and events without a key are spread over the workers.
+	// DefaultEventKey is used when it is nil.
+	Key func(i any) string
+
+	workers []chan dispatchJob
+	next    atomic.Uint32
+	wg      sync.WaitGroup
+
+	handled atomic.Uint64
+	dropped atomic.Uint64
+
+	closeOnce sync.Once
+}
+
+// dispatchJob is an event handler call queued in a Dispatcher.
+type dispatchJob struct {
+	// run calls the handler.
+	run func()
+
+	// drop is called instead of run when the job is dropped.
+	drop func()
+}
+
+// DispatcherStats are metrics of a Dispatcher.
+type DispatcherStats struct {
+	// Queued is the number of handler calls waiting in the queue of each
+	// worker.
+	Queued []int
+
+	// Handled is the number of handler calls run.
+	Handled uint64
+
+	// Dropped is the number of handler calls dropped because a queue was
+	// full.
+	Dropped uint64
+}
+
+// NewDispatcher returns a new Dispatcher, whose workers are started. It runs
+// handlers on the given number of workers, each queuing up to queueSize
+// handler calls. GOMAXPROCS workers are used when workers is not positive,
+// and DefaultDispatcherQueueSize when queueSize is not positive.
+func NewDispatcher(workers, queueSize int) *Dispatcher {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultDispatcherQueueSize
+	}
+
+	d := &Dispatcher{
+		workers: make([]chan dispatchJob, workers),
+	}
+	d.wg.Add(workers)
+	for i := range d.workers {
+		d.workers[i] = make(chan dispatchJob, queueSize)
+		go d.work(d.workers[i])
+	}
+
+	return d
+}
+
+// work runs the jobs of a worker queue until it is closed.
+func (d *Dispatcher) work(queue <-chan dispatchJob) {
+	defer d.wg.Done()
+
+	for job := range queue {
+		job.run()
+		d.handled.Add(1)
+	}
+}
+
+// Stats returns the metrics of the dispatcher.
+func (d *Dispatcher) Stats() DispatcherStats {
+	stats := DispatcherStats{
+		Queued:  make([]int, len(d.workers)),
+		Handled: d.handled.Load(),
+		Dropped: d.dropped.Load(),
+	}
+	for i, queue := range d.workers {
+		stats.Queued[i] = len(queue)
+	}
+	return stats
+}
+
+// Close stops the workers once they have run the handler calls already
+// queued, and waits for them. The dispatcher must not be used by a Session
+// anymore.
+func (d *Dispatcher) Close() {
+	d.closeOnce.Do(func() {
+		for _, queue := range d.workers {
+			close(queue)
+		}
+	})
+	d.wg.Wait()
+}
+
+// dispatch queues a handler call for the event i on the worker of its key,
+// following the overflow policy when its queue is full.
+func (d *Dispatcher) dispatch(i any, job dispatchJob) {
+	queue := d.workers[d.worker(i)]
+
+	switch d.Overflow {
+	case OverflowDropNewest:
+		select {
+		case queue <- job:
+		default:
+			d.dropped.Add(1)
+			job.drop()
+		}
+
+	case OverflowDropOldest:
+		for {
+			select {
+			case queue <- job:
+				return
+			default:
+			}
+
+			select {
+			case old := <-queue:
+				d.dropped.Add(1)
+				old.drop()
+			default:
+			}
+		}
+
+	default:
+		queue <- job
+	}
+}
+
+// worker returns the index of the worker of the event i.
+func (d *Dispatcher) worker(i any) int {
+	keyOf := d.Key
+	if keyOf == nil {
+		keyOf = DefaultEventKey
+	}
+
+	key := keyOf(i)
+	if key == "" {
+		return int(d.next.Add(1) % uint32(len(d.workers)))
+	}
+
+	// FNV-1a
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return int(h % uint32(len(d.workers)))
+}
+
+// DefaultEventKey is the default Dispatcher key of an event: the ID of its
+// channel, or of its guild when it is not in a channel. Events without
+// either, like Ready, have no key. The key of an *Event is the key of its
+// Struct.
+func DefaultEventKey(i any) string {
+	ids := eventIDsOf(i)
+	if ids.channelID != "" {
+		return ids.channelID
+	}
+	return ids.guildID
+}
@@ -0,0 +1,128 @@
+package discordgo
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcherOrder(t *testing.T) {
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+	s.Dispatcher = NewDispatcher(4, 8)
+	defer s.Dispatcher.Close()
+
+	var mu sync.Mutex
+	seen := make(map[string][]string)
+	record := func(channelID, id string) {
+		// Give other workers a chance to run out of order.
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		seen[channelID] = append(seen[channelID], id)
+		mu.Unlock()
+	}
+	s.AddHandler(func(s *Session, m *MessageCreate) { record(m.ChannelID, "create "+m.ID) })
+	s.AddHandler(func(s *Session, m *MessageUpdate) { record(m.ChannelID, "update "+m.ID) })
+
+	var want []string
+	for i := range 20 {
+		id := strconv.Itoa(i)
+		want = append(want, "create "+id, "update "+id)
+		for _, channelID := range []string{"1", "2", "3"} {
+			s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: id, ChannelID: channelID}})
+			s.handleEvent(messageUpdateEventType, &MessageUpdate{Message: &Message{ID: id, ChannelID: channelID}})
+		}
+	}
+	s.handlersWg.Wait()
+
+	for _, channelID := range []string{"1", "2", "3"} {
+		got := seen[channelID]
+		if len(got) != len(want) {
+			t.Fatalf("channel %s: expected %d events, got %d", channelID, len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("channel %s: expected %q at %d, got %q", channelID, want[i], i, got[i])
+			}
+		}
+	}
+
+	// The workers count the handler calls once they returned.
+	s.Dispatcher.Close()
+	if stats := s.Dispatcher.Stats(); stats.Handled != uint64(3*len(want)) || stats.Dropped != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestDispatcherOverflow(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		overflow OverflowPolicy
+		handled  []string
+	}{
+		{"DropNewest", OverflowDropNewest, []string{"1", "2"}},
+		{"DropOldest", OverflowDropOldest, []string{"1", "3"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := New("")
+			if err != nil {
+				t.Fatalf("New returned error: %+v", err)
+			}
+			s.Dispatcher = NewDispatcher(1, 1)
+			s.Dispatcher.Overflow = tc.overflow
+			defer s.Dispatcher.Close()
+
+			started := make(chan struct{}, 1)
+			release := make(chan struct{})
+			var handled []string
+			s.AddHandler(func(s *Session, m *MessageCreate) {
+				started <- struct{}{}
+				<-release
+				handled = append(handled, m.ID)
+			})
+
+			// The first event runs, the second waits in the queue, and the
+			// third overflows.
+			s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: "1"}})
+			<-started
+			s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: "2"}})
+			s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: "3"}})
+
+			stats := s.Dispatcher.Stats()
+			if stats.Dropped != 1 || len(stats.Queued) != 1 || stats.Queued[0] != 1 {
+				t.Errorf("unexpected stats %+v", stats)
+			}
+
+			close(release)
+			s.handlersWg.Wait()
+
+			if len(handled) != len(tc.handled) || handled[0] != tc.handled[0] || handled[1] != tc.handled[1] {
+				t.Errorf("expected %v to be handled, got %v", tc.handled, handled)
+			}
+		})
+	}
+}
+
+func TestDefaultEventKey(t *testing.T) {
+	for _, tc := range []struct {
+		event any
+		key   string
+	}{
+		{&MessageCreate{&Message{ChannelID: "1", GuildID: "2"}}, "1"},
+		{&MessageCreate{}, ""},
+		{&GuildMemberAdd{&Member{GuildID: "2"}}, "2"},
+		{&GuildCreate{Guild{ID: "2"}}, "2"},
+		{&ChannelUpdate{Channel: &Channel{ID: "3", GuildID: "2"}}, "3"},
+		{&Event{Struct: &TypingStart{ChannelID: "1"}}, "1"},
+		{&Ready{}, ""},
+		{"not an event", ""},
+	} {
+		if key := DefaultEventKey(tc.event); key != tc.key {
+			t.Errorf("%T: expected key %q, got %q", tc.event, tc.key, key)
+		}
+	}
+}
@@ -0,0 +1,1659 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains an encoder and decoder for the Erlang External Term
+// Format, which the gateway uses when connecting with encoding=etf.
+
+package discordgo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// GatewayEncoding is the encoding of the payloads of a gateway connection.
+// https://discord.com/developers/docs/topics/gateway#encoding-and-compression
+type GatewayEncoding string
+
+// Valid GatewayEncoding values
+const (
+	// GatewayEncodingJSON encodes payloads as JSON. It is used when no
+	// encoding is set.
+	GatewayEncodingJSON GatewayEncoding = "json"
+	// GatewayEncodingETF encodes payloads in the Erlang External Term Format,
+	// which is smaller and faster to decode than JSON. Snowflakes are sent as
+	// integers, and decoded into the same string fields.
+	GatewayEncodingETF GatewayEncoding = "etf"
+)
+
+// ETF term tags.
+// https://www.erlang.org/doc/apps/erts/erl_ext_dist.html
+const (
+	etfVersion       = 131
+	etfNewFloat      = 70
+	etfBitBinary     = 77
+	etfCompressed    = 80
+	etfSmallInteger  = 97
+	etfInteger       = 98
+	etfFloat         = 99
+	etfAtom          = 100
+	etfSmallTuple    = 104
+	etfLargeTuple    = 105
+	etfNil           = 106
+	etfString        = 107
+	etfList          = 108
+	etfBinary        = 109
+	etfSmallBig      = 110
+	etfLargeBig      = 111
+	etfSmallAtom     = 115
+	etfMap           = 116
+	etfAtomUTF8      = 118
+	etfSmallAtomUTF8 = 119
+)
+
+// maxSafeInteger is the largest integer a float64 holds exactly. Larger
+// integers are decoded as strings where no type says otherwise, as they can
+// only be snowflakes, which JSON sends as strings.
+const maxSafeInteger = 1 << 53
+
+var errETFUnexpectedEnd = errors.New("etf: unexpected end of data")
+
+// MarshalETF returns the ETF encoding of v. Values are encoded following
+// the same rules, and the same struct tags, as Marshal. Types which
+// implement json.Marshaler are encoded from the JSON they return.
+func MarshalETF(v any) ([]byte, error) {
+	e := etfEncoder{buf: []byte{etfVersion}}
+	if err := e.encode(reflect.ValueOf(v), false); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// UnmarshalETF decodes the ETF encoded data into the value pointed to by v,
+// following the same rules, and the same struct tags, as Unmarshal.
+// Integers are also accepted for string fields, as ETF payloads hold
+// snowflakes as integers. Types which implement json.Unmarshaler are decoded
+// from the term converted to JSON.
+func UnmarshalETF(data []byte, v any) error {
+	d, err := newETFDecoder(data)
+	if err != nil {
+		return err
+	}
+	h, err := d.header()
+	if err != nil {
+		return err
+	}
+	return d.decode(h, v)
+}
+
+// etfKind is the kind of a decoded term.
+type etfKind uint8
+
+const (
+	etfKindNil etfKind = iota
+	etfKindBool
+	etfKindInt
+	etfKindFloat
+	etfKindString
+	etfKindList
+	etfKindMap
+)
+
+func (k etfKind) String() string {
+	return [...]string{"nil", "boolean", "integer", "float", "string", "list", "map"}[k]
+}
+
+// An etfHeader is the start of a term. Scalar terms are held entirely by
+// their header, while the elements of lists and maps follow it.
+type etfHeader struct {
+	kind etfKind
+
+	b bool
+
+	// Integers are held as their sign and magnitude, which is either in u,
+	// or in big as little-endian bytes when it is larger than 64 bits.
+	neg bool
+	u   uint64
+	big []byte
+
+	f float64
+
+	// s holds strings, and the name of atoms.
+	s []byte
+	// chars is set for strings sent as a list of bytes.
+	chars bool
+
+	// n is the number of elements of a list, or pairs of a map.
+	n int
+	// tail is set for lists which are followed by a tail term.
+	tail bool
+}
+
+// int64 returns the value of an integer term, and whether it fits.
+func (h *etfHeader) int64() (int64, bool) {
+	if h.big != nil || h.u > 1<<63 || !h.neg && h.u == 1<<63 {
+		return 0, false
+	}
+	if h.neg {
+		return -int64(h.u), true
+	}
+	return int64(h.u), true
+}
+
+// uint64 returns the value of an integer term, and whether it fits.
+func (h *etfHeader) uint64() (uint64, bool) {
+	if h.big != nil || h.neg {
+		return 0, false
+	}
+	return h.u, true
+}
+
+// safe reports whether an integer term is held exactly by a float64.
+func (h *etfHeader) safe() bool {
+	return h.big == nil && h.u <= maxSafeInteger
+}
+
+// float64 returns the value of an integer term as a float64.
+func (h *etfHeader) float64() float64 {
+	var f float64
+	if h.big != nil {
+		f, _ = new(big.Float).SetInt(h.bigInt()).Float64()
+		return f
+	}
+	f = float64(h.u)
+	if h.neg {
+		f = -f
+	}
+	return f
+}
+
+// bigInt returns the value of an integer term as a big.Int.
+func (h *etfHeader) bigInt() *big.Int {
+	i := new(big.Int)
+	if h.big != nil {
+		be := slices.Clone(h.big)
+		slices.Reverse(be)
+		i.SetBytes(be)
+	} else {
+		i.SetUint64(h.u)
+	}
+	if h.neg {
+		i.Neg(i)
+	}
+	return i
+}
+
+// appendInt appends the decimal form of an integer term to b.
+func (h *etfHeader) appendInt(b []byte) []byte {
+	if h.big != nil {
+		return h.bigInt().Append(b, 10)
+	}
+	if h.neg {
+		b = append(b, '-')
+	}
+	return strconv.AppendUint(b, h.u, 10)
+}
+
+// An etfDecoder reads terms from ETF encoded data.
+type etfDecoder struct {
+	data []byte
+	off  int
+}
+
+// newETFDecoder returns a decoder for the term held by data, which starts
+// with the version byte.
+func newETFDecoder(data []byte) (*etfDecoder, error) {
+	if len(data) == 0 {
+		return nil, errETFUnexpectedEnd
+	}
+	if data[0] != etfVersion {
+		return nil, fmt.Errorf("etf: unknown version %d", data[0])
+	}
+	data = data[1:]
+
+	if len(data) > 0 && data[0] == etfCompressed {
+		if len(data) < 5 {
+			return nil, errETFUnexpectedEnd
+		}
+		size := int64(binary.BigEndian.Uint32(data[1:5]))
+		z, err := zlib.NewReader(bytes.NewReader(data[5:]))
+		if err != nil {
+			return nil, err
+		}
+		data, err = io.ReadAll(io.LimitReader(z, size))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) != size {
+			return nil, errETFUnexpectedEnd
+		}
+	}
+
+	return &etfDecoder{data: data}, nil
+}
+
+func (d *etfDecoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.off < n {
+		return nil, errETFUnexpectedEnd
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+func (d *etfDecoder) readByte() (byte, error) {
+	if d.off >= len(d.data) {
+		return 0, errETFUnexpectedEnd
+	}
+	c := d.data[d.off]
+	d.off++
+	return c, nil
+}
+
+func (d *etfDecoder) readUint16() (int, error) {
+	b, err := d.next(2)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(b)), nil
+}
+
+func (d *etfDecoder) readUint32() (int, error) {
+	b, err := d.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+// length reads the length of a list or map, which can not be more than the
+// number of bytes left, as every term takes at least one.
+func (d *etfDecoder) length(large bool, terms int) (int, error) {
+	var n int
+	var err error
+	if large {
+		n, err = d.readUint32()
+	} else {
+		var c byte
+		c, err = d.readByte()
+		n = int(c)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if n > (len(d.data)-d.off)/terms {
+		return 0, errETFUnexpectedEnd
+	}
+	return n, nil
+}
+
+// header reads the start of the next term.
+func (d *etfDecoder) header() (h etfHeader, err error) {
+	tag, err := d.readByte()
+	if err != nil {
+		return h, err
+	}
+
+	var b []byte
+	switch tag {
+	case etfSmallInteger:
+		h.kind = etfKindInt
+		var c byte
+		c, err = d.readByte()
+		h.u = uint64(c)
+
+	case etfInteger:
+		h.kind = etfKindInt
+		if b, err = d.next(4); err == nil {
+			v := int64(int32(binary.BigEndian.Uint32(b)))
+			if v < 0 {
+				h.neg, h.u = true, uint64(-v)
+			} else {
+				h.u = uint64(v)
+			}
+		}
+
+	case etfSmallBig, etfLargeBig:
+		h.kind = etfKindInt
+		var n int
+		if tag == etfSmallBig {
+			var c byte
+			c, err = d.readByte()
+			n = int(c)
+		} else {
+			n, err = d.readUint32()
+		}
+		if err != nil {
+			return h, err
+		}
+		var sign byte
+		if sign, err = d.readByte(); err != nil {
+			return h, err
+		}
+		if b, err = d.next(n); err != nil {
+			return h, err
+		}
+		for len(b) > 0 && b[len(b)-1] == 0 {
+			b = b[:len(b)-1]
+		}
+		if len(b) > 8 {
+			h.big = b
+		} else {
+			for i := len(b) - 1; i >= 0; i-- {
+				h.u = h.u<<8 | uint64(b[i])
+			}
+		}
+		h.neg = sign != 0 && len(b) > 0
+
+	case etfNewFloat:
+		h.kind = etfKindFloat
+		if b, err = d.next(8); err == nil {
+			h.f = math.Float64frombits(binary.BigEndian.Uint64(b))
+		}
+
+	case etfFloat:
+		h.kind = etfKindFloat
+		if b, err = d.next(31); err == nil {
+			h.f, err = strconv.ParseFloat(string(bytes.TrimRight(b, "\x00")), 64)
+		}
+
+	case etfAtom, etfAtomUTF8, etfSmallAtom, etfSmallAtomUTF8:
+		var n int
+		if tag == etfAtom || tag == etfAtomUTF8 {
+			n, err = d.readUint16()
+		} else {
+			var c byte
+			c, err = d.readByte()
+			n = int(c)
+		}
+		if err != nil {
+			return h, err
+		}
+		if h.s, err = d.next(n); err != nil {
+			return h, err
+		}
+		switch string(h.s) {
+		case "nil":
+			h.kind = etfKindNil
+		case "true", "false":
+			h.kind = etfKindBool
+			h.b = h.s[0] == 't'
+		default:
+			h.kind = etfKindString
+		}
+
+	case etfBinary:
+		h.kind = etfKindString
+		var n int
+		if n, err = d.readUint32(); err == nil {
+			h.s, err = d.next(n)
+		}
+
+	case etfBitBinary:
+		h.kind = etfKindString
+		var n int
+		if n, err = d.readUint32(); err != nil {
+			return h, err
+		}
+		if _, err = d.readByte(); err == nil {
+			h.s, err = d.next(n)
+		}
+
+	case etfString:
+		h.kind = etfKindString
+		h.chars = true
+		var n int
+		if n, err = d.readUint16(); err == nil {
+			h.s, err = d.next(n)
+		}
+
+	case etfNil:
+		h.kind = etfKindList
+
+	case etfList:
+		h.kind = etfKindList
+		h.tail = true
+		h.n, err = d.length(true, 1)
+
+	case etfSmallTuple, etfLargeTuple:
+		h.kind = etfKindList
+		h.n, err = d.length(tag == etfLargeTuple, 1)
+
+	case etfMap:
+		h.kind = etfKindMap
+		h.n, err = d.length(true, 2)
+
+	default:
+		err = fmt.Errorf("etf: unsupported term tag %d", tag)
+	}
+
+	return h, err
+}
+
+// listEnd reads what follows the elements of a list.
+func (d *etfDecoder) listEnd(h etfHeader) error {
+	if h.tail {
+		return d.skip()
+	}
+	return nil
+}
+
+// skip skips the next term.
+func (d *etfDecoder) skip() error {
+	h, err := d.header()
+	if err != nil {
+		return err
+	}
+	return d.skipBody(h)
+}
+
+// skipBody skips the elements of a list or map.
+func (d *etfDecoder) skipBody(h etfHeader) error {
+	switch h.kind {
+	case etfKindList:
+		for i := 0; i < h.n; i++ {
+			if err := d.skip(); err != nil {
+				return err
+			}
+		}
+		return d.listEnd(h)
+	case etfKindMap:
+		for i := 0; i < 2*h.n; i++ {
+			if err := d.skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// key reads a map key, which is returned as the string JSON would hold.
+// The result is only valid until the next call.
+func (d *etfDecoder) key(buf []byte) ([]byte, error) {
+	h, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	switch h.kind {
+	case etfKindNil, etfKindBool, etfKindString:
+		if !h.chars {
+			return h.s, nil
+		}
+	case etfKindInt:
+		return h.appendInt(buf[:0]), nil
+	}
+	return nil, fmt.Errorf("etf: invalid map key of kind %s", h.kind)
+}
+
+// decode decodes the term starting with h into the value pointed to by v.
+func (d *etfDecoder) decode(h etfHeader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("etf: decode into non-pointer %T", v)
+	}
+	return etfDecoderFor(rv.Type().Elem())(d, h, rv.Elem())
+}
+
+// any decodes the term starting with h into the value JSON would hold.
+func (d *etfDecoder) any(h etfHeader) (any, error) {
+	switch h.kind {
+	case etfKindNil:
+		return nil, nil
+	case etfKindBool:
+		return h.b, nil
+	case etfKindInt:
+		if h.safe() {
+			return h.float64(), nil
+		}
+		return string(h.appendInt(nil)), nil
+	case etfKindFloat:
+		return h.f, nil
+	case etfKindString:
+		if h.chars {
+			l := make([]any, len(h.s))
+			for i, c := range h.s {
+				l[i] = float64(c)
+			}
+			return l, nil
+		}
+		return string(h.s), nil
+	case etfKindList:
+		l := make([]any, h.n)
+		for i := range l {
+			eh, err := d.header()
+			if err != nil {
+				return nil, err
+			}
+			if l[i], err = d.any(eh); err != nil {
+				return nil, err
+			}
+		}
+		return l, d.listEnd(h)
+	}
+
+	m := make(map[string]any, h.n)
+	var buf [24]byte
+	for i := 0; i < h.n; i++ {
+		k, err := d.key(buf[:])
+		if err != nil {
+			return nil, err
+		}
+		key := string(k)
+		vh, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		if m[key], err = d.any(vh); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// appendJSON appends the JSON form of the term starting with h to b.
+// Integers too large for a float64 are written as strings.
+func (d *etfDecoder) appendJSON(b []byte, h etfHeader) ([]byte, error) {
+	var err error
+	switch h.kind {
+	case etfKindNil:
+		return append(b, "null"...), nil
+	case etfKindBool:
+		return strconv.AppendBool(b, h.b), nil
+	case etfKindInt:
+		if h.safe() {
+			return h.appendInt(b), nil
+		}
+		b = append(b, '"')
+		return append(h.appendInt(b), '"'), nil
+	case etfKindFloat:
+		if math.IsNaN(h.f) || math.IsInf(h.f, 0) {
+			return nil, fmt.Errorf("etf: unsupported float value %v", h.f)
+		}
+		return strconv.AppendFloat(b, h.f, 'g', -1, 64), nil
+	case etfKindString:
+		if h.chars {
+			b = append(b, '[')
+			for i, c := range h.s {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = strconv.AppendUint(b, uint64(c), 10)
+			}
+			return append(b, ']'), nil
+		}
+		return appendJSONString(b, h.s), nil
+	case etfKindList:
+		b = append(b, '[')
+		for i := 0; i < h.n; i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			eh, err := d.header()
+			if err != nil {
+				return nil, err
+			}
+			if b, err = d.appendJSON(b, eh); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), d.listEnd(h)
+	}
+
+	b = append(b, '{')
+	var buf [24]byte
+	for i := 0; i < h.n; i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		k, err := d.key(buf[:])
+		if err != nil {
+			return nil, err
+		}
+		b = append(appendJSONString(b, k), ':')
+		vh, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		if b, err = d.appendJSON(b, vh); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), err
+}
+
+// appendJSONString appends s to b as a quoted JSON string.
+func appendJSONString(b, s []byte) []byte {
+	const hex = "0123456789abcdef"
+
+	b = append(b, '"')
+	start := 0
+	for i, c := range s {
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+		b = append(b, s[start:i]...)
+		switch c {
+		case '"', '\\':
+			b = append(b, '\\', c)
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\r':
+			b = append(b, '\\', 'r')
+		case '\t':
+			b = append(b, '\\', 't')
+		default:
+			b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		}
+		start = i + 1
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+// etfToJSON converts a term, without the version byte, to JSON.
+func etfToJSON(term []byte) ([]byte, error) {
+	d := etfDecoder{data: term}
+	h, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	return d.appendJSON(nil, h)
+}
+
+// An etfUnmarshaler decodes itself from a term. It is implemented by types
+// which implement json.Unmarshaler, and are common enough in gateway events
+// not to be decoded from JSON.
+type etfUnmarshaler interface {
+	unmarshalETF(d *etfDecoder, h etfHeader) error
+}
+
+// An etfTypeError describes a term which can not be decoded into a Go type.
+type etfTypeError struct {
+	kind etfKind
+	typ  reflect.Type
+}
+
+func (e *etfTypeError) Error() string {
+	return "etf: cannot unmarshal " + e.kind.String() + " into Go value of type " + e.typ.String()
+}
+
+var (
+	etfUnmarshalerType  = reflect.TypeFor[etfUnmarshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonNumberType      = reflect.TypeFor[json.Number]()
+)
+
+// An etfDecodeFunc decodes the term starting with h into v, which is
+// addressable.
+type etfDecodeFunc func(d *etfDecoder, h etfHeader, v reflect.Value) error
+
+// etfDecoders caches the etfDecodeFunc of each type.
+var etfDecoders sync.Map
+
+// etfDecoderFor returns the etfDecodeFunc for t.
+func etfDecoderFor(t reflect.Type) etfDecodeFunc {
+	if f, ok := etfDecoders.Load(t); ok {
+		return f.(etfDecodeFunc)
+	}
+
+	// Recursive types get an indirect func, which waits for the real one
+	// to be built.
+	var (
+		wg sync.WaitGroup
+		f  etfDecodeFunc
+	)
+	wg.Add(1)
+	fi, loaded := etfDecoders.LoadOrStore(t, etfDecodeFunc(func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+		wg.Wait()
+		return f(d, h, v)
+	}))
+	if loaded {
+		return fi.(etfDecodeFunc)
+	}
+
+	f = newETFDecodeFunc(t)
+	wg.Done()
+	etfDecoders.Store(t, f)
+	return f
+}
+
+func newETFDecodeFunc(t reflect.Type) etfDecodeFunc {
+	if t.Kind() != reflect.Pointer {
+		pt := reflect.PointerTo(t)
+		switch {
+		case pt.Implements(etfUnmarshalerType):
+			return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+				if h.kind == etfKindNil {
+					return nil
+				}
+				return v.Addr().Interface().(etfUnmarshaler).unmarshalETF(d, h)
+			}
+
+		case pt.Implements(jsonUnmarshalerType):
+			text := pt.Implements(textUnmarshalerType)
+			return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+				if h.kind == etfKindNil {
+					return nil
+				}
+				if text && h.kind == etfKindString && !h.chars {
+					return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(h.s)
+				}
+				data, err := d.appendJSON(nil, h)
+				if err != nil {
+					return err
+				}
+				return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data)
+			}
+
+		case pt.Implements(textUnmarshalerType):
+			kind := newETFKindDecodeFunc(t)
+			return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+				if h.kind == etfKindString && !h.chars {
+					return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(h.s)
+				}
+				return kind(d, h, v)
+			}
+		}
+	}
+
+	return newETFKindDecodeFunc(t)
+}
+
+func newETFKindDecodeFunc(t reflect.Type) etfDecodeFunc {
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := etfDecoderFor(t.Elem())
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			if h.kind == etfKindNil {
+				v.SetZero()
+				return nil
+			}
+			if v.IsNil() {
+				v.Set(reflect.New(t.Elem()))
+			}
+			return elem(d, h, v.Elem())
+		}
+
+	case reflect.Interface:
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			if h.kind == etfKindNil {
+				v.SetZero()
+				return nil
+			}
+			if t.NumMethod() != 0 {
+				return &etfTypeError{h.kind, t}
+			}
+			a, err := d.any(h)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(a))
+			return nil
+		}
+
+	case reflect.Struct:
+		return newETFStructDecodeFunc(t)
+
+	case reflect.Map:
+		return newETFMapDecodeFunc(t)
+
+	case reflect.Slice:
+		return newETFSliceDecodeFunc(t)
+
+	case reflect.Array:
+		elem := etfDecoderFor(t.Elem())
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			if h.kind == etfKindNil {
+				return nil
+			}
+			if h.kind != etfKindList {
+				return &etfTypeError{h.kind, t}
+			}
+			for i := 0; i < h.n; i++ {
+				eh, err := d.header()
+				if err != nil {
+					return err
+				}
+				if i >= v.Len() {
+					err = d.skipBody(eh)
+				} else {
+					err = elem(d, eh, v.Index(i))
+				}
+				if err != nil {
+					return err
+				}
+			}
+			for i := h.n; i < v.Len(); i++ {
+				v.Index(i).SetZero()
+			}
+			return d.listEnd(h)
+		}
+
+	case reflect.String:
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			switch h.kind {
+			case etfKindNil:
+			case etfKindString:
+				v.SetString(string(h.s))
+			case etfKindInt:
+				var buf [24]byte
+				v.SetString(string(h.appendInt(buf[:0])))
+			default:
+				return &etfTypeError{h.kind, t}
+			}
+			return nil
+		}
+
+	case reflect.Bool:
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			switch h.kind {
+			case etfKindNil:
+			case etfKindBool:
+				v.SetBool(h.b)
+			default:
+				return &etfTypeError{h.kind, t}
+			}
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			var n int64
+			ok := false
+			switch h.kind {
+			case etfKindNil:
+				return nil
+			case etfKindInt:
+				n, ok = h.int64()
+			case etfKindFloat:
+				n = int64(h.f)
+				ok = float64(n) == h.f
+			default:
+				return &etfTypeError{h.kind, t}
+			}
+			if !ok || v.OverflowInt(n) {
+				return fmt.Errorf("etf: number does not fit Go value of type %s", t)
+			}
+			v.SetInt(n)
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			var n uint64
+			ok := false
+			switch h.kind {
+			case etfKindNil:
+				return nil
+			case etfKindInt:
+				n, ok = h.uint64()
+			case etfKindFloat:
+				n = uint64(h.f)
+				ok = h.f >= 0 && float64(n) == h.f
+			default:
+				return &etfTypeError{h.kind, t}
+			}
+			if !ok || v.OverflowUint(n) {
+				return fmt.Errorf("etf: number does not fit Go value of type %s", t)
+			}
+			v.SetUint(n)
+			return nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			switch h.kind {
+			case etfKindNil:
+			case etfKindInt:
+				v.SetFloat(h.float64())
+			case etfKindFloat:
+				v.SetFloat(h.f)
+			default:
+				return &etfTypeError{h.kind, t}
+			}
+			return nil
+		}
+	}
+
+	return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+		return &etfTypeError{h.kind, t}
+	}
+}
+
+func newETFSliceDecodeFunc(t reflect.Type) etfDecodeFunc {
+	isBytes := t.Elem().Kind() == reflect.Uint8
+	elem := etfDecoderFor(t.Elem())
+
+	return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+		switch h.kind {
+		case etfKindNil:
+			v.SetZero()
+			return nil
+
+		case etfKindString:
+			switch {
+			case isBytes:
+				s := reflect.MakeSlice(t, len(h.s), len(h.s))
+				reflect.Copy(s, reflect.ValueOf(h.s))
+				v.Set(s)
+			case h.chars:
+				s := reflect.MakeSlice(t, len(h.s), len(h.s))
+				for i, c := range h.s {
+					if err := elem(d, etfHeader{kind: etfKindInt, u: uint64(c)}, s.Index(i)); err != nil {
+						return err
+					}
+				}
+				v.Set(s)
+			default:
+				return &etfTypeError{h.kind, t}
+			}
+			return nil
+
+		case etfKindList:
+			s := reflect.MakeSlice(t, h.n, h.n)
+			for i := 0; i < h.n; i++ {
+				eh, err := d.header()
+				if err != nil {
+					return err
+				}
+				if err = elem(d, eh, s.Index(i)); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return d.listEnd(h)
+		}
+
+		return &etfTypeError{h.kind, t}
+	}
+}
+
+func newETFMapDecodeFunc(t reflect.Type) etfDecodeFunc {
+	kt := t.Key()
+	elem := etfDecoderFor(t.Elem())
+
+	var key func(k []byte) (reflect.Value, error)
+	switch {
+	case kt.Kind() == reflect.String:
+		key = func(k []byte) (reflect.Value, error) {
+			return reflect.ValueOf(string(k)).Convert(kt), nil
+		}
+	case reflect.PointerTo(kt).Implements(textUnmarshalerType):
+		key = func(k []byte) (reflect.Value, error) {
+			kv := reflect.New(kt)
+			err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText(k)
+			return kv.Elem(), err
+		}
+	case kt.Kind() >= reflect.Int && kt.Kind() <= reflect.Int64:
+		key = func(k []byte) (reflect.Value, error) {
+			n, err := strconv.ParseInt(string(k), 10, kt.Bits())
+			return reflect.ValueOf(n).Convert(kt), err
+		}
+	case kt.Kind() >= reflect.Uint && kt.Kind() <= reflect.Uintptr:
+		key = func(k []byte) (reflect.Value, error) {
+			n, err := strconv.ParseUint(string(k), 10, kt.Bits())
+			return reflect.ValueOf(n).Convert(kt), err
+		}
+	default:
+		return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+			return &etfTypeError{h.kind, t}
+		}
+	}
+
+	return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+		if h.kind == etfKindNil {
+			v.SetZero()
+			return nil
+		}
+		if h.kind != etfKindMap {
+			return &etfTypeError{h.kind, t}
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, h.n))
+		}
+
+		var buf [24]byte
+		for i := 0; i < h.n; i++ {
+			k, err := d.key(buf[:])
+			if err != nil {
+				return err
+			}
+			kv, err := key(k)
+			if err != nil {
+				return err
+			}
+			vh, err := d.header()
+			if err != nil {
+				return err
+			}
+			ev := reflect.New(t.Elem()).Elem()
+			if err = elem(d, vh, ev); err != nil {
+				return err
+			}
+			v.SetMapIndex(kv, ev)
+		}
+		return nil
+	}
+}
+
+func newETFStructDecodeFunc(t reflect.Type) etfDecodeFunc {
+	type fieldDecoder struct {
+		*etfField
+		decode etfDecodeFunc
+	}
+
+	info := etfStructFields(t)
+	fields := make(map[string]fieldDecoder, len(info.list))
+	folded := make(map[string]fieldDecoder, len(info.list))
+	for _, f := range info.list {
+		fd := fieldDecoder{f, etfDecoderFor(f.typ)}
+		fields[f.name] = fd
+		if _, ok := folded[strings.ToLower(f.name)]; !ok {
+			folded[strings.ToLower(f.name)] = fd
+		}
+	}
+
+	return func(d *etfDecoder, h etfHeader, v reflect.Value) error {
+		if h.kind == etfKindNil {
+			return nil
+		}
+		if h.kind != etfKindMap {
+			return &etfTypeError{h.kind, t}
+		}
+
+		var buf [24]byte
+		for i := 0; i < h.n; i++ {
+			k, err := d.key(buf[:])
+			if err != nil {
+				return err
+			}
+			f, ok := fields[string(k)]
+			if !ok {
+				// Keys match fields case insensitively, like they do in JSON.
+				f, ok = folded[strings.ToLower(string(k))]
+			}
+
+			vh, err := d.header()
+			if err != nil {
+				return err
+			}
+
+			var fv reflect.Value
+			if ok {
+				fv = etfFieldByIndex(v, f.index, true)
+			}
+			switch {
+			case !fv.IsValid():
+				err = d.skipBody(vh)
+			case f.quoted && vh.kind == etfKindString:
+				err = decodeETFQuoted(vh, fv)
+			default:
+				err = f.decode(d, vh, fv)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// decodeETFQuoted decodes a string into a field with the ",string" option.
+func decodeETFQuoted(h etfHeader, v reflect.Value) error {
+	s := string(h.s)
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		v.SetString(s)
+	}
+	return nil
+}
+
+// etfFieldByIndex returns the field of v at index. Nil embedded pointers are
+// allocated when alloc is set, otherwise the zero Value is returned for
+// fields behind them, as it is when they can not be allocated.
+func etfFieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// An etfField is a struct field, as it is named in JSON.
+type etfField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	depth     int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+// etfStruct holds the fields of a struct type.
+type etfStruct struct {
+	list []*etfField
+}
+
+// etfStructs caches the etfStruct of each type.
+var etfStructs sync.Map
+
+// etfStructFields returns the fields of a struct type, following the rules
+// encoding/json uses for json tags and embedded structs.
+func etfStructFields(t reflect.Type) *etfStruct {
+	if s, ok := etfStructs.Load(t); ok {
+		return s.(*etfStruct)
+	}
+
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []*etfField
+	visited := map[reflect.Type]bool{}
+	next := []embedded{{t, nil}}
+	for depth := 0; len(next) > 0; depth++ {
+		current := next
+		next = nil
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(e.index[:len(e.index):len(e.index)], i)
+
+				ft := sf.Type
+				if sf.Anonymous {
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+					if name == "" && ft.Kind() == reflect.Struct {
+						next = append(next, embedded{ft, index})
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				f := &etfField{name: name, index: index, typ: sf.Type, depth: depth, tagged: name != ""}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				for _, opt := range strings.Split(opts, ",") {
+					switch opt {
+					case "omitempty":
+						f.omitEmpty = true
+					case "string":
+						switch ft.Kind() {
+						case reflect.Bool, reflect.String,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64:
+							f.quoted = true
+						}
+					}
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	// Of the fields sharing a name, the shallowest wins, then a tagged one.
+	// Fields which are still ambiguous are dropped.
+	slices.SortStableFunc(fields, func(a, b *etfField) int {
+		if a.name != b.name {
+			return strings.Compare(a.name, b.name)
+		}
+		if a.depth != b.depth {
+			return a.depth - b.depth
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	s := &etfStruct{}
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if j-i == 1 || fields[i].depth != fields[i+1].depth || fields[i].tagged != fields[i+1].tagged {
+			s.list = append(s.list, fields[i])
+		}
+		i = j
+	}
+	slices.SortFunc(s.list, func(a, b *etfField) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	actual, _ := etfStructs.LoadOrStore(t, s)
+	return actual.(*etfStruct)
+}
+
+// An etfEncoder encodes values as terms.
+type etfEncoder struct {
+	buf []byte
+}
+
+func (e *etfEncoder) atom(name string) {
+	e.buf = append(e.buf, etfSmallAtomUTF8, byte(len(name)))
+	e.buf = append(e.buf, name...)
+}
+
+func (e *etfEncoder) bool(b bool) {
+	if b {
+		e.atom("true")
+	} else {
+		e.atom("false")
+	}
+}
+
+func (e *etfEncoder) int(n int64) {
+	switch {
+	case n >= 0 && n <= math.MaxUint8:
+		e.buf = append(e.buf, etfSmallInteger, byte(n))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		e.buf = append(e.buf, etfInteger)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	case n < 0:
+		e.big(true, uint64(-n))
+	default:
+		e.big(false, uint64(n))
+	}
+}
+
+func (e *etfEncoder) uint(n uint64) {
+	if n <= math.MaxInt32 {
+		e.int(int64(n))
+	} else {
+		e.big(false, n)
+	}
+}
+
+func (e *etfEncoder) big(neg bool, n uint64) {
+	var digits []byte
+	for ; n > 0; n >>= 8 {
+		digits = append(digits, byte(n))
+	}
+	var sign byte
+	if neg {
+		sign = 1
+	}
+	e.buf = append(e.buf, etfSmallBig, byte(len(digits)), sign)
+	e.buf = append(e.buf, digits...)
+}
+
+func (e *etfEncoder) float(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("etf: unsupported float value %v", f)
+	}
+	e.buf = append(e.buf, etfNewFloat)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(f))
+	return nil
+}
+
+func (e *etfEncoder) binary(s string) {
+	e.buf = append(e.buf, etfBinary)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// encode encodes v. quoted is set for fields with the ",string" option.
+func (e *etfEncoder) encode(v reflect.Value, quoted bool) error {
+	if !v.IsValid() {
+		e.atom("nil")
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			e.atom("nil")
+			return nil
+		}
+	}
+
+	t := v.Type()
+	if t == jsonNumberType {
+		return e.number(json.Number(v.String()))
+	}
+	if t.Implements(jsonMarshalerType) {
+		return e.encodeJSON(v.Interface().(json.Marshaler))
+	}
+	if v.CanAddr() && reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return e.encodeJSON(v.Addr().Interface().(json.Marshaler))
+	}
+	if t.Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		e.binary(string(text))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return e.encode(v.Elem(), quoted)
+
+	case reflect.Bool:
+		if quoted {
+			e.binary(strconv.FormatBool(v.Bool()))
+		} else {
+			e.bool(v.Bool())
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if quoted {
+			e.binary(strconv.FormatInt(v.Int(), 10))
+		} else {
+			e.int(v.Int())
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if quoted {
+			e.binary(strconv.FormatUint(v.Uint(), 10))
+		} else {
+			e.uint(v.Uint())
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if quoted {
+			e.binary(strconv.FormatFloat(v.Float(), 'g', -1, t.Bits()))
+			return nil
+		}
+		return e.float(v.Float())
+
+	case reflect.String:
+		e.binary(v.String())
+
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			e.binary(string(v.Bytes()))
+			return nil
+		}
+		if v.Len() == 0 {
+			e.buf = append(e.buf, etfNil)
+			return nil
+		}
+		e.buf = append(e.buf, etfList)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i), false); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, etfNil)
+
+	case reflect.Map:
+		return e.encodeMap(v)
+
+	case reflect.Struct:
+		return e.encodeStruct(v)
+
+	default:
+		return fmt.Errorf("etf: unsupported type %s", t)
+	}
+
+	return nil
+}
+
+// number encodes a JSON number as an integer when it is one.
+func (e *etfEncoder) number(n json.Number) error {
+	if i, err := n.Int64(); err == nil {
+		e.int(i)
+		return nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	return e.float(f)
+}
+
+// encodeJSON encodes the JSON a json.Marshaler returns.
+func (e *etfEncoder) encodeJSON(m json.Marshaler) error {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&v); err != nil {
+		return err
+	}
+	return e.encode(reflect.ValueOf(v), false)
+}
+
+func (e *etfEncoder) encodeMap(v reflect.Value) error {
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		k := iter.Key()
+		var key string
+		switch {
+		case k.Kind() == reflect.String:
+			key = k.String()
+		case k.Type().Implements(textMarshalerType):
+			text, err := k.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return err
+			}
+			key = string(text)
+		case k.CanInt():
+			key = strconv.FormatInt(k.Int(), 10)
+		case k.CanUint():
+			key = strconv.FormatUint(k.Uint(), 10)
+		default:
+			return fmt.Errorf("etf: unsupported map key type %s", k.Type())
+		}
+		entries = append(entries, entry{key, iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+
+	e.buf = append(e.buf, etfMap)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(len(entries)))
+	for _, en := range entries {
+		e.binary(en.key)
+		if err := e.encode(en.value, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *etfEncoder) encodeStruct(v reflect.Value) error {
+	info := etfStructFields(v.Type())
+
+	values := make([]reflect.Value, len(info.list))
+	n := 0
+	for i, f := range info.list {
+		fv := etfFieldByIndex(v, f.index, false)
+		if !fv.IsValid() || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		values[i] = fv
+		n++
+	}
+
+	e.buf = append(e.buf, etfMap)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	for i, f := range info.list {
+		if !values[i].IsValid() {
+			continue
+		}
+		e.binary(f.name)
+		if err := e.encode(values[i], f.quoted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isEmptyValue reports whether a field with the "omitempty" option is left
+// out, as it is by encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// decodeETFEvent decodes a gateway payload. The data of dispatch events
+// which have an event struct is kept as a term, to be decoded straight into
+// the struct; the data of other payloads is converted to JSON.
+func decodeETFEvent(data []byte) (*Event, error) {
+	d, err := newETFDecoder(data)
+	if err != nil {
+		return nil, err
+	}
+	h, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	if h.kind != etfKindMap {
+		return nil, &etfTypeError{h.kind, reflect.TypeFor[Event]()}
+	}
+
+	e := &Event{}
+	var buf [24]byte
+	for i := 0; i < h.n; i++ {
+		k, err := d.key(buf[:])
+		if err != nil {
+			return nil, err
+		}
+		key := string(k)
+
+		start := d.off
+		vh, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "op":
+			err = d.decode(vh, &e.Operation)
+		case "s":
+			err = d.decode(vh, &e.Sequence)
+		case "t":
+			err = d.decode(vh, &e.Type)
+		case "d":
+			if err = d.skipBody(vh); err == nil {
+				e.etfData = d.data[start:d.off]
+			}
+		default:
+			err = d.skipBody(vh)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := registeredInterfaceProviders[e.Type]; e.Operation != 0 || !ok {
+		if e.etfData != nil {
+			if e.RawData, err = etfToJSON(e.etfData); err != nil {
+				return nil, err
+			}
+		}
+		e.etfData = nil
+	}
+
+	return e, nil
+}
+
+// unmarshalData decodes the data of an event into v.
+func (e *Event) unmarshalData(v any) error {
+	if e.etfData == nil {
+		return json.Unmarshal(e.RawData, v)
+	}
+
+	d := etfDecoder{data: e.etfData}
+	h, err := d.header()
+	if err != nil {
+		return err
+	}
+	return d.decode(h, v)
+}
@@ -0,0 +1,441 @@
+package discordgo
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// etfFromJSON encodes a JSON document as ETF the way Discord sends the same
+// payload, with snowflakes as integers.
+func etfFromJSON(t testing.TB, data []byte) []byte {
+	t.Helper()
+
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode returned error: %+v", err)
+	}
+
+	b, err := MarshalETF(snowflakesToInts(v))
+	if err != nil {
+		t.Fatalf("MarshalETF returned error: %+v", err)
+	}
+	return b
+}
+
+// snowflakesToInts replaces the snowflakes in a decoded JSON document with
+// integers.
+func snowflakesToInts(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = snowflakesToInts(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = snowflakesToInts(e)
+		}
+	case string:
+		if len(v) >= 17 {
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return v
+}
+
+// etfFiller fills values with data for every field, which makes every
+// field of an event take part in a round trip.
+type etfFiller struct {
+	n int
+}
+
+func (f *etfFiller) fill(v reflect.Value, name string, depth int) {
+	f.n++
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if depth > 3 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		f.fill(v.Elem(), name, depth+1)
+
+	case reflect.Struct:
+		if v.Type() == reflect.TypeFor[time.Time]() {
+			v.Set(reflect.ValueOf(time.Date(2024, 1, 2, 3, 4, 5, f.n*int(time.Millisecond), time.UTC)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if !sf.IsExported() || tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = sf.Name
+			}
+			f.fill(v.Field(i), tag, depth)
+		}
+
+	case reflect.Slice:
+		if depth > 3 || v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), 2, 2)
+		for i := 0; i < s.Len(); i++ {
+			f.fill(s.Index(i), strings.TrimSuffix(name, "s"), depth+1)
+		}
+		v.Set(s)
+
+	case reflect.Map:
+		if depth > 3 {
+			return
+		}
+		k := reflect.New(v.Type().Key()).Elem()
+		f.fill(k, "id", depth+1)
+		e := reflect.New(v.Type().Elem()).Elem()
+		f.fill(e, name, depth+1)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(k, e)
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			f.fill(v.Index(i), name, depth)
+		}
+
+	case reflect.String:
+		if strings.EqualFold(name, "id") || strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "ID") {
+			v.SetString(strconv.Itoa(1100000000000000000 + f.n))
+		} else {
+			v.SetString(name + " " + strconv.Itoa(f.n))
+		}
+
+	case reflect.Bool:
+		v.SetBool(true)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(f.n % 100))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(f.n % 100))
+
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(f.n%100) + 0.5)
+	}
+}
+
+const etfPresenceUpdate = `{
+	"user": {"id": "1100000000000000001"},
+	"guild_id": "1100000000000000002",
+	"status": "online",
+	"since": 5,
+	"client_status": {"desktop": "online"},
+	"activities": [{
+		"name": "game",
+		"type": 0,
+		"created_at": 1700000000000,
+		"application_id": "1100000000000000003",
+		"timestamps": {"start": 1700000000000},
+		"emoji": {"id": "1100000000000000004", "name": "emoji"},
+		"assets": {"large_image": "image"}
+	}]
+}`
+
+// TestETFEvents checks every event struct decodes the same from ETF, with
+// snowflakes as integers, as it does from the equivalent JSON.
+func TestETFEvents(t *testing.T) {
+	types := make([]string, 0, len(registeredInterfaceProviders))
+	for eventType := range registeredInterfaceProviders {
+		types = append(types, eventType)
+	}
+	slices.Sort(types)
+
+	for _, eventType := range types {
+		eh := registeredInterfaceProviders[eventType]
+
+		t.Run(eventType, func(t *testing.T) {
+			v := eh.New()
+			var f etfFiller
+			f.fill(reflect.ValueOf(v).Elem(), "", 0)
+
+			// goccy/go-json can not encode some of the recursive event types.
+			var data []byte
+			var err error
+			switch v := v.(type) {
+			case *Ready:
+				t.Skip("READY is covered by TestETFReady")
+			case *PresenceUpdate:
+				// Activities are not encoded the way they are sent.
+				data = []byte(etfPresenceUpdate)
+			case *SessionsReplace:
+				data, err = stdjson.Marshal(v.Sessions)
+			default:
+				data, err = stdjson.Marshal(v)
+			}
+			if err != nil {
+				t.Fatalf("Marshal returned error: %+v", err)
+			}
+
+			fromJSON := eh.New()
+			if err = Unmarshal(data, fromJSON); err != nil {
+				t.Fatalf("Unmarshal returned error: %+v", err)
+			}
+
+			fromETF := eh.New()
+			if err = UnmarshalETF(etfFromJSON(t, data), fromETF); err != nil {
+				t.Fatalf("UnmarshalETF returned error: %+v", err)
+			}
+
+			if !reflect.DeepEqual(fromJSON, fromETF) {
+				j, _ := stdjson.Marshal(fromJSON)
+				e, _ := stdjson.Marshal(fromETF)
+				t.Errorf("decoded events differ\nJSON: %s\nETF:  %s", j, e)
+			}
+		})
+	}
+}
+
+func TestETFReady(t *testing.T) {
+	payload, err := os.ReadFile(filepath.Join("testdata", "gateway", "ready.json"))
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %+v", err)
+	}
+
+	var e Event
+	if err = Unmarshal(payload, &e); err != nil {
+		t.Fatalf("Unmarshal returned error: %+v", err)
+	}
+
+	var fromJSON, fromETF Ready
+	if err = Unmarshal(e.RawData, &fromJSON); err != nil {
+		t.Fatalf("Unmarshal returned error: %+v", err)
+	}
+
+	etfEvent, err := decodeETFEvent(etfFromJSON(t, payload))
+	if err != nil {
+		t.Fatalf("decodeETFEvent returned error: %+v", err)
+	}
+	if etfEvent.Type != "READY" || etfEvent.Sequence != 1 || etfEvent.RawData != nil {
+		t.Fatalf("unexpected event %+v", etfEvent)
+	}
+	if err = etfEvent.unmarshalData(&fromETF); err != nil {
+		t.Fatalf("unmarshalData returned error: %+v", err)
+	}
+
+	if fromETF.User == nil || fromETF.User.ID != fromJSON.User.ID || len(fromETF.Guilds) != len(fromJSON.Guilds) {
+		t.Fatalf("unexpected READY %+v", fromETF)
+	}
+	for i := range fromJSON.Guilds {
+		if !reflect.DeepEqual(fromJSON.Guilds[i], fromETF.Guilds[i]) {
+			t.Errorf("guild %d differs", i)
+		}
+	}
+	fromJSON.Guilds, fromETF.Guilds = nil, nil
+	if !reflect.DeepEqual(fromJSON.UserSettings.String(), fromETF.UserSettings.String()) {
+		t.Error("user settings differ")
+	}
+	fromJSON.UserSettings, fromETF.UserSettings = nil, nil
+	if !reflect.DeepEqual(fromJSON, fromETF) {
+		t.Error("decoded READY differs")
+	}
+}
+
+func TestETFRoundTrip(t *testing.T) {
+	type Embedded struct {
+		Name string `json:"name"`
+	}
+	type value struct {
+		*Embedded
+		ID        string            `json:"id"`
+		Ints      []int64           `json:"ints"`
+		Uint      uint64            `json:"uint"`
+		Float     float64           `json:"float"`
+		Bool      bool              `json:"bool"`
+		Nil       *int              `json:"nil"`
+		Empty     []string          `json:"empty"`
+		Omitted   string            `json:"omitted,omitempty"`
+		Quoted    int64             `json:"quoted,string"`
+		Map       map[string]int    `json:"map"`
+		IntKeys   map[int]string    `json:"int_keys"`
+		Time      time.Time         `json:"time"`
+		Any       any               `json:"any"`
+		Raw       json.RawMessage   `json:"raw"`
+		Bytes     []byte            `json:"bytes"`
+		Array     [2]int            `json:"array"`
+		Nested    []map[string]bool `json:"nested"`
+		Skipped   string            `json:"-"`
+		unexposed int
+	}
+
+	want := value{
+		Embedded: &Embedded{Name: "embedded"},
+		ID:       "1100000000000000001",
+		Ints:     []int64{0, 255, 256, -1, math.MaxInt32 + 1, math.MinInt32 - 1, math.MaxInt64, math.MinInt64},
+		Uint:     math.MaxUint64,
+		Float:    -1.25,
+		Bool:     true,
+		Empty:    []string{},
+		Quoted:   1 << 40,
+		Map:      map[string]int{"a": 1, "b": 2},
+		IntKeys:  map[int]string{-3: "c"},
+		Time:     time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Any:      map[string]any{"list": []any{"x", 1.5, nil, true}},
+		Raw:      json.RawMessage(`{"a":[1,"2"]}`),
+		Bytes:    []byte{0, 1, 2},
+		Array:    [2]int{7, 8},
+		Nested:   []map[string]bool{{"t": true}},
+	}
+
+	data, err := MarshalETF(want)
+	if err != nil {
+		t.Fatalf("MarshalETF returned error: %+v", err)
+	}
+
+	var got value
+	if err = UnmarshalETF(data, &got); err != nil {
+		t.Fatalf("UnmarshalETF returned error: %+v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestETFSnowflakes(t *testing.T) {
+	// A map with atom keys, as Discord sends it, holding a snowflake as a
+	// small big integer and a list of small integers as a string.
+	data := []byte{
+		etfVersion, etfMap, 0, 0, 0, 4,
+		etfSmallAtomUTF8, 2, 'i', 'd',
+		etfSmallBig, 8, 0, 1, 0, 238, 4, 44, 252, 67, 15,
+		etfSmallAtomUTF8, 5, 't', 'y', 'p', 'e', 's',
+		etfString, 0, 3, 1, 2, 3,
+		etfSmallAtomUTF8, 4, 'n', 'a', 'm', 'e',
+		etfBinary, 0, 0, 0, 2, 'h', 'i',
+		etfSmallAtomUTF8, 5, 'o', 'w', 'n', 'e', 'r',
+		etfSmallAtomUTF8, 3, 'n', 'i', 'l',
+	}
+
+	var got struct {
+		ID    string  `json:"id"`
+		Types []int   `json:"types"`
+		Name  string  `json:"name"`
+		Owner *string `json:"owner"`
+	}
+	if err := UnmarshalETF(data, &got); err != nil {
+		t.Fatalf("UnmarshalETF returned error: %+v", err)
+	}
+	if got.ID != "1100000000000000001" || !slices.Equal(got.Types, []int{1, 2, 3}) || got.Name != "hi" || got.Owner != nil {
+		t.Errorf("unexpected value %+v", got)
+	}
+
+	j, err := etfToJSON(data[1:])
+	if err != nil {
+		t.Fatalf("etfToJSON returned error: %+v", err)
+	}
+	if want := `{"id":"1100000000000000001","types":[1,2,3],"name":"hi","owner":null}`; string(j) != want {
+		t.Errorf("expected %s, got %s", want, j)
+	}
+}
+
+func TestETFErrors(t *testing.T) {
+	var v struct {
+		Count int `json:"count"`
+	}
+
+	for name, data := range map[string][]byte{
+		"empty":     {},
+		"version":   {130, etfNil},
+		"truncated": {etfVersion, etfMap, 0, 0, 0, 1},
+		"type":      {etfVersion, etfMap, 0, 0, 0, 1, etfBinary, 0, 0, 0, 5, 'c', 'o', 'u', 'n', 't', etfBinary, 0, 0, 0, 0},
+		"overflow":  {etfVersion, etfMap, 0, 0, 0, 1, etfBinary, 0, 0, 0, 5, 'c', 'o', 'u', 'n', 't', etfSmallBig, 9, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		"length":    {etfVersion, etfList, 0xff, 0xff, 0xff, 0xff, etfNil},
+	} {
+		if err := UnmarshalETF(data, &v); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDecodeETFEvent(t *testing.T) {
+	hello := etfFromJSON(t, []byte(`{"op":10,"s":null,"t":null,"d":{"heartbeat_interval":41250}}`))
+	e, err := decodeETFEvent(hello)
+	if err != nil {
+		t.Fatalf("decodeETFEvent returned error: %+v", err)
+	}
+	if e.Operation != 10 || string(e.RawData) != `{"heartbeat_interval":41250}` || e.etfData != nil {
+		t.Errorf("unexpected hello %+v", e)
+	}
+
+	unknown := etfFromJSON(t, []byte(`{"op":0,"s":2,"t":"UNKNOWN_EVENT","d":{"id":"1100000000000000001"}}`))
+	if e, err = decodeETFEvent(unknown); err != nil {
+		t.Fatalf("decodeETFEvent returned error: %+v", err)
+	}
+	if string(e.RawData) != `{"id":"1100000000000000001"}` {
+		t.Errorf("unexpected data %s", e.RawData)
+	}
+
+	message := etfFromJSON(t, []byte(`{"op":0,"s":3,"t":"MESSAGE_CREATE","d":{"id":"1100000000000000001","content":"hello","author":{"id":"1100000000000000002"}}}`))
+	if e, err = decodeETFEvent(message); err != nil {
+		t.Fatalf("decodeETFEvent returned error: %+v", err)
+	}
+	var m MessageCreate
+	if err = e.unmarshalData(&m); err != nil {
+		t.Fatalf("unmarshalData returned error: %+v", err)
+	}
+	if e.Sequence != 3 || m.ID != "1100000000000000001" || m.Content != "hello" || m.Author.ID != "1100000000000000002" {
+		t.Errorf("unexpected message %+v", m.Message)
+	}
+}
+
+// BenchmarkGatewayEncoding decodes the READY payload of
+// BenchmarkGatewayCompression with each gateway encoding.
+func BenchmarkGatewayEncoding(b *testing.B) {
+	payload, err := os.ReadFile(filepath.Join("testdata", "gateway", "ready.json"))
+	if err != nil {
+		b.Fatalf("ReadFile returned error: %+v", err)
+	}
+
+	b.Run("json", func(b *testing.B) {
+		b.SetBytes(int64(len(payload)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var e *Event
+			if err := Unmarshal(payload, &e); err != nil {
+				b.Fatal(err)
+			}
+			if err := e.unmarshalData(&Ready{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("etf", func(b *testing.B) {
+		data := etfFromJSON(b, payload)
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			e, err := decodeETFEvent(data)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := e.unmarshalData(&Ready{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -57,11 +57,24 @@ func registerInterfaceProvider(eh EventInterfaceProvider) {
 // cannot be compared directly.
 type eventHandlerInstance struct {
 	eventHandler EventHandler
+
+	// The handler is only called for the events which all filters pass.
+	filters []EventFilter
+}
+
+// matches reports whether the event i passes all filters of the handler.
+func (ehi *eventHandlerInstance) matches(s *Session, i any) bool {
+	for _, f := range ehi.filters {
+		if !f(s, i) {
+			return false
+		}
+	}
+	return true
 }
 
 // addEventHandler adds an event handler that will be fired anytime
 // the Discord WSAPI matching eventHandler.Type() fires.
-func (s *Session) addEventHandler(eventHandler EventHandler) func() {
+func (s *Session) addEventHandler(eventHandler EventHandler, filters ...EventFilter) func() {
 	s.handlersMu.Lock()
 	defer s.handlersMu.Unlock()
 
@@ -69,7 +82,7 @@ func (s *Session) addEventHandler(eventHandler EventHandler) func() {
 		s.handlers = map[string][]*eventHandlerInstance{}
 	}
 
-	ehi := &eventHandlerInstance{eventHandler}
+	ehi := &eventHandlerInstance{eventHandler, filters}
 	s.handlers[eventHandler.Type()] = append(s.handlers[eventHandler.Type()], ehi)
 
 	return func() {
@@ -79,7 +92,7 @@ func (s *Session) addEventHandler(eventHandler EventHandler) func() {
 
 // addEventHandler adds an event handler that will be fired the next time
 // the Discord WSAPI matching eventHandler.Type() fires.
-func (s *Session) addEventHandlerOnce(eventHandler EventHandler) func() {
+func (s *Session) addEventHandlerOnce(eventHandler EventHandler, filters ...EventFilter) func() {
 	s.handlersMu.Lock()
 	defer s.handlersMu.Unlock()
 
@@ -87,7 +100,7 @@ func (s *Session) addEventHandlerOnce(eventHandler EventHandler) func() {
 		s.onceHandlers = map[string][]*eventHandlerInstance{}
 	}
 
-	ehi := &eventHandlerInstance{eventHandler}
+	ehi := &eventHandlerInstance{eventHandler, filters}
 	s.onceHandlers[eventHandler.Type()] = append(s.onceHandlers[eventHandler.Type()], ehi)
 
 	return func() {
@@ -116,9 +129,14 @@ func (s *Session) addEventHandlerOnce(eventHandler EventHandler) func() {
 // available for handling, like Connect, Disconnect, and RateLimit.
 // events.go contains all of the Discord WSAPI and synthetic events that can be handled.
 //
+// The handler is only called for the events which all filters pass, e.g.
+//
+//	Session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+//	}, discordgo.InGuild(guildID), discordgo.NotSelf(), discordgo.ContentPrefix("!"))
+//
 // The return value of this method is a function, that when called will remove the
 // event handler.
-func (s *Session) AddHandler(handler any) func() {
+func (s *Session) AddHandler(handler any, filters ...EventFilter) func() {
 	eh := handlerForInterface(handler)
 
 	if eh == nil {
@@ -126,13 +144,13 @@ func (s *Session) AddHandler(handler any) func() {
 		return func() {}
 	}
 
-	return s.addEventHandler(eh)
+	return s.addEventHandler(eh, filters...)
 }
 
 // AddHandlerOnce allows you to add an event handler that will be fired the next time
-// the Discord WSAPI event that matches the function fires.
+// the Discord WSAPI event that matches the function fires, and passes all filters.
 // See AddHandler for more details.
-func (s *Session) AddHandlerOnce(handler any) func() {
+func (s *Session) AddHandlerOnce(handler any, filters ...EventFilter) func() {
 	eh := handlerForInterface(handler)
 
 	if eh == nil {
@@ -140,7 +158,7 @@ func (s *Session) AddHandlerOnce(handler any) func() {
 		return func() {}
 	}
 
-	return s.addEventHandlerOnce(eh)
+	return s.addEventHandlerOnce(eh, filters...)
 }
 
 // removeEventHandler instance removes an event handler instance.
@@ -163,13 +181,23 @@ func (s *Session) removeEventHandlerInstance(t string, ehi *eventHandlerInstance
 	}
 }
 
-// Handles calling permanent and once handlers for an event type.
-func (s *Session) handle(t string, i any) {
+// Handles calling permanent and once handlers for an event type. The
+// handlers to run on the Dispatcher are appended to queued and returned, as
+// they are queued once handlersMu is unlocked.
+func (s *Session) handle(t string, i any, queued []*eventHandlerInstance) []*eventHandlerInstance {
 	for _, eh := range s.handlers[t] {
+		if !eh.matches(s, i) {
+			continue
+		}
+
 		if s.SyncEvents {
 			eh.eventHandler.Handle(s, i)
+		} else if s.Dispatcher != nil {
+			queued = append(queued, eh)
 		} else {
+			s.handlersWg.Add(1)
 			go func(eh *eventHandlerInstance) {
+				defer s.handlersWg.Done()
 				defer s.ErrorChecker()
 
 				eh.eventHandler.Handle(s, i)
@@ -178,35 +206,80 @@ func (s *Session) handle(t string, i any) {
 	}
 
 	if len(s.onceHandlers[t]) > 0 {
+		// Once handlers wait for an event passing their filters.
+		var waiting []*eventHandlerInstance
 		for _, eh := range s.onceHandlers[t] {
+			if !eh.matches(s, i) {
+				waiting = append(waiting, eh)
+				continue
+			}
+
 			if s.SyncEvents {
 				eh.eventHandler.Handle(s, i)
+			} else if s.Dispatcher != nil {
+				queued = append(queued, eh)
 			} else {
+				s.handlersWg.Add(1)
 				go func(eh *eventHandlerInstance) {
+					defer s.handlersWg.Done()
 					defer s.ErrorChecker()
 
 					eh.eventHandler.Handle(s, i)
 				}(eh)
 			}
 		}
-		s.onceHandlers[t] = nil
+		s.onceHandlers[t] = waiting
 	}
+
+	return queued
 }
 
-// Handles an event type by calling internal methods, firing handlers and firing the
-// any event.
+// Handles an event type by calling internal methods, then passing it through the
+// event middleware to the handlers.
 func (s *Session) handleEvent(t string, i any) {
-	s.handlersMu.RLock()
-	defer s.handlersMu.RUnlock()
-
 	// All events are dispatched internally first.
 	s.onInterface(i)
 
-	// Then they are dispatched to anyone handling any events.
-	s.handle(interfaceEventType, i)
+	if len(s.EventMiddleware) == 0 {
+		s.dispatchEvent(t, i)
+		return
+	}
+
+	next := s.dispatchEvent
+	for j := len(s.EventMiddleware) - 1; j >= 0; j-- {
+		mw, dispatch := s.EventMiddleware[j], next
+		next = func(t string, i any) {
+			mw(s, t, i, dispatch)
+		}
+	}
+	next(t, i)
+}
+
+// dispatchEvent fires the handlers of an event type and the any event.
+func (s *Session) dispatchEvent(t string, i any) {
+	s.handlersMu.RLock()
+
+	// Events are dispatched to anyone handling any events.
+	queued := s.handle(interfaceEventType, i, nil)
 
 	// Finally they are dispatched to any typed handlers.
-	s.handle(t, i)
+	queued = s.handle(t, i, queued)
+
+	s.handlersMu.RUnlock()
+
+	// The Dispatcher may block, and its handlers may add handlers.
+	for _, eh := range queued {
+		s.handlersWg.Add(1)
+		s.Dispatcher.dispatch(i, dispatchJob{
+			run: func() {
+				defer s.handlersWg.Done()
+				defer s.ErrorChecker()
+
+				eh.eventHandler.Handle(s, i)
+			},
+			drop: s.handlersWg.Done,
+		})
+	}
 }
 
 // setGuildIds will set the GuildID on all the members of a guild.
@@ -258,6 +331,8 @@ func (s *Session) onInterface(i any) {
 
 // onReady handles the ready event.
 func (s *Session) onReady(r *Ready) {
-	// Store the SessionID within the Session struct.
+	// Store the SessionID and the URL to resume it within the Session
+	// struct.
 	s.sessionID = r.SessionID
+	s.resumeGatewayURL = r.ResumeGatewayURL
 }
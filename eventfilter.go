@@ -0,0 +1,246 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the filters of event handlers, and the middleware which
+// intercepts events before they are dispatched to handlers.
+
+package discordgo
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// An EventFilter reports whether an event is passed to an event handler. i is
+// the event as it is passed to handlers, e.g. a *MessageCreate.
+//
+// Filters are given to AddHandler and AddHandlerOnce, and run before the
+// handler is dispatched, so they must not block.
+type EventFilter func(s *Session, i any) bool
+
+// EventDispatch dispatches an event to the event handlers of a Session.
+type EventDispatch func(t string, i any)
+
+// EventMiddleware intercepts every event before it is dispatched to event
+// handlers, including the raw *Event of every gateway dispatch and synthetic
+// events like Connect. t is the type of the event, e.g. MESSAGE_CREATE. A
+// middleware may inspect the event, pass it on to next, possibly changed, or
+// drop it by not calling next. The state is updated before the middleware
+// run.
+type EventMiddleware func(s *Session, t string, i any, next EventDispatch)
+
+// InGuild passes the events of the guild with the given ID.
+func InGuild(guildID string) EventFilter {
+	return func(s *Session, i any) bool {
+		return eventIDsOf(i).guildID == guildID
+	}
+}
+
+// InChannel passes the events of the channel with the given ID.
+func InChannel(channelID string) EventFilter {
+	return func(s *Session, i any) bool {
+		return eventIDsOf(i).channelID == channelID
+	}
+}
+
+// FromUser passes the events of the user with the given ID, such as the
+// messages they sent.
+func FromUser(userID string) EventFilter {
+	return func(s *Session, i any) bool {
+		return eventIDsOf(i).userID == userID
+	}
+}
+
+// NotSelf drops the events of the session user, such as the messages it
+// sent. Events which are not about a user are passed.
+func NotSelf() EventFilter {
+	return func(s *Session, i any) bool {
+		userID := eventIDsOf(i).userID
+		if userID == "" || s.State == nil {
+			return true
+		}
+
+		s.State.RLock()
+		defer s.State.RUnlock()
+
+		return s.State.User == nil || s.State.User.ID != userID
+	}
+}
+
+// ContentPrefix passes the MessageCreate and MessageUpdate events of messages
+// whose content starts with prefix.
+func ContentPrefix(prefix string) EventFilter {
+	return func(s *Session, i any) bool {
+		m := eventMessage(i)
+		return m != nil && strings.HasPrefix(m.Content, prefix)
+	}
+}
+
+// Match passes the events of type T for which match returns true, e.g.
+//
+//	discordgo.Match(func(r *discordgo.MessageReactionAdd) bool {
+//		return r.Emoji.Name == "👍"
+//	})
+func Match[T any](match func(*T) bool) EventFilter {
+	return func(s *Session, i any) bool {
+		t, ok := i.(*T)
+		return ok && match(t)
+	}
+}
+
+// Not passes the events which filter drops.
+func Not(filter EventFilter) EventFilter {
+	return func(s *Session, i any) bool {
+		return !filter(s, i)
+	}
+}
+
+// AnyOf passes the events which any of the filters pass.
+func AnyOf(filters ...EventFilter) EventFilter {
+	return func(s *Session, i any) bool {
+		for _, f := range filters {
+			if f(s, i) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// eventMessage returns the message of a MessageCreate or MessageUpdate
+// event, or nil.
+func eventMessage(i any) *Message {
+	if e, ok := i.(*Event); ok {
+		i = e.Struct
+	}
+
+	switch t := i.(type) {
+	case *MessageCreate:
+		return t.Message
+	case *MessageUpdate:
+		return t.Message
+	}
+	return nil
+}
+
+// eventIDs are the IDs of the channel, guild and user an event is about.
+// They are empty when the event has none.
+type eventIDs struct {
+	channelID string
+	guildID   string
+	userID    string
+}
+
+// eventIDsOf returns the IDs of the event i. The IDs of an *Event are the IDs
+// of its Struct.
+func eventIDsOf(i any) eventIDs {
+	if e, ok := i.(*Event); ok {
+		i = e.Struct
+	}
+
+	// The events of guilds and channels hold their own ID.
+	switch t := i.(type) {
+	case *GuildCreate:
+		return eventIDs{guildID: t.ID}
+	case *GuildUpdate:
+		return eventIDs{guildID: t.ID}
+	case *GuildDelete:
+		return eventIDs{guildID: t.ID}
+	case *ChannelCreate:
+		return channelIDs(t.Channel)
+	case *ChannelUpdate:
+		return channelIDs(t.Channel)
+	case *ChannelDelete:
+		return channelIDs(t.Channel)
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return eventIDs{}
+	}
+	v = v.Elem()
+	fields := eventFieldsOf(v.Type())
+
+	ids := eventIDs{
+		channelID: stringField(v, fields.channelID),
+		guildID:   stringField(v, fields.guildID),
+		userID:    stringField(v, fields.userID),
+	}
+	if ids.userID == "" && fields.user != nil {
+		// Embedded pointers, like the *Message of a MessageCreate, may be
+		// nil.
+		if f, err := v.FieldByIndexErr(fields.user); err == nil && !f.IsNil() {
+			ids.userID = f.Interface().(*User).ID
+		}
+	}
+	return ids
+}
+
+// channelIDs returns the IDs of the channel c, which may be nil.
+func channelIDs(c *Channel) eventIDs {
+	if c == nil {
+		return eventIDs{}
+	}
+	return eventIDs{channelID: c.ID, guildID: c.GuildID}
+}
+
+// eventFieldsCache caches the eventFields of event types, by type.
+var eventFieldsCache sync.Map
+
+// eventFields are the indexes of the fields of an event struct holding its
+// eventIDs, or nil when it has none. They may be promoted from embedded
+// structs.
+type eventFields struct {
+	channelID []int // ChannelID string
+	guildID   []int // GuildID string
+	userID    []int // UserID string
+	user      []int // User or Author *User
+}
+
+// eventFieldsOf returns the eventFields of the struct type t.
+func eventFieldsOf(t reflect.Type) eventFields {
+	if cached, ok := eventFieldsCache.Load(t); ok {
+		return cached.(eventFields)
+	}
+
+	fields := eventFields{
+		channelID: fieldIndex(t, "ChannelID", reflect.TypeOf("")),
+		guildID:   fieldIndex(t, "GuildID", reflect.TypeOf("")),
+		userID:    fieldIndex(t, "UserID", reflect.TypeOf("")),
+		user:      fieldIndex(t, "User", reflect.TypeOf(&User{})),
+	}
+	if fields.user == nil {
+		fields.user = fieldIndex(t, "Author", reflect.TypeOf(&User{}))
+	}
+
+	eventFieldsCache.Store(t, fields)
+	return fields
+}
+
+// fieldIndex returns the index of the field of t with the given name and
+// type, or nil.
+func fieldIndex(t reflect.Type, name string, typ reflect.Type) []int {
+	f, ok := t.FieldByName(name)
+	if !ok || f.Type != typ {
+		return nil
+	}
+	return f.Index
+}
+
+// stringField returns the string field of v at index, or an empty string
+// when index is nil or goes through a nil embedded pointer.
+func stringField(v reflect.Value, index []int) string {
+	if index == nil {
+		return ""
+	}
+	f, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return ""
+	}
+	return f.String()
+}
@@ -0,0 +1,121 @@
+package discordgo
+
+import (
+	"testing"
+)
+
+func TestEventFilters(t *testing.T) {
+	s := &Session{State: NewState()}
+	s.State.User = &User{ID: "self"}
+
+	message := func(guildID, channelID, authorID, content string) *MessageCreate {
+		return &MessageCreate{&Message{GuildID: guildID, ChannelID: channelID, Author: &User{ID: authorID}, Content: content}}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter EventFilter
+		event  any
+		pass   bool
+	}{
+		{"InGuild", InGuild("1"), message("1", "2", "3", ""), true},
+		{"InGuild other", InGuild("1"), message("4", "2", "3", ""), false},
+		{"InGuild guild event", InGuild("1"), &GuildUpdate{Guild{ID: "1"}}, true},
+		{"InGuild raw event", InGuild("1"), &Event{Struct: message("1", "2", "3", "")}, true},
+		{"InChannel", InChannel("2"), message("1", "2", "3", ""), true},
+		{"InChannel channel event", InChannel("2"), &ChannelDelete{&Channel{ID: "2", GuildID: "1"}}, true},
+		{"InChannel no channel", InChannel("2"), &Ready{}, false},
+		{"FromUser", FromUser("3"), message("1", "2", "3", ""), true},
+		{"FromUser member", FromUser("3"), &GuildMemberAdd{&Member{User: &User{ID: "3"}}}, true},
+		{"FromUser reaction", FromUser("3"), &MessageReactionAdd{MessageReaction: &MessageReaction{UserID: "3"}}, true},
+		{"NotSelf", NotSelf(), message("1", "2", "3", ""), true},
+		{"NotSelf self", NotSelf(), message("1", "2", "self", ""), false},
+		{"NotSelf no user", NotSelf(), &MessageCreate{}, true},
+		{"ContentPrefix", ContentPrefix("!"), message("1", "2", "3", "!ping"), true},
+		{"ContentPrefix other", ContentPrefix("!"), message("1", "2", "3", "ping"), false},
+		{"ContentPrefix not a message", ContentPrefix("!"), &Ready{}, false},
+		{"Match", Match(func(m *MessageCreate) bool { return m.Content == "a" }), message("", "", "", "a"), true},
+		{"Match other type", Match(func(m *MessageUpdate) bool { return true }), message("", "", "", "a"), false},
+		{"Not", Not(InGuild("1")), message("1", "2", "3", ""), false},
+		{"AnyOf", AnyOf(InGuild("4"), InChannel("2")), message("1", "2", "3", ""), true},
+		{"AnyOf none", AnyOf(), message("1", "2", "3", ""), false},
+	} {
+		if pass := tc.filter(s, tc.event); pass != tc.pass {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.pass, pass)
+		}
+	}
+}
+
+func TestAddHandlerFilters(t *testing.T) {
+	s := &Session{State: NewState(), SyncEvents: true}
+	s.State.User = &User{ID: "self"}
+
+	var handled []string
+	s.AddHandler(func(s *Session, m *MessageCreate) {
+		handled = append(handled, m.ID)
+	}, InGuild("1"), NotSelf(), ContentPrefix("!"))
+
+	// Generated handler types are filtered too.
+	var once []string
+	s.AddHandlerOnce(messageCreateEventHandler(func(s *Session, m *MessageCreate) {
+		once = append(once, m.ID)
+	}), Match(func(m *MessageCreate) bool { return m.Content == "!second" }))
+
+	for _, m := range []*Message{
+		{ID: "a", GuildID: "1", Author: &User{ID: "2"}, Content: "!first"},
+		{ID: "b", GuildID: "2", Author: &User{ID: "2"}, Content: "!first"},
+		{ID: "c", GuildID: "1", Author: &User{ID: "self"}, Content: "!first"},
+		{ID: "d", GuildID: "1", Author: &User{ID: "2"}, Content: "first"},
+		{ID: "e", GuildID: "1", Author: &User{ID: "2"}, Content: "!second"},
+		{ID: "f", GuildID: "1", Author: &User{ID: "2"}, Content: "!second"},
+	} {
+		s.handleEvent(messageCreateEventType, &MessageCreate{m})
+	}
+
+	if len(handled) != 3 || handled[0] != "a" || handled[1] != "e" || handled[2] != "f" {
+		t.Errorf("expected a, e and f to be handled, got %v", handled)
+	}
+	if len(once) != 1 || once[0] != "e" {
+		t.Errorf("expected only e to be handled once, got %v", once)
+	}
+}
+
+func TestEventMiddleware(t *testing.T) {
+	s := &Session{State: NewState(), SyncEvents: true}
+
+	var seen []string
+	s.EventMiddleware = []EventMiddleware{
+		func(s *Session, t string, i any, next EventDispatch) {
+			seen = append(seen, t)
+			next(t, i)
+		},
+		func(s *Session, t string, i any, next EventDispatch) {
+			if m, ok := i.(*MessageCreate); ok && m.Content == "drop" {
+				return
+			}
+			if e, ok := i.(*Event); ok && e.Type == "MESSAGE_CREATE" {
+				return
+			}
+			next(t, i)
+		},
+	}
+
+	var handled []string
+	s.AddHandler(func(s *Session, m *MessageCreate) { handled = append(handled, m.Content) })
+	var raw int
+	s.AddHandler(func(s *Session, e *Event) { raw++ })
+
+	s.handleEvent(messageCreateEventType, &MessageCreate{&Message{Content: "keep"}})
+	s.handleEvent(messageCreateEventType, &MessageCreate{&Message{Content: "drop"}})
+	s.handleEvent(eventEventType, &Event{Type: "MESSAGE_CREATE"})
+
+	if len(seen) != 3 || seen[0] != messageCreateEventType || seen[2] != eventEventType {
+		t.Errorf("expected the middleware to see every event, got %v", seen)
+	}
+	if len(handled) != 1 || handled[0] != "keep" {
+		t.Errorf("expected only the kept message to be handled, got %v", handled)
+	}
+	if raw != 0 {
+		t.Errorf("expected the raw event to be dropped, got %d", raw)
+	}
+}
@@ -14,6 +14,7 @@ const (
 	autoModerationRuleUpdateEventType            = "AUTO_MODERATION_RULE_UPDATE"
 	channelCreateEventType                       = "CHANNEL_CREATE"
 	channelDeleteEventType                       = "CHANNEL_DELETE"
+	channelPinsAckEventType                      = "CHANNEL_PINS_ACK"
 	channelPinsUpdateEventType                   = "CHANNEL_PINS_UPDATE"
 	channelRecipientAddEventType                 = "CHANNEL_RECIPIENT_ADD"
 	channelRecipientRemoveEventType              = "CHANNEL_RECIPIENT_REMOVE"
@@ -45,6 +46,7 @@ const (
 	interactionModalCreateEventType              = "INTERACTION_MODAL_CREATE"
 	inviteCreateEventType                        = "INVITE_CREATE"
 	inviteDeleteEventType                        = "INVITE_DELETE"
+	messageAckEventType                          = "MESSAGE_ACK"
 	messageCreateEventType                       = "MESSAGE_CREATE"
 	messageDeleteEventType                       = "MESSAGE_DELETE"
 	messageDeleteBulkEventType                   = "MESSAGE_DELETE_BULK"
@@ -56,6 +58,7 @@ const (
 	presencesReplaceEventType                    = "PRESENCES_REPLACE"
 	rateLimitEventType                           = "__RATE_LIMIT__"
 	readyEventType                               = "READY"
+	reconnectFailedEventType                     = "__RECONNECT_FAILED__"
 	relationshipAddEventType                     = "RELATIONSHIP_ADD"
 	relationshipRemoveEventType                  = "RELATIONSHIP_REMOVE"
 	resumedEventType                             = "RESUMED"
@@ -63,6 +66,7 @@ const (
 	stageInstanceEventCreateEventType            = "STAGE_INSTANCE_EVENT_CREATE"
 	stageInstanceEventDeleteEventType            = "STAGE_INSTANCE_EVENT_DELETE"
 	stageInstanceEventUpdateEventType            = "STAGE_INSTANCE_EVENT_UPDATE"
+	stateChangeEventType                         = "__STATE_CHANGE__"
 	threadCreateEventType                        = "THREAD_CREATE"
 	threadDeleteEventType                        = "THREAD_DELETE"
 	threadListSyncEventType                      = "THREAD_LIST_SYNC"
@@ -72,6 +76,7 @@ const (
 	typingStartEventType                         = "TYPING_START"
 	userSettingsProtoUpdateEventType             = "USER_SETTINGS_PROTO_UPDATE"
 	userUpdateEventType                          = "USER_UPDATE"
+	voiceReconnectFailedEventType                = "__VOICE_RECONNECT_FAILED__"
 	voiceServerUpdateEventType                   = "VOICE_SERVER_UPDATE"
 	voiceStateUpdateEventType                    = "VOICE_STATE_UPDATE"
 	webhooksUpdateEventType                      = "WEBHOOKS_UPDATE"
@@ -217,6 +222,26 @@ func (eh channelDeleteEventHandler) Handle(s *Session, i any) {
 	}
 }
 
+// channelPinsAckEventHandler is an event handler for ChannelPinsAck events.
+type channelPinsAckEventHandler func(*Session, *ChannelPinsAck)
+
+// Type returns the event type for ChannelPinsAck events.
+func (eh channelPinsAckEventHandler) Type() string {
+	return channelPinsAckEventType
+}
+
+// New returns a new instance of ChannelPinsAck.
+func (eh channelPinsAckEventHandler) New() any {
+	return &ChannelPinsAck{}
+}
+
+// Handle is the handler for ChannelPinsAck events.
+func (eh channelPinsAckEventHandler) Handle(s *Session, i any) {
+	if t, ok := i.(*ChannelPinsAck); ok {
+		eh(s, t)
+	}
+}
+
 // channelPinsUpdateEventHandler is an event handler for ChannelPinsUpdate events.
 type channelPinsUpdateEventHandler func(*Session, *ChannelPinsUpdate)
 
@@ -822,6 +847,26 @@ func (eh inviteDeleteEventHandler) Handle(s *Session, i any) {
 	}
 }
 
+// messageAckEventHandler is an event handler for MessageAck events.
+type messageAckEventHandler func(*Session, *MessageAck)
+
+// Type returns the event type for MessageAck events.
+func (eh messageAckEventHandler) Type() string {
+	return messageAckEventType
+}
+
+// New returns a new instance of MessageAck.
+func (eh messageAckEventHandler) New() any {
+	return &MessageAck{}
+}
+
+// Handle is the handler for MessageAck events.
+func (eh messageAckEventHandler) Handle(s *Session, i any) {
+	if t, ok := i.(*MessageAck); ok {
+		eh(s, t)
+	}
+}
+
 // messageCreateEventHandler is an event handler for MessageCreate events.
 type messageCreateEventHandler func(*Session, *MessageCreate)
 
@@ -1037,6 +1082,21 @@ func (eh readyEventHandler) Handle(s *Session, i any) {
 	}
 }
 
+// reconnectFailedEventHandler is an event handler for ReconnectFailed events.
+type reconnectFailedEventHandler func(*Session, *ReconnectFailed)
+
+// Type returns the event type for ReconnectFailed events.
+func (eh reconnectFailedEventHandler) Type() string {
+	return reconnectFailedEventType
+}
+
+// Handle is the handler for ReconnectFailed events.
+func (eh reconnectFailedEventHandler) Handle(s *Session, i any) {
+	if t, ok := i.(*ReconnectFailed); ok {
+		eh(s, t)
+	}
+}
+
 // relationshipAddEventHandler is an event handler for RelationshipAdd events.
 type relationshipAddEventHandler func(*Session, *RelationshipAdd)
 
@@ -1177,6 +1237,21 @@ func (eh stageInstanceEventUpdateEventHandler) Handle(s *Session, i any) {
 	}
 }
 
+// stateChangeEventHandler is an event handler for StateChange events.
+type stateChangeEventHandler func(*Session, *StateChange)
+
+// Type returns the event type for StateChange events.
+func (eh stateChangeEventHandler) Type() string {
+	return stateChangeEventType
+}
+
+// Handle is the handler for StateChange events.
+func (eh stateChangeEventHandler) Handle(s *Session, i any) {
+	if t, ok := i.(*StateChange); ok {
+		eh(s, t)
+	}
+}
+
 // threadCreateEventHandler is an event handler for ThreadCreate events.
 type threadCreateEventHandler func(*Session, *ThreadCreate)
 
@@ -1357,6 +1432,21 @@ func (eh userUpdateEventHandler) Handle(s *Session, i any) {
 	}
 }
 
+// voiceReconnectFailedEventHandler is an event handler for VoiceReconnectFailed events.
+type voiceReconnectFailedEventHandler func(*Session, *VoiceReconnectFailed)
+
+// Type returns the event type for VoiceReconnectFailed events.
+func (eh voiceReconnectFailedEventHandler) Type() string {
+	return voiceReconnectFailedEventType
+}
+
+// Handle is the handler for VoiceReconnectFailed events.
+func (eh voiceReconnectFailedEventHandler) Handle(s *Session, i any) {
+	if t, ok := i.(*VoiceReconnectFailed); ok {
+		eh(s, t)
+	}
+}
+
 // voiceServerUpdateEventHandler is an event handler for VoiceServerUpdate events.
 type voiceServerUpdateEventHandler func(*Session, *VoiceServerUpdate)
 
@@ -1435,6 +1525,8 @@ func handlerForInterface(handler any) EventHandler {
 		return channelCreateEventHandler(v)
 	case func(*Session, *ChannelDelete):
 		return channelDeleteEventHandler(v)
+	case func(*Session, *ChannelPinsAck):
+		return channelPinsAckEventHandler(v)
 	case func(*Session, *ChannelPinsUpdate):
 		return channelPinsUpdateEventHandler(v)
 	case func(*Session, *ChannelRecipientAdd):
@@ -1497,6 +1589,8 @@ func handlerForInterface(handler any) EventHandler {
 		return inviteCreateEventHandler(v)
 	case func(*Session, *InviteDelete):
 		return inviteDeleteEventHandler(v)
+	case func(*Session, *MessageAck):
+		return messageAckEventHandler(v)
 	case func(*Session, *MessageCreate):
 		return messageCreateEventHandler(v)
 	case func(*Session, *MessageDelete):
@@ -1519,6 +1613,8 @@ func handlerForInterface(handler any) EventHandler {
 		return rateLimitEventHandler(v)
 	case func(*Session, *Ready):
 		return readyEventHandler(v)
+	case func(*Session, *ReconnectFailed):
+		return reconnectFailedEventHandler(v)
 	case func(*Session, *RelationshipAdd):
 		return relationshipAddEventHandler(v)
 	case func(*Session, *RelationshipRemove):
@@ -1533,6 +1629,8 @@ func handlerForInterface(handler any) EventHandler {
 		return stageInstanceEventDeleteEventHandler(v)
 	case func(*Session, *StageInstanceEventUpdate):
 		return stageInstanceEventUpdateEventHandler(v)
+	case func(*Session, *StateChange):
+		return stateChangeEventHandler(v)
 	case func(*Session, *ThreadCreate):
 		return threadCreateEventHandler(v)
 	case func(*Session, *ThreadDelete):
@@ -1551,12 +1649,16 @@ func handlerForInterface(handler any) EventHandler {
 		return userSettingsProtoUpdateEventHandler(v)
 	case func(*Session, *UserUpdate):
 		return userUpdateEventHandler(v)
+	case func(*Session, *VoiceReconnectFailed):
+		return voiceReconnectFailedEventHandler(v)
 	case func(*Session, *VoiceServerUpdate):
 		return voiceServerUpdateEventHandler(v)
 	case func(*Session, *VoiceStateUpdate):
 		return voiceStateUpdateEventHandler(v)
 	case func(*Session, *WebhooksUpdate):
 		return webhooksUpdateEventHandler(v)
+	case EventHandler:
+		return v
 	}
 
 	return nil
@@ -1570,6 +1672,7 @@ func init() {
 	registerInterfaceProvider(autoModerationRuleUpdateEventHandler(nil))
 	registerInterfaceProvider(channelCreateEventHandler(nil))
 	registerInterfaceProvider(channelDeleteEventHandler(nil))
+	registerInterfaceProvider(channelPinsAckEventHandler(nil))
 	registerInterfaceProvider(channelPinsUpdateEventHandler(nil))
 	registerInterfaceProvider(channelRecipientAddEventHandler(nil))
 	registerInterfaceProvider(channelRecipientRemoveEventHandler(nil))
@@ -1598,6 +1701,7 @@ func init() {
 	registerInterfaceProvider(interactionModalCreateEventHandler(nil))
 	registerInterfaceProvider(inviteCreateEventHandler(nil))
 	registerInterfaceProvider(inviteDeleteEventHandler(nil))
+	registerInterfaceProvider(messageAckEventHandler(nil))
 	registerInterfaceProvider(messageCreateEventHandler(nil))
 	registerInterfaceProvider(messageDeleteEventHandler(nil))
 	registerInterfaceProvider(messageDeleteBulkEventHandler(nil))
@@ -3,6 +3,7 @@ package discordgo
 import (
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"github.com/goccy/go-json"
 
@@ -17,12 +18,61 @@ import (
 
 // Connect is the data for a Connect event.
 // This is a synthetic event and is not dispatched by Discord.
-type Connect struct{}
+type Connect struct {
+	// Resumed is set when the connection resumed the previous session, so
+	// no events were missed. Otherwise a new session was started, and the
+	// state was rebuilt from its Ready event.
+	Resumed bool
+}
 
 // Disconnect is the data for a Disconnect event.
 // This is a synthetic event and is not dispatched by Discord.
 type Disconnect struct{}
 
+// StateChange is the data for a StateChange event, emitted when the state of
+// the gateway connection changes.
+// This is a synthetic event and is not dispatched by Discord.
+type StateChange struct {
+	Old ConnectionState
+	New ConnectionState
+
+	// CloseCode is the code the gateway closed the connection with, when
+	// that caused the change.
+	CloseCode int
+
+	// Err is the cause of the change, if any. It is the error the
+	// connection failed with, or one of ErrGatewayReconnect,
+	// ErrGatewayInvalidSession and ErrHeartbeatACKTimeout.
+	Err error
+}
+
+// ReconnectFailed is the data for a ReconnectFailed event, emitted when the
+// session gives up reconnecting to the gateway.
+// This is a synthetic event and is not dispatched by Discord.
+type ReconnectFailed struct {
+	// Attempts is the number of attempts made to reconnect.
+	Attempts int
+
+	// Err is the error of the last attempt, or the error of the
+	// ReconnectContext when reconnecting was aborted.
+	Err error
+}
+
+// VoiceReconnectFailed is the data for a VoiceReconnectFailed event, emitted
+// when a voice connection gives up reconnecting to its channel.
+// This is a synthetic event and is not dispatched by Discord.
+type VoiceReconnectFailed struct {
+	GuildID   string
+	ChannelID string
+
+	// Attempts is the number of attempts made to reconnect.
+	Attempts int
+
+	// Err is the error of the last attempt, or the error which aborted
+	// reconnecting.
+	Err error
+}
+
 // RateLimit is the data for a RateLimit event.
 // This is a synthetic event and is not dispatched by Discord.
 type RateLimit struct {
@@ -38,12 +88,17 @@ type Event struct {
 	RawData   json.RawMessage `json:"d"`
 	// Struct contains one of the other types in this file.
 	Struct any `json:"-"`
+
+	// etfData holds the data of a dispatch received over an ETF
+	// connection, until it is decoded into Struct.
+	etfData []byte
 }
 
 // A Ready stores all data for the websocket READY event.
 type Ready struct {
 	Version           int                  `json:"v"`
 	SessionID         string               `json:"session_id"`
+	ResumeGatewayURL  string               `json:"resume_gateway_url"`
 	User              *User                `json:"user"`
 	ReadState         []*ReadState         `json:"read_state"`
 	PrivateChannels   []*Channel           `json:"private_channels"`
@@ -56,22 +111,6 @@ type Ready struct {
 }
 
 func (r *Ready) UnmarshalJSON(data []byte) error {
-	type rawReady struct {
-		Version           int                    `json:"v"`
-		SessionID         string                 `json:"session_id"`
-		CountryCode       string                 `json:"country_code"`
-		User              *User                  `json:"user"`
-		Users             []*User                `json:"users"`
-		Guilds            []*Guild               `json:"guilds"`
-		Sessions          []*GatewaySession      `json:"sessions"`
-		ReadState         *ReadStateData         `json:"read_state"`
-		Relationships     []*Relationship        `json:"relationships"`
-		PrivateChannels   []*Channel             `json:"private_channels"`
-		UserGuildSettings *UserGuildSettingsData `json:"user_guild_settings"`
-		UserSettingsProto string                 `json:"user_settings_proto"`
-		ConnectedAccounts []*UserConnection      `json:"connected_accounts"`
-	}
-
 	var ready rawReady
 
 	if err := json.Unmarshal(data, &ready); err != nil {
@@ -79,8 +118,24 @@ func (r *Ready) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	return r.fromRaw(&ready)
+}
+
+func (r *Ready) unmarshalETF(d *etfDecoder, h etfHeader) error {
+	var ready rawReady
+
+	if err := d.decode(h, &ready); err != nil {
+		return err
+	}
+
+	return r.fromRaw(&ready)
+}
+
+// fromRaw sets the fields of r from the READY event as Discord sends it.
+func (r *Ready) fromRaw(ready *rawReady) error {
 	r.Version = ready.Version
 	r.SessionID = ready.SessionID
+	r.ResumeGatewayURL = ready.ResumeGatewayURL
 	r.User = ready.User
 	r.ReadState = ready.ReadState.Entries
 	r.PrivateChannels = ready.PrivateChannels
@@ -119,6 +174,14 @@ type ChannelDelete struct {
 	*Channel
 }
 
+// ChannelPinsAck is the data for a ChannelPinsAck event, sent when the pins
+// of a channel are viewed by any session of the user.
+type ChannelPinsAck struct {
+	ChannelID string    `json:"channel_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   int       `json:"version"`
+}
+
 // ChannelPinsUpdate stores data for a ChannelPinsUpdate event.
 type ChannelPinsUpdate struct {
 	LastPinTimestamp string `json:"last_pin_timestamp"`
@@ -320,6 +383,17 @@ type GuildScheduledEventUserRemove struct {
 	GuildID               string `json:"guild_id"`
 }
 
+// MessageAck is the data for a MessageAck event, sent when a channel is
+// marked as read up to a message by any session of the user. MentionCount is
+// set when the channel is marked as unread manually.
+type MessageAck struct {
+	ChannelID    string `json:"channel_id"`
+	MessageID    string `json:"message_id"`
+	MentionCount int    `json:"mention_count"`
+	Manual       bool   `json:"manual"`
+	Version      int    `json:"version"`
+}
+
 // MessageCreate is the data for a MessageCreate event.
 type MessageCreate struct {
 	*Message
@@ -333,6 +407,10 @@ func (m *MessageCreate) UnmarshalJSON(b []byte) (err error) {
 	return err
 }
 
+func (m *MessageCreate) unmarshalETF(d *etfDecoder, h etfHeader) error {
+	return d.decode(h, &m.Message)
+}
+
 // MessageUpdate is the data for a MessageUpdate event.
 type MessageUpdate struct {
 	*Message
@@ -348,6 +426,10 @@ func (m *MessageUpdate) UnmarshalJSON(b []byte) (err error) {
 	return err
 }
 
+func (m *MessageUpdate) unmarshalETF(d *etfDecoder, h etfHeader) error {
+	return d.decode(h, &m.Message)
+}
+
 // MessageDelete is the data for a MessageDelete event.
 type MessageDelete struct {
 	*Message
@@ -362,6 +444,10 @@ func (m *MessageDelete) UnmarshalJSON(b []byte) (err error) {
 	return err
 }
 
+func (m *MessageDelete) unmarshalETF(d *etfDecoder, h etfHeader) error {
+	return d.decode(h, &m.Message)
+}
+
 // MessageReactionAdd is the data for a MessageReactionAdd event.
 type MessageReactionAdd struct {
 	*MessageReaction
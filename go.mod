@@ -1,25 +1,23 @@
 module github.com/lb-selfbot/discordgo
 
-go 1.22
+go 1.23
 
 require (
+	github.com/cloudflare/circl v1.3.9
+	github.com/goccy/go-json v0.10.5
 	github.com/gorilla/websocket v1.5.3
+	github.com/klauspost/compress v1.17.9
 	golang.org/x/crypto v0.26.0
 	google.golang.org/protobuf v1.34.2
 )
 
-require (
-	github.com/cloudflare/circl v1.3.9 // indirect
-	github.com/goccy/go-json v0.10.5 // indirect
-	github.com/quic-go/quic-go v0.46.0 // indirect
-)
+require github.com/quic-go/quic-go v0.46.0 // indirect
 
 require (
 	github.com/andybalholm/brotli v1.1.0 // indirect
 	github.com/bogdanfinn/fhttp v0.5.28
 	github.com/bogdanfinn/tls-client v1.7.7
 	github.com/bogdanfinn/utls v1.6.1 // indirect
-	github.com/klauspost/compress v1.17.9 // indirect
 	github.com/tam7t/hpkp v0.0.0-20160821193359-2b70b4024ed5 // indirect
 	golang.org/x/net v0.28.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
@@ -0,0 +1,202 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the TLS presentation language encoding of MLS
+// structures, with the variable length integers of RFC 9420 section 2.1.2.
+
+package mls
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrDecode is returned when an MLS structure can not be decoded.
+var ErrDecode = errors.New("mls: malformed message")
+
+// writer encodes MLS structures.
+type writer struct {
+	b []byte
+}
+
+func (w *writer) u8(v uint8)   { w.b = append(w.b, v) }
+func (w *writer) u16(v uint16) { w.b = binary.BigEndian.AppendUint16(w.b, v) }
+func (w *writer) u32(v uint32) { w.b = binary.BigEndian.AppendUint32(w.b, v) }
+func (w *writer) u64(v uint64) { w.b = binary.BigEndian.AppendUint64(w.b, v) }
+
+// varint writes a variable length integer, in its shortest encoding.
+func (w *writer) varint(v int) {
+	switch {
+	case v < 1<<6:
+		w.u8(uint8(v))
+	case v < 1<<14:
+		w.u16(uint16(v) | 0x4000)
+	case v < 1<<30:
+		w.u32(uint32(v) | 0x80000000)
+	default:
+		panic("mls: vector too long")
+	}
+}
+
+// opaque writes a variable length vector of bytes.
+func (w *writer) opaque(v []byte) {
+	w.varint(len(v))
+	w.b = append(w.b, v...)
+}
+
+// vector writes a variable length vector of the elements written by f.
+func (w *writer) vector(f func(w *writer)) {
+	var v writer
+	f(&v)
+	w.opaque(v.b)
+}
+
+// optional writes an optional value, written by f when present.
+func (w *writer) optional(present bool, f func(w *writer)) {
+	if !present {
+		w.u8(0)
+		return
+	}
+	w.u8(1)
+	f(w)
+}
+
+// reader decodes MLS structures. Reads past the end or malformed values set
+// its error, after which it only returns zero values.
+type reader struct {
+	b   []byte
+	err error
+}
+
+// fail records a decoding error.
+func (r *reader) fail() {
+	r.err = ErrDecode
+	r.b = nil
+}
+
+// bytes reads n bytes.
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || len(r.b) < n {
+		r.fail()
+		return nil
+	}
+	v := r.b[:n:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *reader) u8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) u16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) u32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) u64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// varint reads a variable length integer, which must be in its shortest
+// encoding.
+func (r *reader) varint() int {
+	if r.err != nil || len(r.b) == 0 {
+		r.fail()
+		return 0
+	}
+
+	var v, min int
+	switch r.b[0] >> 6 {
+	case 0:
+		return int(r.u8())
+	case 1:
+		v, min = int(r.u16()&0x3FFF), 1<<6
+	case 2:
+		v, min = int(r.u32()&0x3FFFFFFF), 1<<14
+	default:
+		r.fail()
+		return 0
+	}
+	if v < min {
+		r.fail()
+		return 0
+	}
+	return v
+}
+
+// opaque reads a variable length vector of bytes.
+func (r *reader) opaque() []byte {
+	return r.bytes(r.varint())
+}
+
+// vector reads a variable length vector, whose elements are read by f until
+// it is consumed.
+func (r *reader) vector(f func(r *reader)) {
+	v := reader{b: r.opaque(), err: r.err}
+	for v.err == nil && len(v.b) > 0 {
+		f(&v)
+	}
+	if v.err != nil {
+		r.fail()
+	}
+}
+
+// optional reads an optional value, read by f when present.
+func (r *reader) optional(f func(r *reader)) {
+	switch r.u8() {
+	case 0:
+	case 1:
+		f(r)
+	default:
+		r.fail()
+	}
+}
+
+// done returns the error of the reader, or ErrDecode when bytes are left.
+func (r *reader) done() error {
+	if r.err == nil && len(r.b) > 0 {
+		r.fail()
+	}
+	return r.err
+}
+
+// decode decodes data with f, which must consume all of it.
+func decode(data []byte, f func(r *reader)) error {
+	r := reader{b: data}
+	f(&r)
+	return r.done()
+}
+
+// encode returns the encoding written by f.
+func encode(f func(w *writer)) []byte {
+	var w writer
+	f(&w)
+	return w.b
+}
+
+// AppendVector appends v to b, as a variable length vector.
+func AppendVector(b, v []byte) []byte {
+	w := writer{b: b}
+	w.opaque(v)
+	return w.b
+}
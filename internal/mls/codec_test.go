@@ -0,0 +1,75 @@
+package mls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVarint(t *testing.T) {
+	// Examples of RFC 9000 section 16, within the 30 bit range of MLS.
+	tests := []struct {
+		data  []byte
+		value int
+	}{
+		{[]byte{0x25}, 37},
+		{[]byte{0x40, 0x25}, -1},
+		{[]byte{0x7b, 0xbd}, 15293},
+		{[]byte{0x9d, 0x7f, 0x3e, 0x7d}, 494878333},
+		{[]byte{0xc2, 0x19, 0x7c, 0x5e, 0xff, 0x14, 0xe8, 0x8c}, -1},
+	}
+
+	for _, test := range tests {
+		r := reader{b: test.data}
+		v := r.varint()
+		if err := r.done(); test.value < 0 {
+			if err == nil {
+				t.Errorf("expected %x to be rejected, got %d", test.data, v)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("unexpected error decoding %x: %v", test.data, err)
+		}
+		if v != test.value {
+			t.Errorf("expected %x to decode to %d, got %d", test.data, test.value, v)
+		}
+
+		var w writer
+		w.varint(test.value)
+		if !bytes.Equal(w.b, test.data) {
+			t.Errorf("expected %d to encode to %x, got %x", test.value, test.data, w.b)
+		}
+	}
+}
+
+func TestVector(t *testing.T) {
+	data := encode(func(w *writer) {
+		w.vector(func(w *writer) {
+			w.u16(1)
+			w.u16(2)
+		})
+		w.optional(true, func(w *writer) { w.opaque([]byte("abc")) })
+		w.optional(false, nil)
+	})
+	if want := []byte{4, 0, 1, 0, 2, 1, 3, 'a', 'b', 'c', 0}; !bytes.Equal(data, want) {
+		t.Fatalf("expected %x, got %x", want, data)
+	}
+
+	var v []uint16
+	var s []byte
+	err := decode(data, func(r *reader) {
+		v = unmarshalUint16s(r)
+		r.optional(func(r *reader) { s = r.opaque() })
+		r.optional(func(r *reader) { t.Error("unexpected optional value") })
+	})
+	if err != nil || len(v) != 2 || v[1] != 2 || string(s) != "abc" {
+		t.Errorf("unexpected decoding %v %s: %v", v, s, err)
+	}
+
+	// Elements may not overrun their vector.
+	if err := decode([]byte{3, 0, 1, 0}, func(r *reader) { unmarshalUint16s(r) }); err == nil {
+		t.Error("expected truncated element to be rejected")
+	}
+	if err := decode(append(data, 0), func(r *reader) { r.vector(func(r *reader) { r.u16() }) }); err == nil {
+		t.Error("expected trailing data to be rejected")
+	}
+}
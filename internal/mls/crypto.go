@@ -0,0 +1,262 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the cryptographic primitives of the only supported
+// cipher suite, MLS_128_DHKEMP256_AES128GCM_SHA256_P256.
+
+package mls
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/hpke"
+	"github.com/cloudflare/circl/kem"
+	"golang.org/x/crypto/hkdf"
+)
+
+// CipherSuite is the MLS cipher suite used by groups.
+const CipherSuite = 2 // MLS_128_DHKEMP256_AES128GCM_SHA256_P256
+
+// ProtocolVersion is the MLS protocol version, mls10.
+const ProtocolVersion = 1
+
+// Sizes of the secrets, AEAD keys and AEAD nonces of the cipher suite.
+const (
+	hashSize  = sha256.Size
+	keySize   = 16
+	nonceSize = 12
+)
+
+var hpkeSuite = hpke.NewSuite(hpke.KEM_P256_HKDF_SHA256, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM)
+
+var kemScheme = hpke.KEM_P256_HKDF_SHA256.Scheme()
+
+// ErrCrypto is returned when a signature, MAC or ciphertext is invalid.
+var ErrCrypto = errors.New("mls: invalid signature or ciphertext")
+
+// rng is the source of randomness, replaced by tests.
+var rng io.Reader = rand.Reader
+
+// randomBytes returns n random bytes.
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rng, b); err != nil {
+		panic("mls: " + err.Error())
+	}
+	return b
+}
+
+func hash(data []byte) []byte {
+	h := sha256.Sum256(data)
+	return h[:]
+}
+
+func mac(key, data []byte) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write(data)
+	return m.Sum(nil)
+}
+
+// extract is KDF.Extract.
+func extract(salt, ikm []byte) []byte {
+	return hkdf.Extract(sha256.New, ikm, salt)
+}
+
+// expand is KDF.Expand.
+func expand(prk, info []byte, length int) []byte {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), out); err != nil {
+		panic("mls: " + err.Error())
+	}
+	return out
+}
+
+// ExpandWithLabel is the ExpandWithLabel function of RFC 9420 section 8.
+func ExpandWithLabel(secret []byte, label string, context []byte, length int) []byte {
+	info := encode(func(w *writer) {
+		w.u16(uint16(length))
+		w.opaque([]byte("MLS 1.0 " + label))
+		w.opaque(context)
+	})
+	return expand(secret, info, length)
+}
+
+// deriveSecret is the DeriveSecret function of RFC 9420 section 8.
+func deriveSecret(secret []byte, label string) []byte {
+	return ExpandWithLabel(secret, label, nil, hashSize)
+}
+
+// refHash is the RefHash function of RFC 9420 section 5.2.
+func refHash(label string, value []byte) []byte {
+	return hash(encode(func(w *writer) {
+		w.opaque([]byte(label))
+		w.opaque(value)
+	}))
+}
+
+// signContent returns the content signed by SignWithLabel.
+func signContent(label string, content []byte) []byte {
+	return encode(func(w *writer) {
+		w.opaque([]byte("MLS 1.0 " + label))
+		w.opaque(content)
+	})
+}
+
+// SignatureKey is a private ECDSA P-256 signature key.
+type SignatureKey struct {
+	key *ecdsa.PrivateKey
+}
+
+// GenerateSignatureKey returns a new signature key.
+func GenerateSignatureKey() (*SignatureKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rng)
+	if err != nil {
+		return nil, err
+	}
+	return &SignatureKey{key}, nil
+}
+
+// PublicKey returns the encoding of the public key, an uncompressed point.
+func (k *SignatureKey) PublicKey() []byte {
+	pub, err := k.key.PublicKey.ECDH()
+	if err != nil {
+		panic("mls: " + err.Error())
+	}
+	return pub.Bytes()
+}
+
+// signWithLabel is the SignWithLabel function of RFC 9420 section 5.1.2.
+func (k *SignatureKey) signWithLabel(label string, content []byte) []byte {
+	digest := sha256.Sum256(signContent(label, content))
+	sig, err := ecdsa.SignASN1(rng, k.key, digest[:])
+	if err != nil {
+		panic("mls: " + err.Error())
+	}
+	return sig
+}
+
+// verifyWithLabel is the VerifyWithLabel function of RFC 9420 section 5.1.2.
+func verifyWithLabel(publicKey []byte, label string, content, signature []byte) bool {
+	x, y := elliptic.Unmarshal(elliptic.P256(), publicKey)
+	if x == nil {
+		return false
+	}
+	digest := sha256.Sum256(signContent(label, content))
+	return ecdsa.VerifyASN1(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, digest[:], signature)
+}
+
+// hpkeKeyPair is an HPKE key pair, with the encoding of its public key.
+type hpkeKeyPair struct {
+	private kem.PrivateKey
+	public  []byte
+}
+
+// generateHPKEKeyPair returns a new HPKE key pair.
+func generateHPKEKeyPair() *hpkeKeyPair {
+	return deriveHPKEKeyPair(randomBytes(kemScheme.SeedSize()))
+}
+
+// deriveHPKEKeyPair returns the HPKE key pair derived from a secret.
+func deriveHPKEKeyPair(secret []byte) *hpkeKeyPair {
+	pub, priv := kemScheme.DeriveKeyPair(secret)
+	b, err := pub.MarshalBinary()
+	if err != nil {
+		panic("mls: " + err.Error())
+	}
+	return &hpkeKeyPair{priv, b}
+}
+
+// hpkeCiphertext is the HPKECiphertext structure.
+type hpkeCiphertext struct {
+	kemOutput  []byte
+	ciphertext []byte
+}
+
+func (c *hpkeCiphertext) marshal(w *writer) {
+	w.opaque(c.kemOutput)
+	w.opaque(c.ciphertext)
+}
+
+func (c *hpkeCiphertext) unmarshal(r *reader) {
+	c.kemOutput = r.opaque()
+	c.ciphertext = r.opaque()
+}
+
+// encryptContext returns the HPKE info of EncryptWithLabel.
+func encryptContext(label string, context []byte) []byte {
+	return encode(func(w *writer) {
+		w.opaque([]byte("MLS 1.0 " + label))
+		w.opaque(context)
+	})
+}
+
+// encryptWithLabel is the EncryptWithLabel function of RFC 9420 section
+// 5.1.3.
+func encryptWithLabel(publicKey []byte, label string, context, plaintext []byte) (*hpkeCiphertext, error) {
+	pub, err := kemScheme.UnmarshalBinaryPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := hpkeSuite.NewSender(pub, encryptContext(label, context))
+	if err != nil {
+		return nil, err
+	}
+	enc, sealer, err := sender.Setup(rng)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := sealer.Seal(plaintext, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &hpkeCiphertext{enc, ct}, nil
+}
+
+// decryptWithLabel is the DecryptWithLabel function of RFC 9420 section
+// 5.1.3.
+func decryptWithLabel(key *hpkeKeyPair, label string, context []byte, ct *hpkeCiphertext) ([]byte, error) {
+	receiver, err := hpkeSuite.NewReceiver(key.private, encryptContext(label, context))
+	if err != nil {
+		return nil, err
+	}
+	opener, err := receiver.Setup(ct.kemOutput)
+	if err != nil {
+		return nil, ErrCrypto
+	}
+	pt, err := opener.Open(ct.ciphertext, nil)
+	if err != nil {
+		return nil, ErrCrypto
+	}
+	return pt, nil
+}
+
+// newAEAD returns the AEAD of the cipher suite, AES-128-GCM.
+func newAEAD(key []byte) cipher.AEAD {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic("mls: " + err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic("mls: " + err.Error())
+	}
+	return aead
+}
+
+// equal reports whether a and b are equal, in constant time.
+func equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
@@ -0,0 +1,583 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains MLS groups, which are created, joined and updated with
+// add and remove proposals, commits and Welcome messages.
+
+package mls
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"slices"
+)
+
+// ErrInvalid is returned for messages which are not valid in the current
+// epoch of a group, such as proposals of other groups or commits of unknown
+// proposals.
+var ErrInvalid = errors.New("mls: invalid message for group")
+
+// ErrNotWelcomed is returned when a Welcome message does not add the client.
+var ErrNotWelcomed = errors.New("mls: welcome does not add key package")
+
+// cachedProposal is a proposal received in the current epoch.
+type cachedProposal struct {
+	ref      []byte
+	proposal *Proposal
+}
+
+// pendingCommit is a commit sent by the client, and the state of the group
+// once it is accepted.
+type pendingCommit struct {
+	message []byte
+	group   *Group
+}
+
+// A Group is the state of an MLS group for one of its members.
+type Group struct {
+	groupID    []byte
+	epoch      uint64
+	extensions []extension
+	tree       *ratchetTree
+	treeHash   []byte
+
+	// leaf is the leaf index of the client.
+	leaf         uint32
+	signatureKey *SignatureKey
+
+	// privateKeys are the private keys of the nodes of the tree known to
+	// the client, by node index.
+	privateKeys map[uint32]*hpkeKeyPair
+
+	confirmedTranscriptHash []byte
+	interimTranscriptHash   []byte
+	secrets                 epochSecrets
+
+	proposals []cachedProposal
+	pending   *pendingCommit
+}
+
+// NewGroup returns a new group whose only member is the client of a key
+// package, at epoch 0.
+func NewGroup(groupID []byte, keyPackage *KeyPackagePrivate, senders []*ExternalSender) *Group {
+	g := &Group{
+		groupID:      groupID,
+		tree:         &ratchetTree{},
+		signatureKey: keyPackage.SignatureKey,
+		privateKeys:  map[uint32]*hpkeKeyPair{0: keyPackage.encryptionKey},
+		secrets:      newEpochSecrets(randomBytes(hashSize)),
+	}
+	if len(senders) > 0 {
+		g.extensions = []extension{externalSendersExtension(senders)}
+	}
+	g.tree.addLeaf(keyPackage.KeyPackage.leafNode.clone())
+	g.treeHash = g.tree.rootHash()
+	g.interimTranscriptHash = interimTranscriptHash(nil, mac(g.secrets.confirmation, nil))
+	return g
+}
+
+// JoinGroup joins the group of a Welcome message, which must add the client
+// of a key package and include the ratchet tree.
+func JoinGroup(welcome *Welcome, keyPackage *KeyPackagePrivate) (*Group, error) {
+	ref := keyPackage.KeyPackage.ref()
+	i := slices.IndexFunc(welcome.secrets, func(s encryptedGroupSecrets) bool { return equal(s.newMember, ref) })
+	if i < 0 {
+		return nil, ErrNotWelcomed
+	}
+
+	data, err := decryptWithLabel(keyPackage.initKey, "Welcome", welcome.encryptedGroupInfo, &welcome.secrets[i].secrets)
+	if err != nil {
+		return nil, err
+	}
+	var secrets groupSecrets
+	if err := decode(data, secrets.unmarshal); err != nil {
+		return nil, err
+	}
+
+	member := memberSecret(secrets.joinerSecret)
+	key, nonce := welcomeAEAD(member)
+	data, err = newAEAD(key).Open(nil, nonce, welcome.encryptedGroupInfo, nil)
+	if err != nil {
+		return nil, ErrCrypto
+	}
+	var info groupInfo
+	if err := decode(data, info.unmarshal); err != nil {
+		return nil, err
+	}
+
+	treeData, ok := findExtension(info.extensions, extensionRatchetTree)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	tree := &ratchetTree{}
+	if err := decode(treeData, tree.unmarshal); err != nil {
+		return nil, err
+	}
+
+	ctx := &info.groupContext
+	signer := tree.leaf(info.signer)
+	if signer == nil || !verifyWithLabel(signer.signatureKey, "GroupInfoTBS", encode(info.marshalContent), info.signature) {
+		return nil, ErrCrypto
+	}
+	if !tree.verify(ctx.groupID, ctx.treeHash) {
+		return nil, ErrCrypto
+	}
+
+	g := &Group{
+		groupID:                 ctx.groupID,
+		epoch:                   ctx.epoch,
+		extensions:              ctx.extensions,
+		tree:                    tree,
+		treeHash:                ctx.treeHash,
+		signatureKey:            keyPackage.SignatureKey,
+		privateKeys:             map[uint32]*hpkeKeyPair{},
+		confirmedTranscriptHash: ctx.confirmedTranscriptHash,
+		secrets:                 newEpochSecrets(epochSecret(member, ctx)),
+	}
+
+	own := &keyPackage.KeyPackage.leafNode
+	for l := range tree.leaves() {
+		if n := tree.leaf(l); n != nil && equal(n.encryptionKey, own.encryptionKey) && equal(n.signatureKey, own.signatureKey) {
+			g.leaf = l
+			g.privateKeys[2*l] = keyPackage.encryptionKey
+		}
+	}
+	if len(g.privateKeys) == 0 {
+		return nil, ErrNotWelcomed
+	}
+
+	if secrets.pathSecret != nil {
+		path := tree.filteredDirectPath(info.signer)
+		i := slices.IndexFunc(path, func(s pathStep) bool { return inSubtree(2*g.leaf, s.node) })
+		if i < 0 || g.derivePathKeys(path[i:], secrets.pathSecret) == nil {
+			return nil, ErrCrypto
+		}
+	}
+
+	if !equal(mac(g.secrets.confirmation, g.confirmedTranscriptHash), info.confirmationTag) {
+		return nil, ErrCrypto
+	}
+	g.interimTranscriptHash = interimTranscriptHash(g.confirmedTranscriptHash, info.confirmationTag)
+	return g, nil
+}
+
+// GroupID returns the ID of the group.
+func (g *Group) GroupID() []byte {
+	return g.groupID
+}
+
+// Epoch returns the current epoch of the group.
+func (g *Group) Epoch() uint64 {
+	return g.epoch
+}
+
+// Members returns the credentials of the members of the group.
+func (g *Group) Members() []Credential {
+	var members []Credential
+	for l := range g.tree.leaves() {
+		if n := g.tree.leaf(l); n != nil {
+			members = append(members, n.credential)
+		}
+	}
+	return members
+}
+
+// Export returns a secret of the current epoch, with the MLS-Exporter function
+// of RFC 9420 section 8.5.
+func (g *Group) Export(label string, context []byte, length int) []byte {
+	return ExpandWithLabel(deriveSecret(g.secrets.exporter, label), "exported", hash(context), length)
+}
+
+// groupContext returns the group context of the current epoch.
+func (g *Group) groupContext() *groupContext {
+	return &groupContext{
+		groupID:                 g.groupID,
+		epoch:                   g.epoch,
+		treeHash:                g.treeHash,
+		confirmedTranscriptHash: g.confirmedTranscriptHash,
+		extensions:              g.extensions,
+	}
+}
+
+// next returns a copy of the group, in the next epoch.
+func (g *Group) next() *Group {
+	n := *g
+	n.epoch++
+	n.tree = g.tree.clone()
+	n.privateKeys = maps.Clone(g.privateKeys)
+	n.proposals = nil
+	n.pending = nil
+	return &n
+}
+
+// HandleProposal verifies a proposal of an external sender or a member, and
+// caches it for the next commit.
+func (g *Group) HandleProposal(m *Message) (*Proposal, error) {
+	pm := &m.public
+	content := &pm.content
+	if content.contentType != contentProposal || !bytes.Equal(content.groupID, g.groupID) || content.epoch != g.epoch {
+		return nil, ErrInvalid
+	}
+
+	switch content.sender.senderType {
+	case senderExternal:
+		senders, err := externalSenders(g.extensions)
+		if err != nil {
+			return nil, err
+		}
+		if int(content.sender.index) >= len(senders) {
+			return nil, ErrInvalid
+		}
+		if !verifyWithLabel(senders[content.sender.index].SignatureKey, "FramedContentTBS", content.tbs(nil), pm.signature) {
+			return nil, ErrCrypto
+		}
+	case senderMember:
+		if err := g.verifyMember(pm); err != nil {
+			return nil, err
+		}
+	}
+
+	p := content.proposal
+	switch p.proposalType {
+	case proposalAdd:
+		if err := p.Add.verify(); err != nil {
+			return nil, err
+		}
+	case proposalRemove:
+		if g.tree.leaf(p.Remove) == nil {
+			return nil, ErrInvalid
+		}
+	default:
+		return nil, ErrUnsupported
+	}
+
+	g.proposals = append(g.proposals, cachedProposal{m.ProposalRef(), p})
+	return p, nil
+}
+
+// RevokeProposal removes a proposal from the proposals of the next commit.
+func (g *Group) RevokeProposal(ref []byte) {
+	g.proposals = slices.DeleteFunc(g.proposals, func(p cachedProposal) bool { return equal(p.ref, ref) })
+}
+
+// verifyMember verifies the membership tag and signature of a message sent by
+// a member.
+func (g *Group) verifyMember(pm *publicMessage) error {
+	ctx := g.groupContext()
+	signer := g.tree.leaf(pm.content.sender.index)
+	if signer == nil {
+		return ErrInvalid
+	}
+	if !equal(mac(g.secrets.membership, pm.tbm(ctx)), pm.membershipTag) {
+		return ErrCrypto
+	}
+	if !verifyWithLabel(signer.signatureKey, "FramedContentTBS", pm.content.tbs(ctx), pm.signature) {
+		return ErrCrypto
+	}
+	return nil
+}
+
+// applyProposals applies removes then adds to the tree of n, and returns the
+// leaves of the added members.
+func (n *Group) applyProposals(proposals []*Proposal) ([]uint32, error) {
+	for _, p := range proposals {
+		if p.proposalType == proposalRemove {
+			if p.Remove == n.leaf || n.tree.leaf(p.Remove) == nil {
+				return nil, ErrInvalid
+			}
+			n.tree.removeLeaf(p.Remove)
+		}
+	}
+
+	var added []uint32
+	for _, p := range proposals {
+		if p.proposalType == proposalAdd {
+			added = append(added, n.tree.addLeaf(p.Add.leafNode.clone()))
+		}
+	}
+	return added, nil
+}
+
+// Commit commits the cached proposals, with an update path. It returns the
+// commit and, when members are added, their Welcome message. The group moves
+// to the next epoch once the commit is passed to HandleCommit.
+func (g *Group) Commit() (*Message, *Welcome, error) {
+	n := g.next()
+
+	var proposals []*Proposal
+	var refs []proposalOrRef
+	for _, p := range g.proposals {
+		proposals = append(proposals, p.proposal)
+		refs = append(refs, proposalOrRef{ref: p.ref})
+	}
+	added, err := n.applyProposals(proposals)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Replace the direct path, and derive its keys from random path
+	// secrets.
+	leafKey := generateHPKEKeyPair()
+	n.privateKeys = map[uint32]*hpkeKeyPair{2 * g.leaf: leafKey}
+	for _, p := range n.tree.directPath(2 * g.leaf) {
+		n.tree.nodes[p] = node{}
+	}
+
+	path := n.tree.filteredDirectPath(g.leaf)
+	pathSecrets := make([][]byte, len(path))
+	secret := randomBytes(hashSize)
+	for i, step := range path {
+		if i > 0 {
+			secret = deriveSecret(secret, "path")
+		}
+		pathSecrets[i] = secret
+		key := deriveHPKEKeyPair(deriveSecret(secret, "node"))
+		n.tree.nodes[step.node].parent = &parentNode{encryptionKey: key.public}
+		n.privateKeys[step.node] = key
+	}
+	commitSecret := deriveSecret(secret, "path")
+
+	leaf := n.tree.leaf(g.leaf).clone()
+	leaf.encryptionKey = leafKey.public
+	leaf.source = leafSourceCommit
+	leaf.parentHash = n.tree.setParentHashes(g.leaf)
+	leaf.sign(g.signatureKey, g.groupID, g.leaf)
+	n.tree.nodes[2*g.leaf].leaf = leaf
+	n.treeHash = n.tree.rootHash()
+
+	// The path secrets are encrypted to the provisional group context,
+	// except for added members which receive theirs in the Welcome.
+	provisional := encode(n.groupContext().marshal)
+	up := &updatePath{leafNode: *leaf}
+	for i, step := range path {
+		pn := updatePathNode{encryptionKey: n.tree.nodes[step.node].parent.encryptionKey}
+		for _, x := range n.tree.resolution(step.copath) {
+			if x&1 == 0 && slices.Contains(added, x/2) {
+				continue
+			}
+			ct, err := encryptWithLabel(n.tree.nodes[x].encryptionKey(), "UpdatePathNode", provisional, pathSecrets[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			pn.encryptedPathSecret = append(pn.encryptedPathSecret, ct)
+		}
+		up.nodes = append(up.nodes, pn)
+	}
+
+	m := &Message{}
+	pm := &m.public
+	pm.content = framedContent{
+		groupID:     g.groupID,
+		epoch:       g.epoch,
+		sender:      sender{senderMember, g.leaf},
+		contentType: contentCommit,
+		commit:      &commit{proposals: refs, path: up},
+	}
+	ctx := g.groupContext()
+	pm.signature = g.signatureKey.signWithLabel("FramedContentTBS", pm.content.tbs(ctx))
+
+	n.confirmedTranscriptHash = confirmedTranscriptHash(g.interimTranscriptHash, pm)
+	joiner := joinerSecret(g.secrets.init, commitSecret, n.groupContext())
+	n.secrets = newEpochSecrets(epochSecret(memberSecret(joiner), n.groupContext()))
+	pm.confirmationTag = mac(n.secrets.confirmation, n.confirmedTranscriptHash)
+	pm.membershipTag = mac(g.secrets.membership, pm.tbm(ctx))
+	n.interimTranscriptHash = interimTranscriptHash(n.confirmedTranscriptHash, pm.confirmationTag)
+
+	var welcome *Welcome
+	if len(added) > 0 {
+		if welcome, err = n.welcome(proposals, added, path, pathSecrets, joiner, pm.confirmationTag); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	g.pending = &pendingCommit{m.Marshal(), n}
+	return m, welcome, nil
+}
+
+// welcome returns the Welcome message of the members added by a commit,
+// which moved the group to the epoch of n.
+func (n *Group) welcome(proposals []*Proposal, added []uint32, path []pathStep, pathSecrets [][]byte, joiner, confirmationTag []byte) (*Welcome, error) {
+	info := &groupInfo{
+		groupContext:    *n.groupContext(),
+		extensions:      []extension{{extensionRatchetTree, encode(n.tree.marshal)}},
+		confirmationTag: confirmationTag,
+		signer:          n.leaf,
+	}
+	info.signature = n.signatureKey.signWithLabel("GroupInfoTBS", encode(info.marshalContent))
+
+	key, nonce := welcomeAEAD(memberSecret(joiner))
+	welcome := &Welcome{encryptedGroupInfo: newAEAD(key).Seal(nil, nonce, encode(info.marshal), nil)}
+
+	var keyPackages []*KeyPackage
+	for _, p := range proposals {
+		if p.proposalType == proposalAdd {
+			keyPackages = append(keyPackages, p.Add)
+		}
+	}
+	for i, l := range added {
+		// Added members get the path secret of the lowest node of the path
+		// above them.
+		secrets := groupSecrets{joinerSecret: joiner}
+		if j := slices.IndexFunc(path, func(s pathStep) bool { return inSubtree(2*l, s.node) }); j >= 0 {
+			secrets.pathSecret = pathSecrets[j]
+		}
+
+		kp := keyPackages[i]
+		ct, err := encryptWithLabel(kp.initKey, "Welcome", welcome.encryptedGroupInfo, encode(secrets.marshal))
+		if err != nil {
+			return nil, err
+		}
+		welcome.secrets = append(welcome.secrets, encryptedGroupSecrets{kp.ref(), *ct})
+	}
+	return welcome, nil
+}
+
+// HandleCommit processes a commit of a member, or the last commit returned by
+// Commit, and moves the group to the next epoch.
+func (g *Group) HandleCommit(m *Message) error {
+	if g.pending != nil && bytes.Equal(m.Marshal(), g.pending.message) {
+		*g = *g.pending.group
+		return nil
+	}
+
+	pm := &m.public
+	content := &pm.content
+	if content.contentType != contentCommit || !bytes.Equal(content.groupID, g.groupID) || content.epoch != g.epoch {
+		return ErrInvalid
+	}
+	if content.sender.senderType != senderMember || content.sender.index == g.leaf {
+		return ErrInvalid
+	}
+	if err := g.verifyMember(pm); err != nil {
+		return err
+	}
+
+	var proposals []*Proposal
+	for _, p := range content.commit.proposals {
+		if p.proposal != nil {
+			proposals = append(proposals, p.proposal)
+			continue
+		}
+		i := slices.IndexFunc(g.proposals, func(c cachedProposal) bool { return equal(c.ref, p.ref) })
+		if i < 0 {
+			return ErrInvalid
+		}
+		proposals = append(proposals, g.proposals[i].proposal)
+	}
+
+	n := g.next()
+	committer := content.sender.index
+	for _, p := range proposals {
+		if p.proposalType == proposalRemove && p.Remove == committer {
+			return ErrInvalid
+		}
+	}
+	added, err := n.applyProposals(proposals)
+	if err != nil {
+		return err
+	}
+
+	commitSecret := make([]byte, hashSize)
+	if up := content.commit.path; up != nil {
+		if commitSecret, err = n.applyPath(up, committer, added); err != nil {
+			return err
+		}
+	} else {
+		n.treeHash = n.tree.rootHash()
+	}
+
+	n.confirmedTranscriptHash = confirmedTranscriptHash(g.interimTranscriptHash, pm)
+	joiner := joinerSecret(g.secrets.init, commitSecret, n.groupContext())
+	n.secrets = newEpochSecrets(epochSecret(memberSecret(joiner), n.groupContext()))
+	if !equal(mac(n.secrets.confirmation, n.confirmedTranscriptHash), pm.confirmationTag) {
+		return ErrCrypto
+	}
+	n.interimTranscriptHash = interimTranscriptHash(n.confirmedTranscriptHash, pm.confirmationTag)
+
+	// Keys of nodes which have been blanked or replaced are dropped.
+	for x, key := range n.privateKeys {
+		if int(x) >= len(n.tree.nodes) || n.tree.nodes[x].blank() || !equal(n.tree.nodes[x].encryptionKey(), key.public) {
+			delete(n.privateKeys, x)
+		}
+	}
+
+	*g = *n
+	return nil
+}
+
+// applyPath applies the update path of a committer to the tree of n, in the
+// next epoch, and returns the commit secret.
+func (n *Group) applyPath(up *updatePath, committer uint32, added []uint32) ([]byte, error) {
+	leaf := up.leafNode.clone()
+	if leaf.source != leafSourceCommit || !leaf.supported() || !leaf.verify(n.groupID, committer) {
+		return nil, ErrCrypto
+	}
+
+	for _, p := range n.tree.directPath(2 * committer) {
+		n.tree.nodes[p] = node{}
+	}
+	path := n.tree.filteredDirectPath(committer)
+	if len(path) != len(up.nodes) {
+		return nil, ErrInvalid
+	}
+	for i, step := range path {
+		n.tree.nodes[step.node].parent = &parentNode{encryptionKey: up.nodes[i].encryptionKey}
+	}
+	n.tree.nodes[2*committer].leaf = leaf
+	if !bytes.Equal(n.tree.setParentHashes(committer), leaf.parentHash) {
+		return nil, ErrCrypto
+	}
+	n.treeHash = n.tree.rootHash()
+
+	// The path secret is encrypted to a node of the copath resolution above
+	// the client, whose private key is known.
+	i := slices.IndexFunc(path, func(s pathStep) bool { return inSubtree(2*n.leaf, s.copath) })
+	if i < 0 {
+		return nil, ErrInvalid
+	}
+	var res []uint32
+	for _, x := range n.tree.resolution(path[i].copath) {
+		if x&1 == 1 || !slices.Contains(added, x/2) {
+			res = append(res, x)
+		}
+	}
+	j := slices.IndexFunc(res, func(x uint32) bool {
+		key := n.privateKeys[x]
+		return key != nil && equal(key.public, n.tree.nodes[x].encryptionKey())
+	})
+	if j < 0 || len(up.nodes[i].encryptedPathSecret) != len(res) {
+		return nil, ErrInvalid
+	}
+
+	secret, err := decryptWithLabel(n.privateKeys[res[j]], "UpdatePathNode", encode(n.groupContext().marshal), up.nodes[i].encryptedPathSecret[j])
+	if err != nil {
+		return nil, err
+	}
+	if secret = n.derivePathKeys(path[i:], secret); secret == nil {
+		return nil, ErrCrypto
+	}
+	return deriveSecret(secret, "path"), nil
+}
+
+// derivePathKeys derives the private keys of the nodes of a path from the
+// path secret of its first node. It returns the path secret of the last node,
+// or nil when the keys do not match the public keys of the tree.
+func (g *Group) derivePathKeys(path []pathStep, secret []byte) []byte {
+	for i, step := range path {
+		if i > 0 {
+			secret = deriveSecret(secret, "path")
+		}
+		key := deriveHPKEKeyPair(deriveSecret(secret, "node"))
+		pn := g.tree.nodes[step.node].parent
+		if pn == nil || !equal(key.public, pn.encryptionKey) {
+			return nil
+		}
+		g.privateKeys[step.node] = key
+	}
+	return secret
+}
@@ -0,0 +1,201 @@
+package mls
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// newKeyPackage returns the key package of a new client.
+func newKeyPackage(t *testing.T, identity string) *KeyPackagePrivate {
+	t.Helper()
+
+	key, err := GenerateSignatureKey()
+	if err != nil {
+		t.Fatalf("GenerateSignatureKey returned error: %v", err)
+	}
+	return NewKeyPackage(Credential{Identity: []byte(identity)}, key)
+}
+
+// externalSender is the sender of the proposals of the tests.
+type externalSender struct {
+	key    *SignatureKey
+	sender *ExternalSender
+}
+
+func newExternalSender(t *testing.T) *externalSender {
+	key, err := GenerateSignatureKey()
+	if err != nil {
+		t.Fatalf("GenerateSignatureKey returned error: %v", err)
+	}
+	return &externalSender{key, &ExternalSender{key.PublicKey(), Credential{[]byte("server")}}}
+}
+
+// propose sends a proposal to groups, through its encoding.
+func (s *externalSender) propose(t *testing.T, p *Proposal, groups ...*Group) {
+	t.Helper()
+
+	data := NewExternalProposal(groups[0].GroupID(), groups[0].Epoch(), 0, s.key, p).Marshal()
+	for _, g := range groups {
+		m, err := UnmarshalMessage(data)
+		if err != nil {
+			t.Fatalf("UnmarshalMessage returned error: %v", err)
+		}
+		if _, err := g.HandleProposal(m); err != nil {
+			t.Fatalf("HandleProposal returned error: %v", err)
+		}
+	}
+}
+
+// commitAll commits the proposals of a group, handles the commit in the other
+// groups, and joins the new members.
+func commitAll(t *testing.T, committer *Group, groups []*Group, joiners ...*KeyPackagePrivate) []*Group {
+	t.Helper()
+
+	m, welcome, err := committer.Commit()
+	if err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+	data := m.Marshal()
+	for _, g := range groups {
+		m, err := UnmarshalMessage(data)
+		if err != nil {
+			t.Fatalf("UnmarshalMessage returned error: %v", err)
+		}
+		if err := g.HandleCommit(m); err != nil {
+			t.Fatalf("HandleCommit returned error: %v", err)
+		}
+	}
+
+	for _, kp := range joiners {
+		w, err := UnmarshalWelcome(welcome.Marshal())
+		if err != nil {
+			t.Fatalf("UnmarshalWelcome returned error: %v", err)
+		}
+		g, err := JoinGroup(w, kp)
+		if err != nil {
+			t.Fatalf("JoinGroup returned error: %v", err)
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// checkGroups checks that groups agree on their epoch, members and secrets.
+func checkGroups(t *testing.T, epoch uint64, members int, groups ...*Group) {
+	t.Helper()
+
+	secret := groups[0].Export("test", []byte("context"), 16)
+	for i, g := range groups {
+		if g.Epoch() != epoch {
+			t.Errorf("group %d: expected epoch %d, got %d", i, epoch, g.Epoch())
+		}
+		if n := len(g.Members()); n != members {
+			t.Errorf("group %d: expected %d members, got %d", i, members, n)
+		}
+		if !bytes.Equal(g.Export("test", []byte("context"), 16), secret) {
+			t.Errorf("group %d: unexpected exported secret", i)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	server := newExternalSender(t)
+	alice := NewGroup([]byte("group"), newKeyPackage(t, "alice"), []*ExternalSender{server.sender})
+
+	// Alice adds Bob.
+	bobKP := newKeyPackage(t, "bob")
+	server.propose(t, AddProposal(bobKP.KeyPackage), alice)
+	groups := commitAll(t, alice, []*Group{alice}, bobKP)
+	checkGroups(t, 1, 2, groups...)
+
+	// Bob adds Carol and Dave.
+	carolKP, daveKP := newKeyPackage(t, "carol"), newKeyPackage(t, "dave")
+	server.propose(t, AddProposal(carolKP.KeyPackage), groups...)
+	server.propose(t, AddProposal(daveKP.KeyPackage), groups...)
+	groups = commitAll(t, groups[1], groups, carolKP, daveKP)
+	checkGroups(t, 2, 4, groups...)
+
+	// Dave removes Bob, and the tree is updated without him.
+	server.propose(t, RemoveProposal(1), groups...)
+	bob := groups[1]
+	groups = commitAll(t, groups[3], append(groups[:1:1], groups[2:]...))
+	checkGroups(t, 3, 3, groups...)
+	if bytes.Equal(bob.Export("test", []byte("context"), 16), groups[0].Export("test", []byte("context"), 16)) {
+		t.Error("expected removed member not to know the secrets")
+	}
+
+	// Carol commits without proposals, and Erin takes the place of Bob.
+	groups = commitAll(t, groups[1], groups)
+	checkGroups(t, 4, 3, groups...)
+
+	erinKP := newKeyPackage(t, "erin")
+	server.propose(t, AddProposal(erinKP.KeyPackage), groups...)
+	groups = commitAll(t, groups[0], groups, erinKP)
+	checkGroups(t, 5, 4, groups...)
+	if id := groups[3].Members()[1].Identity; string(id) != "erin" {
+		t.Errorf("expected erin at leaf 1, got %s", id)
+	}
+}
+
+func TestGroupConcurrentCommits(t *testing.T) {
+	server := newExternalSender(t)
+	alice := NewGroup([]byte("group"), newKeyPackage(t, "alice"), []*ExternalSender{server.sender})
+	bobKP := newKeyPackage(t, "bob")
+	server.propose(t, AddProposal(bobKP.KeyPackage), alice)
+	groups := commitAll(t, alice, []*Group{alice}, bobKP)
+
+	// Both members commit, and only the commit of Bob is accepted.
+	carolKP := newKeyPackage(t, "carol")
+	server.propose(t, AddProposal(carolKP.KeyPackage), groups...)
+	if _, _, err := groups[0].Commit(); err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+	groups = commitAll(t, groups[1], groups, carolKP)
+	checkGroups(t, 2, 3, groups...)
+}
+
+func TestGroupInvalidMessages(t *testing.T) {
+	server := newExternalSender(t)
+	alice := NewGroup([]byte("group"), newKeyPackage(t, "alice"), []*ExternalSender{server.sender})
+	bob := newKeyPackage(t, "bob")
+
+	// Proposals of other senders, groups or epochs are rejected.
+	other := newExternalSender(t)
+	m := NewExternalProposal([]byte("group"), 0, 0, other.key, AddProposal(bob.KeyPackage))
+	if _, err := alice.HandleProposal(m); !errors.Is(err, ErrCrypto) {
+		t.Errorf("expected ErrCrypto, got %v", err)
+	}
+	m = NewExternalProposal([]byte("group"), 1, 0, server.key, AddProposal(bob.KeyPackage))
+	if _, err := alice.HandleProposal(m); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected ErrInvalid, got %v", err)
+	}
+	m = NewExternalProposal([]byte("group"), 0, 0, server.key, RemoveProposal(0))
+	if _, err := alice.HandleProposal(m); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, _, err := alice.Commit(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected removing the committer to fail, got %v", err)
+	}
+	alice.RevokeProposal(m.ProposalRef())
+
+	// Key packages with invalid signatures are rejected.
+	data := bob.KeyPackage.Marshal()
+	data[len(data)-1] ^= 1
+	if _, err := UnmarshalKeyPackage(data); err == nil {
+		t.Error("expected invalid key package signature to be rejected")
+	}
+	if _, err := UnmarshalKeyPackage(bob.KeyPackage.Marshal()); err != nil {
+		t.Errorf("unexpected error decoding key package: %v", err)
+	}
+
+	// Welcome messages for other clients are rejected.
+	server.propose(t, AddProposal(bob.KeyPackage), alice)
+	_, welcome, err := alice.Commit()
+	if err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+	if _, err := JoinGroup(welcome, newKeyPackage(t, "carol")); !errors.Is(err, ErrNotWelcomed) {
+		t.Errorf("expected ErrNotWelcomed, got %v", err)
+	}
+}
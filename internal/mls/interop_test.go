@@ -0,0 +1,251 @@
+package mls
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// The interop tests run the test vectors of the MLS working group, from the
+// test-vectors directory of github.com/mlswg/mls-implementations, which are
+// not bundled: copy key-schedule.json, welcome.json and tree-validation.json
+// to testdata to run them. Only the vectors of CipherSuite are run. The
+// secret tree vectors do not apply, as private messages are not supported.
+
+// hexBytes is a hex-encoded field of the test vectors.
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := hex.DecodeString(s)
+	*b = v
+	return err
+}
+
+// loadVectors decodes the test vectors of a file of testdata into v, and
+// skips the test when it is missing.
+func loadVectors(t *testing.T, name string, v any) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("testdata/%s not found", name)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decoding %s: %v", name, err)
+	}
+}
+
+// unwrapMLSMessage returns the content of an MLSMessage with the given wire
+// format.
+func unwrapMLSMessage(t *testing.T, data []byte, wireFormat uint16) []byte {
+	t.Helper()
+
+	r := reader{b: data}
+	if r.u16() != ProtocolVersion || r.u16() != wireFormat || r.err != nil {
+		t.Fatalf("expected an MLSMessage with wire format %d", wireFormat)
+	}
+	return r.b
+}
+
+func TestInteropKeySchedule(t *testing.T) {
+	var vectors []struct {
+		CipherSuite       uint16   `json:"cipher_suite"`
+		GroupID           hexBytes `json:"group_id"`
+		InitialInitSecret hexBytes `json:"initial_init_secret"`
+		Epochs            []struct {
+			TreeHash                hexBytes `json:"tree_hash"`
+			CommitSecret            hexBytes `json:"commit_secret"`
+			PSKSecret               hexBytes `json:"psk_secret"`
+			ConfirmedTranscriptHash hexBytes `json:"confirmed_transcript_hash"`
+			GroupContext            hexBytes `json:"group_context"`
+			JoinerSecret            hexBytes `json:"joiner_secret"`
+			WelcomeSecret           hexBytes `json:"welcome_secret"`
+			InitSecret              hexBytes `json:"init_secret"`
+			ExporterSecret          hexBytes `json:"exporter_secret"`
+			ConfirmationKey         hexBytes `json:"confirmation_key"`
+			MembershipKey           hexBytes `json:"membership_key"`
+			Exporter                struct {
+				Label   hexBytes `json:"label"`
+				Context hexBytes `json:"context"`
+				Length  int      `json:"length"`
+				Secret  hexBytes `json:"secret"`
+			} `json:"exporter"`
+		} `json:"epochs"`
+	}
+	loadVectors(t, "key-schedule.json", &vectors)
+
+	for i, v := range vectors {
+		if v.CipherSuite != CipherSuite {
+			continue
+		}
+
+		initSecret := []byte(v.InitialInitSecret)
+		for epoch, e := range v.Epochs {
+			ctx := &groupContext{
+				groupID:                 v.GroupID,
+				epoch:                   uint64(epoch),
+				treeHash:                e.TreeHash,
+				confirmedTranscriptHash: e.ConfirmedTranscriptHash,
+			}
+			if got := encode(ctx.marshal); !equal(got, e.GroupContext) {
+				t.Fatalf("vector %d, epoch %d: expected group context %x, got %x", i, epoch, e.GroupContext, got)
+			}
+
+			joiner := joinerSecret(initSecret, e.CommitSecret, ctx)
+			if !equal(joiner, e.JoinerSecret) {
+				t.Fatalf("vector %d, epoch %d: expected joiner secret %x, got %x", i, epoch, e.JoinerSecret, joiner)
+			}
+
+			// The vectors use pre-shared keys, which groups do not.
+			member := extract(joiner, e.PSKSecret)
+			if got := deriveSecret(member, "welcome"); !equal(got, e.WelcomeSecret) {
+				t.Errorf("vector %d, epoch %d: expected welcome secret %x, got %x", i, epoch, e.WelcomeSecret, got)
+			}
+
+			secrets := newEpochSecrets(epochSecret(member, ctx))
+			for _, s := range []struct {
+				name      string
+				got, want []byte
+			}{
+				{"init secret", secrets.init, e.InitSecret},
+				{"exporter secret", secrets.exporter, e.ExporterSecret},
+				{"confirmation key", secrets.confirmation, e.ConfirmationKey},
+				{"membership key", secrets.membership, e.MembershipKey},
+			} {
+				if !equal(s.got, s.want) {
+					t.Errorf("vector %d, epoch %d: expected %s %x, got %x", i, epoch, s.name, s.want, s.got)
+				}
+			}
+
+			g := &Group{secrets: secrets}
+			if got := g.Export(string(e.Exporter.Label), e.Exporter.Context, e.Exporter.Length); !equal(got, e.Exporter.Secret) {
+				t.Errorf("vector %d, epoch %d: expected exported secret %x, got %x", i, epoch, e.Exporter.Secret, got)
+			}
+
+			initSecret = secrets.init
+		}
+	}
+}
+
+func TestInteropWelcome(t *testing.T) {
+	var vectors []struct {
+		CipherSuite uint16   `json:"cipher_suite"`
+		InitPriv    hexBytes `json:"init_priv"`
+		SignerPub   hexBytes `json:"signer_pub"`
+		KeyPackage  hexBytes `json:"key_package"`
+		Welcome     hexBytes `json:"welcome"`
+	}
+	loadVectors(t, "welcome.json", &vectors)
+
+	const (
+		wireFormatWelcome    = 3
+		wireFormatKeyPackage = 5
+	)
+
+	for i, v := range vectors {
+		if v.CipherSuite != CipherSuite {
+			continue
+		}
+
+		keyPackage, err := UnmarshalKeyPackage(unwrapMLSMessage(t, v.KeyPackage, wireFormatKeyPackage))
+		if err != nil {
+			t.Fatalf("vector %d: UnmarshalKeyPackage returned error: %v", i, err)
+		}
+		welcome, err := UnmarshalWelcome(unwrapMLSMessage(t, v.Welcome, wireFormatWelcome))
+		if err != nil {
+			t.Fatalf("vector %d: UnmarshalWelcome returned error: %v", i, err)
+		}
+		private, err := kemScheme.UnmarshalBinaryPrivateKey(v.InitPriv)
+		if err != nil {
+			t.Fatalf("vector %d: invalid init key: %v", i, err)
+		}
+
+		ref := keyPackage.ref()
+		j := slices.IndexFunc(welcome.secrets, func(s encryptedGroupSecrets) bool { return equal(s.newMember, ref) })
+		if j < 0 {
+			t.Fatalf("vector %d: the key package is not welcomed", i)
+		}
+		data, err := decryptWithLabel(&hpkeKeyPair{private: private}, "Welcome", welcome.encryptedGroupInfo, &welcome.secrets[j].secrets)
+		if err != nil {
+			t.Fatalf("vector %d: decrypting the group secrets returned error: %v", i, err)
+		}
+		var secrets groupSecrets
+		if err := decode(data, secrets.unmarshal); err != nil {
+			t.Fatalf("vector %d: decoding the group secrets returned error: %v", i, err)
+		}
+
+		member := memberSecret(secrets.joinerSecret)
+		key, nonce := welcomeAEAD(member)
+		data, err = newAEAD(key).Open(nil, nonce, welcome.encryptedGroupInfo, nil)
+		if err != nil {
+			t.Fatalf("vector %d: decrypting the group info returned error: %v", i, err)
+		}
+		var info groupInfo
+		if err := decode(data, info.unmarshal); err != nil {
+			t.Fatalf("vector %d: decoding the group info returned error: %v", i, err)
+		}
+
+		if !verifyWithLabel(v.SignerPub, "GroupInfoTBS", encode(info.marshalContent), info.signature) {
+			t.Errorf("vector %d: invalid group info signature", i)
+		}
+		secretsOfEpoch := newEpochSecrets(epochSecret(member, &info.groupContext))
+		if !equal(mac(secretsOfEpoch.confirmation, info.groupContext.confirmedTranscriptHash), info.confirmationTag) {
+			t.Errorf("vector %d: invalid confirmation tag", i)
+		}
+	}
+}
+
+func TestInteropTreeValidation(t *testing.T) {
+	var vectors []struct {
+		CipherSuite uint16     `json:"cipher_suite"`
+		Tree        hexBytes   `json:"tree"`
+		GroupID     hexBytes   `json:"group_id"`
+		Resolutions [][]uint32 `json:"resolutions"`
+		TreeHashes  []hexBytes `json:"tree_hashes"`
+	}
+	loadVectors(t, "tree-validation.json", &vectors)
+
+	for i, v := range vectors {
+		if v.CipherSuite != CipherSuite {
+			continue
+		}
+
+		tree := &ratchetTree{}
+		if err := decode(v.Tree, tree.unmarshal); err != nil {
+			t.Fatalf("vector %d: decoding the tree returned error: %v", i, err)
+		}
+
+		for x, want := range v.Resolutions {
+			if got := tree.resolution(uint32(x)); !slices.Equal(got, want) {
+				t.Errorf("vector %d: expected resolution of node %d %v, got %v", i, x, want, got)
+			}
+		}
+		for x, want := range v.TreeHashes {
+			if got := tree.treeHash(uint32(x), nil); !equal(got, want) {
+				t.Errorf("vector %d: expected tree hash of node %d %x, got %x", i, x, want, got)
+			}
+		}
+
+		for x := range tree.nodes {
+			n := &tree.nodes[x]
+			switch {
+			case n.leaf != nil && !n.leaf.verify(v.GroupID, uint32(x/2)):
+				t.Errorf("vector %d: invalid signature of leaf %d", i, x/2)
+			case n.parent != nil && !tree.parentHashValid(uint32(x)):
+				t.Errorf("vector %d: parent node %d is not parent-hash valid", i, x)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the key schedule and transcript hashes of RFC 9420
+// section 8, without pre-shared keys.
+
+package mls
+
+// epochSecrets are the secrets of an epoch used by groups.
+type epochSecrets struct {
+	init         []byte
+	exporter     []byte
+	confirmation []byte
+	membership   []byte
+}
+
+func newEpochSecrets(epochSecret []byte) epochSecrets {
+	return epochSecrets{
+		init:         deriveSecret(epochSecret, "init"),
+		exporter:     deriveSecret(epochSecret, "exporter"),
+		confirmation: deriveSecret(epochSecret, "confirm"),
+		membership:   deriveSecret(epochSecret, "membership"),
+	}
+}
+
+// joinerSecret returns the joiner secret of the epoch with the given group
+// context.
+func joinerSecret(initSecret, commitSecret []byte, ctx *groupContext) []byte {
+	return ExpandWithLabel(extract(initSecret, commitSecret), "joiner", encode(ctx.marshal), hashSize)
+}
+
+// memberSecret returns the secret from which the welcome and epoch secrets are
+// derived, with an all-zero PSK secret.
+func memberSecret(joinerSecret []byte) []byte {
+	return extract(joinerSecret, make([]byte, hashSize))
+}
+
+// epochSecret returns the epoch secret of the epoch with the given group
+// context.
+func epochSecret(memberSecret []byte, ctx *groupContext) []byte {
+	return ExpandWithLabel(memberSecret, "epoch", encode(ctx.marshal), hashSize)
+}
+
+// welcomeAEAD returns the key and nonce encrypting the group info of Welcome
+// messages.
+func welcomeAEAD(memberSecret []byte) (key, nonce []byte) {
+	welcomeSecret := deriveSecret(memberSecret, "welcome")
+	return ExpandWithLabel(welcomeSecret, "key", nil, keySize), ExpandWithLabel(welcomeSecret, "nonce", nil, nonceSize)
+}
+
+// confirmedTranscriptHash returns the confirmed transcript hash following a
+// commit.
+func confirmedTranscriptHash(interim []byte, m *publicMessage) []byte {
+	return hash(encode(func(w *writer) {
+		w.b = append(w.b, interim...)
+		w.u16(wireFormatPublicMessage)
+		m.content.marshal(w)
+		w.opaque(m.signature)
+	}))
+}
+
+// interimTranscriptHash returns the interim transcript hash of an epoch.
+func interimTranscriptHash(confirmed, confirmationTag []byte) []byte {
+	return hash(encode(func(w *writer) {
+		w.b = append(w.b, confirmed...)
+		w.opaque(confirmationTag)
+	}))
+}
@@ -0,0 +1,900 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the MLS structures of RFC 9420, and their encoding.
+
+package mls
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// ErrUnsupported is returned for MLS messages which use features which are
+// not supported, such as other cipher suites or private messages.
+var ErrUnsupported = errors.New("mls: unsupported message")
+
+const credentialTypeBasic = 1
+
+// Extension types.
+const (
+	extensionRatchetTree     = 2
+	extensionExternalSenders = 5
+)
+
+// Proposal types.
+const (
+	proposalAdd    = 1
+	proposalUpdate = 2
+	proposalRemove = 3
+)
+
+// wireFormatPublicMessage is the only supported wire format of MLSMessages.
+const wireFormatPublicMessage = 1
+
+// Sender types.
+const (
+	senderMember   = 1
+	senderExternal = 2
+)
+
+// Content types.
+const (
+	contentProposal = 2
+	contentCommit   = 3
+)
+
+// Leaf node sources.
+const (
+	leafSourceKeyPackage = 1
+	leafSourceUpdate     = 2
+	leafSourceCommit     = 3
+)
+
+// Node types.
+const (
+	nodeLeaf   = 1
+	nodeParent = 2
+)
+
+// proposalOrRef types.
+const (
+	proposalOrRefProposal  = 1
+	proposalOrRefReference = 2
+)
+
+// Credential is a basic credential, which holds the identity of a member.
+type Credential struct {
+	Identity []byte
+}
+
+func (c *Credential) marshal(w *writer) {
+	w.u16(credentialTypeBasic)
+	w.opaque(c.Identity)
+}
+
+func (c *Credential) unmarshal(r *reader) {
+	if r.u16() != credentialTypeBasic {
+		r.fail()
+		return
+	}
+	c.Identity = r.opaque()
+}
+
+// capabilities is the Capabilities structure.
+type capabilities struct {
+	versions, cipherSuites, extensions, proposals, credentials []uint16
+}
+
+// defaultCapabilities are the capabilities of the leaves of the client.
+var defaultCapabilities = capabilities{
+	versions:     []uint16{ProtocolVersion},
+	cipherSuites: []uint16{CipherSuite},
+	credentials:  []uint16{credentialTypeBasic},
+}
+
+func marshalUint16s(w *writer, v []uint16) {
+	w.vector(func(w *writer) {
+		for _, e := range v {
+			w.u16(e)
+		}
+	})
+}
+
+func unmarshalUint16s(r *reader) (v []uint16) {
+	r.vector(func(r *reader) { v = append(v, r.u16()) })
+	return v
+}
+
+func (c *capabilities) marshal(w *writer) {
+	marshalUint16s(w, c.versions)
+	marshalUint16s(w, c.cipherSuites)
+	marshalUint16s(w, c.extensions)
+	marshalUint16s(w, c.proposals)
+	marshalUint16s(w, c.credentials)
+}
+
+func (c *capabilities) unmarshal(r *reader) {
+	c.versions = unmarshalUint16s(r)
+	c.cipherSuites = unmarshalUint16s(r)
+	c.extensions = unmarshalUint16s(r)
+	c.proposals = unmarshalUint16s(r)
+	c.credentials = unmarshalUint16s(r)
+}
+
+// extension is the Extension structure.
+type extension struct {
+	extensionType uint16
+	data          []byte
+}
+
+func marshalExtensions(w *writer, extensions []extension) {
+	w.vector(func(w *writer) {
+		for _, e := range extensions {
+			w.u16(e.extensionType)
+			w.opaque(e.data)
+		}
+	})
+}
+
+func unmarshalExtensions(r *reader) (extensions []extension) {
+	r.vector(func(r *reader) {
+		extensions = append(extensions, extension{r.u16(), r.opaque()})
+	})
+	return extensions
+}
+
+// findExtension returns the data of the extension of the given type.
+func findExtension(extensions []extension, extensionType uint16) ([]byte, bool) {
+	for _, e := range extensions {
+		if e.extensionType == extensionType {
+			return e.data, true
+		}
+	}
+	return nil, false
+}
+
+// ExternalSender is a sender of proposals which is not a member of the
+// group, such as the voice server of a DAVE call.
+type ExternalSender struct {
+	SignatureKey []byte
+	Credential   Credential
+}
+
+func (s *ExternalSender) marshal(w *writer) {
+	w.opaque(s.SignatureKey)
+	s.Credential.marshal(w)
+}
+
+func (s *ExternalSender) unmarshal(r *reader) {
+	s.SignatureKey = r.opaque()
+	s.Credential.unmarshal(r)
+}
+
+// Marshal returns the encoding of the external sender.
+func (s *ExternalSender) Marshal() []byte {
+	return encode(s.marshal)
+}
+
+// UnmarshalExternalSender decodes an external sender.
+func UnmarshalExternalSender(data []byte) (*ExternalSender, error) {
+	s := &ExternalSender{}
+	return s, decode(data, s.unmarshal)
+}
+
+// externalSendersExtension returns the external_senders extension of a group
+// context.
+func externalSendersExtension(senders []*ExternalSender) extension {
+	return extension{extensionExternalSenders, encode(func(w *writer) {
+		w.vector(func(w *writer) {
+			for _, s := range senders {
+				s.marshal(w)
+			}
+		})
+	})}
+}
+
+// externalSenders returns the external senders of a group context.
+func externalSenders(extensions []extension) ([]*ExternalSender, error) {
+	data, ok := findExtension(extensions, extensionExternalSenders)
+	if !ok {
+		return nil, nil
+	}
+
+	var senders []*ExternalSender
+	err := decode(data, func(r *reader) {
+		r.vector(func(r *reader) {
+			s := &ExternalSender{}
+			s.unmarshal(r)
+			senders = append(senders, s)
+		})
+	})
+	return senders, err
+}
+
+// leafNode is the LeafNode structure.
+type leafNode struct {
+	encryptionKey []byte
+	signatureKey  []byte
+	credential    Credential
+	capabilities  capabilities
+	source        uint8
+
+	// notBefore and notAfter are the lifetime of key package leaves.
+	notBefore, notAfter uint64
+
+	// parentHash is the parent hash of commit leaves.
+	parentHash []byte
+
+	extensions []extension
+	signature  []byte
+}
+
+// marshalContent writes the leaf node without its signature.
+func (n *leafNode) marshalContent(w *writer) {
+	w.opaque(n.encryptionKey)
+	w.opaque(n.signatureKey)
+	n.credential.marshal(w)
+	n.capabilities.marshal(w)
+	w.u8(n.source)
+	switch n.source {
+	case leafSourceKeyPackage:
+		w.u64(n.notBefore)
+		w.u64(n.notAfter)
+	case leafSourceCommit:
+		w.opaque(n.parentHash)
+	}
+	marshalExtensions(w, n.extensions)
+}
+
+func (n *leafNode) marshal(w *writer) {
+	n.marshalContent(w)
+	w.opaque(n.signature)
+}
+
+func (n *leafNode) unmarshal(r *reader) {
+	n.encryptionKey = r.opaque()
+	n.signatureKey = r.opaque()
+	n.credential.unmarshal(r)
+	n.capabilities.unmarshal(r)
+	n.source = r.u8()
+	switch n.source {
+	case leafSourceKeyPackage:
+		n.notBefore = r.u64()
+		n.notAfter = r.u64()
+	case leafSourceUpdate:
+	case leafSourceCommit:
+		n.parentHash = r.opaque()
+	default:
+		r.fail()
+	}
+	n.extensions = unmarshalExtensions(r)
+	n.signature = r.opaque()
+}
+
+// tbs returns the LeafNodeTBS of the leaf node, at the given leaf of a group
+// for update and commit leaves.
+func (n *leafNode) tbs(groupID []byte, leaf uint32) []byte {
+	return encode(func(w *writer) {
+		n.marshalContent(w)
+		if n.source != leafSourceKeyPackage {
+			w.opaque(groupID)
+			w.u32(leaf)
+		}
+	})
+}
+
+// sign signs the leaf node.
+func (n *leafNode) sign(key *SignatureKey, groupID []byte, leaf uint32) {
+	n.signature = key.signWithLabel("LeafNodeTBS", n.tbs(groupID, leaf))
+}
+
+// verify verifies the signature of the leaf node.
+func (n *leafNode) verify(groupID []byte, leaf uint32) bool {
+	return verifyWithLabel(n.signatureKey, "LeafNodeTBS", n.tbs(groupID, leaf), n.signature)
+}
+
+// supported reports whether the leaf node supports the cipher suite and
+// credentials of groups.
+func (n *leafNode) supported() bool {
+	return slices.Contains(n.capabilities.versions, ProtocolVersion) &&
+		slices.Contains(n.capabilities.cipherSuites, CipherSuite) &&
+		slices.Contains(n.capabilities.credentials, credentialTypeBasic)
+}
+
+func (n *leafNode) clone() *leafNode {
+	c := *n
+	return &c
+}
+
+// KeyPackage is a key package, which is used to add a client to a group.
+type KeyPackage struct {
+	initKey    []byte
+	leafNode   leafNode
+	extensions []extension
+	signature  []byte
+}
+
+func (p *KeyPackage) marshalContent(w *writer) {
+	w.u16(ProtocolVersion)
+	w.u16(CipherSuite)
+	w.opaque(p.initKey)
+	p.leafNode.marshal(w)
+	marshalExtensions(w, p.extensions)
+}
+
+func (p *KeyPackage) marshal(w *writer) {
+	p.marshalContent(w)
+	w.opaque(p.signature)
+}
+
+func (p *KeyPackage) unmarshal(r *reader) {
+	if r.u16() != ProtocolVersion || r.u16() != CipherSuite {
+		r.fail()
+		return
+	}
+	p.initKey = r.opaque()
+	p.leafNode.unmarshal(r)
+	p.extensions = unmarshalExtensions(r)
+	p.signature = r.opaque()
+}
+
+// Marshal returns the encoding of the key package.
+func (p *KeyPackage) Marshal() []byte {
+	return encode(p.marshal)
+}
+
+// UnmarshalKeyPackage decodes a key package, and verifies it.
+func UnmarshalKeyPackage(data []byte) (*KeyPackage, error) {
+	p := &KeyPackage{}
+	if err := decode(data, p.unmarshal); err != nil {
+		return nil, err
+	}
+	if err := p.verify(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Credential returns the credential of the client of the key package.
+func (p *KeyPackage) Credential() Credential {
+	return p.leafNode.credential
+}
+
+// ref returns the KeyPackageRef of the key package.
+func (p *KeyPackage) ref() []byte {
+	return refHash("MLS 1.0 KeyPackage Reference", encode(p.marshal))
+}
+
+// verify verifies the signatures and the content of the key package.
+func (p *KeyPackage) verify() error {
+	if !verifyWithLabel(p.leafNode.signatureKey, "KeyPackageTBS", encode(p.marshalContent), p.signature) {
+		return ErrCrypto
+	}
+	if p.leafNode.source != leafSourceKeyPackage || !p.leafNode.verify(nil, 0) {
+		return ErrCrypto
+	}
+	if !p.leafNode.supported() || equal(p.initKey, p.leafNode.encryptionKey) {
+		return ErrUnsupported
+	}
+	return nil
+}
+
+// KeyPackagePrivate holds the private keys of a key package.
+type KeyPackagePrivate struct {
+	KeyPackage   *KeyPackage
+	SignatureKey *SignatureKey
+
+	initKey       *hpkeKeyPair
+	encryptionKey *hpkeKeyPair
+}
+
+// NewKeyPackage returns a new key package of a client with the given
+// credential and signature key.
+func NewKeyPackage(credential Credential, signatureKey *SignatureKey) *KeyPackagePrivate {
+	priv := &KeyPackagePrivate{
+		SignatureKey:  signatureKey,
+		initKey:       generateHPKEKeyPair(),
+		encryptionKey: generateHPKEKeyPair(),
+	}
+
+	p := &KeyPackage{initKey: priv.initKey.public}
+	p.leafNode = leafNode{
+		encryptionKey: priv.encryptionKey.public,
+		signatureKey:  signatureKey.PublicKey(),
+		credential:    credential,
+		capabilities:  defaultCapabilities,
+		source:        leafSourceKeyPackage,
+		notAfter:      math.MaxUint64,
+	}
+	p.leafNode.sign(signatureKey, nil, 0)
+	p.signature = signatureKey.signWithLabel("KeyPackageTBS", encode(p.marshalContent))
+
+	priv.KeyPackage = p
+	return priv
+}
+
+// Proposal is a proposal to add or remove a member of a group.
+type Proposal struct {
+	// Add is the key package of the client added by an add proposal.
+	Add *KeyPackage
+
+	// Remove is the leaf of the member removed by a remove proposal.
+	Remove uint32
+
+	proposalType uint16
+	update       *leafNode
+}
+
+// AddProposal returns a proposal adding the client of a key package.
+func AddProposal(keyPackage *KeyPackage) *Proposal {
+	return &Proposal{proposalType: proposalAdd, Add: keyPackage}
+}
+
+// RemoveProposal returns a proposal removing the member of a leaf.
+func RemoveProposal(leaf uint32) *Proposal {
+	return &Proposal{proposalType: proposalRemove, Remove: leaf}
+}
+
+func (p *Proposal) marshal(w *writer) {
+	w.u16(p.proposalType)
+	switch p.proposalType {
+	case proposalAdd:
+		p.Add.marshal(w)
+	case proposalUpdate:
+		p.update.marshal(w)
+	case proposalRemove:
+		w.u32(p.Remove)
+	}
+}
+
+func (p *Proposal) unmarshal(r *reader) {
+	p.proposalType = r.u16()
+	switch p.proposalType {
+	case proposalAdd:
+		p.Add = &KeyPackage{}
+		p.Add.unmarshal(r)
+	case proposalUpdate:
+		p.update = &leafNode{}
+		p.update.unmarshal(r)
+	case proposalRemove:
+		p.Remove = r.u32()
+	default:
+		r.err = ErrUnsupported
+		r.b = nil
+	}
+}
+
+// proposalOrRef is the ProposalOrRef structure.
+type proposalOrRef struct {
+	proposal *Proposal
+	ref      []byte
+}
+
+// commit is the Commit structure.
+type commit struct {
+	proposals []proposalOrRef
+	path      *updatePath
+}
+
+func (c *commit) marshal(w *writer) {
+	w.vector(func(w *writer) {
+		for _, p := range c.proposals {
+			if p.proposal != nil {
+				w.u8(proposalOrRefProposal)
+				p.proposal.marshal(w)
+			} else {
+				w.u8(proposalOrRefReference)
+				w.opaque(p.ref)
+			}
+		}
+	})
+	w.optional(c.path != nil, func(w *writer) { c.path.marshal(w) })
+}
+
+func (c *commit) unmarshal(r *reader) {
+	r.vector(func(r *reader) {
+		var p proposalOrRef
+		switch r.u8() {
+		case proposalOrRefProposal:
+			p.proposal = &Proposal{}
+			p.proposal.unmarshal(r)
+		case proposalOrRefReference:
+			p.ref = r.opaque()
+		default:
+			r.fail()
+		}
+		c.proposals = append(c.proposals, p)
+	})
+	r.optional(func(r *reader) {
+		c.path = &updatePath{}
+		c.path.unmarshal(r)
+	})
+}
+
+// updatePathNode is the UpdatePathNode structure.
+type updatePathNode struct {
+	encryptionKey       []byte
+	encryptedPathSecret []*hpkeCiphertext
+}
+
+// updatePath is the UpdatePath structure.
+type updatePath struct {
+	leafNode leafNode
+	nodes    []updatePathNode
+}
+
+func (p *updatePath) marshal(w *writer) {
+	p.leafNode.marshal(w)
+	w.vector(func(w *writer) {
+		for _, n := range p.nodes {
+			w.opaque(n.encryptionKey)
+			w.vector(func(w *writer) {
+				for _, ct := range n.encryptedPathSecret {
+					ct.marshal(w)
+				}
+			})
+		}
+	})
+}
+
+func (p *updatePath) unmarshal(r *reader) {
+	p.leafNode.unmarshal(r)
+	r.vector(func(r *reader) {
+		n := updatePathNode{encryptionKey: r.opaque()}
+		r.vector(func(r *reader) {
+			ct := &hpkeCiphertext{}
+			ct.unmarshal(r)
+			n.encryptedPathSecret = append(n.encryptedPathSecret, ct)
+		})
+		p.nodes = append(p.nodes, n)
+	})
+}
+
+// sender is the Sender structure.
+type sender struct {
+	senderType uint8
+	index      uint32
+}
+
+func (s *sender) marshal(w *writer) {
+	w.u8(s.senderType)
+	w.u32(s.index)
+}
+
+func (s *sender) unmarshal(r *reader) {
+	s.senderType = r.u8()
+	if s.senderType != senderMember && s.senderType != senderExternal {
+		r.err = ErrUnsupported
+		r.b = nil
+		return
+	}
+	s.index = r.u32()
+}
+
+// framedContent is the FramedContent structure, of proposals and commits.
+type framedContent struct {
+	groupID           []byte
+	epoch             uint64
+	sender            sender
+	authenticatedData []byte
+	contentType       uint8
+	proposal          *Proposal
+	commit            *commit
+}
+
+func (c *framedContent) marshal(w *writer) {
+	w.opaque(c.groupID)
+	w.u64(c.epoch)
+	c.sender.marshal(w)
+	w.opaque(c.authenticatedData)
+	w.u8(c.contentType)
+	switch c.contentType {
+	case contentProposal:
+		c.proposal.marshal(w)
+	case contentCommit:
+		c.commit.marshal(w)
+	}
+}
+
+func (c *framedContent) unmarshal(r *reader) {
+	c.groupID = r.opaque()
+	c.epoch = r.u64()
+	c.sender.unmarshal(r)
+	c.authenticatedData = r.opaque()
+	c.contentType = r.u8()
+	switch c.contentType {
+	case contentProposal:
+		c.proposal = &Proposal{}
+		c.proposal.unmarshal(r)
+	case contentCommit:
+		c.commit = &commit{}
+		c.commit.unmarshal(r)
+	default:
+		r.err = ErrUnsupported
+		r.b = nil
+	}
+}
+
+// tbs returns the FramedContentTBS of the content, with the group context of
+// member senders.
+func (c *framedContent) tbs(ctx *groupContext) []byte {
+	return encode(func(w *writer) {
+		w.u16(ProtocolVersion)
+		w.u16(wireFormatPublicMessage)
+		c.marshal(w)
+		if c.sender.senderType == senderMember {
+			ctx.marshal(w)
+		}
+	})
+}
+
+// publicMessage is the PublicMessage structure.
+type publicMessage struct {
+	content         framedContent
+	signature       []byte
+	confirmationTag []byte
+	membershipTag   []byte
+}
+
+// marshalAuth writes the FramedContentAuthData of the message.
+func (m *publicMessage) marshalAuth(w *writer) {
+	w.opaque(m.signature)
+	if m.content.contentType == contentCommit {
+		w.opaque(m.confirmationTag)
+	}
+}
+
+func (m *publicMessage) marshal(w *writer) {
+	m.content.marshal(w)
+	m.marshalAuth(w)
+	if m.content.sender.senderType == senderMember {
+		w.opaque(m.membershipTag)
+	}
+}
+
+func (m *publicMessage) unmarshal(r *reader) {
+	m.content.unmarshal(r)
+	m.signature = r.opaque()
+	if m.content.contentType == contentCommit {
+		m.confirmationTag = r.opaque()
+	}
+	if m.content.sender.senderType == senderMember {
+		m.membershipTag = r.opaque()
+	}
+}
+
+// authenticatedContent returns the AuthenticatedContent of the message.
+func (m *publicMessage) authenticatedContent() []byte {
+	return encode(func(w *writer) {
+		w.u16(wireFormatPublicMessage)
+		m.content.marshal(w)
+		m.marshalAuth(w)
+	})
+}
+
+// tbm returns the AuthenticatedContentTBM of the message.
+func (m *publicMessage) tbm(ctx *groupContext) []byte {
+	return encode(func(w *writer) {
+		w.b = append(w.b, m.content.tbs(ctx)...)
+		m.marshalAuth(w)
+	})
+}
+
+// Message is an MLS message, with the public message wire format, which
+// holds a proposal or a commit.
+type Message struct {
+	public publicMessage
+}
+
+// Marshal returns the encoding of the MLSMessage.
+func (m *Message) Marshal() []byte {
+	return encode(func(w *writer) {
+		w.u16(ProtocolVersion)
+		w.u16(wireFormatPublicMessage)
+		m.public.marshal(w)
+	})
+}
+
+// UnmarshalMessage decodes an MLSMessage, which must hold a public message.
+func UnmarshalMessage(data []byte) (*Message, error) {
+	m := &Message{}
+	r := reader{b: data}
+	m.unmarshal(&r)
+	return m, r.done()
+}
+
+func (m *Message) unmarshal(r *reader) {
+	if r.u16() != ProtocolVersion {
+		r.fail()
+		return
+	}
+	if r.u16() != wireFormatPublicMessage {
+		r.err = ErrUnsupported
+		r.b = nil
+		return
+	}
+	m.public.unmarshal(r)
+}
+
+// ReadMessage decodes the MLSMessage at the start of data, and returns it
+// with the rest of data.
+func ReadMessage(data []byte) (*Message, []byte, error) {
+	m := &Message{}
+	r := reader{b: data}
+	m.unmarshal(&r)
+	return m, r.b, r.err
+}
+
+// UnmarshalMessages decodes consecutive MLSMessages.
+func UnmarshalMessages(data []byte) ([]*Message, error) {
+	var messages []*Message
+	r := reader{b: data}
+	for r.err == nil && len(r.b) > 0 {
+		m := &Message{}
+		m.unmarshal(&r)
+		messages = append(messages, m)
+	}
+	return messages, r.err
+}
+
+// Epoch returns the epoch of the group the message was sent in.
+func (m *Message) Epoch() uint64 {
+	return m.public.content.epoch
+}
+
+// NewExternalProposal returns a proposal sent by the external sender at the
+// given index of the external senders of a group.
+func NewExternalProposal(groupID []byte, epoch uint64, senderIndex uint32, key *SignatureKey, p *Proposal) *Message {
+	m := &Message{}
+	m.public.content = framedContent{
+		groupID:     groupID,
+		epoch:       epoch,
+		sender:      sender{senderExternal, senderIndex},
+		contentType: contentProposal,
+		proposal:    p,
+	}
+	m.public.signature = key.signWithLabel("FramedContentTBS", m.public.content.tbs(nil))
+	return m
+}
+
+// ProposalRef returns the reference of a proposal message.
+func (m *Message) ProposalRef() []byte {
+	return refHash("MLS 1.0 Proposal Reference", m.public.authenticatedContent())
+}
+
+// groupContext is the GroupContext structure.
+type groupContext struct {
+	groupID                 []byte
+	epoch                   uint64
+	treeHash                []byte
+	confirmedTranscriptHash []byte
+	extensions              []extension
+}
+
+func (c *groupContext) marshal(w *writer) {
+	w.u16(ProtocolVersion)
+	w.u16(CipherSuite)
+	w.opaque(c.groupID)
+	w.u64(c.epoch)
+	w.opaque(c.treeHash)
+	w.opaque(c.confirmedTranscriptHash)
+	marshalExtensions(w, c.extensions)
+}
+
+func (c *groupContext) unmarshal(r *reader) {
+	if r.u16() != ProtocolVersion || r.u16() != CipherSuite {
+		r.err = ErrUnsupported
+		r.b = nil
+		return
+	}
+	c.groupID = r.opaque()
+	c.epoch = r.u64()
+	c.treeHash = r.opaque()
+	c.confirmedTranscriptHash = r.opaque()
+	c.extensions = unmarshalExtensions(r)
+}
+
+// groupInfo is the GroupInfo structure.
+type groupInfo struct {
+	groupContext    groupContext
+	extensions      []extension
+	confirmationTag []byte
+	signer          uint32
+	signature       []byte
+}
+
+func (g *groupInfo) marshalContent(w *writer) {
+	g.groupContext.marshal(w)
+	marshalExtensions(w, g.extensions)
+	w.opaque(g.confirmationTag)
+	w.u32(g.signer)
+}
+
+func (g *groupInfo) marshal(w *writer) {
+	g.marshalContent(w)
+	w.opaque(g.signature)
+}
+
+func (g *groupInfo) unmarshal(r *reader) {
+	g.groupContext.unmarshal(r)
+	g.extensions = unmarshalExtensions(r)
+	g.confirmationTag = r.opaque()
+	g.signer = r.u32()
+	g.signature = r.opaque()
+}
+
+// groupSecrets is the GroupSecrets structure, without pre-shared keys.
+type groupSecrets struct {
+	joinerSecret []byte
+	pathSecret   []byte
+}
+
+func (s *groupSecrets) marshal(w *writer) {
+	w.opaque(s.joinerSecret)
+	w.optional(s.pathSecret != nil, func(w *writer) { w.opaque(s.pathSecret) })
+	w.varint(0) // psks
+}
+
+func (s *groupSecrets) unmarshal(r *reader) {
+	s.joinerSecret = r.opaque()
+	r.optional(func(r *reader) { s.pathSecret = r.opaque() })
+	if len(r.opaque()) > 0 {
+		r.err = ErrUnsupported
+		r.b = nil
+	}
+}
+
+// encryptedGroupSecrets is the EncryptedGroupSecrets structure.
+type encryptedGroupSecrets struct {
+	newMember []byte
+	secrets   hpkeCiphertext
+}
+
+// Welcome is a welcome message, which adds new members to a group.
+type Welcome struct {
+	secrets            []encryptedGroupSecrets
+	encryptedGroupInfo []byte
+}
+
+func (m *Welcome) marshal(w *writer) {
+	w.u16(CipherSuite)
+	w.vector(func(w *writer) {
+		for _, s := range m.secrets {
+			w.opaque(s.newMember)
+			s.secrets.marshal(w)
+		}
+	})
+	w.opaque(m.encryptedGroupInfo)
+}
+
+func (m *Welcome) unmarshal(r *reader) {
+	if r.u16() != CipherSuite {
+		r.err = ErrUnsupported
+		r.b = nil
+		return
+	}
+	r.vector(func(r *reader) {
+		var s encryptedGroupSecrets
+		s.newMember = r.opaque()
+		s.secrets.unmarshal(r)
+		m.secrets = append(m.secrets, s)
+	})
+	m.encryptedGroupInfo = r.opaque()
+}
+
+// Marshal returns the encoding of the Welcome structure.
+func (m *Welcome) Marshal() []byte {
+	return encode(m.marshal)
+}
+
+// UnmarshalWelcome decodes a Welcome structure.
+func UnmarshalWelcome(data []byte) (*Welcome, error) {
+	m := &Welcome{}
+	return m, decode(data, m.unmarshal)
+}
@@ -0,0 +1,394 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the ratchet tree of RFC 9420 section 7, and the array
+// based tree math of its appendix C.
+
+package mls
+
+import (
+	"bytes"
+	"slices"
+)
+
+// level returns the level of a node, 0 for leaves.
+func level(x uint32) int {
+	k := 0
+	for x&1 == 1 {
+		k++
+		x >>= 1
+	}
+	return k
+}
+
+// left returns the left child of a parent node.
+func left(x uint32) uint32 {
+	return x ^ (1 << (level(x) - 1))
+}
+
+// right returns the right child of a parent node.
+func right(x uint32) uint32 {
+	return x ^ (3 << (level(x) - 1))
+}
+
+// parent returns the parent of a node, in a tree wide enough to contain it.
+func parent(x uint32) uint32 {
+	k := level(x)
+	b := (x >> (k + 1)) & 1
+	return (x | (1 << k)) ^ (b << (k + 1))
+}
+
+// sibling returns the other child of the parent of a node.
+func sibling(x uint32) uint32 {
+	if p := parent(x); x < p {
+		return right(p)
+	} else {
+		return left(p)
+	}
+}
+
+// inSubtree reports whether the node y is in the subtree of x.
+func inSubtree(y, x uint32) bool {
+	span := uint32(1)<<level(x) - 1
+	return y+span >= x && y <= x+span
+}
+
+// parentNode is the ParentNode structure.
+type parentNode struct {
+	encryptionKey  []byte
+	parentHash     []byte
+	unmergedLeaves []uint32
+}
+
+// marshal writes the parent node, without the excluded unmerged leaves.
+func (n *parentNode) marshal(w *writer, exclude []uint32) {
+	w.opaque(n.encryptionKey)
+	w.opaque(n.parentHash)
+	w.vector(func(w *writer) {
+		for _, l := range n.unmergedLeaves {
+			if !slices.Contains(exclude, l) {
+				w.u32(l)
+			}
+		}
+	})
+}
+
+func (n *parentNode) unmarshal(r *reader) {
+	n.encryptionKey = r.opaque()
+	n.parentHash = r.opaque()
+	r.vector(func(r *reader) { n.unmergedLeaves = append(n.unmergedLeaves, r.u32()) })
+}
+
+// node is a node of a ratchet tree, blank when it holds neither a leaf nor a
+// parent node.
+type node struct {
+	leaf   *leafNode
+	parent *parentNode
+}
+
+func (n *node) blank() bool {
+	return n.leaf == nil && n.parent == nil
+}
+
+// encryptionKey returns the public key of a non-blank node.
+func (n *node) encryptionKey() []byte {
+	if n.leaf != nil {
+		return n.leaf.encryptionKey
+	}
+	return n.parent.encryptionKey
+}
+
+// parentHash returns the parent hash of a non-blank node.
+func (n *node) parentHash() []byte {
+	if n.leaf != nil {
+		if n.leaf.source != leafSourceCommit {
+			return nil
+		}
+		return n.leaf.parentHash
+	}
+	return n.parent.parentHash
+}
+
+// ratchetTree is a ratchet tree, whose leaf count is always a power of two.
+type ratchetTree struct {
+	nodes []node
+}
+
+// leaves returns the number of leaves of the tree.
+func (t *ratchetTree) leaves() uint32 {
+	return uint32(len(t.nodes)+1) / 2
+}
+
+// root returns the root node of the tree.
+func (t *ratchetTree) root() uint32 {
+	return uint32(len(t.nodes)) / 2
+}
+
+// leaf returns the leaf node of a leaf index, nil when blank or out of range.
+func (t *ratchetTree) leaf(l uint32) *leafNode {
+	if l >= t.leaves() {
+		return nil
+	}
+	return t.nodes[2*l].leaf
+}
+
+func (t *ratchetTree) clone() *ratchetTree {
+	c := &ratchetTree{nodes: make([]node, len(t.nodes))}
+	for i, n := range t.nodes {
+		if n.leaf != nil {
+			c.nodes[i].leaf = n.leaf.clone()
+		}
+		if n.parent != nil {
+			p := *n.parent
+			p.unmergedLeaves = slices.Clone(p.unmergedLeaves)
+			c.nodes[i].parent = &p
+		}
+	}
+	return c
+}
+
+// directPath returns the ancestors of a node, up to the root.
+func (t *ratchetTree) directPath(x uint32) []uint32 {
+	var path []uint32
+	for x != t.root() {
+		x = parent(x)
+		path = append(path, x)
+	}
+	return path
+}
+
+// resolution returns the resolution of a node.
+func (t *ratchetTree) resolution(x uint32) []uint32 {
+	n := &t.nodes[x]
+	switch {
+	case n.leaf != nil:
+		return []uint32{x}
+	case n.parent != nil:
+		res := []uint32{x}
+		for _, l := range n.parent.unmergedLeaves {
+			res = append(res, 2*l)
+		}
+		return res
+	case level(x) == 0:
+		return nil
+	default:
+		return append(t.resolution(left(x)), t.resolution(right(x))...)
+	}
+}
+
+// pathStep is a node of a filtered direct path, with its child on the copath.
+type pathStep struct {
+	node, copath uint32
+}
+
+// filteredDirectPath returns the filtered direct path of a leaf.
+func (t *ratchetTree) filteredDirectPath(l uint32) []pathStep {
+	var path []pathStep
+	for x := 2 * l; x != t.root(); x = parent(x) {
+		if s := sibling(x); len(t.resolution(s)) > 0 {
+			path = append(path, pathStep{parent(x), s})
+		}
+	}
+	return path
+}
+
+// addLeaf adds a leaf node at the leftmost blank leaf, extending the tree when
+// it is full, and returns its leaf index.
+func (t *ratchetTree) addLeaf(n *leafNode) uint32 {
+	l := uint32(0)
+	for ; l < t.leaves() && t.nodes[2*l].leaf != nil; l++ {
+	}
+	if l == t.leaves() {
+		t.nodes = append(t.nodes, make([]node, len(t.nodes)+1)...)
+	}
+
+	t.nodes[2*l].leaf = n
+	for _, p := range t.directPath(2 * l) {
+		if pn := t.nodes[p].parent; pn != nil {
+			pn.unmergedLeaves = append(pn.unmergedLeaves, l)
+		}
+	}
+	return l
+}
+
+// removeLeaf blanks a leaf and its direct path, and truncates the tree.
+func (t *ratchetTree) removeLeaf(l uint32) {
+	t.nodes[2*l] = node{}
+	for _, p := range t.directPath(2 * l) {
+		t.nodes[p] = node{}
+	}
+
+	for t.leaves() > 1 {
+		n := t.leaves()
+		for x := n; x < 2*n-1; x += 2 {
+			if t.nodes[x].leaf != nil {
+				return
+			}
+		}
+		t.nodes = t.nodes[:n-1]
+	}
+}
+
+// treeHash returns the tree hash of the subtree of a node, with the excluded
+// leaves blanked and removed from unmerged leaves.
+func (t *ratchetTree) treeHash(x uint32, exclude []uint32) []byte {
+	if level(x) == 0 {
+		leaf := t.nodes[x].leaf
+		if slices.Contains(exclude, x/2) {
+			leaf = nil
+		}
+		return hash(encode(func(w *writer) {
+			w.u8(nodeLeaf)
+			w.u32(x / 2)
+			w.optional(leaf != nil, leaf.marshal)
+		}))
+	}
+
+	l, r := t.treeHash(left(x), exclude), t.treeHash(right(x), exclude)
+	p := t.nodes[x].parent
+	return hash(encode(func(w *writer) {
+		w.u8(nodeParent)
+		w.optional(p != nil, func(w *writer) { p.marshal(w, exclude) })
+		w.opaque(l)
+		w.opaque(r)
+	}))
+}
+
+// rootHash returns the tree hash of the tree.
+func (t *ratchetTree) rootHash() []byte {
+	return t.treeHash(t.root(), nil)
+}
+
+// parentHash returns the parent hash of the child of the parent node p,
+// whose sibling is s.
+func (t *ratchetTree) parentHash(p, s uint32) []byte {
+	pn := t.nodes[p].parent
+	return hash(encode(func(w *writer) {
+		w.opaque(pn.encryptionKey)
+		w.opaque(pn.parentHash)
+		w.opaque(t.treeHash(s, pn.unmergedLeaves))
+	}))
+}
+
+// setParentHashes sets the parent hashes of the filtered direct path of a
+// leaf, whose parent nodes have been replaced, and returns the parent hash of
+// the leaf.
+func (t *ratchetTree) setParentHashes(l uint32) []byte {
+	var h []byte
+	path := t.filteredDirectPath(l)
+	for i := len(path) - 1; i >= 0; i-- {
+		t.nodes[path[i].node].parent.parentHash = h
+		h = t.parentHash(path[i].node, path[i].copath)
+	}
+	return h
+}
+
+// parentHashValid reports whether a parent node is parent-hash valid relative
+// to one of its children.
+func (t *ratchetTree) parentHashValid(p uint32) bool {
+	unmerged := t.nodes[p].parent.unmergedLeaves
+	for _, c := range []uint32{left(p), right(p)} {
+		h := t.parentHash(p, sibling(c))
+		for _, d := range t.resolution(c) {
+			if !bytes.Equal(t.nodes[d].parentHash(), h) {
+				continue
+			}
+
+			// The rest of the resolution must be the unmerged leaves of p
+			// below c.
+			rest := 0
+			for _, x := range t.resolution(c) {
+				if x != d {
+					if x&1 == 1 || !slices.Contains(unmerged, x/2) {
+						return false
+					}
+					rest++
+				}
+			}
+			for _, l := range unmerged {
+				if inSubtree(2*l, c) {
+					rest--
+				}
+			}
+			return rest == 0
+		}
+	}
+	return false
+}
+
+// verify verifies the leaf signatures and parent hashes of the tree, and that
+// its hash is treeHash.
+func (t *ratchetTree) verify(groupID, treeHash []byte) bool {
+	if !bytes.Equal(t.rootHash(), treeHash) {
+		return false
+	}
+	for x := range t.nodes {
+		n := &t.nodes[x]
+		switch {
+		case n.leaf != nil:
+			if !n.leaf.supported() || !n.leaf.verify(groupID, uint32(x/2)) {
+				return false
+			}
+		case n.parent != nil:
+			if !t.parentHashValid(uint32(x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// marshal writes the tree, in the ratchet_tree extension format.
+func (t *ratchetTree) marshal(w *writer) {
+	nodes := t.nodes
+	for len(nodes) > 0 && nodes[len(nodes)-1].blank() {
+		nodes = nodes[:len(nodes)-1]
+	}
+	w.vector(func(w *writer) {
+		for _, n := range nodes {
+			w.optional(!n.blank(), func(w *writer) {
+				if n.leaf != nil {
+					w.u8(nodeLeaf)
+					n.leaf.marshal(w)
+				} else {
+					w.u8(nodeParent)
+					n.parent.marshal(w, nil)
+				}
+			})
+		}
+	})
+}
+
+func (t *ratchetTree) unmarshal(r *reader) {
+	r.vector(func(r *reader) {
+		var n node
+		r.optional(func(r *reader) {
+			nodeType := r.u8()
+			switch {
+			case nodeType == nodeLeaf && len(t.nodes)%2 == 0:
+				n.leaf = &leafNode{}
+				n.leaf.unmarshal(r)
+			case nodeType == nodeParent && len(t.nodes)%2 == 1:
+				n.parent = &parentNode{}
+				n.parent.unmarshal(r)
+			default:
+				r.fail()
+			}
+		})
+		t.nodes = append(t.nodes, n)
+	})
+	if len(t.nodes) == 0 || t.nodes[len(t.nodes)-1].blank() {
+		r.fail()
+		return
+	}
+
+	width := 1
+	for width < len(t.nodes) {
+		width = 2*width + 1
+	}
+	t.nodes = append(t.nodes, make([]node, width-len(t.nodes))...)
+}
@@ -0,0 +1,89 @@
+package mls
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTreeMath(t *testing.T) {
+	// The tree of 8 leaves of RFC 9420 appendix C.
+	tree := &ratchetTree{nodes: make([]node, 15)}
+	if tree.leaves() != 8 || tree.root() != 7 {
+		t.Fatalf("unexpected leaves %d and root %d", tree.leaves(), tree.root())
+	}
+
+	tests := []struct {
+		x, level, parent, sibling uint32
+	}{
+		{0, 0, 1, 2},
+		{1, 1, 3, 5},
+		{3, 2, 7, 11},
+		{6, 0, 5, 4},
+		{9, 1, 11, 13},
+		{11, 2, 7, 3},
+		{14, 0, 13, 12},
+	}
+	for _, test := range tests {
+		if l := level(test.x); l != int(test.level) {
+			t.Errorf("expected level(%d) = %d, got %d", test.x, test.level, l)
+		}
+		if p := parent(test.x); p != test.parent {
+			t.Errorf("expected parent(%d) = %d, got %d", test.x, test.parent, p)
+		}
+		if s := sibling(test.x); s != test.sibling {
+			t.Errorf("expected sibling(%d) = %d, got %d", test.x, test.sibling, s)
+		}
+	}
+	if left(7) != 3 || right(7) != 11 || left(5) != 4 || right(5) != 6 {
+		t.Error("unexpected children")
+	}
+	if path := tree.directPath(4); !slices.Equal(path, []uint32{5, 3, 7}) {
+		t.Errorf("unexpected direct path %v", path)
+	}
+	if !inSubtree(6, 3) || inSubtree(8, 3) || !inSubtree(3, 3) {
+		t.Error("unexpected subtrees")
+	}
+}
+
+func TestTreeResolution(t *testing.T) {
+	tree := &ratchetTree{}
+	for i := 0; i < 5; i++ {
+		tree.addLeaf(&leafNode{encryptionKey: []byte{byte(i)}, source: leafSourceUpdate})
+	}
+	if tree.leaves() != 8 {
+		t.Fatalf("expected 8 leaves, got %d", tree.leaves())
+	}
+
+	tree.nodes[3].parent = &parentNode{encryptionKey: []byte{3}}
+	tree.removeLeaf(1)
+	if res := tree.resolution(7); !slices.Equal(res, []uint32{0, 4, 6, 8}) {
+		t.Errorf("unexpected resolution %v", res)
+	}
+	if path := tree.filteredDirectPath(2); !slices.Equal(path, []pathStep{{5, 6}, {3, 1}, {7, 11}}) {
+		t.Errorf("unexpected filtered direct path %v", path)
+	}
+
+	// The parent node above an added leaf lists it as unmerged.
+	tree.nodes[3].parent = &parentNode{encryptionKey: []byte{3}}
+	if l := tree.addLeaf(&leafNode{source: leafSourceUpdate}); l != 1 {
+		t.Fatalf("expected leaf 1 to be reused, got %d", l)
+	}
+	if res := tree.resolution(3); !slices.Equal(res, []uint32{3, 2}) {
+		t.Errorf("unexpected resolution %v", res)
+	}
+
+	// The tree is truncated once its right half is blank.
+	tree.removeLeaf(4)
+	if tree.leaves() != 4 {
+		t.Errorf("expected 4 leaves, got %d", tree.leaves())
+	}
+
+	data := encode(tree.marshal)
+	decoded := &ratchetTree{}
+	if err := decode(data, decoded.unmarshal); err != nil {
+		t.Fatalf("unexpected error decoding tree: %v", err)
+	}
+	if !slices.Equal(decoded.rootHash(), tree.rootHash()) {
+		t.Error("expected decoded tree to have the same hash")
+	}
+}
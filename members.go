@@ -242,19 +242,29 @@ func (s *Session) QueryGuildMembers(params QueryGuildMembersParams) ([]*Member,
 
 	payload := QueryGuildMembersPayload{Op: OP_REQUEST_MEMBERS, Data: data}
 
+	// Subscribe before requesting, so no chunk is missed.
+	chunks, cancel := Subscribe[GuildMembersChunk](s, Match(func(c *GuildMembersChunk) bool {
+		return c.Nonce == nonce
+	}))
+	defer cancel()
+
 	err := s.SendWsData(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	ch := make(chan *GuildMembersChunk, 1)
-
 	members := []*Member{}
 
-	removeHandler := s.AddHandler(func(_ *Session, event *GuildMembersChunk) {
-		if event.Nonce != nonce {
-			return
+	// Chunks may be received out of order, so they are counted.
+	timeout := time.After(10 * time.Second)
+	for received, count := 0, 1; received < count; received++ {
+		var event *GuildMembersChunk
+		select {
+		case event = <-chunks:
+		case <-timeout:
+			return nil, fmt.Errorf("timeout")
 		}
+		count = event.ChunkCount
 
 		for _, member := range event.Members {
 			for _, presence := range event.Presences {
@@ -265,34 +275,21 @@ func (s *Session) QueryGuildMembers(params QueryGuildMembersParams) ([]*Member,
 		}
 
 		members = append(members, event.Members...)
+	}
 
-		if event.ChunkIndex == event.ChunkCount-1 {
-			ch <- event
-		}
-	})
-
-	select {
-	case <-ch:
-		removeHandler()
-
-		if params.Subscribe {
-			// TODO: implement subscribe
-			return nil, fmt.Errorf("subscribe is not supported yet")
-		}
+	if params.Subscribe {
+		// TODO: implement subscribe
+		return nil, fmt.Errorf("subscribe is not supported yet")
+	}
 
-		if params.Cache {
-			for _, member := range members {
-				member.GuildID = params.GuildID
-				s.State.MemberAdd(member)
-			}
+	if params.Cache {
+		for _, member := range members {
+			member.GuildID = params.GuildID
+			s.State.MemberAdd(member)
 		}
-
-		return members, nil
-	case <-time.After(10 * time.Second):
-		removeHandler()
-
-		return nil, fmt.Errorf("timeout")
 	}
+
+	return members, nil
 }
 
 // Scraping member sidebar
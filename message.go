@@ -105,6 +105,10 @@ type Message struct {
 	// The type of the message.
 	Type MessageType `json:"type"`
 
+	// The nonce the message was sent with. It is only set in the response
+	// and the MESSAGE_CREATE event of a message sent with a nonce.
+	Nonce string `json:"nonce,omitempty"`
+
 	// The webhook ID of the message, if it was generated by a webhook
 	WebhookID string `json:"webhook_id"`
 
@@ -160,24 +164,55 @@ type Message struct {
 	GiftCodes []string `json:"gift_codes,omitempty"`
 }
 
+// rawMessage is a Message as Discord sends it, with its components decoded
+// by their type.
+type rawMessage struct {
+	message
+	RawComponents []unmarshalableMessageComponent `json:"components"`
+
+	// Nonces are sent as strings or integers.
+	RawNonce json.RawMessage `json:"nonce"`
+}
+
+type message Message
+
 // UnmarshalJSON is a helper function to unmarshal the Message.
 func (m *Message) UnmarshalJSON(data []byte) error {
-	type message Message
-	var v struct {
-		message
-		RawComponents []unmarshalableMessageComponent `json:"components"`
-	}
+	var v rawMessage
 	err := json.Unmarshal(data, &v)
 	if err != nil {
 		fmt.Println("Error unmarshaling Message: ", err)
 		return err
 	}
+	m.fromRaw(&v)
+	return err
+}
+
+func (m *Message) unmarshalETF(d *etfDecoder, h etfHeader) error {
+	var v rawMessage
+	if err := d.decode(h, &v); err != nil {
+		return err
+	}
+	m.fromRaw(&v)
+	return nil
+}
+
+// fromRaw sets m from the message as Discord sends it.
+func (m *Message) fromRaw(v *rawMessage) {
 	*m = Message(v.message)
 	m.Components = make([]MessageComponent, len(v.RawComponents))
 	for i, v := range v.RawComponents {
 		m.Components[i] = v.MessageComponent
 	}
-	return err
+
+	m.Nonce = ""
+	switch {
+	case len(v.RawNonce) == 0 || string(v.RawNonce) == "null":
+	case v.RawNonce[0] == '"':
+		json.Unmarshal(v.RawNonce, &m.Nonce)
+	default:
+		m.Nonce = string(v.RawNonce)
+	}
 }
 
 // GetCustomEmojis pulls out all the custom (Non-unicode) emojis from a message and returns a Slice of the Emoji struct.
@@ -306,6 +341,20 @@ type MessageSend struct {
 	Flags int    `json:"flags"`
 	Nonce string `json:"nonce,omitempty"`
 
+	// When set, Discord does not create another message with the same nonce
+	// for a few minutes, but returns the message already created, so a
+	// request retried after a network error does not post twice. A nonce is
+	// generated for each call when Nonce is empty, without setting Nonce; it
+	// is the Nonce of the returned message.
+	EnforceNonce bool `json:"enforce_nonce,omitempty"`
+
+	// When set, ChannelMessageSendComplex returns once the gateway dispatched
+	// the MESSAGE_CREATE event of the message, identified by its nonce, and
+	// returns the message of that event. A nonce is generated when Nonce is
+	// empty. It waits at most for MessageConfirmTimeout, or until the context
+	// of the request (see WithContext) is done. The session must be open.
+	Confirm bool `json:"-"`
+
 	// TODO: Remove this when compatibility is not required.
 	File *File `json:"-"`
 
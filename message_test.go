@@ -50,3 +50,21 @@ func TestGettingEmojisFromMessage(t *testing.T) {
 	}
 
 }
+
+func TestMessageNonce(t *testing.T) {
+	tests := map[string]string{
+		`{"id":"1"}`:               "",
+		`{"id":"1","nonce":null}`:  "",
+		`{"id":"1","nonce":"abc"}`: "abc",
+		`{"id":"1","nonce":123}`:   "123",
+	}
+	for data, nonce := range tests {
+		var m Message
+		if err := Unmarshal([]byte(data), &m); err != nil {
+			t.Fatalf("Unmarshal(%s) returned error: %+v", data, err)
+		}
+		if m.Nonce != nonce {
+			t.Errorf("Unmarshal(%s): expected nonce %q, got %q", data, nonce, m.Nonce)
+		}
+	}
+}
@@ -42,9 +42,9 @@ type Team struct {
 
 // Application returns an Application structure of a specific Application
 //   appID : The ID of an Application
-func (s *Session) Application(appID string) (st *Application, err error) {
+func (s *Session) Application(appID string, options ...RequestOption) (st *Application, err error) {
 
-	body, err := s.RequestWithBucketID("GET", EndpointOAuth2Application(appID), nil, EndpointOAuth2Application(""))
+	body, err := s.RequestWithBucketID("GET", EndpointOAuth2Application(appID), nil, EndpointOAuth2Application(""), options...)
 	if err != nil {
 		return
 	}
@@ -54,9 +54,9 @@ func (s *Session) Application(appID string) (st *Application, err error) {
 }
 
 // Applications returns all applications for the authenticated user
-func (s *Session) Applications() (st []*Application, err error) {
+func (s *Session) Applications(options ...RequestOption) (st []*Application, err error) {
 
-	body, err := s.RequestWithBucketID("GET", EndpointOAuth2Applications, nil, EndpointOAuth2Applications)
+	body, err := s.RequestWithBucketID("GET", EndpointOAuth2Applications, nil, EndpointOAuth2Applications, options...)
 	if err != nil {
 		return
 	}
@@ -68,14 +68,14 @@ func (s *Session) Applications() (st []*Application, err error) {
 // ApplicationCreate creates a new Application
 //    name : Name of Application / Bot
 //    uris : Redirect URIs (Not required)
-func (s *Session) ApplicationCreate(ap *Application) (st *Application, err error) {
+func (s *Session) ApplicationCreate(ap *Application, options ...RequestOption) (st *Application, err error) {
 
 	data := struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	}{ap.Name, ap.Description}
 
-	body, err := s.RequestWithBucketID("POST", EndpointOAuth2Applications, data, EndpointOAuth2Applications)
+	body, err := s.RequestWithBucketID("POST", EndpointOAuth2Applications, data, EndpointOAuth2Applications, options...)
 	if err != nil {
 		return
 	}
@@ -86,14 +86,14 @@ func (s *Session) ApplicationCreate(ap *Application) (st *Application, err error
 
 // ApplicationUpdate updates an existing Application
 //   var : desc
-func (s *Session) ApplicationUpdate(appID string, ap *Application) (st *Application, err error) {
+func (s *Session) ApplicationUpdate(appID string, ap *Application, options ...RequestOption) (st *Application, err error) {
 
 	data := struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	}{ap.Name, ap.Description}
 
-	body, err := s.RequestWithBucketID("PUT", EndpointOAuth2Application(appID), data, EndpointOAuth2Application(""))
+	body, err := s.RequestWithBucketID("PUT", EndpointOAuth2Application(appID), data, EndpointOAuth2Application(""), options...)
 	if err != nil {
 		return
 	}
@@ -104,9 +104,9 @@ func (s *Session) ApplicationUpdate(appID string, ap *Application) (st *Applicat
 
 // ApplicationDelete deletes an existing Application
 //   appID : The ID of an Application
-func (s *Session) ApplicationDelete(appID string) (err error) {
+func (s *Session) ApplicationDelete(appID string, options ...RequestOption) (err error) {
 
-	_, err = s.RequestWithBucketID("DELETE", EndpointOAuth2Application(appID), nil, EndpointOAuth2Application(""))
+	_, err = s.RequestWithBucketID("DELETE", EndpointOAuth2Application(appID), nil, EndpointOAuth2Application(""), options...)
 	if err != nil {
 		return
 	}
@@ -122,9 +122,9 @@ type Asset struct {
 }
 
 // ApplicationAssets returns an application's assets
-func (s *Session) ApplicationAssets(appID string) (ass []*Asset, err error) {
+func (s *Session) ApplicationAssets(appID string, options ...RequestOption) (ass []*Asset, err error) {
 
-	body, err := s.RequestWithBucketID("GET", EndpointOAuth2ApplicationAssets(appID), nil, EndpointOAuth2ApplicationAssets(""))
+	body, err := s.RequestWithBucketID("GET", EndpointOAuth2ApplicationAssets(appID), nil, EndpointOAuth2ApplicationAssets(""), options...)
 	if err != nil {
 		return
 	}
@@ -142,9 +142,9 @@ func (s *Session) ApplicationAssets(appID string) (ass []*Asset, err error) {
 //   appID : The ID of an Application
 //
 // NOTE: func name may change, if I can think up something better.
-func (s *Session) ApplicationBotCreate(appID string) (st *User, err error) {
+func (s *Session) ApplicationBotCreate(appID string, options ...RequestOption) (st *User, err error) {
 
-	body, err := s.RequestWithBucketID("POST", EndpointOAuth2ApplicationsBot(appID), nil, EndpointOAuth2ApplicationsBot(""))
+	body, err := s.RequestWithBucketID("POST", EndpointOAuth2ApplicationsBot(appID), nil, EndpointOAuth2ApplicationsBot(""), options...)
 	if err != nil {
 		return
 	}
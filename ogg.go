@@ -0,0 +1,222 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the pages of the Ogg container (RFC 3533), which carry
+// the packets of a logical stream split into segments of up to 255 bytes.
+
+package discordgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidOggOpus is returned when reading an Ogg stream which is corrupt,
+// or does not contain a supported Opus stream.
+var ErrInvalidOggOpus = errors.New("invalid Ogg Opus stream")
+
+// Ogg page header types.
+const (
+	oggContinued = 0x01
+	oggBOS       = 0x02
+	oggEOS       = 0x04
+)
+
+// oggHeaderSize is the size of a page header, without its segment table.
+const oggHeaderSize = 27
+
+// oggNoGranule is the granule position of pages on which no packet ends.
+const oggNoGranule = ^uint64(0)
+
+// oggCRCTable is the table of the CRC of Ogg pages, with the polynomial
+// 0x04C11DB7, not reflected.
+var oggCRCTable = func() (t [256]uint32) {
+	for i := range t {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		t[i] = crc
+	}
+	return
+}()
+
+// oggCRC updates the CRC of an Ogg page with b.
+func oggCRC(crc uint32, b []byte) uint32 {
+	for _, c := range b {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^c]
+	}
+	return crc
+}
+
+// An oggPage is a page of an Ogg stream.
+type oggPage struct {
+	headerType byte
+	granule    uint64
+	serial     uint32
+	sequence   uint32
+
+	// segments are the lacing values of the segments of data.
+	segments []byte
+	data     []byte
+}
+
+// readOggPage reads the next page of an Ogg stream, and checks its CRC. It
+// returns io.EOF at the end of the stream, and io.ErrUnexpectedEOF when it
+// ends within a page.
+func readOggPage(r *bufio.Reader) (*oggPage, error) {
+	header := make([]byte, oggHeaderSize, oggHeaderSize+255)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "OggS" || header[4] != 0 {
+		return nil, fmt.Errorf("%w: bad page header", ErrInvalidOggOpus)
+	}
+
+	p := &oggPage{
+		headerType: header[5],
+		granule:    binary.LittleEndian.Uint64(header[6:]),
+		serial:     binary.LittleEndian.Uint32(header[14:]),
+		sequence:   binary.LittleEndian.Uint32(header[18:]),
+	}
+	header = header[:oggHeaderSize+int(header[26])]
+	if _, err := io.ReadFull(r, header[oggHeaderSize:]); err != nil {
+		return nil, noEOF(err)
+	}
+	p.segments = header[oggHeaderSize:]
+
+	size := 0
+	for _, n := range p.segments {
+		size += int(n)
+	}
+	p.data = make([]byte, size)
+	if _, err := io.ReadFull(r, p.data); err != nil {
+		return nil, noEOF(err)
+	}
+
+	crc := binary.LittleEndian.Uint32(header[22:])
+	binary.LittleEndian.PutUint32(header[22:], 0)
+	if oggCRC(oggCRC(0, header), p.data) != crc {
+		return nil, fmt.Errorf("%w: bad page checksum", ErrInvalidOggOpus)
+	}
+	return p, nil
+}
+
+// noEOF returns io.ErrUnexpectedEOF for io.EOF.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// packets splits the data of a page in packets. partial is the start of a
+// packet continued from the previous page, and the end of the data is
+// returned as the new partial packet when it continues on the next page.
+func (p *oggPage) packets(partial []byte) (packets [][]byte, next []byte) {
+	// A packet which is not continued is lost.
+	if p.headerType&oggContinued == 0 {
+		partial = nil
+	}
+
+	data := p.data
+	for _, n := range p.segments {
+		partial = append(partial, data[:n]...)
+		data = data[n:]
+		if n < 255 {
+			packets = append(packets, partial)
+			partial = nil
+		}
+	}
+	return packets, partial
+}
+
+// marshal returns the encoding of the page, with its CRC.
+func (p *oggPage) marshal() []byte {
+	b := make([]byte, oggHeaderSize, oggHeaderSize+len(p.segments)+len(p.data))
+	copy(b, "OggS")
+	b[5] = p.headerType
+	binary.LittleEndian.PutUint64(b[6:], p.granule)
+	binary.LittleEndian.PutUint32(b[14:], p.serial)
+	binary.LittleEndian.PutUint32(b[18:], p.sequence)
+	b[26] = byte(len(p.segments))
+	b = append(append(b, p.segments...), p.data...)
+
+	binary.LittleEndian.PutUint32(b[22:], oggCRC(0, b))
+	return b
+}
+
+// An oggWriter writes the packets of a logical stream in Ogg pages.
+type oggWriter struct {
+	w      io.Writer
+	serial uint32
+
+	// page is the page being filled, granule the granule position after
+	// the last packet written, and packets the number of packets ending on
+	// the page.
+	page    oggPage
+	granule uint64
+	packets int
+}
+
+// writePacket adds a packet to the current page, and sets the granule
+// position after it. The page is written when its segment table is full, the
+// packet continuing on the next one.
+func (w *oggWriter) writePacket(packet []byte, granule uint64) error {
+	for {
+		n := min(len(packet), 255)
+		w.page.segments = append(w.page.segments, byte(n))
+		w.page.data = append(w.page.data, packet[:n]...)
+		packet = packet[n:]
+		if n < 255 {
+			w.granule = granule
+			w.packets++
+		}
+
+		if len(w.page.segments) == 255 {
+			if err := w.flush(0); err != nil {
+				return err
+			}
+			if n == 255 {
+				w.page.headerType = oggContinued
+			}
+		}
+		if n < 255 {
+			return nil
+		}
+	}
+}
+
+// flush writes the current page, with the given additional header type.
+// Empty pages are only written to end the stream.
+func (w *oggWriter) flush(headerType byte) error {
+	if len(w.page.segments) == 0 && headerType&oggEOS == 0 {
+		return nil
+	}
+
+	w.page.headerType |= headerType
+	if w.page.sequence == 0 {
+		w.page.headerType |= oggBOS
+	}
+	w.page.serial = w.serial
+	w.page.granule = oggNoGranule
+	if w.packets > 0 || len(w.page.segments) == 0 {
+		w.page.granule = w.granule
+	}
+
+	_, err := w.w.Write(w.page.marshal())
+	w.page = oggPage{sequence: w.page.sequence + 1}
+	w.packets = 0
+	return err
+}
@@ -0,0 +1,111 @@
+package discordgo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestOggCRC(t *testing.T) {
+	// The check value of the CRC-32 with the polynomial 0x04C11DB7, without
+	// reflection nor initial and final XOR.
+	if crc := oggCRC(0, []byte("123456789")); crc != 0x89A1897F {
+		t.Errorf("expected CRC 89a1897f, got %08x", crc)
+	}
+}
+
+// readOggPages returns the pages of an Ogg stream.
+func readOggPages(t *testing.T, b []byte) []*oggPage {
+	t.Helper()
+
+	var pages []*oggPage
+	r := bufio.NewReader(bytes.NewReader(b))
+	for {
+		p, err := readOggPage(r)
+		if err == io.EOF {
+			return pages
+		}
+		if err != nil {
+			t.Fatalf("readOggPage returned error: %v", err)
+		}
+		pages = append(pages, p)
+	}
+}
+
+func TestOggPages(t *testing.T) {
+	var b bytes.Buffer
+	w := &oggWriter{w: &b, serial: 42}
+
+	// The second packet is a multiple of 255 bytes, ended by an empty
+	// segment, and the third does not fit in a page.
+	packets := [][]byte{
+		bytes.Repeat([]byte{1}, 10),
+		bytes.Repeat([]byte{2}, 510),
+		bytes.Repeat([]byte{3}, 300*255),
+		bytes.Repeat([]byte{4}, 20),
+	}
+	for i, packet := range packets {
+		if err := w.writePacket(packet, uint64(i+1)*960); err != nil {
+			t.Fatalf("writePacket returned error: %v", err)
+		}
+	}
+	if err := w.flush(oggEOS); err != nil {
+		t.Fatalf("flush returned error: %v", err)
+	}
+
+	pages := readOggPages(t, b.Bytes())
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+	if p := pages[0]; p.headerType != oggBOS || p.granule != 2*960 || p.sequence != 0 || p.serial != 42 {
+		t.Errorf("unexpected first page %+v", p)
+	}
+	if p := pages[1]; p.headerType != oggContinued|oggEOS || p.granule != 4*960 || p.sequence != 1 {
+		t.Errorf("unexpected last page %+v", p)
+	}
+
+	var got [][]byte
+	var partial []byte
+	for _, p := range pages {
+		var ps [][]byte
+		ps, partial = p.packets(partial)
+		got = append(got, ps...)
+	}
+	if len(got) != len(packets) || partial != nil {
+		t.Fatalf("expected %d packets, got %d", len(packets), len(got))
+	}
+	for i := range packets {
+		if !bytes.Equal(got[i], packets[i]) {
+			t.Errorf("packet %d: expected %d bytes, got %d", i, len(packets[i]), len(got[i]))
+		}
+	}
+
+	// Pages on which no packet ends have no granule position.
+	b.Reset()
+	w = &oggWriter{w: &b}
+	w.writePacket(bytes.Repeat([]byte{5}, 600*255), 960)
+	w.flush(oggEOS)
+	if pages := readOggPages(t, b.Bytes()); len(pages) != 3 || pages[0].granule != oggNoGranule || pages[1].granule != oggNoGranule || pages[2].granule != 960 {
+		t.Errorf("unexpected pages %+v", pages)
+	}
+}
+
+func TestOggPageCorrupt(t *testing.T) {
+	var b bytes.Buffer
+	w := &oggWriter{w: &b}
+	w.writePacket([]byte("packet"), 0)
+	w.flush(oggEOS)
+
+	for _, i := range []int{0, 6, 22, b.Len() - 1} {
+		corrupt := bytes.Clone(b.Bytes())
+		corrupt[i] ^= 1
+		if _, err := readOggPage(bufio.NewReader(bytes.NewReader(corrupt))); !errors.Is(err, ErrInvalidOggOpus) {
+			t.Errorf("expected byte %d to be detected, got %v", i, err)
+		}
+	}
+	if _, err := readOggPage(bufio.NewReader(bytes.NewReader(b.Bytes()[:b.Len()-1]))); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
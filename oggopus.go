@@ -0,0 +1,438 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the Ogg Opus files (RFC 7845) of voice connections: a
+// reader of the opus packets of .ogg and .opus files, which are played on
+// voice connections, and a writer of the packets they receive.
+
+package discordgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+// ErrVoiceNotReady is returned when playing or recording audio on a voice
+// connection which is not connected.
+var ErrVoiceNotReady = errors.New("voice connection is not ready")
+
+// oggOpusVendor is the vendor string of the Ogg Opus files written.
+const oggOpusVendor = "discordgo"
+
+// opusSampleRate is the rate of the timestamps and granule positions of opus
+// packets, whatever their actual sample rate.
+const opusSampleRate = 48000
+
+// opusPacketSamples returns the number of samples of an opus packet at 48 kHz,
+// from its TOC byte (RFC 6716, section 3.1), or 0 when it is malformed.
+func opusPacketSamples(packet []byte) int {
+	if len(packet) == 0 {
+		return 0
+	}
+
+	toc := packet[0]
+	config := int(toc >> 3)
+	var samples int
+	switch {
+	case config < 12: // SILK: 10, 20, 40 or 60 ms
+		samples = []int{480, 960, 1920, 2880}[config%4]
+	case config < 16: // Hybrid: 10 or 20 ms
+		samples = []int{480, 960}[config%2]
+	default: // CELT: 2.5, 5, 10 or 20 ms
+		samples = []int{120, 240, 480, 960}[config%4]
+	}
+
+	switch toc & 0x03 {
+	case 0:
+		return samples
+	case 1, 2:
+		return 2 * samples
+	}
+	if len(packet) < 2 || packet[1]&0x3F == 0 {
+		return 0
+	}
+	// The duration of a packet is at most 120 ms.
+	samples *= int(packet[1] & 0x3F)
+	if samples > 120*opusSampleRate/1000 {
+		return 0
+	}
+	return samples
+}
+
+// An OggOpusReader reads the opus packets of an Ogg Opus stream. Only the
+// first Opus logical stream is read, and only mono and stereo streams are
+// supported.
+type OggOpusReader struct {
+	// Channels is the number of channels of the stream, and PreSkip the
+	// number of samples to discard from the start of the decoded audio.
+	Channels int
+	PreSkip  int
+
+	// Vendor and Comments are from the comment header of the stream, the
+	// comments being in the TAG=value form.
+	Vendor   string
+	Comments []string
+
+	r       *bufio.Reader
+	serial  uint32
+	packets [][]byte
+	partial []byte
+	eos     bool
+}
+
+// NewOggOpusReader returns a reader of the Ogg Opus stream of r, after
+// reading its headers.
+func NewOggOpusReader(r io.Reader) (*OggOpusReader, error) {
+	o := &OggOpusReader{r: bufio.NewReader(r)}
+
+	// The stream starts with the first page of every logical stream.
+	for {
+		p, err := readOggPage(o.r)
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		if p.headerType&oggBOS == 0 {
+			return nil, fmt.Errorf("%w: no Opus stream", ErrInvalidOggOpus)
+		}
+		if bytes.HasPrefix(p.data, []byte("OpusHead")) {
+			o.serial = p.serial
+			o.packets, o.partial = p.packets(nil)
+			break
+		}
+	}
+
+	head, err := o.readPacket()
+	if err != nil {
+		return nil, noEOF(err)
+	}
+	if err := o.parseHead(head); err != nil {
+		return nil, err
+	}
+	tags, err := o.readPacket()
+	if err != nil {
+		return nil, noEOF(err)
+	}
+	if err := o.parseTags(tags); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// parseHead parses the identification header of the stream.
+func (o *OggOpusReader) parseHead(head []byte) error {
+	if len(head) < 19 || head[8]&0xF0 != 0 {
+		return fmt.Errorf("%w: bad identification header", ErrInvalidOggOpus)
+	}
+	if mapping := head[18]; mapping != 0 || head[9] < 1 || head[9] > 2 {
+		return fmt.Errorf("%w: unsupported channel mapping %d with %d channels", ErrInvalidOggOpus, mapping, head[9])
+	}
+
+	o.Channels = int(head[9])
+	o.PreSkip = int(binary.LittleEndian.Uint16(head[10:]))
+	return nil
+}
+
+// parseTags parses the comment header of the stream.
+func (o *OggOpusReader) parseTags(tags []byte) error {
+	invalid := fmt.Errorf("%w: bad comment header", ErrInvalidOggOpus)
+
+	if !bytes.HasPrefix(tags, []byte("OpusTags")) {
+		return invalid
+	}
+	tags = tags[8:]
+
+	readString := func() (string, bool) {
+		if len(tags) < 4 {
+			return "", false
+		}
+		n := binary.LittleEndian.Uint32(tags)
+		if uint64(n) > uint64(len(tags)-4) {
+			return "", false
+		}
+		s := string(tags[4 : 4+n])
+		tags = tags[4+n:]
+		return s, true
+	}
+
+	var ok bool
+	if o.Vendor, ok = readString(); !ok || len(tags) < 4 {
+		return invalid
+	}
+	n := binary.LittleEndian.Uint32(tags)
+	tags = tags[4:]
+	for i := uint32(0); i < n; i++ {
+		comment, ok := readString()
+		if !ok {
+			return invalid
+		}
+		o.Comments = append(o.Comments, comment)
+	}
+	return nil
+}
+
+// readPacket returns the next packet of the logical stream.
+func (o *OggOpusReader) readPacket() ([]byte, error) {
+	for len(o.packets) == 0 {
+		if o.eos {
+			return nil, io.EOF
+		}
+
+		p, err := readOggPage(o.r)
+		if err == io.EOF {
+			// A stream without its last page is still played.
+			o.eos = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if p.serial != o.serial {
+			continue
+		}
+		o.packets, o.partial = p.packets(o.partial)
+		o.eos = p.headerType&oggEOS != 0
+	}
+
+	packet := o.packets[0]
+	o.packets = o.packets[1:]
+	return packet, nil
+}
+
+// ReadPacket returns the next opus packet of the stream, and io.EOF at its
+// end.
+func (o *OggOpusReader) ReadPacket() ([]byte, error) {
+	for {
+		packet, err := o.readPacket()
+		// Empty packets carry no audio.
+		if err != nil || len(packet) > 0 {
+			return packet, err
+		}
+	}
+}
+
+// An OggOpusWriter writes opus packets in an Ogg Opus stream.
+type OggOpusWriter struct {
+	ogg     oggWriter
+	samples uint64
+}
+
+// NewOggOpusWriter returns a writer of an Ogg Opus stream with the given
+// number of channels, 1 or 2, to w, after writing its headers. Close must be
+// called to end the stream.
+func NewOggOpusWriter(w io.Writer, channels int) (*OggOpusWriter, error) {
+	if channels < 1 || channels > 2 {
+		return nil, fmt.Errorf("unsupported number of channels %d", channels)
+	}
+	o := &OggOpusWriter{ogg: oggWriter{w: w, serial: rand.Uint32()}}
+
+	// The identification and comment headers are alone on their pages.
+	head := []byte("OpusHead\x01")
+	head = append(head, byte(channels))
+	head = binary.LittleEndian.AppendUint16(head, 0)
+	head = binary.LittleEndian.AppendUint32(head, opusSampleRate)
+	head = append(head, 0, 0, 0)
+	if err := o.writeHeader(head); err != nil {
+		return nil, err
+	}
+
+	tags := []byte("OpusTags")
+	tags = binary.LittleEndian.AppendUint32(tags, uint32(len(oggOpusVendor)))
+	tags = append(tags, oggOpusVendor...)
+	tags = binary.LittleEndian.AppendUint32(tags, 0)
+	if err := o.writeHeader(tags); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// writeHeader writes a header packet on its own page.
+func (o *OggOpusWriter) writeHeader(packet []byte) error {
+	if err := o.ogg.writePacket(packet, 0); err != nil {
+		return err
+	}
+	return o.ogg.flush(0)
+}
+
+// oggOpusPagePackets is the number of packets of the pages written, which
+// is a second of 20 ms packets.
+const oggOpusPagePackets = 50
+
+// WritePacket writes an opus packet. Packets are written by pages of about a
+// second.
+func (o *OggOpusWriter) WritePacket(opus []byte) error {
+	samples := opusPacketSamples(opus)
+	if samples == 0 {
+		return fmt.Errorf("invalid opus packet %x", opus)
+	}
+
+	o.samples += uint64(samples)
+	if err := o.ogg.writePacket(opus, o.samples); err != nil {
+		return err
+	}
+	if o.ogg.packets >= oggOpusPagePackets {
+		return o.ogg.flush(0)
+	}
+	return nil
+}
+
+// Samples returns the number of samples at 48 kHz of the packets written.
+func (o *OggOpusWriter) Samples() uint64 {
+	return o.samples
+}
+
+// Close writes the last page of the stream. It does not close the
+// underlying writer.
+func (o *OggOpusWriter) Close() error {
+	return o.ogg.flush(oggEOS)
+}
+
+// PlayOggOpus sends the opus packets of an Ogg Opus stream to the voice
+// connection, which sends them in time, until the end of the stream or ctx is
+// done.
+func (v *VoiceConnection) PlayOggOpus(ctx context.Context, r io.Reader) error {
+	o, err := NewOggOpusReader(r)
+	if err != nil {
+		return err
+	}
+
+	v.RLock()
+	opusSend := v.OpusSend
+	v.RUnlock()
+	if opusSend == nil {
+		return ErrVoiceNotReady
+	}
+
+	for {
+		packet, err := o.ReadPacket()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case opusSend <- packet:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// An OggOpusRecorder records the opus packets received by a voice
+// connection, in an Ogg Opus stream per SSRC. Silence is written for the
+// time a user does not speak, and late packets are dropped.
+type OggOpusRecorder struct {
+	create func(ssrc uint32) (io.WriteCloser, error)
+	tracks map[uint32]*oggOpusTrack
+}
+
+// An oggOpusTrack is the stream of an SSRC.
+type oggOpusTrack struct {
+	w      io.WriteCloser
+	writer *OggOpusWriter
+
+	// timestamp is the RTP timestamp of the next packet, and missing the
+	// samples of silence not written yet, as silence is written in whole
+	// frames.
+	timestamp uint32
+	missing   int
+}
+
+// NewOggOpusRecorder returns a recorder which writes the stream of every
+// SSRC to the writer returned by create, when its first packet is received.
+func NewOggOpusRecorder(create func(ssrc uint32) (io.WriteCloser, error)) *OggOpusRecorder {
+	return &OggOpusRecorder{create: create, tracks: map[uint32]*oggOpusTrack{}}
+}
+
+// WritePacket writes a received packet to the stream of its SSRC.
+func (r *OggOpusRecorder) WritePacket(p *Packet) error {
+	samples := opusPacketSamples(p.Opus)
+	if samples == 0 {
+		return nil
+	}
+
+	t := r.tracks[p.SSRC]
+	if t == nil {
+		w, err := r.create(p.SSRC)
+		if err != nil {
+			return err
+		}
+		writer, err := NewOggOpusWriter(w, 2)
+		if err != nil {
+			w.Close()
+			return err
+		}
+		t = &oggOpusTrack{w: w, writer: writer, timestamp: p.Timestamp}
+		r.tracks[p.SSRC] = t
+	}
+
+	// Packets are not sent while the user does not speak, which is filled
+	// with silence. Timestamps going back more than a second restart the
+	// stream of the SSRC.
+	// The part of a gap shorter than a frame is carried to the next one, so
+	// the stream keeps the time of the RTP timestamps.
+	gap := int32(p.Timestamp - t.timestamp)
+	switch {
+	case gap < -opusSampleRate:
+		t.missing = 0
+	case gap < 0:
+		return nil
+	default:
+		for t.missing += int(gap); t.missing >= 960; t.missing -= 960 {
+			if err := t.writer.WritePacket(opusSilenceFrame); err != nil {
+				return err
+			}
+		}
+	}
+	t.timestamp = p.Timestamp + uint32(samples)
+	return t.writer.WritePacket(p.Opus)
+}
+
+// Close ends and closes the streams of every SSRC.
+func (r *OggOpusRecorder) Close() error {
+	var errs []error
+	for ssrc, t := range r.tracks {
+		errs = append(errs, t.writer.Close(), t.w.Close())
+		delete(r.tracks, ssrc)
+	}
+	return errors.Join(errs...)
+}
+
+// RecordOggOpus records the opus packets received by the voice connection,
+// in an Ogg Opus stream per SSRC written to the writer returned by create,
+// until ctx is done. The streams are closed before it returns, and nil is
+// returned when ctx is done and they were closed successfully.
+func (v *VoiceConnection) RecordOggOpus(ctx context.Context, create func(ssrc uint32) (io.WriteCloser, error)) error {
+	v.RLock()
+	opusRecv := v.OpusRecv
+	v.RUnlock()
+	if opusRecv == nil {
+		return ErrVoiceNotReady
+	}
+
+	r := NewOggOpusRecorder(create)
+	for {
+		select {
+		case p, ok := <-opusRecv:
+			if !ok {
+				return r.Close()
+			}
+			if err := r.WritePacket(p); err != nil {
+				return errors.Join(err, r.Close())
+			}
+		case <-ctx.Done():
+			return r.Close()
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package discordgo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestOpusPacketSamples(t *testing.T) {
+	tests := []struct {
+		packet  []byte
+		samples int
+	}{
+		{nil, 0},
+		{opusSilenceFrame, 960},          // CELT 20 ms
+		{[]byte{0x08}, 960},              // SILK 20 ms
+		{[]byte{0x18}, 2880},             // SILK 60 ms
+		{[]byte{0x60}, 480},              // Hybrid 10 ms
+		{[]byte{0x80}, 120},              // CELT 2.5 ms
+		{[]byte{0x09}, 1920},             // two SILK 20 ms frames
+		{[]byte{0x0B, 0x03}, 2880},       // three SILK 20 ms frames
+		{[]byte{0x0B}, 0},                // missing frame count
+		{[]byte{0x1B, 0x03}, 0},          // 180 ms
+		{[]byte{0x83, 0x30, 0x00}, 5760}, // 48 CELT 2.5 ms frames
+	}
+
+	for _, test := range tests {
+		if samples := opusPacketSamples(test.packet); samples != test.samples {
+			t.Errorf("%x: expected %d samples, got %d", test.packet, test.samples, samples)
+		}
+	}
+}
+
+// lastGranule returns the granule position of the last page of an Ogg
+// stream.
+func lastGranule(t *testing.T, b []byte) uint64 {
+	pages := readOggPages(t, b)
+	if last := pages[len(pages)-1]; last.headerType&oggEOS != 0 {
+		return last.granule
+	}
+	t.Fatal("expected the last page to end the stream")
+	return 0
+}
+
+// readOggOpus returns the opus packets of an Ogg Opus stream.
+func readOggOpus(t *testing.T, b []byte) (*OggOpusReader, [][]byte) {
+	t.Helper()
+
+	r, err := NewOggOpusReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("NewOggOpusReader returned error: %v", err)
+	}
+	var packets [][]byte
+	for {
+		packet, err := r.ReadPacket()
+		if err == io.EOF {
+			return r, packets
+		}
+		if err != nil {
+			t.Fatalf("ReadPacket returned error: %v", err)
+		}
+		packets = append(packets, packet)
+	}
+}
+
+func TestOggOpus(t *testing.T) {
+	var b bytes.Buffer
+	w, err := NewOggOpusWriter(&b, 2)
+	if err != nil {
+		t.Fatalf("NewOggOpusWriter returned error: %v", err)
+	}
+
+	var packets [][]byte
+	for i := 0; i < 120; i++ {
+		packet := append([]byte{0xFC}, bytes.Repeat([]byte{byte(i)}, i*3)...)
+		packets = append(packets, packet)
+		if err := w.WritePacket(packet); err != nil {
+			t.Fatalf("WritePacket returned error: %v", err)
+		}
+	}
+	if err := w.WritePacket([]byte{0x0B}); err == nil {
+		t.Error("expected invalid packet to be rejected")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	// Headers are alone on their pages, and the packets are written by
+	// pages of a second.
+	pages := readOggPages(t, b.Bytes())
+	if len(pages) != 5 || pages[0].headerType != oggBOS || pages[0].granule != 0 || pages[1].granule != 0 {
+		t.Fatalf("unexpected pages %+v", pages)
+	}
+	for i, granule := range []uint64{48000, 96000, 120 * 960} {
+		if pages[i+2].granule != granule {
+			t.Errorf("page %d: expected granule %d, got %d", i+2, granule, pages[i+2].granule)
+		}
+	}
+	if w.Samples() != 120*960 || lastGranule(t, b.Bytes()) != w.Samples() {
+		t.Errorf("expected %d samples, got %d", 120*960, w.Samples())
+	}
+
+	r, got := readOggOpus(t, b.Bytes())
+	if r.Channels != 2 || r.PreSkip != 0 || r.Vendor != oggOpusVendor || len(r.Comments) != 0 {
+		t.Errorf("unexpected headers %+v", r)
+	}
+	if len(got) != len(packets) {
+		t.Fatalf("expected %d packets, got %d", len(packets), len(got))
+	}
+	for i := range packets {
+		if !bytes.Equal(got[i], packets[i]) {
+			t.Errorf("packet %d: expected %x, got %x", i, packets[i], got[i])
+		}
+	}
+}
+
+func TestOggOpusReaderInvalid(t *testing.T) {
+	// stream returns an Ogg stream of the given header packets.
+	stream := func(head, tags []byte) []byte {
+		var b bytes.Buffer
+		w := &oggWriter{w: &b}
+		w.writePacket(head, 0)
+		w.flush(0)
+		w.writePacket(tags, 0)
+		w.flush(oggEOS)
+		return b.Bytes()
+	}
+	head := []byte("OpusHead\x01\x02\x38\x01\x80\xbb\x00\x00\x00\x00\x00")
+	tags := []byte("OpusTags\x03\x00\x00\x00abc\x01\x00\x00\x00\x07\x00\x00\x00TITLE=x")
+
+	r, packets := readOggOpus(t, stream(head, tags))
+	if r.Channels != 2 || r.PreSkip != 312 || r.Vendor != "abc" || len(r.Comments) != 1 || r.Comments[0] != "TITLE=x" || len(packets) != 0 {
+		t.Errorf("unexpected reader %+v with %d packets", r, len(packets))
+	}
+
+	multichannel := bytes.Clone(head)
+	multichannel[9], multichannel[18] = 6, 1
+	tests := map[string][]byte{
+		"not opus":     stream([]byte("OggVorbis"), tags),
+		"bad version":  stream(append([]byte("OpusHead\x10"), head[9:]...), tags),
+		"multichannel": stream(multichannel, tags),
+		"bad tags":     stream(head, []byte("OpusTags\x10\x00\x00\x00abc")),
+		"missing tags": stream(head, nil)[:47],
+		"not ogg":      []byte("RIFF0000WAVEfmt "),
+	}
+	for name, b := range tests {
+		if _, err := NewOggOpusReader(bytes.NewReader(b)); !errors.Is(err, ErrInvalidOggOpus) && err != io.ErrUnexpectedEOF {
+			t.Errorf("%s: expected an error, got %v", name, err)
+		}
+	}
+}
+
+// nopWriteCloser records whether it was closed.
+type nopWriteCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *nopWriteCloser) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestOggOpusRecorder(t *testing.T) {
+	streams := map[uint32]*nopWriteCloser{}
+	r := NewOggOpusRecorder(func(ssrc uint32) (io.WriteCloser, error) {
+		streams[ssrc] = &nopWriteCloser{}
+		return streams[ssrc], nil
+	})
+
+	packets := []*Packet{
+		{SSRC: 1, Timestamp: 1000, Opus: []byte{0xFC, 1}},
+		{SSRC: 2, Timestamp: 5000, Opus: []byte{0xFC, 2}},
+		{SSRC: 1, Timestamp: 1960, Opus: []byte{0xFC, 3}},
+		// Late and duplicate packets are dropped.
+		{SSRC: 1, Timestamp: 1000, Opus: []byte{0xFC, 4}},
+		{SSRC: 1, Timestamp: 1960, Opus: []byte{0xFC, 5}},
+		// Three packets are missing.
+		{SSRC: 1, Timestamp: 1960 + 4*960, Opus: []byte{0xFC, 6}},
+		// Invalid packets are ignored.
+		{SSRC: 2, Timestamp: 5960, Opus: nil},
+		{SSRC: 2, Timestamp: 5960, Opus: []byte{0xFC, 7}},
+		// Gaps shorter than a frame add up.
+		{SSRC: 2, Timestamp: 6920 + 500, Opus: []byte{0xFC, 8}},
+		{SSRC: 2, Timestamp: 7880 + 500 + 500, Opus: []byte{0xFC, 9}},
+	}
+	for _, p := range packets {
+		if err := r.WritePacket(p); err != nil {
+			t.Fatalf("WritePacket returned error: %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	tests := []struct {
+		ssrc    uint32
+		packets [][]byte
+	}{
+		{1, [][]byte{{0xFC, 1}, {0xFC, 3}, opusSilenceFrame, opusSilenceFrame, opusSilenceFrame, {0xFC, 6}}},
+		{2, [][]byte{{0xFC, 2}, {0xFC, 7}, {0xFC, 8}, opusSilenceFrame, {0xFC, 9}}},
+	}
+	for _, test := range tests {
+		w := streams[test.ssrc]
+		if w == nil || !w.closed {
+			t.Fatalf("expected the stream of SSRC %d to be closed", test.ssrc)
+		}
+		if granule := lastGranule(t, w.Bytes()); granule != uint64(len(test.packets))*960 {
+			t.Errorf("SSRC %d: expected granule %d, got %d", test.ssrc, len(test.packets)*960, granule)
+		}
+		if _, got := readOggOpus(t, w.Bytes()); !slices.EqualFunc(got, test.packets, bytes.Equal) {
+			t.Errorf("SSRC %d: expected packets %x, got %x", test.ssrc, test.packets, got)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains iterators which walk the pages of paginated REST
+// endpoints.
+
+package discordgo
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"time"
+)
+
+// ErrPaginationDirection is returned by an iterator when it is asked to walk
+// an endpoint in a direction the endpoint does not support.
+var ErrPaginationDirection = errors.New("endpoint does not support paginating in this direction")
+
+// PaginationDirection is the direction an iterator walks pages in.
+type PaginationDirection int
+
+// Pagination directions.
+const (
+	// PaginationDefault walks the endpoint in its natural direction: from
+	// newest to oldest for messages, audit log entries and archived threads,
+	// and by ascending ID for everything else.
+	PaginationDefault PaginationDirection = iota
+	// PaginationBefore walks towards older items, by descending ID.
+	PaginationBefore
+	// PaginationAfter walks towards newer items, by ascending ID.
+	PaginationAfter
+)
+
+// PaginationOptions configures a paginated iterator.
+// A nil *PaginationOptions is valid and uses the defaults.
+type PaginationOptions struct {
+	// Direction to walk pages in.
+	Direction PaginationDirection
+
+	// Cursor is the ID to start paginating from, exclusive. If empty,
+	// paginating starts from the newest or oldest item, depending on the
+	// direction.
+	Cursor string
+
+	// PageSize is the number of items requested per page. If zero, the
+	// maximum allowed by the endpoint is used.
+	PageSize int
+
+	// Limit is the maximum number of items to yield in total, or zero for
+	// no limit.
+	Limit int
+
+	// RequestOptions are applied to every page request.
+	RequestOptions []RequestOption
+}
+
+// fetchPageFunc fetches a single page of at most limit items, starting at
+// cursor. It returns the page, the cursor for the next page and whether
+// there may be more pages.
+type fetchPageFunc[T any] func(cursor string, limit int, options ...RequestOption) (page []T, next string, more bool, err error)
+
+// paginate returns an iterator over all items returned by fetch.
+// Each page goes through the REST layer as a normal request, so ratelimits
+// are respected between pages. Iteration stops at the first error, which is
+// yielded along with the zero value of T.
+func paginate[T any](ctx context.Context, opts *PaginationOptions, maxPageSize int, fetch fetchPageFunc[T]) iter.Seq2[T, error] {
+	if opts == nil {
+		opts = &PaginationOptions{}
+	}
+
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		options := append(slices.Clone(opts.RequestOptions), WithContext(ctx))
+		cursor := opts.Cursor
+		count := 0
+
+		for {
+			limit := maxPageSize
+			if opts.PageSize > 0 && opts.PageSize < limit {
+				limit = opts.PageSize
+			}
+			if opts.Limit > 0 && opts.Limit-count < limit {
+				limit = opts.Limit - count
+			}
+
+			page, next, more, err := fetch(cursor, limit, options...)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page {
+				if !yield(item, nil) {
+					return
+				}
+
+				count++
+				if opts.Limit > 0 && count >= opts.Limit {
+					return
+				}
+			}
+
+			if !more || len(page) == 0 || next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// TakeWhile returns an iterator over the items of seq up until the first
+// item for which pred returns false. Errors are always passed through.
+func TakeWhile[T any](seq iter.Seq2[T, error], pred func(T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for item, err := range seq {
+			if err == nil && !pred(item) {
+				return
+			}
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// maxSnowflake is the largest possible snowflake ID, used as the before
+// cursor to make endpoints which default to ascending order walk backwards.
+const maxSnowflake = "9223372036854775807"
+
+// compareSnowflakes compares two snowflake IDs numerically.
+func compareSnowflakes(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// snowflakePage sorts a page in the given direction and returns the ID of
+// its last item, which is the cursor for the next page.
+func snowflakePage[T any](page []T, direction PaginationDirection, id func(T) string) string {
+	slices.SortFunc(page, func(a, b T) int {
+		if direction == PaginationBefore {
+			return compareSnowflakes(id(b), id(a))
+		}
+		return compareSnowflakes(id(a), id(b))
+	})
+
+	if len(page) == 0 {
+		return ""
+	}
+	return id(page[len(page)-1])
+}
+
+// snowflakeCursors returns the before and after parameters for a page
+// request in the given direction.
+func snowflakeCursors(direction PaginationDirection, cursor string) (beforeID, afterID string) {
+	if direction == PaginationBefore {
+		return cursor, ""
+	}
+	return "", cursor
+}
+
+// paginationDirection resolves the direction of opts, using def for PaginationDefault.
+func paginationDirection(opts *PaginationOptions, def PaginationDirection) PaginationDirection {
+	if opts == nil || opts.Direction == PaginationDefault {
+		return def
+	}
+	return opts.Direction
+}
+
+// paginationError returns an iterator which only yields err.
+func paginationError[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
+
+// ChannelMessagesIter returns an iterator over the messages of a channel,
+// from newest to oldest by default.
+// channelID : The ID of a Channel.
+// opts      : Pagination options, may be nil.
+func (s *Session) ChannelMessagesIter(ctx context.Context, channelID string, opts *PaginationOptions) iter.Seq2[*Message, error] {
+	direction := paginationDirection(opts, PaginationBefore)
+
+	return paginate(ctx, opts, 100, func(cursor string, limit int, options ...RequestOption) ([]*Message, string, bool, error) {
+		beforeID, afterID := snowflakeCursors(direction, cursor)
+		if direction == PaginationAfter && afterID == "" {
+			// Messages are returned from newest to oldest unless after is set.
+			afterID = "0"
+		}
+
+		page, err := s.ChannelMessages(channelID, limit, beforeID, afterID, "", options...)
+		next := snowflakePage(page, direction, func(m *Message) string { return m.ID })
+		return page, next, len(page) == limit, err
+	})
+}
+
+// GuildBansIter returns an iterator over the bans of a guild, by ascending
+// user ID by default.
+// guildID : The ID of a Guild.
+// opts    : Pagination options, may be nil.
+func (s *Session) GuildBansIter(ctx context.Context, guildID string, opts *PaginationOptions) iter.Seq2[*GuildBan, error] {
+	direction := paginationDirection(opts, PaginationAfter)
+
+	return paginate(ctx, opts, 1000, func(cursor string, limit int, options ...RequestOption) ([]*GuildBan, string, bool, error) {
+		beforeID, afterID := snowflakeCursors(direction, cursor)
+		if direction == PaginationBefore && beforeID == "" {
+			// Bans are returned by ascending ID unless before is set.
+			beforeID = maxSnowflake
+		}
+
+		page, err := s.GuildBans(guildID, limit, beforeID, afterID, options...)
+		next := snowflakePage(page, direction, func(b *GuildBan) string { return b.User.ID })
+		return page, next, len(page) == limit, err
+	})
+}
+
+// GuildMembersIter returns an iterator over the members of a guild, by
+// ascending user ID. Only PaginationAfter is supported.
+// guildID : The ID of a Guild.
+// opts    : Pagination options, may be nil.
+func (s *Session) GuildMembersIter(ctx context.Context, guildID string, opts *PaginationOptions) iter.Seq2[*Member, error] {
+	if paginationDirection(opts, PaginationAfter) != PaginationAfter {
+		return paginationError[*Member](ErrPaginationDirection)
+	}
+
+	return paginate(ctx, opts, 1000, func(cursor string, limit int, options ...RequestOption) ([]*Member, string, bool, error) {
+		page, err := s.GuildMembers(guildID, cursor, limit, options...)
+		next := snowflakePage(page, PaginationAfter, func(m *Member) string { return m.User.ID })
+		return page, next, len(page) == limit, err
+	})
+}
+
+// UserGuildsIter returns an iterator over the guilds of the current user,
+// by ascending ID by default.
+// opts : Pagination options, may be nil.
+func (s *Session) UserGuildsIter(ctx context.Context, opts *PaginationOptions) iter.Seq2[*UserGuild, error] {
+	direction := paginationDirection(opts, PaginationAfter)
+
+	return paginate(ctx, opts, 200, func(cursor string, limit int, options ...RequestOption) ([]*UserGuild, string, bool, error) {
+		beforeID, afterID := snowflakeCursors(direction, cursor)
+		if direction == PaginationBefore && beforeID == "" {
+			beforeID = maxSnowflake
+		}
+
+		page, err := s.UserGuilds(limit, beforeID, afterID, options...)
+		next := snowflakePage(page, direction, func(g *UserGuild) string { return g.ID })
+		return page, next, len(page) == limit, err
+	})
+}
+
+// MessageReactionsIter returns an iterator over the users who reacted to a
+// message with an emoji, by ascending user ID. Only PaginationAfter is supported.
+// channelID : The channel ID.
+// messageID : The message ID.
+// emojiID   : Either the unicode emoji for the reaction, or a guild emoji identifier.
+// opts      : Pagination options, may be nil.
+func (s *Session) MessageReactionsIter(ctx context.Context, channelID, messageID, emojiID string, opts *PaginationOptions) iter.Seq2[*User, error] {
+	if paginationDirection(opts, PaginationAfter) != PaginationAfter {
+		return paginationError[*User](ErrPaginationDirection)
+	}
+
+	return paginate(ctx, opts, 100, func(cursor string, limit int, options ...RequestOption) ([]*User, string, bool, error) {
+		page, err := s.MessageReactions(channelID, messageID, emojiID, limit, "", cursor, options...)
+		next := snowflakePage(page, PaginationAfter, func(u *User) string { return u.ID })
+		return page, next, len(page) == limit, err
+	})
+}
+
+// GuildScheduledEventUsersIter returns an iterator over the users
+// interested in a scheduled event, by ascending user ID by default.
+// guildID    : The ID of a Guild.
+// eventID    : The ID of the event.
+// withMember : Whether to include the member object in the response.
+// opts       : Pagination options, may be nil.
+func (s *Session) GuildScheduledEventUsersIter(ctx context.Context, guildID, eventID string, withMember bool, opts *PaginationOptions) iter.Seq2[*GuildScheduledEventUser, error] {
+	direction := paginationDirection(opts, PaginationAfter)
+
+	return paginate(ctx, opts, 100, func(cursor string, limit int, options ...RequestOption) ([]*GuildScheduledEventUser, string, bool, error) {
+		beforeID, afterID := snowflakeCursors(direction, cursor)
+		if direction == PaginationBefore && beforeID == "" {
+			beforeID = maxSnowflake
+		}
+
+		page, err := s.GuildScheduledEventUsers(guildID, eventID, limit, withMember, beforeID, afterID, options...)
+		next := snowflakePage(page, direction, func(u *GuildScheduledEventUser) string { return u.User.ID })
+		return page, next, len(page) == limit, err
+	})
+}
+
+// GuildAuditLogIter returns an iterator over the audit log entries of a
+// guild, from newest to oldest. Only PaginationBefore is supported.
+// guildID    : The ID of a Guild.
+// userID     : If provided the log will be filtered for the given ID.
+// actionType : If provided the log will be filtered for the given Action Type.
+// opts       : Pagination options, may be nil.
+func (s *Session) GuildAuditLogIter(ctx context.Context, guildID, userID string, actionType int, opts *PaginationOptions) iter.Seq2[*AuditLogEntry, error] {
+	if paginationDirection(opts, PaginationBefore) != PaginationBefore {
+		return paginationError[*AuditLogEntry](ErrPaginationDirection)
+	}
+
+	return paginate(ctx, opts, 100, func(cursor string, limit int, options ...RequestOption) ([]*AuditLogEntry, string, bool, error) {
+		auditLog, err := s.GuildAuditLog(guildID, userID, cursor, actionType, limit, options...)
+		if err != nil {
+			return nil, "", false, err
+		}
+
+		page := auditLog.AuditLogEntries
+		next := snowflakePage(page, PaginationBefore, func(e *AuditLogEntry) string { return e.ID })
+		return page, next, len(page) == limit, nil
+	})
+}
+
+// threadsArchivedIter returns an iterator over archived threads using one of
+// the Threads*Archived methods, walking backwards by archive timestamp.
+func threadsArchivedIter(ctx context.Context, opts *PaginationOptions, fetch func(before *time.Time, limit int, options ...RequestOption) (*ThreadsList, error)) iter.Seq2[*Channel, error] {
+	if paginationDirection(opts, PaginationBefore) != PaginationBefore {
+		return paginationError[*Channel](ErrPaginationDirection)
+	}
+
+	return paginate(ctx, opts, 100, func(cursor string, limit int, options ...RequestOption) ([]*Channel, string, bool, error) {
+		var before *time.Time
+		if cursor != "" {
+			t, err := time.Parse(time.RFC3339Nano, cursor)
+			if err != nil {
+				return nil, "", false, err
+			}
+			before = &t
+		}
+
+		list, err := fetch(before, limit, options...)
+		if err != nil {
+			return nil, "", false, err
+		}
+
+		var next string
+		if n := len(list.Threads); n > 0 && list.Threads[n-1].ThreadMetadata != nil {
+			next = list.Threads[n-1].ThreadMetadata.ArchiveTimestamp.Format(time.RFC3339Nano)
+		}
+		return list.Threads, next, list.HasMore, nil
+	})
+}
+
+// ThreadsArchivedIter returns an iterator over the archived public threads
+// of a channel, from most to least recently archived.
+// The Cursor in opts is an RFC 3339 archive timestamp rather than an ID.
+// channelID : The ID of a Channel.
+// opts      : Pagination options, may be nil.
+func (s *Session) ThreadsArchivedIter(ctx context.Context, channelID string, opts *PaginationOptions) iter.Seq2[*Channel, error] {
+	return threadsArchivedIter(ctx, opts, func(before *time.Time, limit int, options ...RequestOption) (*ThreadsList, error) {
+		return s.ThreadsArchived(channelID, before, limit, options...)
+	})
+}
+
+// ThreadsPrivateArchivedIter returns an iterator over the archived private
+// threads of a channel, from most to least recently archived.
+// The Cursor in opts is an RFC 3339 archive timestamp rather than an ID.
+// channelID : The ID of a Channel.
+// opts      : Pagination options, may be nil.
+func (s *Session) ThreadsPrivateArchivedIter(ctx context.Context, channelID string, opts *PaginationOptions) iter.Seq2[*Channel, error] {
+	return threadsArchivedIter(ctx, opts, func(before *time.Time, limit int, options ...RequestOption) (*ThreadsList, error) {
+		return s.ThreadsPrivateArchived(channelID, before, limit, options...)
+	})
+}
+
+// ThreadsPrivateJoinedArchivedIter returns an iterator over the archived
+// private threads of a channel the current user has joined, from most to
+// least recently archived.
+// The Cursor in opts is an RFC 3339 archive timestamp rather than an ID.
+// channelID : The ID of a Channel.
+// opts      : Pagination options, may be nil.
+func (s *Session) ThreadsPrivateJoinedArchivedIter(ctx context.Context, channelID string, opts *PaginationOptions) iter.Seq2[*Channel, error] {
+	return threadsArchivedIter(ctx, opts, func(before *time.Time, limit int, options ...RequestOption) (*ThreadsList, error) {
+		return s.ThreadsPrivateJoinedArchived(channelID, before, limit, options...)
+	})
+}
@@ -0,0 +1,151 @@
+package discordgo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// newFakeMessagesSession returns a session which answers channel message
+// requests from the given message IDs, which must be in ascending order.
+func newFakeMessagesSession(t *testing.T, ids []int, requests *int) *Session {
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	s.RESTMiddleware = []RESTMiddleware{
+		func(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error) {
+			*requests++
+
+			q := req.Request.URL.Query()
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			before, _ := strconv.Atoi(q.Get("before"))
+			after, _ := strconv.Atoi(q.Get("after"))
+
+			var page []*Message
+			if q.Has("after") {
+				for _, id := range ids {
+					if id > after && len(page) < limit {
+						page = append(page, &Message{ID: strconv.Itoa(id)})
+					}
+				}
+			} else {
+				for i := len(ids) - 1; i >= 0; i-- {
+					if (before == 0 || ids[i] < before) && len(page) < limit {
+						page = append(page, &Message{ID: strconv.Itoa(ids[i])})
+					}
+				}
+			}
+
+			body, err := Marshal(page)
+			if err != nil {
+				return nil, err
+			}
+			return &RESTResponse{Response: &http.Response{StatusCode: http.StatusOK}, Body: body}, nil
+		},
+	}
+
+	return s
+}
+
+func collectMessageIDs(t *testing.T, s *Session, opts *PaginationOptions) []string {
+	var ids []string
+	for m, err := range s.ChannelMessagesIter(context.Background(), "1", opts) {
+		if err != nil {
+			t.Fatalf("ChannelMessagesIter returned error: %+v", err)
+		}
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestChannelMessagesIter(t *testing.T) {
+	ids := []int{10, 20, 30, 40, 50, 60, 70}
+
+	tests := []struct {
+		name     string
+		opts     *PaginationOptions
+		expected []string
+		requests int
+	}{
+		{"default", nil, []string{"70", "60", "50", "40", "30", "20", "10"}, 1},
+		{"before pages", &PaginationOptions{PageSize: 3}, []string{"70", "60", "50", "40", "30", "20", "10"}, 3},
+		{"before cursor", &PaginationOptions{PageSize: 2, Cursor: "50"}, []string{"40", "30", "20", "10"}, 3},
+		{"after pages", &PaginationOptions{Direction: PaginationAfter, PageSize: 3}, []string{"10", "20", "30", "40", "50", "60", "70"}, 3},
+		{"after cursor", &PaginationOptions{Direction: PaginationAfter, PageSize: 2, Cursor: "30"}, []string{"40", "50", "60", "70"}, 3},
+		{"limit", &PaginationOptions{PageSize: 2, Limit: 3}, []string{"70", "60", "50"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			s := newFakeMessagesSession(t, ids, &requests)
+
+			got := collectMessageIDs(t, s, tt.opts)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, got)
+				}
+			}
+
+			if requests != tt.requests {
+				t.Errorf("expected %d requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestTakeWhile(t *testing.T) {
+	var requests int
+	s := newFakeMessagesSession(t, []int{10, 20, 30, 40, 50}, &requests)
+
+	var got []string
+	seq := s.ChannelMessagesIter(context.Background(), "1", &PaginationOptions{PageSize: 2})
+	for m, err := range TakeWhile(seq, func(m *Message) bool { return m.ID != "30" }) {
+		if err != nil {
+			t.Fatalf("TakeWhile returned error: %+v", err)
+		}
+		got = append(got, m.ID)
+	}
+
+	if len(got) != 2 || got[0] != "50" || got[1] != "40" {
+		t.Errorf("expected [50 40], got %v", got)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestPaginationErrors(t *testing.T) {
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	for _, err := range s.GuildMembersIter(context.Background(), "1", &PaginationOptions{Direction: PaginationBefore}) {
+		if !errors.Is(err, ErrPaginationDirection) {
+			t.Errorf("expected %v, got %v", ErrPaginationDirection, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var count int
+	for _, err := range s.ChannelMessagesIter(ctx, "1", nil) {
+		count++
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected a single error, got %d items", count)
+	}
+}
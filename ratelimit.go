@@ -1,6 +1,7 @@
 package discordgo
 
 import (
+	"context"
 	"math"
 	"strconv"
 	"strings"
@@ -91,21 +92,51 @@ func (r *RateLimiter) LockBucket(bucketID string) *Bucket {
 	return r.LockBucketObject(r.GetBucket(bucketID))
 }
 
+// LockBucketContext Locks until a request can be made or the context is done
+func (r *RateLimiter) LockBucketContext(ctx context.Context, bucketID string) (*Bucket, error) {
+	return r.LockBucketObjectContext(ctx, r.GetBucket(bucketID))
+}
+
 // LockBucketObject Locks an already resolved bucket until a request can be made
 func (r *RateLimiter) LockBucketObject(b *Bucket) *Bucket {
-	b.Lock()
+	b, _ = r.LockBucketObjectContext(context.Background(), b)
+	return b
+}
+
+// LockBucketObjectContext Locks an already resolved bucket until a request can be made
+// or the context is done. The bucket is left unlocked if an error is returned.
+func (r *RateLimiter) LockBucketObjectContext(ctx context.Context, b *Bucket) (*Bucket, error) {
+	if err := b.LockContext(ctx); err != nil {
+		return nil, err
+	}
 
 	if wait := r.GetWaitTime(b, 1); wait > 0 {
-		time.Sleep(wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			b.Unlock()
+			return nil, err
+		}
 	}
 
 	b.Remaining--
-	return b
+	return b, nil
+}
+
+// sleepContext pauses the current goroutine for at least the duration d,
+// returning early with the context error if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Bucket represents a ratelimit bucket, each bucket gets ratelimited individually (-global ratelimits)
 type Bucket struct {
-	sync.Mutex
 	Key       string
 	Remaining int
 	limit     int
@@ -115,6 +146,59 @@ type Bucket struct {
 	lastReset       time.Time
 	customRateLimit *customRateLimit
 	Userdata        any
+
+	// sema is a one slot semaphore used in place of a mutex
+	// so that waiting for the bucket can be cancelled. Lock, TryLock
+	// and Unlock keep the methods of the mutex.
+	sema     chan struct{}
+	semaOnce sync.Once
+}
+
+func (b *Bucket) semaphore() chan struct{} {
+	b.semaOnce.Do(func() {
+		b.sema = make(chan struct{}, 1)
+	})
+	return b.sema
+}
+
+// Lock locks the bucket, blocking until it is available.
+func (b *Bucket) Lock() {
+	b.semaphore() <- struct{}{}
+}
+
+// LockContext locks the bucket, blocking until it is available or the
+// context is done.
+func (b *Bucket) LockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case b.semaphore() <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryLock tries to lock the bucket, and reports whether it succeeded.
+func (b *Bucket) TryLock() bool {
+	select {
+	case b.semaphore() <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unlock unlocks the bucket.
+// It is a run-time error if the bucket is not locked on entry to Unlock.
+func (b *Bucket) Unlock() {
+	select {
+	case <-b.semaphore():
+	default:
+		panic("discordgo: unlock of unlocked bucket")
+	}
 }
 
 // Release unlocks the bucket and reads the headers to update the buckets ratelimit info
@@ -1,11 +1,13 @@
 package discordgo
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
 	"time"
-	
+
 	http "github.com/bogdanfinn/fhttp"
 )
 
@@ -112,3 +114,85 @@ func sendBenchReq(endpoint string, rl *RateLimiter) {
 
 	bucket.Release(headers)
 }
+
+// This test takes ~100 milliseconds to run
+func TestRatelimitLockContext(t *testing.T) {
+	rl := NewRatelimiter()
+
+	bucket := rl.LockBucket("/guilds/99/channels")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The bucket is held above, so this should give up once the context expires.
+	if _, err := rl.LockBucketContext(ctx, "/guilds/99/channels"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockBucketContext returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	if err := bucket.Release(nil); err != nil {
+		t.Fatalf("Release returned error: %v", err)
+	}
+
+	bucket, err := rl.LockBucketContext(context.Background(), "/guilds/99/channels")
+	if err != nil {
+		t.Fatalf("LockBucketContext returned error after release: %v", err)
+	}
+	bucket.Release(nil)
+}
+
+func TestBucketLock(t *testing.T) {
+	b := &Bucket{}
+
+	b.Lock()
+	if b.TryLock() {
+		t.Fatal("expected TryLock to fail on a locked bucket")
+	}
+	b.Unlock()
+	if !b.TryLock() {
+		t.Fatal("expected TryLock to lock an unlocked bucket")
+	}
+	b.Unlock()
+
+	// A done context does not lock an available bucket.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.LockContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("LockContext returned %v, expected %v", err, context.Canceled)
+	}
+	if !b.TryLock() {
+		t.Fatal("expected the bucket to be unlocked")
+	}
+}
+
+// This test takes ~100 milliseconds to run
+func TestRatelimitWaitContext(t *testing.T) {
+	rl := NewRatelimiter()
+
+	bucket := rl.LockBucket("/guilds/99/channels")
+
+	headers := http.Header(make(map[string][]string))
+	headers.Set("X-RateLimit-Remaining", "0")
+	headers.Set("X-RateLimit-Reset-After", "10")
+
+	if err := bucket.Release(headers); err != nil {
+		t.Fatalf("Release returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	sent := time.Now()
+	if _, err := rl.LockBucketContext(ctx, "/guilds/99/channels"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockBucketContext returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	if time.Since(sent) >= time.Second {
+		t.Error("Did not stop waiting on cancellation, got:", time.Since(sent))
+	}
+
+	// The bucket must have been unlocked when the wait was cancelled.
+	if err := bucket.LockContext(context.Background()); err != nil {
+		t.Fatalf("LockContext returned error: %v", err)
+	}
+	bucket.Unlock()
+}
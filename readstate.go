@@ -0,0 +1,271 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the read states tracked by a State, which tell which
+// channels have unread messages, mentions and pins.
+
+package discordgo
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ChannelUnread is the read state of a channel.
+type ChannelUnread struct {
+	ChannelID string
+	GuildID   string
+
+	// LastReadMessageID is the ID of the last message read, and
+	// LastMessageID the ID of the last message sent in the channel.
+	LastReadMessageID string
+	LastMessageID     string
+
+	// Unread is whether messages were sent after the last message read.
+	// Channels which were never read have no read state, and are not
+	// unread.
+	Unread bool
+
+	// MentionCount is the number of unread messages mentioning the user.
+	MentionCount int
+
+	// UnreadPins is whether a message was pinned since the pins were last
+	// viewed.
+	UnreadPins bool
+}
+
+// GuildUnread is the read state of the channels of a guild.
+type GuildUnread struct {
+	GuildID string
+
+	// MentionCount is the number of unread messages mentioning the user in
+	// all channels of the guild.
+	MentionCount int
+
+	// Channels are the unread channels of the guild, or the channels with
+	// unread mentions.
+	Channels []*ChannelUnread
+}
+
+// Unread returns the read state of a channel.
+func (s *State) Unread(channelID string) (*ChannelUnread, error) {
+	if s == nil {
+		return nil, ErrNilState
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	c, err := s.store().Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return s.channelUnread(c), nil
+}
+
+// UnreadGuilds returns the read states of the guilds with unread channels,
+// or with unread mentions.
+func (s *State) UnreadGuilds() ([]*GuildUnread, error) {
+	if s == nil {
+		return nil, ErrNilState
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	guilds, err := s.store().Guilds()
+	if err != nil {
+		return nil, err
+	}
+
+	var unread []*GuildUnread
+	for _, g := range guilds {
+		gu := &GuildUnread{GuildID: g.ID}
+		for _, c := range slices.Concat(g.Channels, g.Threads) {
+			cu := s.channelUnread(c)
+			if cu.Unread || cu.MentionCount > 0 {
+				gu.MentionCount += cu.MentionCount
+				gu.Channels = append(gu.Channels, cu)
+			}
+		}
+		if len(gu.Channels) > 0 {
+			unread = append(unread, gu)
+		}
+	}
+	return unread, nil
+}
+
+// channelUnread returns the read state of the channel c. s must be locked.
+func (s *State) channelUnread(c *Channel) *ChannelUnread {
+	cu := &ChannelUnread{
+		ChannelID:     c.ID,
+		GuildID:       c.GuildID,
+		LastMessageID: c.LastMessageID,
+	}
+
+	rs, ok := s.readStates[c.ID]
+	if !ok {
+		return cu
+	}
+
+	cu.LastReadMessageID = readStateMessageID(rs)
+	cu.Unread = c.LastMessageID != "" && compareSnowflakes(c.LastMessageID, cu.LastReadMessageID) > 0
+	cu.MentionCount = rs.MentionCount
+	cu.UnreadPins = c.LastPinTimestamp != nil && (rs.LastPinTimestamp == nil || c.LastPinTimestamp.After(*rs.LastPinTimestamp))
+	return cu
+}
+
+// readStateMessageID returns the ID of the last message read of a read
+// state, which Discord sends as a string or a number.
+func readStateMessageID(rs *ReadState) string {
+	switch id := rs.LastMessageID.(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(id, 10)
+	}
+	return ""
+}
+
+// indexReadStates indexes the read states of the Ready by channel. s must
+// be locked.
+func (s *State) indexReadStates() {
+	s.readStates = make(map[string]*ReadState, len(s.ReadState))
+	for _, rs := range s.ReadState {
+		s.readStates[rs.ID] = rs
+	}
+}
+
+// readState returns the read state of a channel, which is added when it has
+// none. s must be locked.
+func (s *State) readState(channelID string) *ReadState {
+	if rs, ok := s.readStates[channelID]; ok {
+		return rs
+	}
+
+	if s.readStates == nil {
+		s.readStates = make(map[string]*ReadState)
+	}
+	rs := &ReadState{ID: channelID}
+	s.readStates[channelID] = rs
+	s.ReadState = append(s.ReadState, rs)
+	return rs
+}
+
+// trackReadStates reports whether the read states of the state of the
+// session are updated.
+func (s *Session) trackReadStates() bool {
+	return s.StateEnabled && s.State != nil && s.State.TrackReadStates
+}
+
+// messageAck marks a channel as read up to a message, with the given number
+// of unread mentions left.
+func (s *State) messageAck(channelID, messageID string, mentionCount int) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	rs := s.readState(channelID)
+	rs.LastMessageID = messageID
+	rs.MentionCount = mentionCount
+}
+
+// pinsAck marks the pins of a channel as viewed at the given time.
+func (s *State) pinsAck(channelID string, timestamp time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.readState(channelID).LastPinTimestamp = &timestamp
+}
+
+// onMessageCreateReadState updates the last message of the channel of a new
+// message, and its read state.
+func (s *State) onMessageCreateReadState(m *Message) error {
+	s.Lock()
+	defer s.Unlock()
+
+	c, err := s.store().Channel(m.ChannelID)
+	if err != nil {
+		return err
+	}
+	if c.LastMessageID == "" || compareSnowflakes(m.ID, c.LastMessageID) > 0 {
+		c.LastMessageID = m.ID
+		if err = s.store().ChannelPut(c); err != nil {
+			return err
+		}
+	}
+
+	if s.User == nil || m.Author == nil {
+		return nil
+	}
+
+	// The messages of the user mark their channel as read.
+	if m.Author.ID == s.User.ID {
+		rs := s.readState(m.ChannelID)
+		rs.LastMessageID = m.ID
+		rs.MentionCount = 0
+		return nil
+	}
+
+	if s.mentionsUser(c, m) {
+		s.readState(m.ChannelID).MentionCount++
+	}
+	return nil
+}
+
+// mentionsUser reports whether a message sent in the channel c mentions the
+// user, which every message of a DM does. s must be locked.
+func (s *State) mentionsUser(c *Channel, m *Message) bool {
+	if c.Type == ChannelTypeDM || c.Type == ChannelTypeGroupDM || m.MentionEveryone {
+		return true
+	}
+
+	for _, u := range m.Mentions {
+		if u.ID == s.User.ID {
+			return true
+		}
+	}
+
+	if len(m.MentionRoles) > 0 {
+		if member, err := s.store().Member(c.GuildID, s.User.ID); err == nil {
+			for _, roleID := range member.Roles {
+				if slices.Contains(m.MentionRoles, roleID) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// onChannelPinsUpdate updates the last pin of a channel.
+func (s *State) onChannelPinsUpdate(u *ChannelPinsUpdate) error {
+	s.Lock()
+	defer s.Unlock()
+
+	c, err := s.store().Channel(u.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	c.LastPinTimestamp = nil
+	if u.LastPinTimestamp != "" {
+		t, err := time.Parse(time.RFC3339, u.LastPinTimestamp)
+		if err != nil {
+			return err
+		}
+		c.LastPinTimestamp = &t
+	}
+	return s.store().ChannelPut(c)
+}
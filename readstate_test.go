@@ -0,0 +1,128 @@
+package discordgo
+
+import (
+	"testing"
+	"time"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+func TestStateReadStates(t *testing.T) {
+	state := NewState()
+	se := &Session{StateEnabled: true, State: state}
+
+	ready := testReady()
+	ready.Guilds[0].Channels[0].LastMessageID = "25"
+	ready.ReadState = []*ReadState{{ID: "20", LastMessageID: "25"}}
+	if err := state.OnInterface(se, ready); err != nil {
+		t.Fatalf("OnInterface(Ready) returned error: %+v", err)
+	}
+
+	unread := func(channelID string) *ChannelUnread {
+		t.Helper()
+		cu, err := state.Unread(channelID)
+		if err != nil {
+			t.Fatalf("Unread returned error: %+v", err)
+		}
+		return cu
+	}
+	event := func(i any) {
+		t.Helper()
+		if err := state.OnInterface(se, i); err != nil {
+			t.Fatalf("OnInterface(%T) returned error: %+v", i, err)
+		}
+	}
+	other, self := &User{ID: "2"}, &User{ID: "1"}
+
+	if cu := unread("20"); cu.Unread || cu.MentionCount != 0 || cu.LastReadMessageID != "25" {
+		t.Errorf("expected channel 20 to be read, got %+v", cu)
+	}
+
+	event(&MessageCreate{&Message{ID: "30", ChannelID: "20", Author: other, Mentions: []*User{self}}})
+	event(&MessageCreate{&Message{ID: "31", ChannelID: "20", Author: other, MentionEveryone: true}})
+	event(&MessageCreate{&Message{ID: "32", ChannelID: "20", Author: other}})
+	if cu := unread("20"); !cu.Unread || cu.MentionCount != 2 || cu.LastMessageID != "32" {
+		t.Errorf("expected channel 20 to be unread with 2 mentions, got %+v", cu)
+	}
+
+	guilds, err := state.UnreadGuilds()
+	if err != nil {
+		t.Fatalf("UnreadGuilds returned error: %+v", err)
+	}
+	if len(guilds) != 1 || guilds[0].GuildID != "10" || guilds[0].MentionCount != 2 || len(guilds[0].Channels) != 1 {
+		t.Errorf("unexpected unread guilds %+v", guilds)
+	}
+
+	event(&MessageAck{ChannelID: "20", MessageID: "31"})
+	if cu := unread("20"); !cu.Unread || cu.MentionCount != 0 || cu.LastReadMessageID != "31" {
+		t.Errorf("expected channel 20 to be read up to 31, got %+v", cu)
+	}
+
+	// The messages of the user mark their channel as read.
+	event(&MessageCreate{&Message{ID: "33", ChannelID: "20", Author: self}})
+	if cu := unread("20"); cu.Unread {
+		t.Errorf("expected channel 20 to be read, got %+v", cu)
+	}
+	if guilds, _ = state.UnreadGuilds(); len(guilds) != 0 {
+		t.Errorf("expected no unread guilds, got %+v", guilds)
+	}
+
+	// Every message of a DM mentions the user.
+	event(&MessageCreate{&Message{ID: "34", ChannelID: "21", Author: other}})
+	if cu := unread("21"); !cu.Unread || cu.MentionCount != 1 {
+		t.Errorf("expected channel 21 to be unread with 1 mention, got %+v", cu)
+	}
+
+	pinned := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	event(&ChannelPinsUpdate{ChannelID: "20", LastPinTimestamp: pinned.Format(time.RFC3339)})
+	if cu := unread("20"); !cu.UnreadPins {
+		t.Errorf("expected channel 20 to have unread pins, got %+v", cu)
+	}
+	event(&ChannelPinsAck{ChannelID: "20", Timestamp: pinned.Add(time.Second)})
+	if cu := unread("20"); cu.UnreadPins {
+		t.Errorf("expected channel 20 to have no unread pins, got %+v", cu)
+	}
+}
+
+func TestMessageAckState(t *testing.T) {
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+	s.RESTMiddleware = []RESTMiddleware{
+		func(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error) {
+			return &RESTResponse{
+				Response: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+				Body:     []byte("{}"),
+			}, nil
+		},
+	}
+
+	acked := func() bool {
+		s.State.RLock()
+		defer s.State.RUnlock()
+		return s.State.readStates["20"] != nil
+	}
+
+	// The state is not updated when it is disabled, or does not track read
+	// states.
+	s.StateEnabled = false
+	if err = s.MessageAck("20", "30"); err != nil {
+		t.Fatalf("MessageAck returned error: %+v", err)
+	}
+	s.StateEnabled, s.State.TrackReadStates = true, false
+	if err = s.MessageUnack("20", "30", 1); err != nil {
+		t.Fatalf("MessageUnack returned error: %+v", err)
+	}
+	if acked() {
+		t.Error("expected the read state not to be updated")
+	}
+
+	s.State.TrackReadStates = true
+	if err = s.MessageAck("20", "30"); err != nil {
+		t.Fatalf("MessageAck returned error: %+v", err)
+	}
+	if !acked() {
+		t.Error("expected the read state to be updated")
+	}
+}
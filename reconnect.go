@@ -0,0 +1,115 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the policies deciding when a Session reconnects to the
+// gateway and to voice channels.
+
+package discordgo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrSessionClosed is returned when a Session stops reconnecting because it
+// was closed.
+var ErrSessionClosed = errors.New("session closed")
+
+// A ReconnectPolicy decides how long a Session waits before each attempt to
+// reconnect, and when it gives up.
+type ReconnectPolicy interface {
+	// Backoff returns the delay before the given attempt, starting at 1.
+	// err is the error the previous attempt failed with, or nil before the
+	// first attempt. Returning false gives up reconnecting.
+	Backoff(attempt int, err error) (delay time.Duration, ok bool)
+}
+
+// ExponentialBackoff is a ReconnectPolicy whose delays grow exponentially,
+// with full jitter: the delay before an attempt is a random duration up to
+// Base doubled for every previous attempt, capped at Max. The jitter spreads
+// the reconnects of sessions which lost their connection at the same time.
+type ExponentialBackoff struct {
+	// Base is the maximum delay before the first attempt.
+	Base time.Duration
+
+	// Max caps the maximum delay before an attempt. The delay is not capped
+	// when it is zero, other than by the largest time.Duration.
+	Max time.Duration
+
+	// MaxAttempts is the number of attempts before giving up. There is no
+	// limit when it is zero.
+	MaxAttempts int
+}
+
+// Backoff implements ReconnectPolicy.
+func (b ExponentialBackoff) Backoff(attempt int, err error) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempt > b.MaxAttempts {
+		return 0, false
+	}
+
+	limit := b.Max
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+
+	ceiling := min(b.Base, limit)
+	for i := 1; i < attempt && ceiling < limit; i++ {
+		if ceiling > limit/2 {
+			ceiling = limit
+			break
+		}
+		ceiling *= 2
+	}
+	if ceiling <= 0 {
+		return 0, true
+	}
+
+	// The ceiling is included, even when it is the largest time.Duration.
+	return time.Duration(rand.Uint64N(uint64(ceiling) + 1)), true
+}
+
+// DefaultReconnectPolicy is the ReconnectPolicy used when
+// Session.ReconnectPolicy is not set. It retries forever, waiting up to one
+// second before the first attempt and up to ten minutes once the delay has
+// grown.
+var DefaultReconnectPolicy ReconnectPolicy = ExponentialBackoff{
+	Base: time.Second,
+	Max:  10 * time.Minute,
+}
+
+// reconnectPolicy returns the ReconnectPolicy of the session.
+func (s *Session) reconnectPolicy() ReconnectPolicy {
+	if s.ReconnectPolicy != nil {
+		return s.ReconnectPolicy
+	}
+	return DefaultReconnectPolicy
+}
+
+// reconnectWait waits for delay before an attempt to reconnect. It returns
+// ErrSessionClosed when closed is closed, or the error of ReconnectContext
+// when it is done, before the delay elapsed.
+func (s *Session) reconnectWait(delay time.Duration, closed <-chan struct{}) error {
+	ctx := s.ReconnectContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-closed:
+		return ErrSessionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
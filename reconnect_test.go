@@ -0,0 +1,79 @@
+package discordgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Second, Max: 10 * time.Second, MaxAttempts: 6}
+
+	for _, tc := range []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{6, 10 * time.Second},
+	} {
+		for range 100 {
+			delay, ok := b.Backoff(tc.attempt, nil)
+			if !ok {
+				t.Fatalf("attempt %d: expected to reconnect", tc.attempt)
+			}
+			if delay < 0 || delay > tc.ceiling {
+				t.Fatalf("attempt %d: expected a delay up to %v, got %v", tc.attempt, tc.ceiling, delay)
+			}
+		}
+	}
+
+	if _, ok := b.Backoff(7, nil); ok {
+		t.Error("expected to give up after MaxAttempts")
+	}
+}
+
+func TestExponentialBackoffJitter(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Second, Max: time.Minute}
+
+	// Sessions reconnecting at the same time must not wait the same delay.
+	seen := make(map[time.Duration]bool)
+	for range 10 {
+		delay, _ := b.Backoff(1, nil)
+		seen[delay] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("expected jittered delays, got %v", seen)
+	}
+}
+
+func TestExponentialBackoffLimits(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Second, Max: time.Hour}
+
+	// The delay must not overflow after many attempts.
+	delay, ok := b.Backoff(1000, nil)
+	if !ok || delay < 0 || delay > time.Hour {
+		t.Errorf("expected a delay up to %v, got %v", time.Hour, delay)
+	}
+
+	if delay, ok = (ExponentialBackoff{}).Backoff(1, nil); !ok || delay != 0 {
+		t.Errorf("expected no delay without a base, got %v", delay)
+	}
+
+	// Without a maximum, the delay keeps growing, up to the largest
+	// duration.
+	b = ExponentialBackoff{Base: time.Second}
+	var grown bool
+	for i := 0; i < 100 && !grown; i++ {
+		delay, ok = b.Backoff(10, nil)
+		grown = delay > time.Second
+	}
+	if !ok || !grown {
+		t.Errorf("expected the delay to grow without a maximum, got %v", delay)
+	}
+	if delay, ok = b.Backoff(1000, nil); !ok || delay < 0 {
+		t.Errorf("expected a positive delay after many attempts, got %v", delay)
+	}
+}
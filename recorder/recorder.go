@@ -0,0 +1,456 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package recorder provides an HTTP client which records REST requests and
+// their responses to fixture files, and replays them later without network
+// access. It is meant to be plugged into Session.Client to write hermetic
+// tests for the REST API.
+//
+//	rec, err := recorder.New("testdata/user.json", recorder.ModeReplay, nil)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	s.Client = rec
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	http "github.com/bogdanfinn/fhttp"
+	tls_client "github.com/bogdanfinn/tls-client"
+	"github.com/bogdanfinn/tls-client/bandwidth"
+)
+
+// ErrInteractionNotFound is returned in replay mode when no recorded
+// interaction matches a request.
+var ErrInteractionNotFound = errors.New("recorder: no recorded interaction matches the request")
+
+// ErrClientRequired is returned when recording without an HTTP client to
+// send the requests with.
+var ErrClientRequired = errors.New("recorder: a client is required to record")
+
+// Redacted replaces the value of scrubbed headers.
+const Redacted = "[REDACTED]"
+
+// Mode is the mode a Recorder operates in.
+type Mode int
+
+// Recorder modes.
+const (
+	// ModeReplay serves responses from the fixture file, and never
+	// touches the network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests with the wrapped client and saves them,
+	// along with their responses, to the fixture file on Stop.
+	ModeRecord
+)
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	Status     string      `json:"status"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Interaction is a request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Body is a recorded request or response body. It is saved as a string
+// when it is valid UTF-8, and as base64 otherwise.
+type Body []byte
+
+type encodedBody struct {
+	Base64 string `json:"base64"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(encodedBody{base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+
+	var e encodedBody
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(e.Base64)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// fixture is the format of a fixture file.
+type fixture struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// A Recorder is a tls_client.HttpClient which records or replays
+// interactions, depending on its mode.
+type Recorder struct {
+	// ScrubHeaders lists the request and response headers whose values are
+	// replaced with Redacted before an interaction is saved.
+	ScrubHeaders []string
+
+	// BeforeSave, if set, is called with every interaction before it is
+	// saved, e.g. to remove tokens from URLs.
+	BeforeSave func(i *Interaction)
+
+	// Match reports whether a recorded interaction matches a request.
+	// The default matches on the method, URL and body.
+	Match func(req *Request, i *Interaction) bool
+
+	mode   Mode
+	path   string
+	client tls_client.HttpClient
+	jar    http.CookieJar
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     []bool
+}
+
+// New returns a Recorder using the fixture file at path.
+// In ModeRecord, requests are sent with client and the fixture file is
+// written on Stop. In ModeReplay, the fixture file is loaded immediately
+// and client may be nil.
+func New(path string, mode Mode, client tls_client.HttpClient) (*Recorder, error) {
+	r := &Recorder{
+		ScrubHeaders: []string{"Authorization", "Cookie", "Set-Cookie"},
+		Match:        DefaultMatch,
+		mode:         mode,
+		path:         path,
+		client:       client,
+		jar:          tls_client.NewCookieJar(),
+	}
+
+	switch mode {
+	case ModeRecord:
+		if client == nil {
+			return nil, ErrClientRequired
+		}
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var f fixture
+		if err = json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("recorder: error decoding fixture %s, %w", path, err)
+		}
+		r.interactions = f.Interactions
+		r.replayed = make([]bool, len(f.Interactions))
+	default:
+		return nil, fmt.Errorf("recorder: unknown mode %d", mode)
+	}
+
+	return r, nil
+}
+
+// DefaultMatch matches requests on their method, URL and body.
+func DefaultMatch(req *Request, i *Interaction) bool {
+	return req.Method == i.Request.Method && req.URL == i.Request.URL && bytes.Equal(req.Body, i.Request.Body)
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Interaction(nil), r.interactions...)
+}
+
+// Stop writes the fixture file when recording. It does nothing when replaying.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	f := fixture{Interactions: r.interactions}
+	data, err := json.MarshalIndent(f, "", "\t")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// Do records or replays a request, depending on the mode of the Recorder.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.scrub(req.Header),
+			Body:   body,
+		},
+		Response: Response{
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Header:     r.scrub(resp.Header),
+			Body:       respBody,
+		},
+	}
+	if r.BeforeSave != nil {
+		r.BeforeSave(i)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, i)
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	recorded := &Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header,
+		Body:   body,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Interactions are replayed in the order they were recorded, so the
+	// same request can return different responses over time.
+	for n, i := range r.interactions {
+		if r.replayed[n] || !r.Match(recorded, i) {
+			continue
+		}
+		r.replayed[n] = true
+
+		return &http.Response{
+			Status:        i.Response.Status,
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, req.URL)
+}
+
+// scrub returns a copy of header with the values of ScrubHeaders redacted.
+func (r *Recorder) scrub(header http.Header) http.Header {
+	header = header.Clone()
+	for k := range header {
+		for _, s := range r.ScrubHeaders {
+			if strings.EqualFold(k, s) {
+				header[k] = []string{Redacted}
+			}
+		}
+	}
+
+	// Header order is an fhttp implementation detail, not part of the request.
+	delete(header, http.HeaderOrderKey)
+	delete(header, http.PHeaderOrderKey)
+	return header
+}
+
+// readBody reads the body of req and replaces it so it can be sent again.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Get issues a GET request to the given URL.
+func (r *Recorder) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Do(req)
+}
+
+// Head issues a HEAD request to the given URL.
+func (r *Recorder) Head(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Do(req)
+}
+
+// Post issues a POST request to the given URL.
+func (r *Recorder) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return r.Do(req)
+}
+
+// GetCookies returns the cookies for the given URL.
+func (r *Recorder) GetCookies(u *url.URL) []*http.Cookie {
+	if r.client != nil {
+		return r.client.GetCookies(u)
+	}
+	return r.jar.Cookies(u)
+}
+
+// SetCookies sets the cookies for the given URL.
+func (r *Recorder) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if r.client != nil {
+		r.client.SetCookies(u, cookies)
+		return
+	}
+	r.jar.SetCookies(u, cookies)
+}
+
+// SetCookieJar sets the cookie jar.
+func (r *Recorder) SetCookieJar(jar http.CookieJar) {
+	if r.client != nil {
+		r.client.SetCookieJar(jar)
+		return
+	}
+	r.jar = jar
+}
+
+// GetCookieJar returns the cookie jar.
+func (r *Recorder) GetCookieJar() http.CookieJar {
+	if r.client != nil {
+		return r.client.GetCookieJar()
+	}
+	return r.jar
+}
+
+// SetProxy sets the proxy of the wrapped client. It is ignored when replaying.
+func (r *Recorder) SetProxy(proxyURL string) error {
+	if r.client != nil {
+		return r.client.SetProxy(proxyURL)
+	}
+	return nil
+}
+
+// GetProxy returns the proxy of the wrapped client.
+func (r *Recorder) GetProxy() string {
+	if r.client != nil {
+		return r.client.GetProxy()
+	}
+	return ""
+}
+
+// SetFollowRedirect sets whether the wrapped client follows redirects.
+func (r *Recorder) SetFollowRedirect(followRedirect bool) {
+	if r.client != nil {
+		r.client.SetFollowRedirect(followRedirect)
+	}
+}
+
+// GetFollowRedirect returns whether the wrapped client follows redirects.
+func (r *Recorder) GetFollowRedirect() bool {
+	if r.client != nil {
+		return r.client.GetFollowRedirect()
+	}
+	return false
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped client.
+func (r *Recorder) CloseIdleConnections() {
+	if r.client != nil {
+		r.client.CloseIdleConnections()
+	}
+}
+
+// GetBandwidthTracker returns the bandwidth tracker of the wrapped client.
+func (r *Recorder) GetBandwidthTracker() bandwidth.BandwidthTracker {
+	if r.client != nil {
+		return r.client.GetBandwidthTracker()
+	}
+	return bandwidth.NewNopeTracker()
+}
+
+var _ tls_client.HttpClient = (*Recorder)(nil)
@@ -0,0 +1,123 @@
+package recorder
+
+import (
+	"errors"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	http "github.com/bogdanfinn/fhttp"
+	tls_client "github.com/bogdanfinn/tls-client"
+)
+
+func newClient(t *testing.T) tls_client.HttpClient {
+	client, err := tls_client.NewHttpClient(tls_client.NewNoopLogger())
+	if err != nil {
+		t.Fatalf("NewHttpClient returned error: %+v", err)
+	}
+	return client
+}
+
+func doRequest(t *testing.T, client tls_client.HttpClient, method, url, body string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %+v", err)
+	}
+	req.Header.Set("Authorization", "secret-token")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do returned error: %+v", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %+v", err)
+	}
+	return resp.StatusCode, string(b)
+}
+
+func TestRecordReplay(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		hits++
+		b, _ := io.ReadAll(r.Body)
+		w.WriteHeader(nethttp.StatusCreated)
+		w.Write([]byte(r.Method + " " + string(b) + " " + r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "record.json")
+
+	rec, err := New(path, ModeRecord, newClient(t))
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	status, body := doRequest(t, rec, "POST", srv.URL+"/a", "first")
+	if status != 201 || body != "POST first secret-token" {
+		t.Fatalf("unexpected recorded response %d %q", status, body)
+	}
+	doRequest(t, rec, "POST", srv.URL+"/a", "second")
+
+	if err = rec.Stop(); err != nil {
+		t.Fatalf("Stop returned error: %+v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %+v", err)
+	}
+	if strings.Contains(string(data), `"secret-token"`) {
+		t.Error("authorization header was not scrubbed from the fixture")
+	}
+
+	srv.Close()
+
+	rec, err = New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	// Requests are matched by body as well, regardless of order.
+	status, body = doRequest(t, rec, "POST", srv.URL+"/a", "second")
+	if status != 201 || body != "POST second secret-token" {
+		t.Errorf("unexpected replayed response %d %q", status, body)
+	}
+	_, body = doRequest(t, rec, "POST", srv.URL+"/a", "first")
+	if body != "POST first secret-token" {
+		t.Errorf("unexpected replayed response %q", body)
+	}
+
+	// Every interaction is only replayed once.
+	req, _ := http.NewRequest("POST", srv.URL+"/a", strings.NewReader("first"))
+	if _, err = rec.Do(req); !errors.Is(err, ErrInteractionNotFound) {
+		t.Errorf("expected %v, got %v", ErrInteractionNotFound, err)
+	}
+
+	if hits != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %d", hits)
+	}
+}
+
+func TestBinaryBody(t *testing.T) {
+	b := Body{0xff, 0x00, 0xfe}
+
+	data, err := b.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON returned error: %+v", err)
+	}
+
+	var decoded Body
+	if err = decoded.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON returned error: %+v", err)
+	}
+	if string(decoded) != string(b) {
+		t.Errorf("expected %v, got %v", b, decoded)
+	}
+}
@@ -12,6 +12,7 @@ package discordgo
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"github.com/goccy/go-json"
 
 	http "github.com/bogdanfinn/fhttp"
+	tls_client "github.com/bogdanfinn/tls-client"
 	"github.com/lb-selfbot/discordgo/protos"
 	"google.golang.org/protobuf/proto"
 )
@@ -41,6 +43,7 @@ var (
 	ErrGuildNoIcon             = errors.New("guild does not have an icon set")
 	ErrGuildNoSplash           = errors.New("guild does not have a splash set")
 	ErrUnauthorized            = errors.New("HTTP request was unauthorized. This could be because the provided token was not a bot token. Please add \"Bot \" to the start of your token. https://discord.com/developers/docs/reference#authentication-example-bot-token-authorization-header")
+	ErrMessageNotConfirmed     = errors.New("message was not confirmed by the gateway")
 )
 
 var (
@@ -84,6 +87,58 @@ func (r RESTError) Error() string {
 	return "HTTP " + r.Response.Status + ", " + string(r.ResponseBody)
 }
 
+// Is reports whether the error matches target. A RESTError matches an
+// ErrorCode if Discord responded with that JSON error code, so RESTErrors can
+// be checked using errors.Is(err, ErrCodeUnknownMessage).
+func (r RESTError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && r.Message != nil && r.Message.Code == code
+}
+
+// FieldErrors returns the per-field validation errors of the response,
+// or nil if there are none.
+func (r RESTError) FieldErrors() *APIFieldErrors {
+	if r.Message == nil {
+		return nil
+	}
+	return r.Message.Errors
+}
+
+// ErrorCodeOf returns the Discord JSON error code of a REST error.
+// ok is false if err is not a RESTError or no error code was returned.
+func ErrorCodeOf(err error) (code ErrorCode, ok bool) {
+	var restErr *RESTError
+	if !errors.As(err, &restErr) || restErr.Message == nil {
+		return ErrCodeGeneralError, false
+	}
+	return restErr.Message.Code, true
+}
+
+// IsMissingPermissions reports whether a request failed because the
+// session user lacks the permissions to perform it.
+func IsMissingPermissions(err error) bool {
+	return errors.Is(err, ErrCodeMissingPermissions)
+}
+
+// IsMissingAccess reports whether a request failed because the session
+// user cannot access the resource, e.g. a channel it cannot view.
+func IsMissingAccess(err error) bool {
+	return errors.Is(err, ErrCodeMissingAccess)
+}
+
+// IsUnknownResource reports whether a request failed because the resource
+// it refers to does not exist, e.g. ErrCodeUnknownMessage or ErrCodeUnknownChannel.
+func IsUnknownResource(err error) bool {
+	code, ok := ErrorCodeOf(err)
+	return ok && code >= 10001 && code < 20000
+}
+
+// IsInvalidFormBody reports whether a request failed validation. The
+// details can be found with RESTError.FieldErrors.
+func IsInvalidFormBody(err error) bool {
+	return errors.Is(err, ErrCodeInvalidFormBody)
+}
+
 // RateLimitError is returned when a request exceeds a rate limit
 // and ShouldRetryOnRateLimit is false. The request may be manually
 // retried after waiting the duration specified by RetryAfter.
@@ -96,13 +151,108 @@ func (e RateLimitError) Error() string {
 	return "Rate limit exceeded on " + e.URL + ", retry after " + e.RetryAfter.String()
 }
 
+// RequestConfig is an HTTP request configuration.
+type RequestConfig struct {
+	// Context controls the lifetime of the request, including any time spent
+	// waiting for a ratelimit bucket and sleeping before a retry.
+	Context context.Context
+
+	// Headers are set on the request after the session headers.
+	Headers map[string]string
+
+	ShouldRetryOnRateLimit bool
+	MaxRestRetries         int
+	Client                 tls_client.HttpClient
+}
+
+// newRequestConfig returns a new HTTP request configuration based on parameters in Session.
+func newRequestConfig(s *Session, options ...RequestOption) *RequestConfig {
+	cfg := &RequestConfig{
+		Context:                context.Background(),
+		ShouldRetryOnRateLimit: s.ShouldRetryOnRateLimit,
+		MaxRestRetries:         s.MaxRestRetries,
+		Client:                 s.Client,
+	}
+
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// RequestOption is a function which mutates request configuration.
+// It can be supplied as an argument to any REST method.
+type RequestOption func(cfg *RequestConfig)
+
+// WithContext changes context of the request.
+// Cancelling the context aborts the request, the wait for its ratelimit
+// bucket and any retry sleep.
+func WithContext(ctx context.Context) RequestOption {
+	return func(cfg *RequestConfig) {
+		cfg.Context = ctx
+	}
+}
+
+// WithClient changes the HTTP client used for the request.
+func WithClient(client tls_client.HttpClient) RequestOption {
+	return func(cfg *RequestConfig) {
+		if client != nil {
+			cfg.Client = client
+		}
+	}
+}
+
+// WithRetryOnRatelimit controls whether session will retry the request on rate limit.
+func WithRetryOnRatelimit(retry bool) RequestOption {
+	return func(cfg *RequestConfig) {
+		cfg.ShouldRetryOnRateLimit = retry
+	}
+}
+
+// WithRestRetries changes maximum amount of retries if request fails.
+func WithRestRetries(max int) RequestOption {
+	return func(cfg *RequestConfig) {
+		cfg.MaxRestRetries = max
+	}
+}
+
+// WithHeader sets a header in the request.
+func WithHeader(key, value string) RequestOption {
+	return func(cfg *RequestConfig) {
+		if cfg.Headers == nil {
+			cfg.Headers = map[string]string{}
+		}
+		cfg.Headers[key] = value
+	}
+}
+
+// WithHeaders sets all of the given headers in the request.
+func WithHeaders(headers map[string]string) RequestOption {
+	return func(cfg *RequestConfig) {
+		for k, v := range headers {
+			WithHeader(k, v)(cfg)
+		}
+	}
+}
+
+// WithAuditLogReason changes audit log reason associated with the request.
+func WithAuditLogReason(reason string) RequestOption {
+	return WithHeader("X-Audit-Log-Reason", url.PathEscape(reason))
+}
+
+// WithLocale changes accepted locale of the request.
+func WithLocale(locale Locale) RequestOption {
+	return WithHeader("X-Discord-Locale", string(locale))
+}
+
 // Request is the same as RequestWithBucketID but the bucket id is the same as the urlStr
-func (s *Session) Request(method, urlStr string, data any) (response []byte, err error) {
-	return s.RequestWithBucketID(method, urlStr, data, strings.SplitN(urlStr, "?", 2)[0])
+func (s *Session) Request(method, urlStr string, data any, options ...RequestOption) (response []byte, err error) {
+	return s.RequestWithBucketID(method, urlStr, data, strings.SplitN(urlStr, "?", 2)[0], options...)
 }
 
 // RequestWithBucketID makes a (GET/POST/...) Requests to Discord REST API with JSON data.
-func (s *Session) RequestWithBucketID(method, urlStr string, data any, bucketID string, headers ...map[string]string) (response []byte, err error) {
+func (s *Session) RequestWithBucketID(method, urlStr string, data any, bucketID string, options ...RequestOption) (response []byte, err error) {
 	var body []byte
 	if data != nil {
 		body, err = Marshal(data)
@@ -111,27 +261,31 @@ func (s *Session) RequestWithBucketID(method, urlStr string, data any, bucketID
 		}
 	}
 
-	return s.request(method, urlStr, "application/json", body, bucketID, 0, headers...)
+	return s.request(method, urlStr, "application/json", body, bucketID, 0, options...)
 }
 
 // request makes a (GET/POST/...) Requests to Discord REST API.
 // Sequence is the sequence number, if it fails with a 502 it will
 // retry with sequence+1 until it either succeeds or sequence >= session.MaxRestRetries
-func (s *Session) request(method, urlStr, contentType string, b []byte, bucketID string, sequence int, headers ...map[string]string) (response []byte, err error) {
+func (s *Session) request(method, urlStr, contentType string, b []byte, bucketID string, sequence int, options ...RequestOption) (response []byte, err error) {
 	if bucketID == "" {
 		bucketID = strings.SplitN(urlStr, "?", 2)[0]
 	}
-	return s.RequestWithLockedBucket(method, urlStr, contentType, b, s.Ratelimiter.LockBucket(bucketID), sequence, headers...)
+
+	cfg := newRequestConfig(s, options...)
+	bucket, err := s.Ratelimiter.LockBucketContext(cfg.Context, bucketID)
+	if err != nil {
+		return
+	}
+
+	return s.RequestWithLockedBucket(method, urlStr, contentType, b, bucket, sequence, options...)
 }
 
 // RequestWithLockedBucket makes a request using a bucket that's already been locked
-func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b []byte, bucket *Bucket, sequence int, headers ...map[string]string) (response []byte, err error) {
-	if s.Debug {
-		log.Printf("API REQUEST %8s :: %s\n", method, urlStr)
-		log.Printf("API REQUEST  PAYLOAD :: [%s]\n", string(b))
-	}
+func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b []byte, bucket *Bucket, sequence int, options ...RequestOption) (response []byte, err error) {
+	cfg := newRequestConfig(s, options...)
 
-	req, err := http.NewRequest(method, urlStr, bytes.NewBuffer(b))
+	req, err := http.NewRequestWithContext(cfg.Context, method, urlStr, nil)
 	if err != nil {
 		bucket.Release(nil)
 		return
@@ -152,54 +306,50 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 	// TODO: Make a configurable static variable.
 	req.Header.Set("User-Agent", s.UserAgent)
 
-	for k, v := range s.Headers {
+	for k, v := range cfg.Headers {
 		req.Header.Set(k, v)
 	}
 
-	for _, header := range headers {
-		for k, v := range header {
-			req.Header.Set(k, v)
+	// The bucket is locked on entry, any further attempts made by the
+	// middleware have to wait for it again.
+	locked := true
+	send := func(r *RESTRequest) (*RESTResponse, error) {
+		if !locked {
+			var err error
+			bucket, err = s.Ratelimiter.LockBucketObjectContext(r.Request.Context(), bucket)
+			if err != nil {
+				return nil, err
+			}
 		}
-	}
+		locked = false
 
-	if s.Debug {
-		for k, v := range req.Header {
-			log.Printf("API REQUEST   HEADER :: [%s] = %+v\n", k, v)
-		}
+		return s.doRequest(r, bucket)
 	}
 
-	resp, err := s.Client.Do(req)
-	if err != nil {
+	restReq := &RESTRequest{
+		Request: req,
+		Payload: b,
+		Config:  cfg,
+		Attempt: sequence,
+	}
+	restResp, err := s.restHandler(send)(restReq)
+	if locked {
+		// A middleware responded without sending the request.
 		bucket.Release(nil)
-		return
 	}
-	defer func() {
-		err2 := resp.Body.Close()
-		if s.Debug && err2 != nil {
-			log.Println("error closing resp body")
-		}
-	}()
-
-	err = bucket.Release(resp.Header)
 	if err != nil {
 		return
 	}
-
-	response, err = io.ReadAll(resp.Body)
-	if err != nil {
+	if restResp == nil || restResp.Response == nil {
+		err = ErrRESTNoResponse
 		return
 	}
 
-	if s.Debug {
+	req, resp := restReq.Request, restResp.Response
+	response = restResp.Body
+	sequence = restReq.Attempt
 
-		log.Printf("API RESPONSE  STATUS :: %s\n", resp.Status)
-		for k, v := range resp.Header {
-			log.Printf("API RESPONSE  HEADER :: [%s] = %+v\n", k, v)
-		}
-		log.Printf("API RESPONSE    BODY :: [%s]\n\n\n", response)
-	}
-
-	if sequence > s.MaxRestRetries {
+	if sequence > cfg.MaxRestRetries {
 		err = fmt.Errorf("exceeded max retries HTTP %s, %s", resp.Status, response)
 		return
 	}
@@ -208,10 +358,6 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 	case http.StatusOK:
 	case http.StatusCreated:
 	case http.StatusNoContent:
-	case http.StatusBadGateway:
-		// Retry sending request
-		s.log(LogInformational, "%s Failed (%s), Retrying...", urlStr, resp.Status)
-		response, err = s.RequestWithLockedBucket(method, urlStr, contentType, b, s.Ratelimiter.LockBucketObject(bucket), sequence+1)
 	case 429: // TOO MANY REQUESTS - Rate limiting
 		rl := TooManyRequests{}
 		err = Unmarshal(response, &rl)
@@ -220,7 +366,7 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 			return
 		}
 
-		if s.ShouldRetryOnRateLimit {
+		if cfg.ShouldRetryOnRateLimit {
 			s.log(LogInformational, "Rate Limiting %s, retry in %v", urlStr, rl.RetryAfter)
 			s.handleEvent(rateLimitEventType, &RateLimit{TooManyRequests: &rl, URL: urlStr})
 
@@ -233,11 +379,18 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 			fmt.Println("[RATELIMIT] Response:", resp.Status, string(response))
 			fmt.Println("[RATELIMIT] Retrying after:", rl.RetryAfter)
 
-			time.Sleep(rl.RetryAfter)
+			err = sleepContext(cfg.Context, rl.RetryAfter)
+			if err != nil {
+				return
+			}
 			// we can make the above smarter
 			// this method can cause longer delays than required
 
-			response, err = s.RequestWithLockedBucket(method, urlStr, contentType, b, s.Ratelimiter.LockBucketObject(bucket), sequence+1)
+			bucket, err = s.Ratelimiter.LockBucketObjectContext(cfg.Context, bucket)
+			if err != nil {
+				return
+			}
+			response, err = s.RequestWithLockedBucket(method, urlStr, contentType, b, bucket, sequence+1, options...)
 		} else {
 			err = &RateLimitError{&RateLimit{TooManyRequests: &rl, URL: urlStr}}
 		}
@@ -254,6 +407,58 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 	return
 }
 
+// doRequest sends a single REST request using a locked bucket, releasing
+// the bucket once the response headers are received.
+func (s *Session) doRequest(r *RESTRequest, bucket *Bucket) (*RESTResponse, error) {
+	req := r.Request.Clone(r.Request.Context())
+	req.Body, req.ContentLength = http.NoBody, 0
+	if len(r.Payload) > 0 {
+		req.Body = io.NopCloser(bytes.NewReader(r.Payload))
+		req.ContentLength = int64(len(r.Payload))
+	}
+
+	if s.Debug {
+		log.Printf("API REQUEST %8s :: %s\n", req.Method, req.URL)
+		log.Printf("API REQUEST  PAYLOAD :: [%s]\n", string(r.Payload))
+		for k, v := range req.Header {
+			log.Printf("API REQUEST   HEADER :: [%s] = %+v\n", k, v)
+		}
+	}
+
+	resp, err := r.Config.Client.Do(req)
+	if err != nil {
+		bucket.Release(nil)
+		return nil, err
+	}
+	defer func() {
+		err2 := resp.Body.Close()
+		if s.Debug && err2 != nil {
+			log.Println("error closing resp body")
+		}
+	}()
+
+	err = bucket.Release(resp.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Debug {
+
+		log.Printf("API RESPONSE  STATUS :: %s\n", resp.Status)
+		for k, v := range resp.Header {
+			log.Printf("API RESPONSE  HEADER :: [%s] = %+v\n", k, v)
+		}
+		log.Printf("API RESPONSE    BODY :: [%s]\n\n\n", body)
+	}
+
+	return &RESTResponse{Response: resp, Body: body}, nil
+}
+
 func unmarshal(data []byte, v any) error {
 	err := Unmarshal(data, v)
 	if err != nil {
@@ -269,8 +474,8 @@ func unmarshal(data []byte, v any) error {
 
 // User returns the user details of the given userID
 // userID    : A user ID or "@me" which is a shortcut of current user ID
-func (s *Session) User(userID string) (st *User, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointUser(userID), nil, EndpointUsers)
+func (s *Session) User(userID string, options ...RequestOption) (st *User, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointUser(userID), nil, EndpointUsers, options...)
 	if err != nil {
 		return
 	}
@@ -282,30 +487,30 @@ func (s *Session) User(userID string) (st *User, err error) {
 // UserWithFallback returns a User from state if available, otherwise
 // it will make a request to the API to get the user.
 // userID    : A user ID or "@me" which is a shortcut of current user ID
-func (s *Session) UserWithFallback(userID string) (*User, error) {
+func (s *Session) UserWithFallback(userID string, options ...RequestOption) (*User, error) {
 	user, err := s.State.GetUser(userID)
 	if err == nil {
 		return user, nil
 	}
 
-	return s.User(userID)
+	return s.User(userID, options...)
 }
 
 // UserAvatar is deprecated. Please use UserAvatarDecode
 // userID    : A user ID or "@me" which is a shortcut of current user ID
-func (s *Session) UserAvatar(userID string) (img image.Image, err error) {
-	u, err := s.User(userID)
+func (s *Session) UserAvatar(userID string, options ...RequestOption) (img image.Image, err error) {
+	u, err := s.User(userID, options...)
 	if err != nil {
 		return
 	}
-	img, err = s.UserAvatarDecode(u)
+	img, err = s.UserAvatarDecode(u, options...)
 	return
 }
 
 // UserAvatarDecode returns an image.Image of a user's Avatar
 // user : The user which avatar should be retrieved
-func (s *Session) UserAvatarDecode(u *User) (img image.Image, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointUserAvatar(u.ID, u.Avatar), nil, EndpointUserAvatar("", ""))
+func (s *Session) UserAvatarDecode(u *User, options ...RequestOption) (img image.Image, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointUserAvatar(u.ID, u.Avatar), nil, EndpointUserAvatar("", ""), options...)
 	if err != nil {
 		return
 	}
@@ -315,7 +520,7 @@ func (s *Session) UserAvatarDecode(u *User) (img image.Image, err error) {
 }
 
 // UserUpdate updates current user settings.
-func (s *Session) UserUpdate(username, avatar string) (st *User, err error) {
+func (s *Session) UserUpdate(username, avatar string, options ...RequestOption) (st *User, err error) {
 	// NOTE: Avatar must be either the hash/id of existing Avatar or
 	// data:image/png;base64,BASE64_STRING_OF_NEW_AVATAR_PNG
 	// to set a new avatar.
@@ -326,7 +531,7 @@ func (s *Session) UserUpdate(username, avatar string) (st *User, err error) {
 		Avatar   string `json:"avatar,omitempty"`
 	}{username, avatar}
 
-	body, err := s.RequestWithBucketID("PATCH", EndpointUser("@me"), data, EndpointUsers)
+	body, err := s.RequestWithBucketID("PATCH", EndpointUser("@me"), data, EndpointUsers, options...)
 	if err != nil {
 		return
 	}
@@ -336,20 +541,20 @@ func (s *Session) UserUpdate(username, avatar string) (st *User, err error) {
 }
 
 // UserSettingsProtoUpdate updates the user's settings with an encoded protobuf
-func (s *Session) UserSettingsProtoUpdate(sType, settings string, reqDataVersion *int) (err error) {
+func (s *Session) UserSettingsProtoUpdate(sType, settings string, reqDataVersion *int, options ...RequestOption) (err error) {
 	data := struct {
 		Settings            string `json:"settings"`
 		RequiredDataVersion *int   `json:"required_data_version,omitempty"`
 	}{settings, reqDataVersion}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointUserSettingsProto(sType), data, EndpointUserSettingsProto(sType))
+	_, err = s.RequestWithBucketID("PATCH", EndpointUserSettingsProto(sType), data, EndpointUserSettingsProto(sType), options...)
 
 	return
 }
 
 // UserFrecencySettings returns the user's frecency settings
-func (s *Session) UserFrecencySettings() (settings *protos.FrecencyUserSettings, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointUserSettingsProto("2"), nil, EndpointUserSettingsProto("2"))
+func (s *Session) UserFrecencySettings(options ...RequestOption) (settings *protos.FrecencyUserSettings, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointUserSettingsProto("2"), nil, EndpointUserSettingsProto("2"), options...)
 	if err != nil {
 		return nil, err
 	}
@@ -378,7 +583,7 @@ func (s *Session) UserFrecencySettings() (settings *protos.FrecencyUserSettings,
 }
 
 // UserSettingsUpdate updates the user's settings
-func (s *Session) UserSettingsUpdate(settings *protos.PreloadedUserSettings, requireVersion bool) (err error) {
+func (s *Session) UserSettingsUpdate(settings *protos.PreloadedUserSettings, requireVersion bool, options ...RequestOption) (err error) {
 	rawSettings, err := proto.Marshal(settings)
 	if err != nil {
 		return
@@ -393,11 +598,11 @@ func (s *Session) UserSettingsUpdate(settings *protos.PreloadedUserSettings, req
 		dataVersion = &settingsDataVersion
 	}
 
-	return s.UserSettingsProtoUpdate("1", encodedSettings, dataVersion)
+	return s.UserSettingsProtoUpdate("1", encodedSettings, dataVersion, options...)
 }
 
 // FrecencySettingsUpdate updates the user's frecency settings
-func (s *Session) FrecencySettingsUpdate(settings *protos.FrecencyUserSettings, requireVersion bool) (err error) {
+func (s *Session) FrecencySettingsUpdate(settings *protos.FrecencyUserSettings, requireVersion bool, options ...RequestOption) (err error) {
 	rawSettings, err := proto.Marshal(settings)
 	if err != nil {
 		return
@@ -412,12 +617,12 @@ func (s *Session) FrecencySettingsUpdate(settings *protos.FrecencyUserSettings,
 		dataVersion = &settingsDataVersion
 	}
 
-	return s.UserSettingsProtoUpdate("2", encodedSettings, dataVersion)
+	return s.UserSettingsProtoUpdate("2", encodedSettings, dataVersion, options...)
 }
 
 // UserConnections returns the user's connections
-func (s *Session) UserConnections() (conn []*UserConnection, err error) {
-	response, err := s.RequestWithBucketID("GET", EndpointUserConnections("@me"), nil, EndpointUserConnections("@me"))
+func (s *Session) UserConnections(options ...RequestOption) (conn []*UserConnection, err error) {
+	response, err := s.RequestWithBucketID("GET", EndpointUserConnections("@me"), nil, EndpointUserConnections("@me"), options...)
 	if err != nil {
 		return nil, err
 	}
@@ -431,8 +636,8 @@ func (s *Session) UserConnections() (conn []*UserConnection, err error) {
 }
 
 // UserMutualFriends returns the client's mutual friends with the given user
-func (s *Session) UserMutualFriends(userID string) (users []*User, err error) {
-	response, err := s.RequestWithBucketID("GET", EndpointUserRelationships(userID), nil, EndpointUserConnections(""))
+func (s *Session) UserMutualFriends(userID string, options ...RequestOption) (users []*User, err error) {
+	response, err := s.RequestWithBucketID("GET", EndpointUserRelationships(userID), nil, EndpointUserConnections(""), options...)
 	if err != nil {
 		return nil, err
 	}
@@ -446,8 +651,8 @@ func (s *Session) UserMutualFriends(userID string) (users []*User, err error) {
 }
 
 // UserProfile returns the user's profile
-func (s *Session) UserProfile(userID string) (st *Profile, err error) {
-	response, err := s.RequestWithBucketID("GET", EndpointUserProfile(userID)+"?with_mutual_guilds=true", nil, EndpointUserProfile(""))
+func (s *Session) UserProfile(userID string, options ...RequestOption) (st *Profile, err error) {
+	response, err := s.RequestWithBucketID("GET", EndpointUserProfile(userID)+"?with_mutual_guilds=true", nil, EndpointUserProfile(""), options...)
 	if err != nil {
 		return nil, err
 	}
@@ -462,12 +667,12 @@ func (s *Session) UserProfile(userID string) (st *Profile, err error) {
 
 // UserChannelCreate creates a new User (Private) Channel with another User
 // recipientID : A user ID for the user to which this channel is opened with.
-func (s *Session) UserChannelCreate(recipientID string) (st *Channel, err error) {
+func (s *Session) UserChannelCreate(recipientID string, options ...RequestOption) (st *Channel, err error) {
 	data := struct {
 		RecipientID string `json:"recipient_id"`
 	}{recipientID}
 
-	body, err := s.RequestWithBucketID("POST", EndpointUserChannels("@me"), data, EndpointUserChannels(""))
+	body, err := s.RequestWithBucketID("POST", EndpointUserChannels("@me"), data, EndpointUserChannels(""), options...)
 	if err != nil {
 		return
 	}
@@ -478,8 +683,8 @@ func (s *Session) UserChannelCreate(recipientID string) (st *Channel, err error)
 
 // UserGuildMember returns a guild member object for the current user in the given Guild.
 // guildID : ID of the guild
-func (s *Session) UserGuildMember(guildID string) (st *Member, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointUserGuildMember("@me", guildID), nil, EndpointUserGuildMember("@me", guildID))
+func (s *Session) UserGuildMember(guildID string, options ...RequestOption) (st *Member, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointUserGuildMember("@me", guildID), nil, EndpointUserGuildMember("@me", guildID), options...)
 	if err != nil {
 		return
 	}
@@ -492,7 +697,7 @@ func (s *Session) UserGuildMember(guildID string) (st *Member, err error) {
 // limit     : The number guilds that can be returned. (max 100)
 // beforeID  : If provided all guilds returned will be before given ID.
 // afterID   : If provided all guilds returned will be after given ID.
-func (s *Session) UserGuilds(limit int, beforeID, afterID string) (st []*UserGuild, err error) {
+func (s *Session) UserGuilds(limit int, beforeID, afterID string, options ...RequestOption) (st []*UserGuild, err error) {
 	v := url.Values{}
 
 	if limit > 0 {
@@ -511,7 +716,7 @@ func (s *Session) UserGuilds(limit int, beforeID, afterID string) (st []*UserGui
 		uri += "?" + v.Encode()
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointUserGuilds(""))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointUserGuilds(""), options...)
 	if err != nil {
 		return
 	}
@@ -526,7 +731,7 @@ func (s *Session) UserGuilds(limit int, beforeID, afterID string) (st []*UserGui
 //
 // NOTE: This function is now deprecated and will be removed in the future.
 // Please see the same function inside state.go
-func (s *Session) UserChannelPermissions(userID, channelID string) (apermissions int64, err error) {
+func (s *Session) UserChannelPermissions(userID, channelID string, options ...RequestOption) (apermissions int64, err error) {
 	// Try to just get permissions from state.
 	apermissions, err = s.State.UserChannelPermissions(userID, channelID)
 	if err == nil {
@@ -534,12 +739,12 @@ func (s *Session) UserChannelPermissions(userID, channelID string) (apermissions
 	}
 
 	// Otherwise try get as much data from state as possible, falling back to the network.
-	channel, err := s.ChannelWithFallback(channelID)
+	channel, err := s.ChannelWithFallback(channelID, options...)
 	if err != nil {
 		return
 	}
 
-	guild, err := s.GuildWithFallback(channel.GuildID)
+	guild, err := s.GuildWithFallback(channel.GuildID, options...)
 	if err != nil {
 		return
 	}
@@ -551,7 +756,7 @@ func (s *Session) UserChannelPermissions(userID, channelID string) (apermissions
 
 	member, err := s.State.Member(guild.ID, userID)
 	if err != nil || member == nil {
-		member, err = s.GuildMember(guild.ID, userID)
+		member, err = s.GuildMember(guild.ID, userID, options...)
 		if err != nil {
 			return
 		}
@@ -627,8 +832,8 @@ func memberPermissions(guild *Guild, channel *Channel, userID string, roles []st
 	return apermissions
 }
 
-func (s *Session) UserRelationships() (st []*Relationship, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointUserRelationships("@me"), nil, EndpointUserRelationships(""))
+func (s *Session) UserRelationships(options ...RequestOption) (st []*Relationship, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointUserRelationships("@me"), nil, EndpointUserRelationships(""), options...)
 	if err != nil {
 		return
 	}
@@ -637,27 +842,23 @@ func (s *Session) UserRelationships() (st []*Relationship, err error) {
 	return
 }
 
-func (s *Session) UserRelationshipDelete(userID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointUserRelationship(userID), nil, EndpointUserRelationships(""), map[string]string{
-		"x-context-properties": "eyJsb2NhdGlvbiI6IkNvbnRleHRNZW51In0=",
-	})
+func (s *Session) UserRelationshipDelete(userID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointUserRelationship(userID), nil, EndpointUserRelationships(""), append([]RequestOption{WithHeader("x-context-properties", "eyJsb2NhdGlvbiI6IkNvbnRleHRNZW51In0=")}, options...)...)
 	return
 }
 
-func (s *Session) UserRelationshipCreate(userID string, relType int) (err error) {
+func (s *Session) UserRelationshipCreate(userID string, relType int, options ...RequestOption) (err error) {
 	data := map[string]int{}
 
 	if relType != 1 {
 		data["type"] = relType
 	}
 
-	_, err = s.RequestWithBucketID("PUT", EndpointUserRelationship(userID), data, EndpointUserRelationships(""), map[string]string{
-		"x-context-properties": "eyJsb2NhdGlvbiI6IkNvbnRleHRNZW51In0=",
-	})
+	_, err = s.RequestWithBucketID("PUT", EndpointUserRelationship(userID), data, EndpointUserRelationships(""), append([]RequestOption{WithHeader("x-context-properties", "eyJsb2NhdGlvbiI6IkNvbnRleHRNZW51In0=")}, options...)...)
 	return
 }
 
-func (s *Session) UserSendFriendRequest(username string, discriminator int) (err error) {
+func (s *Session) UserSendFriendRequest(username string, discriminator int, options ...RequestOption) (err error) {
 	data := map[string]any{
 		"username":      username,
 		"discriminator": nil,
@@ -667,9 +868,7 @@ func (s *Session) UserSendFriendRequest(username string, discriminator int) (err
 		data["discriminator"] = discriminator
 	}
 
-	_, err = s.RequestWithBucketID("POST", EndpointUserRelationships("@me"), data, EndpointUserRelationships(""), map[string]string{
-		"x-context-properties": "eyJsb2NhdGlvbiI6IkFkZCBGcmllbmQifQ==",
-	})
+	_, err = s.RequestWithBucketID("POST", EndpointUserRelationships("@me"), data, EndpointUserRelationships(""), append([]RequestOption{WithHeader("x-context-properties", "eyJsb2NhdGlvbiI6IkFkZCBGcmllbmQifQ==")}, options...)...)
 	return
 }
 
@@ -679,8 +878,8 @@ func (s *Session) UserSendFriendRequest(username string, discriminator int) (err
 
 // Guild returns a Guild structure of a specific Guild.
 // guildID   : The ID of a Guild
-func (s *Session) Guild(guildID string) (st *Guild, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuild(guildID), nil, EndpointGuild(guildID))
+func (s *Session) Guild(guildID string, options ...RequestOption) (st *Guild, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuild(guildID), nil, EndpointGuild(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -692,19 +891,19 @@ func (s *Session) Guild(guildID string) (st *Guild, err error) {
 // GuildWithFallback returns a Guild from state if available, otherwise
 // it will make a request to the API to get the guild.
 // guildID  :  The ID of a Guild
-func (s *Session) GuildWithFallback(guildID string) (*Guild, error) {
+func (s *Session) GuildWithFallback(guildID string, options ...RequestOption) (*Guild, error) {
 	guild, err := s.State.Guild(guildID)
 	if err == nil {
 		return guild, nil
 	}
 
-	return s.Guild(guildID)
+	return s.Guild(guildID, options...)
 }
 
 // GuildWithCounts returns a Guild structure of a specific Guild with approximate member and presence counts.
 // guildID    : The ID of a Guild
-func (s *Session) GuildWithCounts(guildID string) (st *Guild, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuild(guildID)+"?with_counts=true", nil, EndpointGuild(guildID))
+func (s *Session) GuildWithCounts(guildID string, options ...RequestOption) (st *Guild, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuild(guildID)+"?with_counts=true", nil, EndpointGuild(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -715,8 +914,8 @@ func (s *Session) GuildWithCounts(guildID string) (st *Guild, err error) {
 
 // GuildPreview returns a GuildPreview structure of a specific public Guild.
 // guildID   : The ID of a Guild
-func (s *Session) GuildPreview(guildID string) (st *GuildPreview, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildPreview(guildID), nil, EndpointGuildPreview(guildID))
+func (s *Session) GuildPreview(guildID string, options ...RequestOption) (st *GuildPreview, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildPreview(guildID), nil, EndpointGuildPreview(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -727,12 +926,12 @@ func (s *Session) GuildPreview(guildID string) (st *GuildPreview, err error) {
 
 // GuildCreate creates a new Guild
 // name      : A name for the Guild (2-100 characters)
-func (s *Session) GuildCreate(name string) (st *Guild, err error) {
+func (s *Session) GuildCreate(name string, options ...RequestOption) (st *Guild, err error) {
 	data := struct {
 		Name string `json:"name"`
 	}{name}
 
-	body, err := s.RequestWithBucketID("POST", EndpointGuildCreate, data, EndpointGuildCreate)
+	body, err := s.RequestWithBucketID("POST", EndpointGuildCreate, data, EndpointGuildCreate, options...)
 	if err != nil {
 		return
 	}
@@ -744,7 +943,7 @@ func (s *Session) GuildCreate(name string) (st *Guild, err error) {
 // GuildEdit edits a new Guild
 // guildID   : The ID of a Guild
 // g 		 : A GuildParams struct with the values Name, Region and VerificationLevel defined.
-func (s *Session) GuildEdit(guildID string, g *GuildParams) (st *Guild, err error) {
+func (s *Session) GuildEdit(guildID string, g *GuildParams, options ...RequestOption) (st *Guild, err error) {
 	// Bounds checking for VerificationLevel, interval: [0, 4]
 	if g.VerificationLevel != nil {
 		val := *g.VerificationLevel
@@ -757,7 +956,7 @@ func (s *Session) GuildEdit(guildID string, g *GuildParams) (st *Guild, err erro
 	// Bounds checking for regions
 	if g.Region != "" {
 		isValid := false
-		regions, _ := s.VoiceRegions()
+		regions, _ := s.VoiceRegions(options...)
 		for _, r := range regions {
 			if g.Region == r.ID {
 				isValid = true
@@ -773,7 +972,7 @@ func (s *Session) GuildEdit(guildID string, g *GuildParams) (st *Guild, err erro
 		}
 	}
 
-	body, err := s.RequestWithBucketID("PATCH", EndpointGuild(guildID), g, EndpointGuild(guildID))
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuild(guildID), g, EndpointGuild(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -784,8 +983,8 @@ func (s *Session) GuildEdit(guildID string, g *GuildParams) (st *Guild, err erro
 
 // GuildDelete deletes a Guild.
 // guildID   : The ID of a Guild
-func (s *Session) GuildDelete(guildID string) (st *Guild, err error) {
-	body, err := s.RequestWithBucketID("DELETE", EndpointGuild(guildID), nil, EndpointGuild(guildID))
+func (s *Session) GuildDelete(guildID string, options ...RequestOption) (st *Guild, err error) {
+	body, err := s.RequestWithBucketID("DELETE", EndpointGuild(guildID), nil, EndpointGuild(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -796,8 +995,8 @@ func (s *Session) GuildDelete(guildID string) (st *Guild, err error) {
 
 // GuildLeave leaves a Guild.
 // guildID   : The ID of a Guild
-func (s *Session) GuildLeave(guildID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointUserGuild("@me", guildID), nil, EndpointUserGuild("", guildID))
+func (s *Session) GuildLeave(guildID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointUserGuild("@me", guildID), nil, EndpointUserGuild("", guildID), options...)
 	return
 }
 
@@ -807,7 +1006,7 @@ func (s *Session) GuildLeave(guildID string) (err error) {
 //	limit     : Max number of bans to return (max 1000)
 //	beforeID  : If not empty all returned users will be after the given id
 //	afterID   : If not empty all returned users will be before the given id
-func (s *Session) GuildBans(guildID string, limit int, beforeID, afterID string) (st []*GuildBan, err error) {
+func (s *Session) GuildBans(guildID string, limit int, beforeID, afterID string, options ...RequestOption) (st []*GuildBan, err error) {
 	uri := EndpointGuildBans(guildID)
 
 	v := url.Values{}
@@ -825,7 +1024,7 @@ func (s *Session) GuildBans(guildID string, limit int, beforeID, afterID string)
 		uri += "?" + v.Encode()
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildBans(guildID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildBans(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -839,13 +1038,13 @@ func (s *Session) GuildBans(guildID string, limit int, beforeID, afterID string)
 // guildID   : The ID of a Guild.
 // userID    : The ID of a User
 // days      : The number of days of previous comments to delete.
-func (s *Session) GuildBanCreate(guildID, userID string, days int) (err error) {
-	return s.GuildBanCreateWithReason(guildID, userID, "", days)
+func (s *Session) GuildBanCreate(guildID, userID string, days int, options ...RequestOption) (err error) {
+	return s.GuildBanCreateWithReason(guildID, userID, "", days, options...)
 }
 
 // GuildBan finds ban by given guild and user id and returns GuildBan structure
-func (s *Session) GuildBan(guildID, userID string) (st *GuildBan, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildBan(guildID, userID), nil, EndpointGuildBan(guildID, userID))
+func (s *Session) GuildBan(guildID, userID string, options ...RequestOption) (st *GuildBan, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildBan(guildID, userID), nil, EndpointGuildBan(guildID, userID), options...)
 	if err != nil {
 		return
 	}
@@ -860,7 +1059,7 @@ func (s *Session) GuildBan(guildID, userID string) (st *GuildBan, err error) {
 // userID    : The ID of a User
 // reason    : The reason for this ban
 // days      : The number of days of previous comments to delete.
-func (s *Session) GuildBanCreateWithReason(guildID, userID, reason string, days int) (err error) {
+func (s *Session) GuildBanCreateWithReason(guildID, userID, reason string, days int, options ...RequestOption) (err error) {
 	uri := EndpointGuildBan(guildID, userID)
 
 	queryParams := url.Values{}
@@ -875,15 +1074,15 @@ func (s *Session) GuildBanCreateWithReason(guildID, userID, reason string, days
 		uri += "?" + queryParams.Encode()
 	}
 
-	_, err = s.RequestWithBucketID("PUT", uri, nil, EndpointGuildBan(guildID, ""))
+	_, err = s.RequestWithBucketID("PUT", uri, nil, EndpointGuildBan(guildID, ""), options...)
 	return
 }
 
 // GuildBanDelete removes the given user from the guild bans
 // guildID   : The ID of a Guild.
 // userID    : The ID of a User
-func (s *Session) GuildBanDelete(guildID, userID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointGuildBan(guildID, userID), nil, EndpointGuildBan(guildID, ""))
+func (s *Session) GuildBanDelete(guildID, userID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildBan(guildID, userID), nil, EndpointGuildBan(guildID, ""), options...)
 	return
 }
 
@@ -892,7 +1091,7 @@ func (s *Session) GuildBanDelete(guildID, userID string) (err error) {
 //	guildID  : The ID of a Guild.
 //	after    : The id of the member to return members after
 //	limit    : max number of members to return (max 1000)
-func (s *Session) GuildMembers(guildID string, after string, limit int) (st []*Member, err error) {
+func (s *Session) GuildMembers(guildID string, after string, limit int, options ...RequestOption) (st []*Member, err error) {
 	uri := EndpointGuildMembers(guildID)
 
 	v := url.Values{}
@@ -909,7 +1108,7 @@ func (s *Session) GuildMembers(guildID string, after string, limit int) (st []*M
 		uri += "?" + v.Encode()
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildMembers(guildID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildMembers(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -922,7 +1121,7 @@ func (s *Session) GuildMembers(guildID string, after string, limit int) (st []*M
 // guildID  : The ID of a Guild
 // query    : Query string to match username(s) and nickname(s) against
 // limit    : Max number of members to return (default 1, min 1, max 1000)
-func (s *Session) GuildMembersSearch(guildID, query string, limit int) (st []*Member, err error) {
+func (s *Session) GuildMembersSearch(guildID, query string, limit int, options ...RequestOption) (st []*Member, err error) {
 	uri := EndpointGuildMembersSearch(guildID)
 
 	queryParams := url.Values{}
@@ -931,7 +1130,7 @@ func (s *Session) GuildMembersSearch(guildID, query string, limit int) (st []*Me
 		queryParams.Set("limit", strconv.Itoa(limit))
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri+"?"+queryParams.Encode(), nil, uri)
+	body, err := s.RequestWithBucketID("GET", uri+"?"+queryParams.Encode(), nil, uri, options...)
 	if err != nil {
 		return
 	}
@@ -944,8 +1143,8 @@ func (s *Session) GuildMembersSearch(guildID, query string, limit int) (st []*Me
 //
 //	guildID   : The ID of a Guild.
 //	userID    : The ID of a User
-func (s *Session) GuildMember(guildID, userID string) (st *Member, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildMember(guildID, userID), nil, EndpointGuildMember(guildID, ""))
+func (s *Session) GuildMember(guildID, userID string, options ...RequestOption) (st *Member, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildMember(guildID, userID), nil, EndpointGuildMember(guildID, ""), options...)
 	if err != nil {
 		return
 	}
@@ -961,8 +1160,8 @@ func (s *Session) GuildMember(guildID, userID string) (st *Member, err error) {
 //	guildID       : The ID of a Guild.
 //	userID        : The ID of a User.
 //	data          : Parameters of the user to add.
-func (s *Session) GuildMemberAdd(guildID, userID string, data *GuildMemberAddParams) (err error) {
-	_, err = s.RequestWithBucketID("PUT", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""))
+func (s *Session) GuildMemberAdd(guildID, userID string, data *GuildMemberAddParams, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("PUT", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""), options...)
 	if err != nil {
 		return err
 	}
@@ -973,21 +1172,21 @@ func (s *Session) GuildMemberAdd(guildID, userID string, data *GuildMemberAddPar
 // GuildMemberDelete removes the given user from the given guild.
 // guildID   : The ID of a Guild.
 // userID    : The ID of a User
-func (s *Session) GuildMemberDelete(guildID, userID string) (err error) {
-	return s.GuildMemberDeleteWithReason(guildID, userID, "")
+func (s *Session) GuildMemberDelete(guildID, userID string, options ...RequestOption) (err error) {
+	return s.GuildMemberDeleteWithReason(guildID, userID, "", options...)
 }
 
 // GuildMemberDeleteWithReason removes the given user from the given guild.
 // guildID   : The ID of a Guild.
 // userID    : The ID of a User
 // reason    : The reason for the kick
-func (s *Session) GuildMemberDeleteWithReason(guildID, userID, reason string) (err error) {
+func (s *Session) GuildMemberDeleteWithReason(guildID, userID, reason string, options ...RequestOption) (err error) {
 	uri := EndpointGuildMember(guildID, userID)
 	if reason != "" {
 		uri += "?reason=" + url.QueryEscape(reason)
 	}
 
-	_, err = s.RequestWithBucketID("DELETE", uri, nil, EndpointGuildMember(guildID, ""))
+	_, err = s.RequestWithBucketID("DELETE", uri, nil, EndpointGuildMember(guildID, ""), options...)
 	return
 }
 
@@ -995,9 +1194,9 @@ func (s *Session) GuildMemberDeleteWithReason(guildID, userID, reason string) (e
 // guildID  : The ID of a Guild.
 // userID   : The ID of a User.
 // data     : Updated GuildMember data.
-func (s *Session) GuildMemberEdit(guildID, userID string, data *GuildMemberParams) (st *Member, err error) {
+func (s *Session) GuildMemberEdit(guildID, userID string, data *GuildMemberParams, options ...RequestOption) (st *Member, err error) {
 	var body []byte
-	body, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""))
+	body, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""), options...)
 	if err != nil {
 		return nil, err
 	}
@@ -1011,8 +1210,8 @@ func (s *Session) GuildMemberEdit(guildID, userID string, data *GuildMemberParam
 // guildID  : The ID of a Guild.
 // userID   : The ID of a User.
 // data     : A GuildMemberEditData struct with the new nickname and roles
-func (s *Session) GuildMemberEditComplex(guildID, userID string, data *GuildMemberParams) (st *Member, err error) {
-	return s.GuildMemberEdit(guildID, userID, data)
+func (s *Session) GuildMemberEditComplex(guildID, userID string, data *GuildMemberParams, options ...RequestOption) (st *Member, err error) {
+	return s.GuildMemberEdit(guildID, userID, data, options...)
 }
 
 // GuildMemberMove moves a guild member from one voice channel to another/none
@@ -1023,12 +1222,12 @@ func (s *Session) GuildMemberEditComplex(guildID, userID string, data *GuildMemb
 //
 // NOTE : I am not entirely set on the name of this function and it may change
 // prior to the final 1.0.0 release of Discordgo
-func (s *Session) GuildMemberMove(guildID string, userID string, channelID *string) (err error) {
+func (s *Session) GuildMemberMove(guildID string, userID string, channelID *string, options ...RequestOption) (err error) {
 	data := struct {
 		ChannelID *string `json:"channel_id"`
 	}{channelID}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""))
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""), options...)
 	return
 }
 
@@ -1037,7 +1236,7 @@ func (s *Session) GuildMemberMove(guildID string, userID string, channelID *stri
 // userID    : The ID of a user
 // userID    : The ID of a user or "@me" which is a shortcut of the current user ID
 // nickname  : The nickname of the member, "" will reset their nickname
-func (s *Session) GuildMemberNickname(guildID, userID, nickname string) (err error) {
+func (s *Session) GuildMemberNickname(guildID, userID, nickname string, options ...RequestOption) (err error) {
 	data := struct {
 		Nick string `json:"nick"`
 	}{nickname}
@@ -1046,7 +1245,7 @@ func (s *Session) GuildMemberNickname(guildID, userID, nickname string) (err err
 		userID += "/nick"
 	}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""))
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""), options...)
 	return
 }
 
@@ -1055,12 +1254,12 @@ func (s *Session) GuildMemberNickname(guildID, userID, nickname string) (err err
 //	guildID   : The ID of a Guild.
 //	userID    : The ID of a User.
 //	mute    : boolean value for if the user should be muted
-func (s *Session) GuildMemberMute(guildID string, userID string, mute bool) (err error) {
+func (s *Session) GuildMemberMute(guildID string, userID string, mute bool, options ...RequestOption) (err error) {
 	data := struct {
 		Mute bool `json:"mute"`
 	}{mute}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""))
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""), options...)
 	return
 }
 
@@ -1070,12 +1269,12 @@ func (s *Session) GuildMemberMute(guildID string, userID string, mute bool) (err
 //	userID    : The ID of a User.
 //	until     : The timestamp for how long a member should be timed out.
 //	            Set to nil to remove timeout.
-func (s *Session) GuildMemberTimeout(guildID string, userID string, until *time.Time) (err error) {
+func (s *Session) GuildMemberTimeout(guildID string, userID string, until *time.Time, options ...RequestOption) (err error) {
 	data := struct {
 		CommunicationDisabledUntil *time.Time `json:"communication_disabled_until"`
 	}{until}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""))
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""), options...)
 	return
 }
 
@@ -1084,12 +1283,12 @@ func (s *Session) GuildMemberTimeout(guildID string, userID string, until *time.
 //	guildID   : The ID of a Guild.
 //	userID    : The ID of a User.
 //	deaf    : boolean value for if the user should be deafened
-func (s *Session) GuildMemberDeafen(guildID string, userID string, deaf bool) (err error) {
+func (s *Session) GuildMemberDeafen(guildID string, userID string, deaf bool, options ...RequestOption) (err error) {
 	data := struct {
 		Deaf bool `json:"deaf"`
 	}{deaf}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""))
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildMember(guildID, userID), data, EndpointGuildMember(guildID, ""), options...)
 	return
 }
 
@@ -1098,8 +1297,8 @@ func (s *Session) GuildMemberDeafen(guildID string, userID string, deaf bool) (e
 //	guildID   : The ID of a Guild.
 //	userID    : The ID of a User.
 //	roleID 	  : The ID of a Role to be assigned to the user.
-func (s *Session) GuildMemberRoleAdd(guildID, userID, roleID string) (err error) {
-	_, err = s.RequestWithBucketID("PUT", EndpointGuildMemberRole(guildID, userID, roleID), nil, EndpointGuildMemberRole(guildID, "", ""))
+func (s *Session) GuildMemberRoleAdd(guildID, userID, roleID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("PUT", EndpointGuildMemberRole(guildID, userID, roleID), nil, EndpointGuildMemberRole(guildID, "", ""), options...)
 
 	return
 }
@@ -1109,8 +1308,8 @@ func (s *Session) GuildMemberRoleAdd(guildID, userID, roleID string) (err error)
 //	guildID   : The ID of a Guild.
 //	userID    : The ID of a User.
 //	roleID 	  : The ID of a Role to be removed from the user.
-func (s *Session) GuildMemberRoleRemove(guildID, userID, roleID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointGuildMemberRole(guildID, userID, roleID), nil, EndpointGuildMemberRole(guildID, "", ""))
+func (s *Session) GuildMemberRoleRemove(guildID, userID, roleID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildMemberRole(guildID, userID, roleID), nil, EndpointGuildMemberRole(guildID, "", ""), options...)
 
 	return
 }
@@ -1118,8 +1317,8 @@ func (s *Session) GuildMemberRoleRemove(guildID, userID, roleID string) (err err
 // GuildChannels returns an array of Channel structures for all channels of a
 // given guild.
 // guildID   : The ID of a Guild.
-func (s *Session) GuildChannels(guildID string) (st []*Channel, err error) {
-	body, err := s.request("GET", EndpointGuildChannels(guildID), "", nil, EndpointGuildChannels(guildID), 0)
+func (s *Session) GuildChannels(guildID string, options ...RequestOption) (st []*Channel, err error) {
+	body, err := s.request("GET", EndpointGuildChannels(guildID), "", nil, EndpointGuildChannels(guildID), 0, options...)
 	if err != nil {
 		return
 	}
@@ -1146,8 +1345,8 @@ type GuildChannelCreateData struct {
 // GuildChannelCreateComplex creates a new channel in the given guild
 // guildID      : The ID of a Guild
 // data         : A data struct describing the new Channel, Name and Type are mandatory, other fields depending on the type
-func (s *Session) GuildChannelCreateComplex(guildID string, data GuildChannelCreateData) (st *Channel, err error) {
-	body, err := s.RequestWithBucketID("POST", EndpointGuildChannels(guildID), data, EndpointGuildChannels(guildID))
+func (s *Session) GuildChannelCreateComplex(guildID string, data GuildChannelCreateData, options ...RequestOption) (st *Channel, err error) {
+	body, err := s.RequestWithBucketID("POST", EndpointGuildChannels(guildID), data, EndpointGuildChannels(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1160,17 +1359,17 @@ func (s *Session) GuildChannelCreateComplex(guildID string, data GuildChannelCre
 // guildID   : The ID of a Guild.
 // name      : Name of the channel (2-100 chars length)
 // ctype     : Type of the channel
-func (s *Session) GuildChannelCreate(guildID, name string, ctype ChannelType) (st *Channel, err error) {
+func (s *Session) GuildChannelCreate(guildID, name string, ctype ChannelType, options ...RequestOption) (st *Channel, err error) {
 	return s.GuildChannelCreateComplex(guildID, GuildChannelCreateData{
 		Name: name,
 		Type: ctype,
-	})
+	}, options...)
 }
 
 // GuildChannelsReorder updates the order of channels in a guild
 // guildID   : The ID of a Guild.
 // channels  : Updated channels.
-func (s *Session) GuildChannelsReorder(guildID string, channels []*Channel) (err error) {
+func (s *Session) GuildChannelsReorder(guildID string, channels []*Channel, options ...RequestOption) (err error) {
 	data := make([]struct {
 		ID       string `json:"id"`
 		Position int    `json:"position"`
@@ -1181,14 +1380,14 @@ func (s *Session) GuildChannelsReorder(guildID string, channels []*Channel) (err
 		data[i].Position = c.Position
 	}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildChannels(guildID), data, EndpointGuildChannels(guildID))
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildChannels(guildID), data, EndpointGuildChannels(guildID), options...)
 	return
 }
 
 // GuildInvites returns an array of Invite structures for the given guild
 // guildID   : The ID of a Guild.
-func (s *Session) GuildInvites(guildID string) (st []*Invite, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildInvites(guildID), nil, EndpointGuildInvites(guildID))
+func (s *Session) GuildInvites(guildID string, options ...RequestOption) (st []*Invite, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildInvites(guildID), nil, EndpointGuildInvites(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1199,8 +1398,8 @@ func (s *Session) GuildInvites(guildID string) (st []*Invite, err error) {
 
 // GuildRoles returns all roles for a given guild.
 // guildID   : The ID of a Guild.
-func (s *Session) GuildRoles(guildID string) (st []*Role, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildRoles(guildID), nil, EndpointGuildRoles(guildID))
+func (s *Session) GuildRoles(guildID string, options ...RequestOption) (st []*Role, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildRoles(guildID), nil, EndpointGuildRoles(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1213,8 +1412,8 @@ func (s *Session) GuildRoles(guildID string) (st []*Role, err error) {
 // GuildRoleCreate creates a new Guild Role and returns it.
 // guildID : The ID of a Guild.
 // data    : New Role parameters.
-func (s *Session) GuildRoleCreate(guildID string, data *RoleParams) (st *Role, err error) {
-	body, err := s.RequestWithBucketID("POST", EndpointGuildRoles(guildID), data, EndpointGuildRoles(guildID))
+func (s *Session) GuildRoleCreate(guildID string, data *RoleParams, options ...RequestOption) (st *Role, err error) {
+	body, err := s.RequestWithBucketID("POST", EndpointGuildRoles(guildID), data, EndpointGuildRoles(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1228,13 +1427,13 @@ func (s *Session) GuildRoleCreate(guildID string, data *RoleParams) (st *Role, e
 // guildID   : The ID of a Guild.
 // roleID    : The ID of a Role.
 // data 		 : Updated Role data.
-func (s *Session) GuildRoleEdit(guildID, roleID string, data *RoleParams) (st *Role, err error) {
+func (s *Session) GuildRoleEdit(guildID, roleID string, data *RoleParams, options ...RequestOption) (st *Role, err error) {
 	// Prevent sending a color int that is too big.
 	if data.Color != nil && *data.Color > 0xFFFFFF {
 		return nil, fmt.Errorf("color value cannot be larger than 0xFFFFFF")
 	}
 
-	body, err := s.RequestWithBucketID("PATCH", EndpointGuildRole(guildID, roleID), data, EndpointGuildRole(guildID, ""))
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildRole(guildID, roleID), data, EndpointGuildRole(guildID, ""), options...)
 	if err != nil {
 		return
 	}
@@ -1247,8 +1446,8 @@ func (s *Session) GuildRoleEdit(guildID, roleID string, data *RoleParams) (st *R
 // GuildRoleReorder reoders guild roles
 // guildID   : The ID of a Guild.
 // roles     : A list of ordered roles.
-func (s *Session) GuildRoleReorder(guildID string, roles []*Role) (st []*Role, err error) {
-	body, err := s.RequestWithBucketID("PATCH", EndpointGuildRoles(guildID), roles, EndpointGuildRoles(guildID))
+func (s *Session) GuildRoleReorder(guildID string, roles []*Role, options ...RequestOption) (st []*Role, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildRoles(guildID), roles, EndpointGuildRoles(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1261,8 +1460,8 @@ func (s *Session) GuildRoleReorder(guildID string, roles []*Role) (st []*Role, e
 // GuildRoleDelete deletes an existing role.
 // guildID   : The ID of a Guild.
 // roleID    : The ID of a Role.
-func (s *Session) GuildRoleDelete(guildID, roleID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointGuildRole(guildID, roleID), nil, EndpointGuildRole(guildID, ""))
+func (s *Session) GuildRoleDelete(guildID, roleID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildRole(guildID, roleID), nil, EndpointGuildRole(guildID, ""), options...)
 
 	return
 }
@@ -1271,7 +1470,7 @@ func (s *Session) GuildRoleDelete(guildID, roleID string) (err error) {
 // Requires 'KICK_MEMBER' permission.
 // guildID	: The ID of a Guild.
 // days		: The number of days to count prune for (1 or more).
-func (s *Session) GuildPruneCount(guildID string, days uint32) (count uint32, err error) {
+func (s *Session) GuildPruneCount(guildID string, days uint32, options ...RequestOption) (count uint32, err error) {
 	count = 0
 
 	if days <= 0 {
@@ -1284,7 +1483,7 @@ func (s *Session) GuildPruneCount(guildID string, days uint32) (count uint32, er
 	}{}
 
 	uri := EndpointGuildPrune(guildID) + "?days=" + strconv.FormatUint(uint64(days), 10)
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildPrune(guildID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildPrune(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1303,7 +1502,7 @@ func (s *Session) GuildPruneCount(guildID string, days uint32) (count uint32, er
 // Returns an object with one 'pruned' key indicating the number of members that were removed in the prune operation.
 // guildID	: The ID of a Guild.
 // days		: The number of days to count prune for (1 or more).
-func (s *Session) GuildPrune(guildID string, days uint32) (count uint32, err error) {
+func (s *Session) GuildPrune(guildID string, days uint32, options ...RequestOption) (count uint32, err error) {
 	count = 0
 
 	if days <= 0 {
@@ -1319,7 +1518,7 @@ func (s *Session) GuildPrune(guildID string, days uint32) (count uint32, err err
 		Pruned uint32 `json:"pruned"`
 	}{}
 
-	body, err := s.RequestWithBucketID("POST", EndpointGuildPrune(guildID), data, EndpointGuildPrune(guildID))
+	body, err := s.RequestWithBucketID("POST", EndpointGuildPrune(guildID), data, EndpointGuildPrune(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1336,8 +1535,8 @@ func (s *Session) GuildPrune(guildID string, days uint32) (count uint32, err err
 
 // GuildIntegrations returns an array of Integrations for a guild.
 // guildID   : The ID of a Guild.
-func (s *Session) GuildIntegrations(guildID string) (st []*Integration, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildIntegrations(guildID), nil, EndpointGuildIntegrations(guildID))
+func (s *Session) GuildIntegrations(guildID string, options ...RequestOption) (st []*Integration, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildIntegrations(guildID), nil, EndpointGuildIntegrations(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1351,13 +1550,13 @@ func (s *Session) GuildIntegrations(guildID string) (st []*Integration, err erro
 // guildID          : The ID of a Guild.
 // integrationType  : The Integration type.
 // integrationID    : The ID of an integration.
-func (s *Session) GuildIntegrationCreate(guildID, integrationType, integrationID string) (err error) {
+func (s *Session) GuildIntegrationCreate(guildID, integrationType, integrationID string, options ...RequestOption) (err error) {
 	data := struct {
 		Type string `json:"type"`
 		ID   string `json:"id"`
 	}{integrationType, integrationID}
 
-	_, err = s.RequestWithBucketID("POST", EndpointGuildIntegrations(guildID), data, EndpointGuildIntegrations(guildID))
+	_, err = s.RequestWithBucketID("POST", EndpointGuildIntegrations(guildID), data, EndpointGuildIntegrations(guildID), options...)
 	return
 }
 
@@ -1368,29 +1567,29 @@ func (s *Session) GuildIntegrationCreate(guildID, integrationType, integrationID
 // expireBehavior	      : The behavior when an integration subscription lapses (see the integration object documentation).
 // expireGracePeriod    : Period (in seconds) where the integration will ignore lapsed subscriptions.
 // enableEmoticons	    : Whether emoticons should be synced for this integration (twitch only currently).
-func (s *Session) GuildIntegrationEdit(guildID, integrationID string, expireBehavior, expireGracePeriod int, enableEmoticons bool) (err error) {
+func (s *Session) GuildIntegrationEdit(guildID, integrationID string, expireBehavior, expireGracePeriod int, enableEmoticons bool, options ...RequestOption) (err error) {
 	data := struct {
 		ExpireBehavior    int  `json:"expire_behavior"`
 		ExpireGracePeriod int  `json:"expire_grace_period"`
 		EnableEmoticons   bool `json:"enable_emoticons"`
 	}{expireBehavior, expireGracePeriod, enableEmoticons}
 
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildIntegration(guildID, integrationID), data, EndpointGuildIntegration(guildID, ""))
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildIntegration(guildID, integrationID), data, EndpointGuildIntegration(guildID, ""), options...)
 	return
 }
 
 // GuildIntegrationDelete removes the given integration from the Guild.
 // guildID          : The ID of a Guild.
 // integrationID    : The ID of an integration.
-func (s *Session) GuildIntegrationDelete(guildID, integrationID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointGuildIntegration(guildID, integrationID), nil, EndpointGuildIntegration(guildID, ""))
+func (s *Session) GuildIntegrationDelete(guildID, integrationID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildIntegration(guildID, integrationID), nil, EndpointGuildIntegration(guildID, ""), options...)
 	return
 }
 
 // GuildIcon returns an image.Image of a guild icon.
 // guildID   : The ID of a Guild.
-func (s *Session) GuildIcon(guildID string) (img image.Image, err error) {
-	g, err := s.Guild(guildID)
+func (s *Session) GuildIcon(guildID string, options ...RequestOption) (img image.Image, err error) {
+	g, err := s.Guild(guildID, options...)
 	if err != nil {
 		return
 	}
@@ -1400,7 +1599,7 @@ func (s *Session) GuildIcon(guildID string) (img image.Image, err error) {
 		return
 	}
 
-	body, err := s.RequestWithBucketID("GET", EndpointGuildIcon(guildID, g.Icon), nil, EndpointGuildIcon(guildID, ""))
+	body, err := s.RequestWithBucketID("GET", EndpointGuildIcon(guildID, g.Icon), nil, EndpointGuildIcon(guildID, ""), options...)
 	if err != nil {
 		return
 	}
@@ -1411,8 +1610,8 @@ func (s *Session) GuildIcon(guildID string) (img image.Image, err error) {
 
 // GuildSplash returns an image.Image of a guild splash image.
 // guildID   : The ID of a Guild.
-func (s *Session) GuildSplash(guildID string) (img image.Image, err error) {
-	g, err := s.Guild(guildID)
+func (s *Session) GuildSplash(guildID string, options ...RequestOption) (img image.Image, err error) {
+	g, err := s.Guild(guildID, options...)
 	if err != nil {
 		return
 	}
@@ -1422,7 +1621,7 @@ func (s *Session) GuildSplash(guildID string) (img image.Image, err error) {
 		return
 	}
 
-	body, err := s.RequestWithBucketID("GET", EndpointGuildSplash(guildID, g.Splash), nil, EndpointGuildSplash(guildID, ""))
+	body, err := s.RequestWithBucketID("GET", EndpointGuildSplash(guildID, g.Splash), nil, EndpointGuildSplash(guildID, ""), options...)
 	if err != nil {
 		return
 	}
@@ -1433,8 +1632,8 @@ func (s *Session) GuildSplash(guildID string) (img image.Image, err error) {
 
 // GuildEmbed returns the embed for a Guild.
 // guildID   : The ID of a Guild.
-func (s *Session) GuildEmbed(guildID string) (st *GuildEmbed, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildEmbed(guildID), nil, EndpointGuildEmbed(guildID))
+func (s *Session) GuildEmbed(guildID string, options ...RequestOption) (st *GuildEmbed, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildEmbed(guildID), nil, EndpointGuildEmbed(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1446,8 +1645,8 @@ func (s *Session) GuildEmbed(guildID string) (st *GuildEmbed, err error) {
 // GuildEmbedEdit edits the embed of a Guild.
 // guildID   : The ID of a Guild.
 // data      : New GuildEmbed data.
-func (s *Session) GuildEmbedEdit(guildID string, data *GuildEmbed) (err error) {
-	_, err = s.RequestWithBucketID("PATCH", EndpointGuildEmbed(guildID), data, EndpointGuildEmbed(guildID))
+func (s *Session) GuildEmbedEdit(guildID string, data *GuildEmbed, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("PATCH", EndpointGuildEmbed(guildID), data, EndpointGuildEmbed(guildID), options...)
 	return
 }
 
@@ -1457,7 +1656,7 @@ func (s *Session) GuildEmbedEdit(guildID string, data *GuildEmbed) (err error) {
 // beforeID    : If provided all log entries returned will be before the given ID.
 // actionType  : If provided the log will be filtered for the given Action Type.
 // limit       : The number messages that can be returned. (default 50, min 1, max 100)
-func (s *Session) GuildAuditLog(guildID, userID, beforeID string, actionType, limit int) (st *GuildAuditLog, err error) {
+func (s *Session) GuildAuditLog(guildID, userID, beforeID string, actionType, limit int, options ...RequestOption) (st *GuildAuditLog, err error) {
 	uri := EndpointGuildAuditLogs(guildID)
 
 	v := url.Values{}
@@ -1477,7 +1676,7 @@ func (s *Session) GuildAuditLog(guildID, userID, beforeID string, actionType, li
 		uri = fmt.Sprintf("%s?%s", uri, v.Encode())
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildAuditLogs(guildID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildAuditLogs(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1488,8 +1687,8 @@ func (s *Session) GuildAuditLog(guildID, userID, beforeID string, actionType, li
 
 // GuildEmojis returns all emoji
 // guildID : The ID of a Guild.
-func (s *Session) GuildEmojis(guildID string) (emoji []*Emoji, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildEmojis(guildID), nil, EndpointGuildEmojis(guildID))
+func (s *Session) GuildEmojis(guildID string, options ...RequestOption) (emoji []*Emoji, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildEmojis(guildID), nil, EndpointGuildEmojis(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1501,9 +1700,9 @@ func (s *Session) GuildEmojis(guildID string) (emoji []*Emoji, err error) {
 // GuildEmoji returns specified emoji.
 // guildID : The ID of a Guild
 // emojiID : The ID of an Emoji to retrieve
-func (s *Session) GuildEmoji(guildID, emojiID string) (emoji *Emoji, err error) {
+func (s *Session) GuildEmoji(guildID, emojiID string, options ...RequestOption) (emoji *Emoji, err error) {
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", EndpointGuildEmoji(guildID, emojiID), nil, EndpointGuildEmoji(guildID, emojiID))
+	body, err = s.RequestWithBucketID("GET", EndpointGuildEmoji(guildID, emojiID), nil, EndpointGuildEmoji(guildID, emojiID), options...)
 	if err != nil {
 		return
 	}
@@ -1515,8 +1714,8 @@ func (s *Session) GuildEmoji(guildID, emojiID string) (emoji *Emoji, err error)
 // GuildEmojiCreate creates a new Emoji.
 // guildID : The ID of a Guild.
 // data    : New Emoji data.
-func (s *Session) GuildEmojiCreate(guildID string, data *EmojiParams) (emoji *Emoji, err error) {
-	body, err := s.RequestWithBucketID("POST", EndpointGuildEmojis(guildID), data, EndpointGuildEmojis(guildID))
+func (s *Session) GuildEmojiCreate(guildID string, data *EmojiParams, options ...RequestOption) (emoji *Emoji, err error) {
+	body, err := s.RequestWithBucketID("POST", EndpointGuildEmojis(guildID), data, EndpointGuildEmojis(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1529,8 +1728,8 @@ func (s *Session) GuildEmojiCreate(guildID string, data *EmojiParams) (emoji *Em
 // guildID : The ID of a Guild.
 // emojiID : The ID of an Emoji.
 // data    : Updated Emoji data.
-func (s *Session) GuildEmojiEdit(guildID, emojiID string, data *EmojiParams) (emoji *Emoji, err error) {
-	body, err := s.RequestWithBucketID("PATCH", EndpointGuildEmoji(guildID, emojiID), data, EndpointGuildEmojis(guildID))
+func (s *Session) GuildEmojiEdit(guildID, emojiID string, data *EmojiParams, options ...RequestOption) (emoji *Emoji, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildEmoji(guildID, emojiID), data, EndpointGuildEmojis(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1542,15 +1741,15 @@ func (s *Session) GuildEmojiEdit(guildID, emojiID string, data *EmojiParams) (em
 // GuildEmojiDelete deletes an Emoji.
 // guildID : The ID of a Guild.
 // emojiID : The ID of an Emoji.
-func (s *Session) GuildEmojiDelete(guildID, emojiID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointGuildEmoji(guildID, emojiID), nil, EndpointGuildEmojis(guildID))
+func (s *Session) GuildEmojiDelete(guildID, emojiID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildEmoji(guildID, emojiID), nil, EndpointGuildEmojis(guildID), options...)
 	return
 }
 
 // GuildTemplate returns a GuildTemplate for the given code
 // templateCode: The Code of a GuildTemplate
-func (s *Session) GuildTemplate(templateCode string) (st *GuildTemplate, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildTemplate(templateCode), nil, EndpointGuildTemplate(templateCode))
+func (s *Session) GuildTemplate(templateCode string, options ...RequestOption) (st *GuildTemplate, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildTemplate(templateCode), nil, EndpointGuildTemplate(templateCode), options...)
 	if err != nil {
 		return
 	}
@@ -1563,13 +1762,13 @@ func (s *Session) GuildTemplate(templateCode string) (st *GuildTemplate, err err
 // templateCode: The Code of a GuildTemplate
 // name: The name of the guild (2-100) characters
 // icon: base64 encoded 128x128 image for the guild icon
-func (s *Session) GuildCreateWithTemplate(templateCode, name, icon string) (st *Guild, err error) {
+func (s *Session) GuildCreateWithTemplate(templateCode, name, icon string, options ...RequestOption) (st *Guild, err error) {
 	data := struct {
 		Name string `json:"name"`
 		Icon string `json:"icon"`
 	}{name, icon}
 
-	body, err := s.RequestWithBucketID("POST", EndpointGuildTemplate(templateCode), data, EndpointGuildTemplate(templateCode))
+	body, err := s.RequestWithBucketID("POST", EndpointGuildTemplate(templateCode), data, EndpointGuildTemplate(templateCode), options...)
 	if err != nil {
 		return
 	}
@@ -1580,8 +1779,8 @@ func (s *Session) GuildCreateWithTemplate(templateCode, name, icon string) (st *
 
 // GuildTemplates returns all of GuildTemplates
 // guildID: The ID of the guild
-func (s *Session) GuildTemplates(guildID string) (st []*GuildTemplate, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildTemplates(guildID), nil, EndpointGuildTemplates(guildID))
+func (s *Session) GuildTemplates(guildID string, options ...RequestOption) (st []*GuildTemplate, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildTemplates(guildID), nil, EndpointGuildTemplates(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1593,8 +1792,8 @@ func (s *Session) GuildTemplates(guildID string) (st []*GuildTemplate, err error
 // GuildTemplateCreate creates a template for the guild
 // guildID : The ID of the guild
 // data    : Template metadata
-func (s *Session) GuildTemplateCreate(guildID string, data *GuildTemplateParams) (st *GuildTemplate) {
-	body, err := s.RequestWithBucketID("POST", EndpointGuildTemplates(guildID), data, EndpointGuildTemplates(guildID))
+func (s *Session) GuildTemplateCreate(guildID string, data *GuildTemplateParams, options ...RequestOption) (st *GuildTemplate) {
+	body, err := s.RequestWithBucketID("POST", EndpointGuildTemplates(guildID), data, EndpointGuildTemplates(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -1606,8 +1805,8 @@ func (s *Session) GuildTemplateCreate(guildID string, data *GuildTemplateParams)
 // GuildTemplateSync syncs the template to the guild's current state
 // guildID: The ID of the guild
 // templateCode: The code of the template
-func (s *Session) GuildTemplateSync(guildID, templateCode string) (err error) {
-	_, err = s.RequestWithBucketID("PUT", EndpointGuildTemplateSync(guildID, templateCode), nil, EndpointGuildTemplateSync(guildID, ""))
+func (s *Session) GuildTemplateSync(guildID, templateCode string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("PUT", EndpointGuildTemplateSync(guildID, templateCode), nil, EndpointGuildTemplateSync(guildID, ""), options...)
 	return
 }
 
@@ -1615,8 +1814,8 @@ func (s *Session) GuildTemplateSync(guildID, templateCode string) (err error) {
 // guildID      : The ID of the guild
 // templateCode : The code of the template
 // data         : New template metadata
-func (s *Session) GuildTemplateEdit(guildID, templateCode string, data *GuildTemplateParams) (st *GuildTemplate, err error) {
-	body, err := s.RequestWithBucketID("PATCH", EndpointGuildTemplateSync(guildID, templateCode), data, EndpointGuildTemplateSync(guildID, ""))
+func (s *Session) GuildTemplateEdit(guildID, templateCode string, data *GuildTemplateParams, options ...RequestOption) (st *GuildTemplate, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildTemplateSync(guildID, templateCode), data, EndpointGuildTemplateSync(guildID, ""), options...)
 	if err != nil {
 		return
 	}
@@ -1628,21 +1827,21 @@ func (s *Session) GuildTemplateEdit(guildID, templateCode string, data *GuildTem
 // GuildTemplateDelete deletes the template
 // guildID: The ID of the guild
 // templateCode: The code of the template
-func (s *Session) GuildTemplateDelete(guildID, templateCode string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointGuildTemplateSync(guildID, templateCode), nil, EndpointGuildTemplateSync(guildID, ""))
+func (s *Session) GuildTemplateDelete(guildID, templateCode string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildTemplateSync(guildID, templateCode), nil, EndpointGuildTemplateSync(guildID, ""), options...)
 	return
 }
 
 // GuildAck acknowledges a guild
-func (s *Session) GuildAck(guildID string) (err error) {
-	_, err = s.RequestWithBucketID("POST", EndpointGuildAck(guildID), struct{}{}, EndpointGuildAck(guildID))
+func (s *Session) GuildAck(guildID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("POST", EndpointGuildAck(guildID), struct{}{}, EndpointGuildAck(guildID), options...)
 	return
 }
 
 // GuildApplicationCommandIndex gets the application command index of a guild
-func (s *Session) GuildApplicationCommandIndex(guildID string) ([]*ApplicationCommand, []*ApplicationCommandApplication, error) {
+func (s *Session) GuildApplicationCommandIndex(guildID string, options ...RequestOption) ([]*ApplicationCommand, []*ApplicationCommandApplication, error) {
 	var data []byte
-	data, err := s.RequestWithBucketID("GET", EndpointGuildApplicationCommandIndex(guildID), nil, EndpointGuildApplicationCommandIndex(""))
+	data, err := s.RequestWithBucketID("GET", EndpointGuildApplicationCommandIndex(guildID), nil, EndpointGuildApplicationCommandIndex(""), options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1666,8 +1865,8 @@ func (s *Session) GuildApplicationCommandIndex(guildID string) ([]*ApplicationCo
 
 // Channel returns a Channel structure of a specific Channel.
 // channelID  : The ID of the Channel you want returned.
-func (s *Session) Channel(channelID string) (st *Channel, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointChannel(channelID), nil, EndpointChannel(channelID))
+func (s *Session) Channel(channelID string, options ...RequestOption) (st *Channel, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointChannel(channelID), nil, EndpointChannel(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -1679,20 +1878,20 @@ func (s *Session) Channel(channelID string) (st *Channel, err error) {
 // ChannelWithFallback returns a Channel from state if available, otherwise
 // it will make a request to the API to get the channel.
 // channelID  : The ID of the Channel you want returned.
-func (s *Session) ChannelWithFallback(channelID string) (*Channel, error) {
+func (s *Session) ChannelWithFallback(channelID string, options ...RequestOption) (*Channel, error) {
 	channel, err := s.State.Channel(channelID)
 	if err == nil {
 		return channel, nil
 	}
 
-	return s.Channel(channelID)
+	return s.Channel(channelID, options...)
 }
 
 // ChannelEdit edits the given channel and returns the updated Channel data.
 // channelID  : The ID of a Channel.
 // data       : New Channel data.
-func (s *Session) ChannelEdit(channelID string, data *ChannelEdit) (st *Channel, err error) {
-	body, err := s.RequestWithBucketID("PATCH", EndpointChannel(channelID), data, EndpointChannel(channelID))
+func (s *Session) ChannelEdit(channelID string, data *ChannelEdit, options ...RequestOption) (st *Channel, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointChannel(channelID), data, EndpointChannel(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -1705,23 +1904,28 @@ func (s *Session) ChannelEdit(channelID string, data *ChannelEdit) (st *Channel,
 // NOTE: deprecated, use ChannelEdit instead
 // channelID     : The ID of a Channel
 // data          : The channel struct to send
-func (s *Session) ChannelEditComplex(channelID string, data *ChannelEdit) (st *Channel, err error) {
-	return s.ChannelEdit(channelID, data)
+func (s *Session) ChannelEditComplex(channelID string, data *ChannelEdit, options ...RequestOption) (st *Channel, err error) {
+	return s.ChannelEdit(channelID, data, options...)
 }
 
 // ChannelDelete deletes the given channel
 // channelID  : The ID of a Channel
+func (s *Session) ChannelDelete(channelID string, options ...RequestOption) (st *Channel, err error) {
+	return s.channelDelete(EndpointChannel(channelID), channelID, options...)
+}
+
+// ChannelDeleteSilent deletes the given channel, optionally doing so silently
+// channelID  : The ID of a Channel
 // silent     : If deleting the channel should be done silently (for group DMs)
-func (s *Session) ChannelDelete(channelID string, silent ...bool) (st *Channel, err error) {
-	uri := EndpointChannel(channelID)
+func (s *Session) ChannelDeleteSilent(channelID string, silent bool, options ...RequestOption) (st *Channel, err error) {
+	v := url.Values{}
+	v.Set("silent", strconv.FormatBool(silent))
 
-	if len(silent) > 0 {
-		v := url.Values{}
-		v.Set("silent", strconv.FormatBool(silent[0]))
-		uri += "?" + v.Encode()
-	}
+	return s.channelDelete(EndpointChannel(channelID)+"?"+v.Encode(), channelID, options...)
+}
 
-	body, err := s.RequestWithBucketID("DELETE", uri, nil, EndpointChannel(channelID))
+func (s *Session) channelDelete(uri, channelID string, options ...RequestOption) (st *Channel, err error) {
+	body, err := s.RequestWithBucketID("DELETE", uri, nil, EndpointChannel(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -1733,8 +1937,8 @@ func (s *Session) ChannelDelete(channelID string, silent ...bool) (st *Channel,
 // ChannelTyping broadcasts to all members that authenticated user is typing in
 // the given channel.
 // channelID  : The ID of a Channel
-func (s *Session) ChannelTyping(channelID string) (err error) {
-	_, err = s.RequestWithBucketID("POST", EndpointChannelTyping(channelID), nil, EndpointChannelTyping(channelID))
+func (s *Session) ChannelTyping(channelID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("POST", EndpointChannelTyping(channelID), nil, EndpointChannelTyping(channelID), options...)
 	return
 }
 
@@ -1742,12 +1946,12 @@ func (s *Session) ChannelTyping(channelID string) (err error) {
 // the given channel for a specific duration.
 // channelID  : The ID of a Channel
 // duration   : The duration to broadcast typing for
-func (s *Session) ChannelTypingDuration(channelID string, duration time.Duration) (err error) {
+func (s *Session) ChannelTypingDuration(channelID string, duration time.Duration, options ...RequestOption) (err error) {
 	const interval = 5 * time.Second
 
 	iterations := int(duration/interval) + 1
 	for range iterations {
-		if err := s.ChannelTyping(channelID); err != nil {
+		if err := s.ChannelTyping(channelID, options...); err != nil {
 			return err
 		}
 
@@ -1764,7 +1968,7 @@ func (s *Session) ChannelTypingDuration(channelID string, duration time.Duration
 // beforeID  : If provided all messages returned will be before given ID.
 // afterID   : If provided all messages returned will be after given ID.
 // aroundID  : If provided all messages returned will be around given ID.
-func (s *Session) ChannelMessages(channelID string, limit int, beforeID, afterID, aroundID string) (st []*Message, err error) {
+func (s *Session) ChannelMessages(channelID string, limit int, beforeID, afterID, aroundID string, options ...RequestOption) (st []*Message, err error) {
 	uri := EndpointChannelMessages(channelID)
 
 	v := url.Values{}
@@ -1784,7 +1988,7 @@ func (s *Session) ChannelMessages(channelID string, limit int, beforeID, afterID
 		uri += "?" + v.Encode()
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointChannelMessages(channelID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointChannelMessages(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -1796,8 +2000,8 @@ func (s *Session) ChannelMessages(channelID string, limit int, beforeID, afterID
 // ChannelMessage gets a single message by ID from a given channel.
 // channeld  : The ID of a Channel
 // messageID : the ID of a Message
-func (s *Session) ChannelMessage(channelID, messageID string) (st *Message, err error) {
-	response, err := s.RequestWithBucketID("GET", EndpointChannelMessage(channelID, messageID), nil, EndpointChannelMessage(channelID, ""))
+func (s *Session) ChannelMessage(channelID, messageID string, options ...RequestOption) (st *Message, err error) {
+	response, err := s.RequestWithBucketID("GET", EndpointChannelMessage(channelID, messageID), nil, EndpointChannelMessage(channelID, ""), options...)
 	if err != nil {
 		return
 	}
@@ -1809,10 +2013,10 @@ func (s *Session) ChannelMessage(channelID, messageID string) (st *Message, err
 // ChannelMessageSend sends a message to the given channel.
 // channelID : The ID of a Channel.
 // content   : The message to send.
-func (s *Session) ChannelMessageSend(channelID string, content string) (*Message, error) {
+func (s *Session) ChannelMessageSend(channelID string, content string, options ...RequestOption) (*Message, error) {
 	return s.ChannelMessageSendComplex(channelID, &MessageSend{
 		Content: content,
-	})
+	}, options...)
 }
 
 var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
@@ -1820,7 +2024,7 @@ var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 // ChannelMessageSendComplex sends a message to the given channel.
 // channelID : The ID of a Channel.
 // data      : The message struct to send.
-func (s *Session) ChannelMessageSendComplex(channelID string, data *MessageSend) (st *Message, err error) {
+func (s *Session) ChannelMessageSendComplex(channelID string, data *MessageSend, options ...RequestOption) (st *Message, err error) {
 	// TODO: Remove this when compatibility is not required.
 	if data.Embed != nil {
 		if data.Embeds == nil {
@@ -1838,6 +2042,26 @@ func (s *Session) ChannelMessageSendComplex(channelID string, data *MessageSend)
 	}
 	endpoint := EndpointChannelMessages(channelID)
 
+	// The nonce is generated on a copy, so data is not modified and can be
+	// sent again, or concurrently, with another nonce.
+	if (data.EnforceNonce || data.Confirm) && data.Nonce == "" {
+		sent := *data
+		sent.Nonce = GenerateNonce()
+		data = &sent
+	}
+
+	// The MESSAGE_CREATE event may be received before the response, so it is
+	// waited for before sending.
+	var confirmed *messageConfirmation
+	if data.Confirm {
+		if s.Status() != ConnectionStateReady {
+			err = ErrWSNotFound
+			return
+		}
+		confirmed = s.awaitMessage(channelID, data.Nonce)
+		defer confirmed.cancel()
+	}
+
 	// TODO: Remove this when compatibility is not required.
 	files := data.Files
 	if data.File != nil {
@@ -1856,78 +2080,82 @@ func (s *Session) ChannelMessageSendComplex(channelID string, data *MessageSend)
 			return st, encodeErr
 		}
 
-		response, err = s.request("POST", endpoint, contentType, body, endpoint, 0)
+		response, err = s.request("POST", endpoint, contentType, body, endpoint, 0, options...)
 	} else {
-		response, err = s.RequestWithBucketID("POST", endpoint, data, endpoint)
+		response, err = s.RequestWithBucketID("POST", endpoint, data, endpoint, options...)
 	}
 	if err != nil {
 		return
 	}
 
 	err = unmarshal(response, &st)
-	return
+	if err != nil || confirmed == nil {
+		return
+	}
+
+	return confirmed.wait(newRequestConfig(s, options...).Context, st)
 }
 
 // ChannelMessageSendTTS sends a message to the given channel with Text to Speech.
 // channelID : The ID of a Channel.
 // content   : The message to send.
-func (s *Session) ChannelMessageSendTTS(channelID string, content string) (*Message, error) {
+func (s *Session) ChannelMessageSendTTS(channelID string, content string, options ...RequestOption) (*Message, error) {
 	return s.ChannelMessageSendComplex(channelID, &MessageSend{
 		Content: content,
 		TTS:     true,
-	})
+	}, options...)
 }
 
 // ChannelMessageSendEmbed sends a message to the given channel with embedded data.
 // channelID : The ID of a Channel.
 // embed     : The embed data to send.
-func (s *Session) ChannelMessageSendEmbed(channelID string, embed *MessageEmbed) (*Message, error) {
-	return s.ChannelMessageSendEmbeds(channelID, []*MessageEmbed{embed})
+func (s *Session) ChannelMessageSendEmbed(channelID string, embed *MessageEmbed, options ...RequestOption) (*Message, error) {
+	return s.ChannelMessageSendEmbeds(channelID, []*MessageEmbed{embed}, options...)
 }
 
 // ChannelMessageSendEmbeds sends a message to the given channel with multiple embedded data.
 // channelID : The ID of a Channel.
 // embeds    : The embeds data to send.
-func (s *Session) ChannelMessageSendEmbeds(channelID string, embeds []*MessageEmbed) (*Message, error) {
+func (s *Session) ChannelMessageSendEmbeds(channelID string, embeds []*MessageEmbed, options ...RequestOption) (*Message, error) {
 	return s.ChannelMessageSendComplex(channelID, &MessageSend{
 		Embeds: embeds,
-	})
+	}, options...)
 }
 
 // ChannelMessageSendReply sends a message to the given channel with reference data.
 // channelID : The ID of a Channel.
 // content   : The message to send.
 // reference : The message reference to send.
-func (s *Session) ChannelMessageSendReply(channelID string, content string, reference *MessageReference) (*Message, error) {
+func (s *Session) ChannelMessageSendReply(channelID string, content string, reference *MessageReference, options ...RequestOption) (*Message, error) {
 	if reference == nil {
 		return nil, fmt.Errorf("reply attempted with nil message reference")
 	}
 	return s.ChannelMessageSendComplex(channelID, &MessageSend{
 		Content:   content,
 		Reference: reference,
-	})
+	}, options...)
 }
 
 // ChannelMessageSendEmbedReply sends a message to the given channel with reference data and embedded data.
 // channelID : The ID of a Channel.
 // embed   : The embed data to send.
 // reference : The message reference to send.
-func (s *Session) ChannelMessageSendEmbedReply(channelID string, embed *MessageEmbed, reference *MessageReference) (*Message, error) {
-	return s.ChannelMessageSendEmbedsReply(channelID, []*MessageEmbed{embed}, reference)
+func (s *Session) ChannelMessageSendEmbedReply(channelID string, embed *MessageEmbed, reference *MessageReference, options ...RequestOption) (*Message, error) {
+	return s.ChannelMessageSendEmbedsReply(channelID, []*MessageEmbed{embed}, reference, options...)
 }
 
 // ChannelMessageSendEmbedsReply sends a message to the given channel with reference data and multiple embedded data.
 // channelID : The ID of a Channel.
 // embeds    : The embeds data to send.
 // reference : The message reference to send.
-func (s *Session) ChannelMessageSendEmbedsReply(channelID string, embeds []*MessageEmbed, reference *MessageReference) (*Message, error) {
+func (s *Session) ChannelMessageSendEmbedsReply(channelID string, embeds []*MessageEmbed, reference *MessageReference, options ...RequestOption) (*Message, error) {
 	if reference == nil {
 		return nil, fmt.Errorf("reply attempted with nil message reference")
 	}
 	return s.ChannelMessageSendComplex(channelID, &MessageSend{
 		Embeds:    embeds,
 		Reference: reference,
-	})
+	}, options...)
 }
 
 // ChannelMessageEdit edits an existing message, replacing it entirely with
@@ -1935,13 +2163,13 @@ func (s *Session) ChannelMessageSendEmbedsReply(channelID string, embeds []*Mess
 // channelID  : The ID of a Channel
 // messageID  : The ID of a Message
 // content    : The contents of the message
-func (s *Session) ChannelMessageEdit(channelID, messageID, content string) (*Message, error) {
-	return s.ChannelMessageEditComplex(NewMessageEdit(channelID, messageID).SetContent(content))
+func (s *Session) ChannelMessageEdit(channelID, messageID, content string, options ...RequestOption) (*Message, error) {
+	return s.ChannelMessageEditComplex(NewMessageEdit(channelID, messageID).SetContent(content), options...)
 }
 
 // ChannelMessageEditComplex edits an existing message, replacing it entirely with
 // the given MessageEdit struct
-func (s *Session) ChannelMessageEditComplex(m *MessageEdit) (st *Message, err error) {
+func (s *Session) ChannelMessageEditComplex(m *MessageEdit, options ...RequestOption) (st *Message, err error) {
 	// TODO: Remove this when compatibility is not required.
 	if m.Embed != nil {
 		if m.Embeds == nil {
@@ -1966,9 +2194,9 @@ func (s *Session) ChannelMessageEditComplex(m *MessageEdit) (st *Message, err er
 		if encodeErr != nil {
 			return st, encodeErr
 		}
-		response, err = s.request("PATCH", endpoint, contentType, body, EndpointChannelMessage(m.Channel, ""), 0)
+		response, err = s.request("PATCH", endpoint, contentType, body, EndpointChannelMessage(m.Channel, ""), 0, options...)
 	} else {
-		response, err = s.RequestWithBucketID("PATCH", endpoint, m, EndpointChannelMessage(m.Channel, ""))
+		response, err = s.RequestWithBucketID("PATCH", endpoint, m, EndpointChannelMessage(m.Channel, ""), options...)
 	}
 	if err != nil {
 		return
@@ -1982,21 +2210,21 @@ func (s *Session) ChannelMessageEditComplex(m *MessageEdit) (st *Message, err er
 // channelID : The ID of a Channel
 // messageID : The ID of a Message
 // embed     : The embed data to send
-func (s *Session) ChannelMessageEditEmbed(channelID, messageID string, embed *MessageEmbed) (*Message, error) {
-	return s.ChannelMessageEditEmbeds(channelID, messageID, []*MessageEmbed{embed})
+func (s *Session) ChannelMessageEditEmbed(channelID, messageID string, embed *MessageEmbed, options ...RequestOption) (*Message, error) {
+	return s.ChannelMessageEditEmbeds(channelID, messageID, []*MessageEmbed{embed}, options...)
 }
 
 // ChannelMessageEditEmbeds edits an existing message with multiple embedded data.
 // channelID : The ID of a Channel
 // messageID : The ID of a Message
 // embeds    : The embeds data to send
-func (s *Session) ChannelMessageEditEmbeds(channelID, messageID string, embeds []*MessageEmbed) (*Message, error) {
-	return s.ChannelMessageEditComplex(NewMessageEdit(channelID, messageID).SetEmbeds(embeds))
+func (s *Session) ChannelMessageEditEmbeds(channelID, messageID string, embeds []*MessageEmbed, options ...RequestOption) (*Message, error) {
+	return s.ChannelMessageEditComplex(NewMessageEdit(channelID, messageID).SetEmbeds(embeds), options...)
 }
 
 // ChannelMessageDelete deletes a message from the Channel.
-func (s *Session) ChannelMessageDelete(channelID, messageID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointChannelMessage(channelID, messageID), nil, EndpointChannelMessage(channelID, ""))
+func (s *Session) ChannelMessageDelete(channelID, messageID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointChannelMessage(channelID, messageID), nil, EndpointChannelMessage(channelID, ""), options...)
 	return
 }
 
@@ -2005,13 +2233,13 @@ func (s *Session) ChannelMessageDelete(channelID, messageID string) (err error)
 // If the slice is empty do nothing.
 // channelID : The ID of the channel for the messages to delete.
 // messages  : The IDs of the messages to be deleted. A slice of string IDs. A maximum of 100 messages.
-func (s *Session) ChannelMessagesBulkDelete(channelID string, messages []string) (err error) {
+func (s *Session) ChannelMessagesBulkDelete(channelID string, messages []string, options ...RequestOption) (err error) {
 	if len(messages) == 0 {
 		return
 	}
 
 	if len(messages) == 1 {
-		err = s.ChannelMessageDelete(channelID, messages[0])
+		err = s.ChannelMessageDelete(channelID, messages[0], options...)
 		return
 	}
 
@@ -2023,31 +2251,31 @@ func (s *Session) ChannelMessagesBulkDelete(channelID string, messages []string)
 		Messages []string `json:"messages"`
 	}{messages}
 
-	_, err = s.RequestWithBucketID("POST", EndpointChannelMessagesBulkDelete(channelID), data, EndpointChannelMessagesBulkDelete(channelID))
+	_, err = s.RequestWithBucketID("POST", EndpointChannelMessagesBulkDelete(channelID), data, EndpointChannelMessagesBulkDelete(channelID), options...)
 	return
 }
 
 // ChannelMessagePin pins a message within a given channel.
 // channelID: The ID of a channel.
 // messageID: The ID of a message.
-func (s *Session) ChannelMessagePin(channelID, messageID string) (err error) {
-	_, err = s.RequestWithBucketID("PUT", EndpointChannelMessagePin(channelID, messageID), nil, EndpointChannelMessagePin(channelID, ""))
+func (s *Session) ChannelMessagePin(channelID, messageID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("PUT", EndpointChannelMessagePin(channelID, messageID), nil, EndpointChannelMessagePin(channelID, ""), options...)
 	return
 }
 
 // ChannelMessageUnpin unpins a message within a given channel.
 // channelID: The ID of a channel.
 // messageID: The ID of a message.
-func (s *Session) ChannelMessageUnpin(channelID, messageID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointChannelMessagePin(channelID, messageID), nil, EndpointChannelMessagePin(channelID, ""))
+func (s *Session) ChannelMessageUnpin(channelID, messageID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointChannelMessagePin(channelID, messageID), nil, EndpointChannelMessagePin(channelID, ""), options...)
 	return
 }
 
 // ChannelMessagesPinned returns an array of Message structures for pinned messages
 // within a given channel
 // channelID : The ID of a Channel.
-func (s *Session) ChannelMessagesPinned(channelID string) (st []*Message, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointChannelMessagesPins(channelID), nil, EndpointChannelMessagesPins(channelID))
+func (s *Session) ChannelMessagesPinned(channelID string, options ...RequestOption) (st []*Message, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointChannelMessagesPins(channelID), nil, EndpointChannelMessagesPins(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -2060,8 +2288,8 @@ func (s *Session) ChannelMessagesPinned(channelID string) (st []*Message, err er
 // channelID : The ID of a Channel.
 // name: The name of the file.
 // io.Reader : A reader for the file contents.
-func (s *Session) ChannelFileSend(channelID, name string, r io.Reader) (*Message, error) {
-	return s.ChannelMessageSendComplex(channelID, &MessageSend{File: &File{Name: name, Reader: r}})
+func (s *Session) ChannelFileSend(channelID, name string, r io.Reader, options ...RequestOption) (*Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &MessageSend{File: &File{Name: name, Reader: r}}, options...)
 }
 
 // ChannelFileSendWithMessage sends a file to the given channel with an message.
@@ -2070,14 +2298,14 @@ func (s *Session) ChannelFileSend(channelID, name string, r io.Reader) (*Message
 // content: Optional Message content.
 // name: The name of the file.
 // io.Reader : A reader for the file contents.
-func (s *Session) ChannelFileSendWithMessage(channelID, content string, name string, r io.Reader) (*Message, error) {
-	return s.ChannelMessageSendComplex(channelID, &MessageSend{File: &File{Name: name, Reader: r}, Content: content})
+func (s *Session) ChannelFileSendWithMessage(channelID, content string, name string, r io.Reader, options ...RequestOption) (*Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &MessageSend{File: &File{Name: name, Reader: r}, Content: content}, options...)
 }
 
 // ChannelInvites returns an array of Invite structures for the given channel
 // channelID   : The ID of a Channel
-func (s *Session) ChannelInvites(channelID string) (st []*Invite, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointChannelInvites(channelID), nil, EndpointChannelInvites(channelID))
+func (s *Session) ChannelInvites(channelID string, options ...RequestOption) (st []*Invite, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointChannelInvites(channelID), nil, EndpointChannelInvites(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -2091,7 +2319,7 @@ func (s *Session) ChannelInvites(channelID string) (st []*Invite, err error) {
 // maxAge	   : Duration of invite in seconds before expiry, or 0 for never.
 // maxUses	   : Maximum number of uses or 0 for unlimited.
 // temp		   : Whether this invite only grants temporary membership.
-func (s *Session) ChannelInviteCreate(channelID string, maxAge, maxUses int, temp bool) (st *Invite, err error) {
+func (s *Session) ChannelInviteCreate(channelID string, maxAge, maxUses int, temp bool, options ...RequestOption) (st *Invite, err error) {
 	data := struct {
 		Flags      int  `json:"flags"`
 		MaxAge     int  `json:"max_age"`
@@ -2100,9 +2328,7 @@ func (s *Session) ChannelInviteCreate(channelID string, maxAge, maxUses int, tem
 		Temporary  bool `json:"temporary"`
 	}{0, maxAge, maxUses, nil, temp}
 
-	body, err := s.RequestWithBucketID("POST", EndpointChannelInvites(channelID), data, EndpointChannelInvites(channelID), map[string]string{
-		"x-context-properties": "eyJsb2NhdGlvbiI6Ikd1aWxkIEhlYWRlciJ9",
-	})
+	body, err := s.RequestWithBucketID("POST", EndpointChannelInvites(channelID), data, EndpointChannelInvites(channelID), append([]RequestOption{WithHeader("x-context-properties", "eyJsb2NhdGlvbiI6Ikd1aWxkIEhlYWRlciJ9")}, options...)...)
 	if err != nil {
 		return
 	}
@@ -2114,7 +2340,7 @@ func (s *Session) ChannelInviteCreate(channelID string, maxAge, maxUses int, tem
 // ChannelPermissionSet creates a Permission Override for the given channel.
 // NOTE: This func name may changed.  Using Set instead of Create because
 // you can both create a new override or update an override with this function.
-func (s *Session) ChannelPermissionSet(channelID, targetID string, targetType PermissionOverwriteType, allow, deny int64) (err error) {
+func (s *Session) ChannelPermissionSet(channelID, targetID string, targetType PermissionOverwriteType, allow, deny int64, options ...RequestOption) (err error) {
 	data := struct {
 		ID    string                  `json:"id"`
 		Type  PermissionOverwriteType `json:"type"`
@@ -2122,14 +2348,14 @@ func (s *Session) ChannelPermissionSet(channelID, targetID string, targetType Pe
 		Deny  int64                   `json:"deny,string"`
 	}{targetID, targetType, allow, deny}
 
-	_, err = s.RequestWithBucketID("PUT", EndpointChannelPermission(channelID, targetID), data, EndpointChannelPermission(channelID, ""))
+	_, err = s.RequestWithBucketID("PUT", EndpointChannelPermission(channelID, targetID), data, EndpointChannelPermission(channelID, ""), options...)
 	return
 }
 
 // ChannelPermissionDelete deletes a specific permission override for the given channel.
 // NOTE: Name of this func may change.
-func (s *Session) ChannelPermissionDelete(channelID, targetID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointChannelPermission(channelID, targetID), nil, EndpointChannelPermission(channelID, ""))
+func (s *Session) ChannelPermissionDelete(channelID, targetID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointChannelPermission(channelID, targetID), nil, EndpointChannelPermission(channelID, ""), options...)
 	return
 }
 
@@ -2137,10 +2363,10 @@ func (s *Session) ChannelPermissionDelete(channelID, targetID string) (err error
 // of the channel
 // channelID   : The ID of a Channel
 // messageID   : The ID of a Message
-func (s *Session) ChannelMessageCrosspost(channelID, messageID string) (st *Message, err error) {
+func (s *Session) ChannelMessageCrosspost(channelID, messageID string, options ...RequestOption) (st *Message, err error) {
 	endpoint := EndpointChannelMessageCrosspost(channelID, messageID)
 
-	body, err := s.RequestWithBucketID("POST", endpoint, nil, endpoint)
+	body, err := s.RequestWithBucketID("POST", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -2152,14 +2378,14 @@ func (s *Session) ChannelMessageCrosspost(channelID, messageID string) (st *Mess
 // ChannelNewsFollow follows a news channel in the targetID
 // channelID   : The ID of a News Channel
 // targetID    : The ID of a Channel where the News Channel should post to
-func (s *Session) ChannelNewsFollow(channelID, targetID string) (st *ChannelFollow, err error) {
+func (s *Session) ChannelNewsFollow(channelID, targetID string, options ...RequestOption) (st *ChannelFollow, err error) {
 	endpoint := EndpointChannelFollow(channelID)
 
 	data := struct {
 		WebhookChannelID string `json:"webhook_channel_id"`
 	}{targetID}
 
-	body, err := s.RequestWithBucketID("POST", endpoint, data, endpoint)
+	body, err := s.RequestWithBucketID("POST", endpoint, data, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -2171,16 +2397,16 @@ func (s *Session) ChannelNewsFollow(channelID, targetID string) (st *ChannelFoll
 // ChannelRecipientAdd adds a recipient to a GroupDM
 // channelID   : The ID of a GroupDM Channel
 // userID      : The ID of a User
-func (s *Session) ChannelRecipientAdd(channelID, userID string) (err error) {
-	_, err = s.RequestWithBucketID("PUT", EndpointChannelRecipient(channelID, userID), nil, EndpointChannelRecipient(channelID, ""))
+func (s *Session) ChannelRecipientAdd(channelID, userID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("PUT", EndpointChannelRecipient(channelID, userID), nil, EndpointChannelRecipient(channelID, ""), options...)
 	return
 }
 
 // ChannelRecipientRemove removes a recipient from a GroupDM
 // channelID   : The ID of a GroupDM Channel
 // userID      : The ID of a User
-func (s *Session) ChannelRecipientRemove(channelID, userID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointChannelRecipient(channelID, userID), nil, EndpointChannelRecipient(channelID, ""))
+func (s *Session) ChannelRecipientRemove(channelID, userID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointChannelRecipient(channelID, userID), nil, EndpointChannelRecipient(channelID, ""), options...)
 	return
 }
 
@@ -2210,7 +2436,7 @@ type ApplicationCommandsSearchParams struct {
 	IncludeApplications bool
 }
 
-func (s *Session) ChannelApplicationCommandsSearch(searchParams *ApplicationCommandsSearchParams) (commands []*ApplicationCommand, applications []*Application, err error) {
+func (s *Session) ChannelApplicationCommandsSearch(searchParams *ApplicationCommandsSearchParams, options ...RequestOption) (commands []*ApplicationCommand, applications []*Application, err error) {
 	params := url.Values{}
 	params.Set("type", strconv.Itoa(searchParams.Type))
 
@@ -2248,7 +2474,7 @@ func (s *Session) ChannelApplicationCommandsSearch(searchParams *ApplicationComm
 	endpoint := EndpointChannelApplicationCommandsSearch(searchParams.ChannelID)
 
 	var data []byte
-	data, err = s.RequestWithBucketID("GET", endpoint+"?"+params.Encode(), nil, EndpointChannelApplicationCommandsSearch(""))
+	data, err = s.RequestWithBucketID("GET", endpoint+"?"+params.Encode(), nil, EndpointChannelApplicationCommandsSearch(""), options...)
 	if err != nil {
 		return
 	}
@@ -2280,8 +2506,8 @@ func (s *Session) ChannelApplicationCommandsSearch(searchParams *ApplicationComm
 
 // Invite returns an Invite structure of the given invite
 // inviteID : The invite code
-func (s *Session) Invite(inviteID string) (st *Invite, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointInvite(inviteID), nil, EndpointInvite(""))
+func (s *Session) Invite(inviteID string, options ...RequestOption) (st *Invite, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointInvite(inviteID), nil, EndpointInvite(""), options...)
 	if err != nil {
 		return
 	}
@@ -2292,8 +2518,8 @@ func (s *Session) Invite(inviteID string) (st *Invite, err error) {
 
 // InviteWithCounts returns an Invite structure of the given invite including approximate member counts
 // inviteID : The invite code
-func (s *Session) InviteWithCounts(inviteID string) (st *Invite, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointInvite(inviteID)+"?with_counts=true", nil, EndpointInvite(""))
+func (s *Session) InviteWithCounts(inviteID string, options ...RequestOption) (st *Invite, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointInvite(inviteID)+"?with_counts=true", nil, EndpointInvite(""), options...)
 	if err != nil {
 		return
 	}
@@ -2308,7 +2534,7 @@ func (s *Session) InviteWithCounts(inviteID string) (st *Invite, err error) {
 //	guildScheduledEventID     : If specified, includes specified guild scheduled event.
 //	withCounts                : Whether to include approximate member counts or not
 //	withExpiration            : Whether to include expiration time or not
-func (s *Session) InviteComplex(inviteID, guildScheduledEventID, inputValue string, withCounts, withExpiration bool) (st *Invite, err error) {
+func (s *Session) InviteComplex(inviteID, guildScheduledEventID, inputValue string, withCounts, withExpiration bool, options ...RequestOption) (st *Invite, err error) {
 	endpoint := EndpointInvite(inviteID)
 	v := url.Values{}
 	if guildScheduledEventID != "" {
@@ -2328,7 +2554,7 @@ func (s *Session) InviteComplex(inviteID, guildScheduledEventID, inputValue stri
 		endpoint += "?" + v.Encode()
 	}
 
-	body, err := s.RequestWithBucketID("GET", endpoint, nil, EndpointInvite(""))
+	body, err := s.RequestWithBucketID("GET", endpoint, nil, EndpointInvite(""), options...)
 	if err != nil {
 		return
 	}
@@ -2339,8 +2565,8 @@ func (s *Session) InviteComplex(inviteID, guildScheduledEventID, inputValue stri
 
 // InviteDelete deletes an existing invite
 // inviteID   : the code of an invite
-func (s *Session) InviteDelete(inviteID string) (st *Invite, err error) {
-	body, err := s.RequestWithBucketID("DELETE", EndpointInvite(inviteID), nil, EndpointInvite(""))
+func (s *Session) InviteDelete(inviteID string, options ...RequestOption) (st *Invite, err error) {
+	body, err := s.RequestWithBucketID("DELETE", EndpointInvite(inviteID), nil, EndpointInvite(""), options...)
 	if err != nil {
 		return
 	}
@@ -2351,20 +2577,18 @@ func (s *Session) InviteDelete(inviteID string) (st *Invite, err error) {
 
 // InviteAccept accepts an Invite to a Guild or Channel
 // inviteID : The invite code
-func (s *Session) InviteAccept(inviteID, guildID, channelID string, channelType int) (st *Invite, err error) {
+func (s *Session) InviteAccept(inviteID, guildID, channelID string, channelType int, options ...RequestOption) (st *Invite, err error) {
 	contextProperties := "{\"location\":\"Join Guild\",\"location_guild_id\":\"%s\",\"location_channel_id\":\"%s\",\"location_channel_type\":%d}"
 
 	contextProperties = fmt.Sprintf(contextProperties, guildID, channelID, channelType)
 
-	headers := map[string]string{
-		"x-context-properties": base64.StdEncoding.EncodeToString([]byte(contextProperties)),
-	}
+	options = append([]RequestOption{WithHeader("x-context-properties", base64.StdEncoding.EncodeToString([]byte(contextProperties)))}, options...)
 
 	data := map[string]string{
 		"session_id": s.State.SessionID,
 	}
 
-	body, err := s.RequestWithBucketID("POST", EndpointInvite(inviteID), data, EndpointInvite(""), headers)
+	body, err := s.RequestWithBucketID("POST", EndpointInvite(inviteID), data, EndpointInvite(""), options...)
 	if err != nil {
 		return
 	}
@@ -2374,8 +2598,8 @@ func (s *Session) InviteAccept(inviteID, guildID, channelID string, channelType
 }
 
 // CreateFriendInvite creates a friend invite for the given user.
-func (s *Session) CreateFriendInvite(userID string) (st *Invite, err error) {
-	body, err := s.RequestWithBucketID("POST", EndpointUserInvites(userID), map[string]string{}, EndpointUserInvites(""))
+func (s *Session) CreateFriendInvite(userID string, options ...RequestOption) (st *Invite, err error) {
+	body, err := s.RequestWithBucketID("POST", EndpointUserInvites(userID), map[string]string{}, EndpointUserInvites(""), options...)
 	if err != nil {
 		return
 	}
@@ -2389,8 +2613,8 @@ func (s *Session) CreateFriendInvite(userID string) (st *Invite, err error) {
 // ------------------------------------------------------------------------------------------------
 
 // VoiceRegions returns the voice server regions
-func (s *Session) VoiceRegions() (st []*VoiceRegion, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointVoiceRegions, nil, EndpointVoiceRegions)
+func (s *Session) VoiceRegions(options ...RequestOption) (st []*VoiceRegion, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointVoiceRegions, nil, EndpointVoiceRegions, options...)
 	if err != nil {
 		return
 	}
@@ -2404,8 +2628,8 @@ func (s *Session) VoiceRegions() (st []*VoiceRegion, err error) {
 // ------------------------------------------------------------------------------------------------
 
 // Gateway returns the websocket Gateway address
-func (s *Session) Gateway() (gateway string, err error) {
-	response, err := s.RequestWithBucketID("GET", EndpointGateway, nil, EndpointGateway)
+func (s *Session) Gateway(options ...RequestOption) (gateway string, err error) {
+	response, err := s.RequestWithBucketID("GET", EndpointGateway, nil, EndpointGateway, options...)
 	if err != nil {
 		return
 	}
@@ -2431,8 +2655,8 @@ func (s *Session) Gateway() (gateway string, err error) {
 }
 
 // GatewayBot returns the websocket Gateway address and the recommended number of shards
-func (s *Session) GatewayBot() (st *GatewayBotResponse, err error) {
-	response, err := s.RequestWithBucketID("GET", EndpointGatewayBot, nil, EndpointGatewayBot)
+func (s *Session) GatewayBot(options ...RequestOption) (st *GatewayBotResponse, err error) {
+	response, err := s.RequestWithBucketID("GET", EndpointGatewayBot, nil, EndpointGatewayBot, options...)
 	if err != nil {
 		return
 	}
@@ -2457,13 +2681,13 @@ func (s *Session) GatewayBot() (st *GatewayBotResponse, err error) {
 // channelID: The ID of a Channel.
 // name     : The name of the webhook.
 // avatar   : The avatar of the webhook.
-func (s *Session) WebhookCreate(channelID, name, avatar string) (st *Webhook, err error) {
+func (s *Session) WebhookCreate(channelID, name, avatar string, options ...RequestOption) (st *Webhook, err error) {
 	data := struct {
 		Name   string `json:"name"`
 		Avatar string `json:"avatar,omitempty"`
 	}{name, avatar}
 
-	body, err := s.RequestWithBucketID("POST", EndpointChannelWebhooks(channelID), data, EndpointChannelWebhooks(channelID))
+	body, err := s.RequestWithBucketID("POST", EndpointChannelWebhooks(channelID), data, EndpointChannelWebhooks(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -2475,8 +2699,8 @@ func (s *Session) WebhookCreate(channelID, name, avatar string) (st *Webhook, er
 
 // ChannelWebhooks returns all webhooks for a given channel.
 // channelID: The ID of a channel.
-func (s *Session) ChannelWebhooks(channelID string) (st []*Webhook, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointChannelWebhooks(channelID), nil, EndpointChannelWebhooks(channelID))
+func (s *Session) ChannelWebhooks(channelID string, options ...RequestOption) (st []*Webhook, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointChannelWebhooks(channelID), nil, EndpointChannelWebhooks(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -2488,8 +2712,8 @@ func (s *Session) ChannelWebhooks(channelID string) (st []*Webhook, err error) {
 
 // GuildWebhooks returns all webhooks for a given guild.
 // guildID: The ID of a Guild.
-func (s *Session) GuildWebhooks(guildID string) (st []*Webhook, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointGuildWebhooks(guildID), nil, EndpointGuildWebhooks(guildID))
+func (s *Session) GuildWebhooks(guildID string, options ...RequestOption) (st []*Webhook, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildWebhooks(guildID), nil, EndpointGuildWebhooks(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -2501,8 +2725,8 @@ func (s *Session) GuildWebhooks(guildID string) (st []*Webhook, err error) {
 
 // Webhook returns a webhook for a given ID
 // webhookID: The ID of a webhook.
-func (s *Session) Webhook(webhookID string) (st *Webhook, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointWebhook(webhookID), nil, EndpointWebhooks)
+func (s *Session) Webhook(webhookID string, options ...RequestOption) (st *Webhook, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointWebhook(webhookID), nil, EndpointWebhooks, options...)
 	if err != nil {
 		return
 	}
@@ -2515,8 +2739,8 @@ func (s *Session) Webhook(webhookID string) (st *Webhook, err error) {
 // WebhookWithToken returns a webhook for a given ID
 // webhookID: The ID of a webhook.
 // token    : The auth token for the webhook.
-func (s *Session) WebhookWithToken(webhookID, token string) (st *Webhook, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointWebhookToken(webhookID, token), nil, EndpointWebhookToken("", ""))
+func (s *Session) WebhookWithToken(webhookID, token string, options ...RequestOption) (st *Webhook, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointWebhookToken(webhookID, token), nil, EndpointWebhookToken("", ""), options...)
 	if err != nil {
 		return
 	}
@@ -2530,14 +2754,14 @@ func (s *Session) WebhookWithToken(webhookID, token string) (st *Webhook, err er
 // webhookID: The ID of a webhook.
 // name     : The name of the webhook.
 // avatar   : The avatar of the webhook.
-func (s *Session) WebhookEdit(webhookID, name, avatar, channelID string) (st *Role, err error) {
+func (s *Session) WebhookEdit(webhookID, name, avatar, channelID string, options ...RequestOption) (st *Role, err error) {
 	data := struct {
 		Name      string `json:"name,omitempty"`
 		Avatar    string `json:"avatar,omitempty"`
 		ChannelID string `json:"channel_id,omitempty"`
 	}{name, avatar, channelID}
 
-	body, err := s.RequestWithBucketID("PATCH", EndpointWebhook(webhookID), data, EndpointWebhooks)
+	body, err := s.RequestWithBucketID("PATCH", EndpointWebhook(webhookID), data, EndpointWebhooks, options...)
 	if err != nil {
 		return
 	}
@@ -2552,13 +2776,13 @@ func (s *Session) WebhookEdit(webhookID, name, avatar, channelID string) (st *Ro
 // token    : The auth token for the webhook.
 // name     : The name of the webhook.
 // avatar   : The avatar of the webhook.
-func (s *Session) WebhookEditWithToken(webhookID, token, name, avatar string) (st *Role, err error) {
+func (s *Session) WebhookEditWithToken(webhookID, token, name, avatar string, options ...RequestOption) (st *Role, err error) {
 	data := struct {
 		Name   string `json:"name,omitempty"`
 		Avatar string `json:"avatar,omitempty"`
 	}{name, avatar}
 
-	body, err := s.RequestWithBucketID("PATCH", EndpointWebhookToken(webhookID, token), data, EndpointWebhookToken("", ""))
+	body, err := s.RequestWithBucketID("PATCH", EndpointWebhookToken(webhookID, token), data, EndpointWebhookToken("", ""), options...)
 	if err != nil {
 		return
 	}
@@ -2570,8 +2794,8 @@ func (s *Session) WebhookEditWithToken(webhookID, token, name, avatar string) (s
 
 // WebhookDelete deletes a webhook for a given ID
 // webhookID: The ID of a webhook.
-func (s *Session) WebhookDelete(webhookID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointWebhook(webhookID), nil, EndpointWebhooks)
+func (s *Session) WebhookDelete(webhookID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointWebhook(webhookID), nil, EndpointWebhooks, options...)
 
 	return
 }
@@ -2579,8 +2803,8 @@ func (s *Session) WebhookDelete(webhookID string) (err error) {
 // WebhookDeleteWithToken deletes a webhook for a given ID with an auth token.
 // webhookID: The ID of a webhook.
 // token    : The auth token for the webhook.
-func (s *Session) WebhookDeleteWithToken(webhookID, token string) (st *Webhook, err error) {
-	body, err := s.RequestWithBucketID("DELETE", EndpointWebhookToken(webhookID, token), nil, EndpointWebhookToken("", ""))
+func (s *Session) WebhookDeleteWithToken(webhookID, token string, options ...RequestOption) (st *Webhook, err error) {
+	body, err := s.RequestWithBucketID("DELETE", EndpointWebhookToken(webhookID, token), nil, EndpointWebhookToken("", ""), options...)
 	if err != nil {
 		return
 	}
@@ -2590,7 +2814,7 @@ func (s *Session) WebhookDeleteWithToken(webhookID, token string) (st *Webhook,
 	return
 }
 
-func (s *Session) webhookExecute(webhookID, token string, wait bool, threadID string, data *WebhookParams) (st *Message, err error) {
+func (s *Session) webhookExecute(webhookID, token string, wait bool, threadID string, data *WebhookParams, options ...RequestOption) (st *Message, err error) {
 	uri := EndpointWebhookToken(webhookID, token)
 
 	v := url.Values{}
@@ -2605,10 +2829,10 @@ func (s *Session) webhookExecute(webhookID, token string, wait bool, threadID st
 		uri += "?" + v.Encode()
 	}
 
-	headers := map[string]string{
+	options = append([]RequestOption{WithHeaders(map[string]string{
 		"Origin":        uri,
 		"Authorization": "",
-	}
+	})}, options...)
 
 	var response []byte
 	if len(data.Files) > 0 {
@@ -2617,9 +2841,9 @@ func (s *Session) webhookExecute(webhookID, token string, wait bool, threadID st
 			return st, encodeErr
 		}
 
-		response, err = s.request("POST", uri, contentType, body, uri, 0, headers)
+		response, err = s.request("POST", uri, contentType, body, uri, 0, options...)
 	} else {
-		response, err = s.RequestWithBucketID("POST", uri, data, uri, headers)
+		response, err = s.RequestWithBucketID("POST", uri, data, uri, options...)
 	}
 	if !wait || err != nil {
 		return
@@ -2633,8 +2857,8 @@ func (s *Session) webhookExecute(webhookID, token string, wait bool, threadID st
 // webhookID: The ID of a webhook.
 // token    : The auth token for the webhook
 // wait     : Waits for server confirmation of message send and ensures that the return struct is populated (it is nil otherwise)
-func (s *Session) WebhookExecute(webhookID, token string, wait bool, data *WebhookParams) (st *Message, err error) {
-	return s.webhookExecute(webhookID, token, wait, "", data)
+func (s *Session) WebhookExecute(webhookID, token string, wait bool, data *WebhookParams, options ...RequestOption) (st *Message, err error) {
+	return s.webhookExecute(webhookID, token, wait, "", data, options...)
 }
 
 // WebhookThreadExecute executes a webhook in a thread.
@@ -2642,18 +2866,18 @@ func (s *Session) WebhookExecute(webhookID, token string, wait bool, data *Webho
 // token    : The auth token for the webhook
 // wait     : Waits for server confirmation of message send and ensures that the return struct is populated (it is nil otherwise)
 // threadID :	Sends a message to the specified thread within a webhook's channel. The thread will automatically be unarchived.
-func (s *Session) WebhookThreadExecute(webhookID, token string, wait bool, threadID string, data *WebhookParams) (st *Message, err error) {
-	return s.webhookExecute(webhookID, token, wait, threadID, data)
+func (s *Session) WebhookThreadExecute(webhookID, token string, wait bool, threadID string, data *WebhookParams, options ...RequestOption) (st *Message, err error) {
+	return s.webhookExecute(webhookID, token, wait, threadID, data, options...)
 }
 
 // WebhookMessage gets a webhook message.
 // webhookID : The ID of a webhook
 // token     : The auth token for the webhook
 // messageID : The ID of message to get
-func (s *Session) WebhookMessage(webhookID, token, messageID string) (message *Message, err error) {
+func (s *Session) WebhookMessage(webhookID, token, messageID string, options ...RequestOption) (message *Message, err error) {
 	uri := EndpointWebhookMessage(webhookID, token, messageID)
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointWebhookToken("", ""))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointWebhookToken("", ""), options...)
 	if err != nil {
 		return
 	}
@@ -2667,7 +2891,7 @@ func (s *Session) WebhookMessage(webhookID, token, messageID string) (message *M
 // webhookID : The ID of a webhook
 // token     : The auth token for the webhook
 // messageID : The ID of message to edit
-func (s *Session) WebhookMessageEdit(webhookID, token, messageID string, data *WebhookEdit) (st *Message, err error) {
+func (s *Session) WebhookMessageEdit(webhookID, token, messageID string, data *WebhookEdit, options ...RequestOption) (st *Message, err error) {
 	uri := EndpointWebhookMessage(webhookID, token, messageID)
 
 	var response []byte
@@ -2677,12 +2901,12 @@ func (s *Session) WebhookMessageEdit(webhookID, token, messageID string, data *W
 			return nil, err
 		}
 
-		response, err = s.request("PATCH", uri, contentType, body, uri, 0)
+		response, err = s.request("PATCH", uri, contentType, body, uri, 0, options...)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		response, err = s.RequestWithBucketID("PATCH", uri, data, EndpointWebhookToken("", ""))
+		response, err = s.RequestWithBucketID("PATCH", uri, data, EndpointWebhookToken("", ""), options...)
 		if err != nil {
 			return nil, err
 		}
@@ -2696,10 +2920,10 @@ func (s *Session) WebhookMessageEdit(webhookID, token, messageID string, data *W
 // webhookID : The ID of a webhook
 // token     : The auth token for the webhook
 // messageID : The ID of a message to edit
-func (s *Session) WebhookMessageDelete(webhookID, token, messageID string) (err error) {
+func (s *Session) WebhookMessageDelete(webhookID, token, messageID string, options ...RequestOption) (err error) {
 	uri := EndpointWebhookMessage(webhookID, token, messageID)
 
-	_, err = s.RequestWithBucketID("DELETE", uri, nil, EndpointWebhookToken("", ""))
+	_, err = s.RequestWithBucketID("DELETE", uri, nil, EndpointWebhookToken("", ""), options...)
 	return
 }
 
@@ -2707,10 +2931,10 @@ func (s *Session) WebhookMessageDelete(webhookID, token, messageID string) (err
 // channelID : The channel ID.
 // messageID : The message ID.
 // emojiID   : Either the unicode emoji for the reaction, or a guild emoji identifier in name:id format (e.g. "hello:1234567654321")
-func (s *Session) MessageReactionAdd(channelID, messageID, emojiID string) error {
+func (s *Session) MessageReactionAdd(channelID, messageID, emojiID string, options ...RequestOption) error {
 	// emoji such as  #⃣ need to have # escaped
 	emojiID = strings.Replace(emojiID, "#", "%23", -1)
-	_, err := s.RequestWithBucketID("PUT", EndpointMessageReaction(channelID, messageID, emojiID, "@me"), nil, EndpointMessageReaction(channelID, "", "", ""))
+	_, err := s.RequestWithBucketID("PUT", EndpointMessageReaction(channelID, messageID, emojiID, "@me"), nil, EndpointMessageReaction(channelID, "", "", ""), options...)
 
 	return err
 }
@@ -2720,10 +2944,10 @@ func (s *Session) MessageReactionAdd(channelID, messageID, emojiID string) error
 // messageID : The message ID.
 // emojiID   : Either the unicode emoji for the reaction, or a guild emoji identifier.
 // userID	 : @me or ID of the user to delete the reaction for.
-func (s *Session) MessageReactionRemove(channelID, messageID, emojiID, userID string) error {
+func (s *Session) MessageReactionRemove(channelID, messageID, emojiID, userID string, options ...RequestOption) error {
 	// emoji such as  #⃣ need to have # escaped
 	emojiID = strings.Replace(emojiID, "#", "%23", -1)
-	_, err := s.RequestWithBucketID("DELETE", EndpointMessageReaction(channelID, messageID, emojiID, userID), nil, EndpointMessageReaction(channelID, "", "", ""))
+	_, err := s.RequestWithBucketID("DELETE", EndpointMessageReaction(channelID, messageID, emojiID, userID), nil, EndpointMessageReaction(channelID, "", "", ""), options...)
 
 	return err
 }
@@ -2731,8 +2955,8 @@ func (s *Session) MessageReactionRemove(channelID, messageID, emojiID, userID st
 // MessageReactionsRemoveAll deletes all reactions from a message
 // channelID : The channel ID
 // messageID : The message ID.
-func (s *Session) MessageReactionsRemoveAll(channelID, messageID string) error {
-	_, err := s.RequestWithBucketID("DELETE", EndpointMessageReactionsAll(channelID, messageID), nil, EndpointMessageReactionsAll(channelID, messageID))
+func (s *Session) MessageReactionsRemoveAll(channelID, messageID string, options ...RequestOption) error {
+	_, err := s.RequestWithBucketID("DELETE", EndpointMessageReactionsAll(channelID, messageID), nil, EndpointMessageReactionsAll(channelID, messageID), options...)
 
 	return err
 }
@@ -2741,10 +2965,10 @@ func (s *Session) MessageReactionsRemoveAll(channelID, messageID string) error {
 // channelID : The channel ID
 // messageID : The message ID
 // emojiID   : The emoji ID
-func (s *Session) MessageReactionsRemoveEmoji(channelID, messageID, emojiID string) error {
+func (s *Session) MessageReactionsRemoveEmoji(channelID, messageID, emojiID string, options ...RequestOption) error {
 	// emoji such as  #⃣ need to have # escaped
 	emojiID = strings.Replace(emojiID, "#", "%23", -1)
-	_, err := s.RequestWithBucketID("DELETE", EndpointMessageReactions(channelID, messageID, emojiID), nil, EndpointMessageReactions(channelID, messageID, emojiID))
+	_, err := s.RequestWithBucketID("DELETE", EndpointMessageReactions(channelID, messageID, emojiID), nil, EndpointMessageReactions(channelID, messageID, emojiID), options...)
 
 	return err
 }
@@ -2756,7 +2980,7 @@ func (s *Session) MessageReactionsRemoveEmoji(channelID, messageID, emojiID stri
 // limit    : max number of users to return (max 100)
 // beforeID  : If provided all reactions returned will be before given ID.
 // afterID   : If provided all reactions returned will be after given ID.
-func (s *Session) MessageReactions(channelID, messageID, emojiID string, limit int, beforeID, afterID string) (st []*User, err error) {
+func (s *Session) MessageReactions(channelID, messageID, emojiID string, limit int, beforeID, afterID string, options ...RequestOption) (st []*User, err error) {
 	// emoji such as  #⃣ need to have # escaped
 	emojiID = strings.Replace(emojiID, "#", "%23", -1)
 	uri := EndpointMessageReactions(channelID, messageID, emojiID)
@@ -2778,7 +3002,7 @@ func (s *Session) MessageReactions(channelID, messageID, emojiID string, limit i
 		uri += "?" + v.Encode()
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointMessageReaction(channelID, "", "", ""))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointMessageReaction(channelID, "", "", ""), options...)
 	if err != nil {
 		return
 	}
@@ -2787,19 +3011,26 @@ func (s *Session) MessageReactions(channelID, messageID, emojiID string, limit i
 	return
 }
 
-// MessageAck acknowledges a message.
-func (s *Session) MessageAck(channelID, messageID string) error {
+// MessageAck acknowledges a message, marking its channel as read in the state.
+func (s *Session) MessageAck(channelID, messageID string, options ...RequestOption) error {
 	data := map[string]any{"token": nil}
 
-	_, err := s.RequestWithBucketID("POST", EndpointMessageAck(channelID, messageID), data, EndpointMessageAck(channelID, ""))
+	_, err := s.RequestWithBucketID("POST", EndpointMessageAck(channelID, messageID), data, EndpointMessageAck(channelID, ""), options...)
+	if err == nil && s.trackReadStates() {
+		s.State.messageAck(channelID, messageID, 0)
+	}
 	return err
 }
 
-// MessageUnack unacknowledges a message.
-func (s *Session) MessageUnack(channelID, messageID string, mentionCount int) error {
+// MessageUnack unacknowledges a message, marking its channel as unread in the
+// state.
+func (s *Session) MessageUnack(channelID, messageID string, mentionCount int, options ...RequestOption) error {
 	data := map[string]any{"manual": true, "mention_count": mentionCount}
 
-	_, err := s.RequestWithBucketID("POST", EndpointMessageAck(channelID, messageID), data, EndpointMessageAck(channelID, ""))
+	_, err := s.RequestWithBucketID("POST", EndpointMessageAck(channelID, messageID), data, EndpointMessageAck(channelID, ""), options...)
+	if err == nil && s.trackReadStates() {
+		s.State.messageAck(channelID, messageID, mentionCount)
+	}
 	return err
 }
 
@@ -2811,10 +3042,10 @@ func (s *Session) MessageUnack(channelID, messageID string, mentionCount int) er
 // channelID : Channel to create thread in
 // messageID : Message to start thread from
 // data : Parameters of the thread
-func (s *Session) MessageThreadStartComplex(channelID, messageID string, data *ThreadStart) (ch *Channel, err error) {
+func (s *Session) MessageThreadStartComplex(channelID, messageID string, data *ThreadStart, options ...RequestOption) (ch *Channel, err error) {
 	endpoint := EndpointChannelMessageThread(channelID, messageID)
 	var body []byte
-	body, err = s.RequestWithBucketID("POST", endpoint, data, endpoint)
+	body, err = s.RequestWithBucketID("POST", endpoint, data, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -2828,20 +3059,20 @@ func (s *Session) MessageThreadStartComplex(channelID, messageID string, data *T
 // messageID       : Message to start thread from
 // name            : Name of the thread
 // archiveDuration : Auto archive duration (in minutes)
-func (s *Session) MessageThreadStart(channelID, messageID string, name string, archiveDuration int) (ch *Channel, err error) {
+func (s *Session) MessageThreadStart(channelID, messageID string, name string, archiveDuration int, options ...RequestOption) (ch *Channel, err error) {
 	return s.MessageThreadStartComplex(channelID, messageID, &ThreadStart{
 		Name:                name,
 		AutoArchiveDuration: archiveDuration,
-	})
+	}, options...)
 }
 
 // ThreadStartComplex creates a new thread.
 // channelID : Channel to create thread in
 // data : Parameters of the thread
-func (s *Session) ThreadStartComplex(channelID string, data *ThreadStart) (ch *Channel, err error) {
+func (s *Session) ThreadStartComplex(channelID string, data *ThreadStart, options ...RequestOption) (ch *Channel, err error) {
 	endpoint := EndpointChannelThreads(channelID)
 	var body []byte
-	body, err = s.RequestWithBucketID("POST", endpoint, data, endpoint)
+	body, err = s.RequestWithBucketID("POST", endpoint, data, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -2854,19 +3085,19 @@ func (s *Session) ThreadStartComplex(channelID string, data *ThreadStart) (ch *C
 // channelID       : Channel to create thread in
 // name            : Name of the thread
 // archiveDuration : Auto archive duration (in minutes)
-func (s *Session) ThreadStart(channelID, name string, typ ChannelType, archiveDuration int) (ch *Channel, err error) {
+func (s *Session) ThreadStart(channelID, name string, typ ChannelType, archiveDuration int, options ...RequestOption) (ch *Channel, err error) {
 	return s.ThreadStartComplex(channelID, &ThreadStart{
 		Name:                name,
 		Type:                typ,
 		AutoArchiveDuration: archiveDuration,
-	})
+	}, options...)
 }
 
 // ForumThreadStartComplex starts a new thread (creates a post) in a forum channel.
 // channelID   : Channel to create thread in.
 // threadData  : Parameters of the thread.
 // messageData : Parameters of the starting message.
-func (s *Session) ForumThreadStartComplex(channelID string, threadData *ThreadStart, messageData *MessageSend) (th *Channel, err error) {
+func (s *Session) ForumThreadStartComplex(channelID string, threadData *ThreadStart, messageData *MessageSend, options ...RequestOption) (th *Channel, err error) {
 	endpoint := EndpointChannelThreads(channelID)
 
 	// TODO: Remove this when compatibility is not required.
@@ -2908,9 +3139,9 @@ func (s *Session) ForumThreadStartComplex(channelID string, threadData *ThreadSt
 			return th, encodeErr
 		}
 
-		response, err = s.request("POST", endpoint, contentType, body, endpoint, 0)
+		response, err = s.request("POST", endpoint, contentType, body, endpoint, 0, options...)
 	} else {
-		response, err = s.RequestWithBucketID("POST", endpoint, data, endpoint)
+		response, err = s.RequestWithBucketID("POST", endpoint, data, endpoint, options...)
 	}
 	if err != nil {
 		return
@@ -2925,11 +3156,11 @@ func (s *Session) ForumThreadStartComplex(channelID string, threadData *ThreadSt
 // name            : Name of the thread.
 // archiveDuration : Auto archive duration.
 // content         : Content of the starting message.
-func (s *Session) ForumThreadStart(channelID, name string, archiveDuration int, content string) (th *Channel, err error) {
+func (s *Session) ForumThreadStart(channelID, name string, archiveDuration int, content string, options ...RequestOption) (th *Channel, err error) {
 	return s.ForumThreadStartComplex(channelID, &ThreadStart{
 		Name:                name,
 		AutoArchiveDuration: archiveDuration,
-	}, &MessageSend{Content: content})
+	}, &MessageSend{Content: content}, options...)
 }
 
 // ForumThreadStartEmbed starts a new thread (post) in a forum channel.
@@ -2937,11 +3168,11 @@ func (s *Session) ForumThreadStart(channelID, name string, archiveDuration int,
 // name            : Name of the thread.
 // archiveDuration : Auto archive duration.
 // embed           : Embed data of the starting message.
-func (s *Session) ForumThreadStartEmbed(channelID, name string, archiveDuration int, embed *MessageEmbed) (th *Channel, err error) {
+func (s *Session) ForumThreadStartEmbed(channelID, name string, archiveDuration int, embed *MessageEmbed, options ...RequestOption) (th *Channel, err error) {
 	return s.ForumThreadStartComplex(channelID, &ThreadStart{
 		Name:                name,
 		AutoArchiveDuration: archiveDuration,
-	}, &MessageSend{Embeds: []*MessageEmbed{embed}})
+	}, &MessageSend{Embeds: []*MessageEmbed{embed}}, options...)
 }
 
 // ForumThreadStartEmbeds starts a new thread (post) in a forum channel.
@@ -2949,46 +3180,46 @@ func (s *Session) ForumThreadStartEmbed(channelID, name string, archiveDuration
 // name            : Name of the thread.
 // archiveDuration : Auto archive duration.
 // embeds          : Embeds data of the starting message.
-func (s *Session) ForumThreadStartEmbeds(channelID, name string, archiveDuration int, embeds []*MessageEmbed) (th *Channel, err error) {
+func (s *Session) ForumThreadStartEmbeds(channelID, name string, archiveDuration int, embeds []*MessageEmbed, options ...RequestOption) (th *Channel, err error) {
 	return s.ForumThreadStartComplex(channelID, &ThreadStart{
 		Name:                name,
 		AutoArchiveDuration: archiveDuration,
-	}, &MessageSend{Embeds: embeds})
+	}, &MessageSend{Embeds: embeds}, options...)
 }
 
 // ThreadJoin adds current user to a thread
-func (s *Session) ThreadJoin(id string) error {
+func (s *Session) ThreadJoin(id string, options ...RequestOption) error {
 	endpoint := EndpointThreadMember(id, "@me")
-	_, err := s.RequestWithBucketID("PUT", endpoint, nil, endpoint)
+	_, err := s.RequestWithBucketID("PUT", endpoint, nil, endpoint, options...)
 	return err
 }
 
 // ThreadLeave removes current user to a thread
-func (s *Session) ThreadLeave(id string) error {
+func (s *Session) ThreadLeave(id string, options ...RequestOption) error {
 	endpoint := EndpointThreadMember(id, "@me")
-	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint)
+	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint, options...)
 	return err
 }
 
 // ThreadMemberAdd adds another member to a thread
-func (s *Session) ThreadMemberAdd(threadID, memberID string) error {
+func (s *Session) ThreadMemberAdd(threadID, memberID string, options ...RequestOption) error {
 	endpoint := EndpointThreadMember(threadID, memberID)
-	_, err := s.RequestWithBucketID("PUT", endpoint, nil, endpoint)
+	_, err := s.RequestWithBucketID("PUT", endpoint, nil, endpoint, options...)
 	return err
 }
 
 // ThreadMemberRemove removes another member from a thread
-func (s *Session) ThreadMemberRemove(threadID, memberID string) error {
+func (s *Session) ThreadMemberRemove(threadID, memberID string, options ...RequestOption) error {
 	endpoint := EndpointThreadMember(threadID, memberID)
-	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint)
+	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint, options...)
 	return err
 }
 
 // ThreadMember returns thread member object for the specified member of a thread
-func (s *Session) ThreadMember(threadID, memberID string) (member *ThreadMember, err error) {
+func (s *Session) ThreadMember(threadID, memberID string, options ...RequestOption) (member *ThreadMember, err error) {
 	endpoint := EndpointThreadMember(threadID, memberID)
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -2998,9 +3229,9 @@ func (s *Session) ThreadMember(threadID, memberID string) (member *ThreadMember,
 }
 
 // ThreadMembers returns all members of specified thread.
-func (s *Session) ThreadMembers(threadID string) (members []*ThreadMember, err error) {
+func (s *Session) ThreadMembers(threadID string, options ...RequestOption) (members []*ThreadMember, err error) {
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", EndpointThreadMembers(threadID), nil, EndpointThreadMembers(threadID))
+	body, err = s.RequestWithBucketID("GET", EndpointThreadMembers(threadID), nil, EndpointThreadMembers(threadID), options...)
 	if err != nil {
 		return
 	}
@@ -3010,9 +3241,9 @@ func (s *Session) ThreadMembers(threadID string) (members []*ThreadMember, err e
 }
 
 // ThreadsActive returns all active threads for specified channel.
-func (s *Session) ThreadsActive(channelID string) (threads *ThreadsList, err error) {
+func (s *Session) ThreadsActive(channelID string, options ...RequestOption) (threads *ThreadsList, err error) {
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", EndpointChannelActiveThreads(channelID), nil, EndpointChannelActiveThreads(channelID))
+	body, err = s.RequestWithBucketID("GET", EndpointChannelActiveThreads(channelID), nil, EndpointChannelActiveThreads(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -3022,9 +3253,9 @@ func (s *Session) ThreadsActive(channelID string) (threads *ThreadsList, err err
 }
 
 // GuildThreadsActive returns all active threads for specified guild.
-func (s *Session) GuildThreadsActive(guildID string) (threads *ThreadsList, err error) {
+func (s *Session) GuildThreadsActive(guildID string, options ...RequestOption) (threads *ThreadsList, err error) {
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", EndpointGuildActiveThreads(guildID), nil, EndpointGuildActiveThreads(guildID))
+	body, err = s.RequestWithBucketID("GET", EndpointGuildActiveThreads(guildID), nil, EndpointGuildActiveThreads(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -3036,7 +3267,7 @@ func (s *Session) GuildThreadsActive(guildID string) (threads *ThreadsList, err
 // ThreadsArchived returns archived threads for specified channel.
 // before : If specified returns only threads before the timestamp
 // limit  : Optional maximum amount of threads to return.
-func (s *Session) ThreadsArchived(channelID string, before *time.Time, limit int) (threads *ThreadsList, err error) {
+func (s *Session) ThreadsArchived(channelID string, before *time.Time, limit int, options ...RequestOption) (threads *ThreadsList, err error) {
 	endpoint := EndpointChannelPublicArchivedThreads(channelID)
 	v := url.Values{}
 	if before != nil {
@@ -3052,7 +3283,7 @@ func (s *Session) ThreadsArchived(channelID string, before *time.Time, limit int
 	}
 
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3064,7 +3295,7 @@ func (s *Session) ThreadsArchived(channelID string, before *time.Time, limit int
 // ThreadsPrivateArchived returns archived private threads for specified channel.
 // before : If specified returns only threads before the timestamp
 // limit  : Optional maximum amount of threads to return.
-func (s *Session) ThreadsPrivateArchived(channelID string, before *time.Time, limit int) (threads *ThreadsList, err error) {
+func (s *Session) ThreadsPrivateArchived(channelID string, before *time.Time, limit int, options ...RequestOption) (threads *ThreadsList, err error) {
 	endpoint := EndpointChannelPrivateArchivedThreads(channelID)
 	v := url.Values{}
 	if before != nil {
@@ -3079,7 +3310,7 @@ func (s *Session) ThreadsPrivateArchived(channelID string, before *time.Time, li
 		endpoint += "?" + v.Encode()
 	}
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3091,7 +3322,7 @@ func (s *Session) ThreadsPrivateArchived(channelID string, before *time.Time, li
 // ThreadsPrivateJoinedArchived returns archived joined private threads for specified channel.
 // before : If specified returns only threads before the timestamp
 // limit  : Optional maximum amount of threads to return.
-func (s *Session) ThreadsPrivateJoinedArchived(channelID string, before *time.Time, limit int) (threads *ThreadsList, err error) {
+func (s *Session) ThreadsPrivateJoinedArchived(channelID string, before *time.Time, limit int, options ...RequestOption) (threads *ThreadsList, err error) {
 	endpoint := EndpointChannelJoinedPrivateArchivedThreads(channelID)
 	v := url.Values{}
 	if before != nil {
@@ -3106,7 +3337,7 @@ func (s *Session) ThreadsPrivateJoinedArchived(channelID string, before *time.Ti
 		endpoint += "?" + v.Encode()
 	}
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3123,13 +3354,13 @@ func (s *Session) ThreadsPrivateJoinedArchived(channelID string, before *time.Ti
 // appID       : The application ID.
 // guildID     : Guild ID to create guild-specific application command. If empty - creates global application command.
 // cmd         : New application command data.
-func (s *Session) ApplicationCommandCreate(appID string, guildID string, cmd *ApplicationCommand) (ccmd *ApplicationCommand, err error) {
+func (s *Session) ApplicationCommandCreate(appID string, guildID string, cmd *ApplicationCommand, options ...RequestOption) (ccmd *ApplicationCommand, err error) {
 	endpoint := EndpointApplicationGlobalCommands(appID)
 	if guildID != "" {
 		endpoint = EndpointApplicationGuildCommands(appID, guildID)
 	}
 
-	body, err := s.RequestWithBucketID("POST", endpoint, *cmd, endpoint)
+	body, err := s.RequestWithBucketID("POST", endpoint, *cmd, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3144,13 +3375,13 @@ func (s *Session) ApplicationCommandCreate(appID string, guildID string, cmd *Ap
 // cmdID       : Application command ID to edit.
 // guildID     : Guild ID to edit guild-specific application command. If empty - edits global application command.
 // cmd         : Updated application command data.
-func (s *Session) ApplicationCommandEdit(appID, guildID, cmdID string, cmd *ApplicationCommand) (updated *ApplicationCommand, err error) {
+func (s *Session) ApplicationCommandEdit(appID, guildID, cmdID string, cmd *ApplicationCommand, options ...RequestOption) (updated *ApplicationCommand, err error) {
 	endpoint := EndpointApplicationGlobalCommand(appID, cmdID)
 	if guildID != "" {
 		endpoint = EndpointApplicationGuildCommand(appID, guildID, cmdID)
 	}
 
-	body, err := s.RequestWithBucketID("PATCH", endpoint, *cmd, endpoint)
+	body, err := s.RequestWithBucketID("PATCH", endpoint, *cmd, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3163,13 +3394,13 @@ func (s *Session) ApplicationCommandEdit(appID, guildID, cmdID string, cmd *Appl
 // ApplicationCommandBulkOverwrite Creates commands overwriting existing commands. Returns a list of commands.
 // appID    : The application ID.
 // commands : The commands to create.
-func (s *Session) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*ApplicationCommand) (createdCommands []*ApplicationCommand, err error) {
+func (s *Session) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*ApplicationCommand, options ...RequestOption) (createdCommands []*ApplicationCommand, err error) {
 	endpoint := EndpointApplicationGlobalCommands(appID)
 	if guildID != "" {
 		endpoint = EndpointApplicationGuildCommands(appID, guildID)
 	}
 
-	body, err := s.RequestWithBucketID("PUT", endpoint, commands, endpoint)
+	body, err := s.RequestWithBucketID("PUT", endpoint, commands, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3183,13 +3414,13 @@ func (s *Session) ApplicationCommandBulkOverwrite(appID string, guildID string,
 // appID       : The application ID.
 // cmdID       : Application command ID to delete.
 // guildID     : Guild ID to delete guild-specific application command. If empty - deletes global application command.
-func (s *Session) ApplicationCommandDelete(appID, guildID, cmdID string) error {
+func (s *Session) ApplicationCommandDelete(appID, guildID, cmdID string, options ...RequestOption) error {
 	endpoint := EndpointApplicationGlobalCommand(appID, cmdID)
 	if guildID != "" {
 		endpoint = EndpointApplicationGuildCommand(appID, guildID, cmdID)
 	}
 
-	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint)
+	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint, options...)
 
 	return err
 }
@@ -3198,13 +3429,13 @@ func (s *Session) ApplicationCommandDelete(appID, guildID, cmdID string) error {
 // appID       : The application ID.
 // cmdID       : Application command ID.
 // guildID     : Guild ID to retrieve guild-specific application command. If empty - retrieves global application command.
-func (s *Session) ApplicationCommand(appID, guildID, cmdID string) (cmd *ApplicationCommand, err error) {
+func (s *Session) ApplicationCommand(appID, guildID, cmdID string, options ...RequestOption) (cmd *ApplicationCommand, err error) {
 	endpoint := EndpointApplicationGlobalCommand(appID, cmdID)
 	if guildID != "" {
 		endpoint = EndpointApplicationGuildCommand(appID, guildID, cmdID)
 	}
 
-	body, err := s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err := s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3217,13 +3448,13 @@ func (s *Session) ApplicationCommand(appID, guildID, cmdID string) (cmd *Applica
 // ApplicationCommands retrieves all commands in application.
 // appID       : The application ID.
 // guildID     : Guild ID to retrieve all guild-specific application commands. If empty - retrieves global application commands.
-func (s *Session) ApplicationCommands(appID, guildID string) (cmd []*ApplicationCommand, err error) {
+func (s *Session) ApplicationCommands(appID, guildID string, options ...RequestOption) (cmd []*ApplicationCommand, err error) {
 	endpoint := EndpointApplicationGlobalCommands(appID)
 	if guildID != "" {
 		endpoint = EndpointApplicationGuildCommands(appID, guildID)
 	}
 
-	body, err := s.RequestWithBucketID("GET", endpoint+"?with_localizations=true", nil, "GET "+endpoint)
+	body, err := s.RequestWithBucketID("GET", endpoint+"?with_localizations=true", nil, "GET "+endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3236,11 +3467,11 @@ func (s *Session) ApplicationCommands(appID, guildID string) (cmd []*Application
 // GuildApplicationCommandsPermissions returns permissions for application commands in a guild.
 // appID       : The application ID
 // guildID     : Guild ID to retrieve application commands permissions for.
-func (s *Session) GuildApplicationCommandsPermissions(appID, guildID string) (permissions []*GuildApplicationCommandPermissions, err error) {
+func (s *Session) GuildApplicationCommandsPermissions(appID, guildID string, options ...RequestOption) (permissions []*GuildApplicationCommandPermissions, err error) {
 	endpoint := EndpointApplicationCommandsGuildPermissions(appID, guildID)
 
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3253,11 +3484,11 @@ func (s *Session) GuildApplicationCommandsPermissions(appID, guildID string) (pe
 // appID       : The Application ID
 // guildID     : The guild ID containing the application command
 // cmdID       : The command ID to retrieve the permissions of
-func (s *Session) ApplicationCommandPermissions(appID, guildID, cmdID string) (permissions *GuildApplicationCommandPermissions, err error) {
+func (s *Session) ApplicationCommandPermissions(appID, guildID, cmdID string, options ...RequestOption) (permissions *GuildApplicationCommandPermissions, err error) {
 	endpoint := EndpointApplicationCommandPermissions(appID, guildID, cmdID)
 
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3273,10 +3504,10 @@ func (s *Session) ApplicationCommandPermissions(appID, guildID, cmdID string) (p
 // permissions : An object containing a list of permissions for the application command
 //
 // NOTE: Requires OAuth2 token with applications.commands.permissions.update scope
-func (s *Session) ApplicationCommandPermissionsEdit(appID, guildID, cmdID string, permissions *ApplicationCommandPermissionsList) (err error) {
+func (s *Session) ApplicationCommandPermissionsEdit(appID, guildID, cmdID string, permissions *ApplicationCommandPermissionsList, options ...RequestOption) (err error) {
 	endpoint := EndpointApplicationCommandPermissions(appID, guildID, cmdID)
 
-	_, err = s.RequestWithBucketID("PUT", endpoint, permissions, endpoint)
+	_, err = s.RequestWithBucketID("PUT", endpoint, permissions, endpoint, options...)
 	return
 }
 
@@ -3286,17 +3517,17 @@ func (s *Session) ApplicationCommandPermissionsEdit(appID, guildID, cmdID string
 // permissions : A list of permissions paired with a command ID, guild ID, and application ID per application command
 //
 // NOTE: This endpoint has been disabled with updates to command permissions (Permissions v2). Please use ApplicationCommandPermissionsEdit instead.
-func (s *Session) ApplicationCommandPermissionsBatchEdit(appID, guildID string, permissions []*GuildApplicationCommandPermissions) (err error) {
+func (s *Session) ApplicationCommandPermissionsBatchEdit(appID, guildID string, permissions []*GuildApplicationCommandPermissions, options ...RequestOption) (err error) {
 	endpoint := EndpointApplicationCommandsGuildPermissions(appID, guildID)
 
-	_, err = s.RequestWithBucketID("PUT", endpoint, permissions, endpoint)
+	_, err = s.RequestWithBucketID("PUT", endpoint, permissions, endpoint, options...)
 	return
 }
 
 // InteractionRespond creates the response to an interaction.
 // interaction : Interaction instance.
 // resp        : Response message data.
-func (s *Session) InteractionRespond(interaction *Interaction, resp *InteractionResponse) error {
+func (s *Session) InteractionRespond(interaction *Interaction, resp *InteractionResponse, options ...RequestOption) error {
 	endpoint := EndpointInteractionResponse(interaction.ID, interaction.Token)
 
 	if resp.Data != nil && len(resp.Data.Files) > 0 {
@@ -3305,33 +3536,33 @@ func (s *Session) InteractionRespond(interaction *Interaction, resp *Interaction
 			return err
 		}
 
-		_, err = s.request("POST", endpoint, contentType, body, endpoint, 0)
+		_, err = s.request("POST", endpoint, contentType, body, endpoint, 0, options...)
 		return err
 	}
 
-	_, err := s.RequestWithBucketID("POST", endpoint, *resp, endpoint)
+	_, err := s.RequestWithBucketID("POST", endpoint, *resp, endpoint, options...)
 	return err
 }
 
 // InteractionResponse gets the response to an interaction.
 // interaction : Interaction instance.
-func (s *Session) InteractionResponse(interaction *Interaction) (*Message, error) {
-	return s.WebhookMessage(interaction.AppID, interaction.Token, "@original")
+func (s *Session) InteractionResponse(interaction *Interaction, options ...RequestOption) (*Message, error) {
+	return s.WebhookMessage(interaction.AppID, interaction.Token, "@original", options...)
 }
 
 // InteractionResponseEdit edits the response to an interaction.
 // interaction : Interaction instance.
 // newresp     : Updated response message data.
-func (s *Session) InteractionResponseEdit(interaction *Interaction, newresp *WebhookEdit) (*Message, error) {
-	return s.WebhookMessageEdit(interaction.AppID, interaction.Token, "@original", newresp)
+func (s *Session) InteractionResponseEdit(interaction *Interaction, newresp *WebhookEdit, options ...RequestOption) (*Message, error) {
+	return s.WebhookMessageEdit(interaction.AppID, interaction.Token, "@original", newresp, options...)
 }
 
 // InteractionResponseDelete deletes the response to an interaction.
 // interaction : Interaction instance.
-func (s *Session) InteractionResponseDelete(interaction *Interaction) error {
+func (s *Session) InteractionResponseDelete(interaction *Interaction, options ...RequestOption) error {
 	endpoint := EndpointInteractionResponseActions(interaction.AppID, interaction.Token)
 
-	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint)
+	_, err := s.RequestWithBucketID("DELETE", endpoint, nil, endpoint, options...)
 
 	return err
 }
@@ -3340,23 +3571,23 @@ func (s *Session) InteractionResponseDelete(interaction *Interaction) error {
 // interaction : Interaction instance.
 // wait        : Waits for server confirmation of message send and ensures that the return struct is populated (it is nil otherwise)
 // data        : Data of the message to send.
-func (s *Session) FollowupMessageCreate(interaction *Interaction, wait bool, data *WebhookParams) (*Message, error) {
-	return s.WebhookExecute(interaction.AppID, interaction.Token, wait, data)
+func (s *Session) FollowupMessageCreate(interaction *Interaction, wait bool, data *WebhookParams, options ...RequestOption) (*Message, error) {
+	return s.WebhookExecute(interaction.AppID, interaction.Token, wait, data, options...)
 }
 
 // FollowupMessageEdit edits a followup message of an interaction.
 // interaction : Interaction instance.
 // messageID   : The followup message ID.
 // data        : Data to update the message
-func (s *Session) FollowupMessageEdit(interaction *Interaction, messageID string, data *WebhookEdit) (*Message, error) {
-	return s.WebhookMessageEdit(interaction.AppID, interaction.Token, messageID, data)
+func (s *Session) FollowupMessageEdit(interaction *Interaction, messageID string, data *WebhookEdit, options ...RequestOption) (*Message, error) {
+	return s.WebhookMessageEdit(interaction.AppID, interaction.Token, messageID, data, options...)
 }
 
 // FollowupMessageDelete deletes a followup message of an interaction.
 // interaction : Interaction instance.
 // messageID   : The followup message ID.
-func (s *Session) FollowupMessageDelete(interaction *Interaction, messageID string) error {
-	return s.WebhookMessageDelete(interaction.AppID, interaction.Token, messageID)
+func (s *Session) FollowupMessageDelete(interaction *Interaction, messageID string, options ...RequestOption) error {
+	return s.WebhookMessageDelete(interaction.AppID, interaction.Token, messageID, options...)
 }
 
 type InteractData struct {
@@ -3383,7 +3614,7 @@ type InteractData struct {
 }
 
 // Interact creates a new interaction.
-func (s *Session) Interact(interactData *InteractData) error {
+func (s *Session) Interact(interactData *InteractData, options ...RequestOption) error {
 	payload := map[string]any{
 		"application_id": interactData.ApplicationID,
 		"channel_id":     interactData.ChannelID,
@@ -3413,13 +3644,13 @@ func (s *Session) Interact(interactData *InteractData) error {
 		payload["analytics_location"] = "slash_ui"
 	}
 
-	_, err := s.RequestWithBucketID("POST", EndpointInteractions, payload, EndpointInteractions)
+	_, err := s.RequestWithBucketID("POST", EndpointInteractions, payload, EndpointInteractions, options...)
 	return err
 }
 
 // InteractionClick sends a click interaction.
 // DEPRECATED: Use Interact instead.
-func (s *Session) InteractionClick(button *Button, message *Message) error {
+func (s *Session) InteractionClick(button *Button, message *Message, options ...RequestOption) error {
 	appID := message.Author.ID
 
 	if message.ApplicationID != "" {
@@ -3441,12 +3672,12 @@ func (s *Session) InteractionClick(button *Button, message *Message) error {
 		Type:         3,
 	}
 
-	_, err := s.RequestWithBucketID("POST", EndpointInteractions, data, EndpointInteractions)
+	_, err := s.RequestWithBucketID("POST", EndpointInteractions, data, EndpointInteractions, options...)
 	return err
 }
 
 // DEPRECATED: Use Interact instead.
-func (s *Session) InteractionSubmitModal(message *Message, modal *InteractionModalCreate, customID, value string) error {
+func (s *Session) InteractionSubmitModal(message *Message, modal *InteractionModalCreate, customID, value string, options ...RequestOption) error {
 	data := ModalSubmit{
 		Type:          InteractionModalSubmit,
 		ApplicationID: message.Author.ID,
@@ -3470,7 +3701,7 @@ func (s *Session) InteractionSubmitModal(message *Message, modal *InteractionMod
 		Nonce:     GenerateNonce(),
 	}
 
-	_, err := s.RequestWithBucketID("POST", EndpointInteractions, data, EndpointInteractions)
+	_, err := s.RequestWithBucketID("POST", EndpointInteractions, data, EndpointInteractions, options...)
 	return err
 }
 
@@ -3481,8 +3712,8 @@ func (s *Session) InteractionSubmitModal(message *Message, modal *InteractionMod
 // StageInstanceCreate creates and returns a new Stage instance associated to a Stage channel.
 // data : Parameters needed to create a stage instance.
 // data : The data of the Stage instance to create
-func (s *Session) StageInstanceCreate(data *StageInstanceParams) (si *StageInstance, err error) {
-	body, err := s.RequestWithBucketID("POST", EndpointStageInstances, data, EndpointStageInstances)
+func (s *Session) StageInstanceCreate(data *StageInstanceParams, options ...RequestOption) (si *StageInstance, err error) {
+	body, err := s.RequestWithBucketID("POST", EndpointStageInstances, data, EndpointStageInstances, options...)
 	if err != nil {
 		return
 	}
@@ -3493,8 +3724,8 @@ func (s *Session) StageInstanceCreate(data *StageInstanceParams) (si *StageInsta
 
 // StageInstance will retrieve a Stage instance by ID of the Stage channel.
 // channelID : The ID of the Stage channel
-func (s *Session) StageInstance(channelID string) (si *StageInstance, err error) {
-	body, err := s.RequestWithBucketID("GET", EndpointStageInstance(channelID), nil, EndpointStageInstance(channelID))
+func (s *Session) StageInstance(channelID string, options ...RequestOption) (si *StageInstance, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointStageInstance(channelID), nil, EndpointStageInstance(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -3506,8 +3737,8 @@ func (s *Session) StageInstance(channelID string) (si *StageInstance, err error)
 // StageInstanceEdit will edit a Stage instance by ID of the Stage channel.
 // channelID : The ID of the Stage channel
 // data : The data to edit the Stage instance
-func (s *Session) StageInstanceEdit(channelID string, data *StageInstanceParams) (si *StageInstance, err error) {
-	body, err := s.RequestWithBucketID("PATCH", EndpointStageInstance(channelID), data, EndpointStageInstance(channelID))
+func (s *Session) StageInstanceEdit(channelID string, data *StageInstanceParams, options ...RequestOption) (si *StageInstance, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointStageInstance(channelID), data, EndpointStageInstance(channelID), options...)
 	if err != nil {
 		return
 	}
@@ -3518,8 +3749,8 @@ func (s *Session) StageInstanceEdit(channelID string, data *StageInstanceParams)
 
 // StageInstanceDelete will delete a Stage instance by ID of the Stage channel.
 // channelID : The ID of the Stage channel
-func (s *Session) StageInstanceDelete(channelID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointStageInstance(channelID), nil, EndpointStageInstance(channelID))
+func (s *Session) StageInstanceDelete(channelID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointStageInstance(channelID), nil, EndpointStageInstance(channelID), options...)
 	return
 }
 
@@ -3530,13 +3761,13 @@ func (s *Session) StageInstanceDelete(channelID string) (err error) {
 // GuildScheduledEvents returns an array of GuildScheduledEvent for a guild
 // guildID        : The ID of a Guild
 // userCount      : Whether to include the user count in the response
-func (s *Session) GuildScheduledEvents(guildID string, userCount bool) (st []*GuildScheduledEvent, err error) {
+func (s *Session) GuildScheduledEvents(guildID string, userCount bool, options ...RequestOption) (st []*GuildScheduledEvent, err error) {
 	uri := EndpointGuildScheduledEvents(guildID)
 	if userCount {
 		uri += "?with_user_count=true"
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildScheduledEvents(guildID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildScheduledEvents(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -3549,13 +3780,13 @@ func (s *Session) GuildScheduledEvents(guildID string, userCount bool) (st []*Gu
 // guildID        : The ID of a Guild
 // eventID        : The ID of the event
 // userCount      : Whether to include the user count in the response
-func (s *Session) GuildScheduledEvent(guildID, eventID string, userCount bool) (st *GuildScheduledEvent, err error) {
+func (s *Session) GuildScheduledEvent(guildID, eventID string, userCount bool, options ...RequestOption) (st *GuildScheduledEvent, err error) {
 	uri := EndpointGuildScheduledEvent(guildID, eventID)
 	if userCount {
 		uri += "?with_user_count=true"
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildScheduledEvent(guildID, eventID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildScheduledEvent(guildID, eventID), options...)
 	if err != nil {
 		return
 	}
@@ -3567,8 +3798,8 @@ func (s *Session) GuildScheduledEvent(guildID, eventID string, userCount bool) (
 // GuildScheduledEventCreate creates a GuildScheduledEvent for a guild and returns it
 // guildID   : The ID of a Guild
 // eventID   : The ID of the event
-func (s *Session) GuildScheduledEventCreate(guildID string, event *GuildScheduledEventParams) (st *GuildScheduledEvent, err error) {
-	body, err := s.RequestWithBucketID("POST", EndpointGuildScheduledEvents(guildID), event, EndpointGuildScheduledEvents(guildID))
+func (s *Session) GuildScheduledEventCreate(guildID string, event *GuildScheduledEventParams, options ...RequestOption) (st *GuildScheduledEvent, err error) {
+	body, err := s.RequestWithBucketID("POST", EndpointGuildScheduledEvents(guildID), event, EndpointGuildScheduledEvents(guildID), options...)
 	if err != nil {
 		return
 	}
@@ -3580,8 +3811,8 @@ func (s *Session) GuildScheduledEventCreate(guildID string, event *GuildSchedule
 // GuildScheduledEventEdit updates a specific event for a guild and returns it.
 // guildID   : The ID of a Guild
 // eventID   : The ID of the event
-func (s *Session) GuildScheduledEventEdit(guildID, eventID string, event *GuildScheduledEventParams) (st *GuildScheduledEvent, err error) {
-	body, err := s.RequestWithBucketID("PATCH", EndpointGuildScheduledEvent(guildID, eventID), event, EndpointGuildScheduledEvent(guildID, eventID))
+func (s *Session) GuildScheduledEventEdit(guildID, eventID string, event *GuildScheduledEventParams, options ...RequestOption) (st *GuildScheduledEvent, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildScheduledEvent(guildID, eventID), event, EndpointGuildScheduledEvent(guildID, eventID), options...)
 	if err != nil {
 		return
 	}
@@ -3593,8 +3824,8 @@ func (s *Session) GuildScheduledEventEdit(guildID, eventID string, event *GuildS
 // GuildScheduledEventDelete deletes a specific GuildScheduledEvent in a guild
 // guildID   : The ID of a Guild
 // eventID   : The ID of the event
-func (s *Session) GuildScheduledEventDelete(guildID, eventID string) (err error) {
-	_, err = s.RequestWithBucketID("DELETE", EndpointGuildScheduledEvent(guildID, eventID), nil, EndpointGuildScheduledEvent(guildID, eventID))
+func (s *Session) GuildScheduledEventDelete(guildID, eventID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildScheduledEvent(guildID, eventID), nil, EndpointGuildScheduledEvent(guildID, eventID), options...)
 	return
 }
 
@@ -3605,7 +3836,7 @@ func (s *Session) GuildScheduledEventDelete(guildID, eventID string) (err error)
 // withMember : Whether to include the member object in the response
 // beforeID   : If is not empty all returned users entries will be before the given ID
 // afterID    : If is not empty all returned users entries will be after the given ID
-func (s *Session) GuildScheduledEventUsers(guildID, eventID string, limit int, withMember bool, beforeID, afterID string) (st []*GuildScheduledEventUser, err error) {
+func (s *Session) GuildScheduledEventUsers(guildID, eventID string, limit int, withMember bool, beforeID, afterID string, options ...RequestOption) (st []*GuildScheduledEventUser, err error) {
 	uri := EndpointGuildScheduledEventUsers(guildID, eventID)
 
 	queryParams := url.Values{}
@@ -3626,7 +3857,7 @@ func (s *Session) GuildScheduledEventUsers(guildID, eventID string, limit int, w
 		uri += "?" + queryParams.Encode()
 	}
 
-	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildScheduledEventUsers(guildID, eventID))
+	body, err := s.RequestWithBucketID("GET", uri, nil, EndpointGuildScheduledEventUsers(guildID, eventID), options...)
 	if err != nil {
 		return
 	}
@@ -3641,11 +3872,11 @@ func (s *Session) GuildScheduledEventUsers(guildID, eventID string, limit int, w
 
 // AutoModerationRules returns a list of auto moderation rules.
 // guildID : ID of the guild
-func (s *Session) AutoModerationRules(guildID string) (st []*AutoModerationRule, err error) {
+func (s *Session) AutoModerationRules(guildID string, options ...RequestOption) (st []*AutoModerationRule, err error) {
 	endpoint := EndpointGuildAutoModerationRules(guildID)
 
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3657,11 +3888,11 @@ func (s *Session) AutoModerationRules(guildID string) (st []*AutoModerationRule,
 // AutoModerationRule returns an auto moderation rule.
 // guildID : ID of the guild
 // ruleID  : ID of the auto moderation rule
-func (s *Session) AutoModerationRule(guildID, ruleID string) (st *AutoModerationRule, err error) {
+func (s *Session) AutoModerationRule(guildID, ruleID string, options ...RequestOption) (st *AutoModerationRule, err error) {
 	endpoint := EndpointGuildAutoModerationRule(guildID, ruleID)
 
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3673,11 +3904,11 @@ func (s *Session) AutoModerationRule(guildID, ruleID string) (st *AutoModeration
 // AutoModerationRuleCreate creates an auto moderation rule with the given data and returns it.
 // guildID : ID of the guild
 // rule    : Rule data
-func (s *Session) AutoModerationRuleCreate(guildID string, rule *AutoModerationRule) (st *AutoModerationRule, err error) {
+func (s *Session) AutoModerationRuleCreate(guildID string, rule *AutoModerationRule, options ...RequestOption) (st *AutoModerationRule, err error) {
 	endpoint := EndpointGuildAutoModerationRules(guildID)
 
 	var body []byte
-	body, err = s.RequestWithBucketID("POST", endpoint, rule, endpoint)
+	body, err = s.RequestWithBucketID("POST", endpoint, rule, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3690,11 +3921,11 @@ func (s *Session) AutoModerationRuleCreate(guildID string, rule *AutoModerationR
 // guildID : ID of the guild
 // ruleID  : ID of the auto moderation rule
 // rule    : New rule data
-func (s *Session) AutoModerationRuleEdit(guildID, ruleID string, rule *AutoModerationRule) (st *AutoModerationRule, err error) {
+func (s *Session) AutoModerationRuleEdit(guildID, ruleID string, rule *AutoModerationRule, options ...RequestOption) (st *AutoModerationRule, err error) {
 	endpoint := EndpointGuildAutoModerationRule(guildID, ruleID)
 
 	var body []byte
-	body, err = s.RequestWithBucketID("PATCH", endpoint, rule, endpoint)
+	body, err = s.RequestWithBucketID("PATCH", endpoint, rule, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3706,18 +3937,18 @@ func (s *Session) AutoModerationRuleEdit(guildID, ruleID string, rule *AutoModer
 // AutoModerationRuleDelete deletes an auto moderation rule.
 // guildID : ID of the guild
 // ruleID  : ID of the auto moderation rule
-func (s *Session) AutoModerationRuleDelete(guildID, ruleID string) (err error) {
+func (s *Session) AutoModerationRuleDelete(guildID, ruleID string, options ...RequestOption) (err error) {
 	endpoint := EndpointGuildAutoModerationRule(guildID, ruleID)
-	_, err = s.RequestWithBucketID("DELETE", endpoint, nil, endpoint)
+	_, err = s.RequestWithBucketID("DELETE", endpoint, nil, endpoint, options...)
 	return
 }
 
 // ApplicationRoleConnectionMetadata returns application role connection metadata.
 // appID : ID of the application
-func (s *Session) ApplicationRoleConnectionMetadata(appID string) (st []*ApplicationRoleConnectionMetadata, err error) {
+func (s *Session) ApplicationRoleConnectionMetadata(appID string, options ...RequestOption) (st []*ApplicationRoleConnectionMetadata, err error) {
 	endpoint := EndpointApplicationRoleConnectionMetadata(appID)
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3729,10 +3960,10 @@ func (s *Session) ApplicationRoleConnectionMetadata(appID string) (st []*Applica
 // ApplicationRoleConnectionMetadataUpdate updates and returns application role connection metadata.
 // appID    : ID of the application
 // metadata : New metadata
-func (s *Session) ApplicationRoleConnectionMetadataUpdate(appID string, metadata []*ApplicationRoleConnectionMetadata) (st []*ApplicationRoleConnectionMetadata, err error) {
+func (s *Session) ApplicationRoleConnectionMetadataUpdate(appID string, metadata []*ApplicationRoleConnectionMetadata, options ...RequestOption) (st []*ApplicationRoleConnectionMetadata, err error) {
 	endpoint := EndpointApplicationRoleConnectionMetadata(appID)
 	var body []byte
-	body, err = s.RequestWithBucketID("PUT", endpoint, metadata, endpoint)
+	body, err = s.RequestWithBucketID("PUT", endpoint, metadata, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3743,10 +3974,10 @@ func (s *Session) ApplicationRoleConnectionMetadataUpdate(appID string, metadata
 
 // UserApplicationRoleConnection returns user role connection to the specified application.
 // appID : ID of the application
-func (s *Session) UserApplicationRoleConnection(appID string) (st *ApplicationRoleConnection, err error) {
+func (s *Session) UserApplicationRoleConnection(appID string, options ...RequestOption) (st *ApplicationRoleConnection, err error) {
 	endpoint := EndpointUserApplicationRoleConnection(appID)
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	body, err = s.RequestWithBucketID("GET", endpoint, nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3758,10 +3989,10 @@ func (s *Session) UserApplicationRoleConnection(appID string) (st *ApplicationRo
 // UserApplicationRoleConnectionUpdate updates and returns user role connection to the specified application.
 // appID      : ID of the application
 // connection : New ApplicationRoleConnection data
-func (s *Session) UserApplicationRoleConnectionUpdate(appID string, rconn *ApplicationRoleConnection) (st *ApplicationRoleConnection, err error) {
+func (s *Session) UserApplicationRoleConnectionUpdate(appID string, rconn *ApplicationRoleConnection, options ...RequestOption) (st *ApplicationRoleConnection, err error) {
 	endpoint := EndpointUserApplicationRoleConnection(appID)
 	var body []byte
-	body, err = s.RequestWithBucketID("PUT", endpoint, rconn, endpoint)
+	body, err = s.RequestWithBucketID("PUT", endpoint, rconn, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -3775,13 +4006,13 @@ func (s *Session) UserApplicationRoleConnectionUpdate(appID string, rconn *Appli
 // ----------------------------------------------------------------------
 
 // LoginSessions returns an array of currently logged in sessions for the current user.
-func (s *Session) LoginSessions() (sessions []*LoginSession, err error) {
+func (s *Session) LoginSessions(options ...RequestOption) (sessions []*LoginSession, err error) {
 	var sessionData struct {
 		UserSessions []*LoginSession `json:"user_sessions"`
 	}
 
 	var body []byte
-	body, err = s.RequestWithBucketID("GET", EndpointSessions, nil, EndpointSessions)
+	body, err = s.RequestWithBucketID("GET", EndpointSessions, nil, EndpointSessions, options...)
 	if err != nil {
 		return
 	}
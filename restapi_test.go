@@ -1,8 +1,19 @@
 package discordgo
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	http "github.com/bogdanfinn/fhttp"
+	"github.com/lb-selfbot/discordgo/recorder"
 )
 
 //////////////////////////////////////////////////////////////////////////////
@@ -235,3 +246,200 @@ func Test_unmarshal(t *testing.T) {
 		t.Errorf("Unexpected error type: %T", err)
 	}
 }
+
+func TestRequestWithContext(t *testing.T) {
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusTooManyRequests)
+		w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 10, "global": false}`))
+	}))
+	defer srv.Close()
+
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	sent := time.Now()
+	_, err = s.Request("GET", srv.URL+"/users/@me", nil, WithContext(ctx))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Request returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	if time.Since(sent) >= time.Second {
+		t.Error("Request did not stop on cancellation, took:", time.Since(sent))
+	}
+}
+
+func TestRESTErrorCode(t *testing.T) {
+	body := []byte(`{"message": "Unknown Message", "code": 10008}`)
+	var err error = newRestError(nil, &http.Response{Status: "404 Not Found", StatusCode: 404}, body)
+
+	if !errors.Is(err, ErrCodeUnknownMessage) {
+		t.Error("expected error to match ErrCodeUnknownMessage")
+	}
+	if errors.Is(err, ErrCodeUnknownChannel) {
+		t.Error("expected error not to match ErrCodeUnknownChannel")
+	}
+	if !IsUnknownResource(err) {
+		t.Error("expected IsUnknownResource to be true")
+	}
+	if IsMissingPermissions(err) {
+		t.Error("expected IsMissingPermissions to be false")
+	}
+
+	if code, ok := ErrorCodeOf(fmt.Errorf("wrapped: %w", err)); !ok || code != ErrCodeUnknownMessage {
+		t.Errorf("ErrorCodeOf returned %d, %v", code, ok)
+	}
+	if _, ok := ErrorCodeOf(ErrJSONUnmarshal); ok {
+		t.Error("expected ErrorCodeOf to fail for a non REST error")
+	}
+}
+
+func TestRESTErrorFieldErrors(t *testing.T) {
+	body := []byte(`{
+		"code": 50035,
+		"message": "Invalid Form Body",
+		"errors": {
+			"content": {"_errors": [{"code": "BASE_TYPE_MAX_LENGTH", "message": "Must be 2000 or fewer in length."}]},
+			"embeds": {"0": {"title": {"_errors": [{"code": "BASE_TYPE_REQUIRED", "message": "This field is required"}]}}}
+		}
+	}`)
+	restErr := newRestError(nil, &http.Response{Status: "400 Bad Request", StatusCode: 400}, body)
+
+	if !IsInvalidFormBody(restErr) {
+		t.Fatal("expected IsInvalidFormBody to be true")
+	}
+
+	fields := restErr.FieldErrors()
+	if fields == nil {
+		t.Fatal("expected field errors to be parsed")
+	}
+
+	if errs := fields.Get("embeds", "0", "title"); len(errs) != 1 || errs[0].Code != "BASE_TYPE_REQUIRED" {
+		t.Errorf("unexpected errors for embeds.0.title: %+v", errs)
+	}
+	if errs := fields.Get("embeds", "1", "title"); errs != nil {
+		t.Errorf("expected no errors for embeds.1.title, got %+v", errs)
+	}
+
+	flat := fields.Flatten()
+	if len(flat) != 2 || len(flat["content"]) != 1 || len(flat["embeds.0.title"]) != 1 {
+		t.Errorf("unexpected flattened errors: %+v", flat)
+	}
+
+	expected := "content: Must be 2000 or fewer in length. (BASE_TYPE_MAX_LENGTH)\nembeds.0.title: This field is required (BASE_TYPE_REQUIRED)"
+	if s := fields.String(); s != expected {
+		t.Errorf("expected %q, got %q", expected, s)
+	}
+}
+
+func TestUserReplay(t *testing.T) {
+	s := newReplaySession(t)
+
+	u, err := s.User("@me")
+	if err != nil {
+		t.Fatalf("User returned error: %+v", err)
+	}
+	if u.ID != "80351110224678912" || u.Username != "Nelly" {
+		t.Errorf("unexpected user %+v", u)
+	}
+
+	_, err = s.User("1")
+	if !errors.Is(err, ErrCodeUnknownUser) {
+		t.Errorf("expected %v, got %v", ErrCodeUnknownUser, err)
+	}
+}
+
+func TestChannelMessageSendReplay(t *testing.T) {
+	s := newReplaySession(t)
+
+	m, err := s.ChannelMessageSend("81384788765712384", "Hello, World!")
+	if err != nil {
+		t.Fatalf("ChannelMessageSend returned error: %+v", err)
+	}
+	if m.ID != "162701077035089920" || m.Content != "Hello, World!" {
+		t.Errorf("unexpected message %+v", m)
+	}
+}
+
+// TestReplayRoundTrip records the requests of a session through the recorder
+// and replays them, as the fixtures of the replay tests are written by hand.
+func TestReplayRoundTrip(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/users/@me" {
+			w.WriteHeader(nethttp.StatusNotFound)
+			w.Write([]byte(`{"message": "Unknown User", "code": 10013}`))
+			return
+		}
+		w.Write([]byte(`{"id": "80351110224678912", "username": "Nelly"}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	run := func(mode recorder.Mode) {
+		s, err := New("secret-token")
+		if err != nil {
+			t.Fatalf("New returned error: %+v", err)
+		}
+		rec, err := recorder.New(path, mode, s.Client)
+		if err != nil {
+			t.Fatalf("recorder.New returned error: %+v", err)
+		}
+		s.Client = rec
+
+		b, err := s.Request("GET", srv.URL+"/users/@me", nil)
+		if err != nil {
+			t.Fatalf("Request returned error: %+v", err)
+		}
+		var u User
+		if err = unmarshal(b, &u); err != nil {
+			t.Fatalf("unmarshal returned error: %+v", err)
+		}
+		if u.ID != "80351110224678912" || u.Username != "Nelly" {
+			t.Errorf("unexpected user %+v", u)
+		}
+
+		_, err = s.Request("GET", srv.URL+"/users/1", nil)
+		if !errors.Is(err, ErrCodeUnknownUser) {
+			t.Errorf("expected %v, got %v", ErrCodeUnknownUser, err)
+		}
+
+		if err = rec.Stop(); err != nil {
+			t.Fatalf("Stop returned error: %+v", err)
+		}
+	}
+
+	run(recorder.ModeRecord)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %+v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("authorization header was not scrubbed from the fixture")
+	}
+
+	rec, err := recorder.New(path, recorder.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("recorder.New returned error: %+v", err)
+	}
+	for _, i := range rec.Interactions() {
+		if i.Request.Header.Get("Authorization") != recorder.Redacted || i.Response.Header.Get("Content-Type") == "" {
+			t.Errorf("headers not recorded for %s %s", i.Request.Method, i.Request.URL)
+		}
+	}
+
+	srv.Close()
+	run(recorder.ModeReplay)
+
+	if hits != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %d", hits)
+	}
+}
@@ -0,0 +1,112 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the middleware chain every REST request is sent
+// through, along with the built-in middleware.
+
+package discordgo
+
+import (
+	"errors"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// ErrRESTNoResponse is returned by REST requests when a middleware returns
+// neither a response nor an error.
+var ErrRESTNoResponse = errors.New("REST middleware returned no response")
+
+// RESTRequest is a single REST request as seen by REST middleware.
+type RESTRequest struct {
+	// Request is the outgoing HTTP request. Its body is replaced with
+	// Payload every time the request is sent.
+	Request *http.Request
+
+	// Payload is the raw request body.
+	Payload []byte
+
+	// Config is the configuration the request was made with.
+	Config *RequestConfig
+
+	// Attempt is the number of times the request has been retried.
+	Attempt int
+}
+
+// RESTResponse is the response to a RESTRequest. The response body has
+// already been read and closed, its contents are held in Body.
+type RESTResponse struct {
+	Response *http.Response
+	Body     []byte
+}
+
+// RESTHandler sends a RESTRequest and returns its response.
+type RESTHandler func(req *RESTRequest) (*RESTResponse, error)
+
+// RESTMiddleware intercepts a REST request made by a Session.
+// It may modify the request before passing it on to next, and inspect or
+// modify the response before it is returned. A middleware may also respond
+// without calling next at all, or call next more than once to retry.
+type RESTMiddleware func(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error)
+
+// DefaultRESTMiddleware is the REST middleware chain used by sessions
+// created with New, and by sessions whose RESTMiddleware is nil.
+var DefaultRESTMiddleware = []RESTMiddleware{
+	RESTHeaderMiddleware,
+	RESTBadGatewayRetryMiddleware,
+}
+
+// RESTHeaderMiddleware sets the session default headers on every request.
+// Headers given with request options take precedence over them.
+func RESTHeaderMiddleware(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error) {
+	for k, v := range s.Headers {
+		req.Request.Header.Set(k, v)
+	}
+
+	for k, v := range req.Config.Headers {
+		req.Request.Header.Set(k, v)
+	}
+
+	return next(req)
+}
+
+// RESTBadGatewayRetryMiddleware resends requests that failed with a
+// 502 Bad Gateway, until the request succeeds or MaxRestRetries is exceeded.
+func RESTBadGatewayRetryMiddleware(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error) {
+	for {
+		resp, err := next(req)
+		if err != nil {
+			return resp, err
+		}
+		if resp == nil || resp.Response == nil {
+			return nil, ErrRESTNoResponse
+		}
+		if resp.Response.StatusCode != http.StatusBadGateway || req.Attempt > req.Config.MaxRestRetries {
+			return resp, nil
+		}
+
+		s.log(LogInformational, "%s Failed (%s), Retrying...", req.Request.URL, resp.Response.Status)
+		req.Attempt++
+	}
+}
+
+// restHandler wraps the given handler in the session's REST middleware.
+// The first middleware in the chain is the first to see the request.
+func (s *Session) restHandler(h RESTHandler) RESTHandler {
+	middleware := s.RESTMiddleware
+	if middleware == nil {
+		middleware = DefaultRESTMiddleware
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		mw, next := middleware[i], h
+		h = func(req *RESTRequest) (*RESTResponse, error) {
+			return mw(s, req, next)
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,161 @@
+package discordgo
+
+import (
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+func TestRESTMiddlewareOrder(t *testing.T) {
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Write([]byte(r.Header.Get("X-Trace")))
+	}))
+	defer srv.Close()
+
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	trace := func(name string) RESTMiddleware {
+		return func(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error) {
+			req.Request.Header.Set("X-Trace", req.Request.Header.Get("X-Trace")+name)
+			resp, err := next(req)
+			if err == nil {
+				resp.Body = append(resp.Body, name...)
+			}
+			return resp, err
+		}
+	}
+	s.RESTMiddleware = append(s.RESTMiddleware, trace("a"), trace("b"))
+
+	body, err := s.Request("GET", srv.URL, nil)
+	if err != nil {
+		t.Fatalf("Request returned error: %+v", err)
+	}
+
+	if string(body) != "abba" {
+		t.Errorf("expected body %q, got %q", "abba", body)
+	}
+}
+
+func TestRESTMiddlewareShortCircuit(t *testing.T) {
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	s.RESTMiddleware = []RESTMiddleware{
+		func(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error) {
+			return &RESTResponse{
+				Response: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+				Body:     []byte("cached"),
+			}, nil
+		},
+	}
+
+	// The bucket must be released even though the request was never sent,
+	// otherwise the second request would block forever.
+	for i := 0; i < 2; i++ {
+		body, err := s.Request("GET", "http://127.0.0.1:0/cached", nil)
+		if err != nil {
+			t.Fatalf("Request returned error: %+v", err)
+		}
+		if string(body) != "cached" {
+			t.Errorf("expected body %q, got %q", "cached", body)
+		}
+	}
+}
+
+func TestRESTMiddlewareNoResponse(t *testing.T) {
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	for _, resp := range []*RESTResponse{nil, {Body: []byte("body")}} {
+		none := func(s *Session, req *RESTRequest, next RESTHandler) (*RESTResponse, error) {
+			return resp, nil
+		}
+
+		// The default middleware does not panic on the missing response.
+		for _, chain := range [][]RESTMiddleware{{none}, append(slices.Clone(DefaultRESTMiddleware), none)} {
+			s.RESTMiddleware = chain
+			if _, err := s.Request("GET", "http://127.0.0.1:0/none", nil); !errors.Is(err, ErrRESTNoResponse) {
+				t.Errorf("expected ErrRESTNoResponse for %+v with %d middleware, got %v", resp, len(chain), err)
+			}
+		}
+	}
+}
+
+func TestRESTMiddlewareDefault(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if atomic.AddInt32(&hits, 1) < 2 {
+			w.WriteHeader(nethttp.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(r.Header.Get("X-Default")))
+	}))
+	defer srv.Close()
+
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	// Without middleware, the session headers are still set and 502s are
+	// still retried.
+	s.RESTMiddleware = nil
+	s.Headers = map[string]string{"X-Default": "header"}
+
+	body, err := s.Request("GET", srv.URL, nil)
+	if err != nil {
+		t.Fatalf("Request returned error: %+v", err)
+	}
+	if string(body) != "header" || hits != 2 {
+		t.Errorf("expected the header after 2 attempts, got %q after %d", body, hits)
+	}
+}
+
+func TestRESTBadGatewayRetryMiddleware(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.WriteHeader(nethttp.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	if _, err = s.Request("GET", srv.URL, nil); err != nil {
+		t.Fatalf("Request returned error: %+v", err)
+	}
+	if hits != 3 {
+		t.Errorf("expected 3 attempts, got %d", hits)
+	}
+
+	// Without the retry middleware the first 502 is returned as an error.
+	atomic.StoreInt32(&hits, 0)
+	s.RESTMiddleware = []RESTMiddleware{RESTHeaderMiddleware}
+
+	_, err = s.Request("GET", srv.URL, nil)
+	var restErr *RESTError
+	if !errors.As(err, &restErr) || restErr.Response.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a 502 RESTError, got %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 attempt, got %d", hits)
+	}
+}
@@ -14,8 +14,10 @@ package discordgo
 
 import (
 	"errors"
+	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 )
@@ -49,15 +51,44 @@ type State struct {
 	TrackRoles         bool
 	TrackVoice         bool
 	TrackPresences     bool
+	TrackReadStates    bool
 
-	guildMap   map[string]*Guild
-	channelMap map[string]*Channel
-	memberMap  map[string]map[string]*Member
+	// Store stores the tracked guilds, channels, members and so forth. They
+	// are kept in memory when it is nil. Ready.Guilds and
+	// Ready.PrivateChannels are only kept up to date by the in-memory store.
+	// It must not be changed once the session is opened.
+	Store StateStore
+
+	// MessageCache and MemberCache bound the messages and the members
+	// cached, see CacheStats.
+	MessageCache MessageCachePolicy
+	MemberCache  MemberCachePolicy
+
+	memory *memoryStateStore
+
+	// sequence is the sequence number of the last gateway event applied
+	// to the state, and restored whether the state was restored from a
+	// snapshot whose session was not resumed yet.
+	sequence int64
+	restored bool
+
+	// readStates indexes Ready.ReadState by channel.
+	readStates map[string]*ReadState
+
+	messageLRU cacheLRU
+	memberLRU  cacheLRU
+
+	// keptMembers are the members MemberCache keeps. They are tracked
+	// outside of memberLRU, so they are neither aged nor evicted.
+	keptMembers map[cacheKey]struct{}
+
+	evictedMessages uint64
+	evictedMembers  uint64
 }
 
 // NewState creates an empty state.
 func NewState() *State {
-	return &State{
+	s := &State{
 		Ready: Ready{
 			PrivateChannels: []*Channel{},
 			Guilds:          []*Guild{},
@@ -70,18 +101,18 @@ func NewState() *State {
 		TrackRoles:         true,
 		TrackVoice:         true,
 		TrackPresences:     true,
-		guildMap:           make(map[string]*Guild),
-		channelMap:         make(map[string]*Channel),
-		memberMap:          make(map[string]map[string]*Member),
+		TrackReadStates:    true,
 	}
+	s.memory = newMemoryStateStore(&s.Ready)
+	return s
 }
 
-func (s *State) createMemberMap(guild *Guild) {
-	members := make(map[string]*Member)
-	for _, m := range guild.Members {
-		members[m.User.ID] = m
+// store returns the StateStore of the state.
+func (s *State) store() StateStore {
+	if s.Store != nil {
+		return s.Store
 	}
-	s.memberMap[guild.ID] = members
+	return s.memory
 }
 
 // GuildAdd adds a guild to the current world state, or
@@ -94,25 +125,10 @@ func (s *State) GuildAdd(guild *Guild) error {
 	s.Lock()
 	defer s.Unlock()
 
-	// Update the channels to point to the right guild, adding them to the channelMap as we go
-	for _, c := range guild.Channels {
-		s.channelMap[c.ID] = c
-	}
-
-	// Add all the threads to the state in case of thread sync list.
-	for _, t := range guild.Threads {
-		s.channelMap[t.ID] = t
-	}
-
-	// If this guild contains a new member slice, we must regenerate the member map so the pointers stay valid
-	if guild.Members != nil {
-		s.createMemberMap(guild)
-	} else if _, ok := s.memberMap[guild.ID]; !ok {
-		// Even if we have no new member slice, we still initialize the member map for this guild if it doesn't exist
-		s.memberMap[guild.ID] = make(map[string]*Member)
-	}
+	members := guild.Members
 
-	if g, ok := s.guildMap[guild.ID]; ok {
+	g, err := s.store().Guild(guild.ID)
+	if err == nil {
 		// We are about to replace `g` in the state with `guild`, but first we need to
 		// make sure we preserve any fields that the `guild` doesn't contain from `g`.
 		if guild.MemberCount == 0 {
@@ -139,13 +155,15 @@ func (s *State) GuildAdd(guild *Guild) error {
 		if guild.VoiceStates == nil {
 			guild.VoiceStates = g.VoiceStates
 		}
-		*g = *guild
-		return nil
+	} else if !errors.Is(err, ErrStateNotFound) {
+		return err
 	}
 
-	s.Guilds = append(s.Guilds, guild)
-	s.guildMap[guild.ID] = guild
+	if err = s.store().GuildPut(guild); err != nil {
+		return err
+	}
 
+	s.membersSeen(guild.ID, members)
 	return nil
 }
 
@@ -155,23 +173,19 @@ func (s *State) GuildRemove(guild *Guild) error {
 		return ErrNilState
 	}
 
-	_, err := s.Guild(guild.ID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	delete(s.guildMap, guild.ID)
+	if err := s.store().GuildDelete(guild.ID); err != nil {
+		return err
+	}
 
-	for i, g := range s.Guilds {
-		if g.ID == guild.ID {
-			s.Guilds = append(s.Guilds[:i], s.Guilds[i+1:]...)
-			return nil
+	s.memberLRU.removeParent(guild.ID)
+	for key := range s.keptMembers {
+		if key.parentID == guild.ID {
+			delete(s.keptMembers, key)
 		}
 	}
-
 	return nil
 }
 
@@ -188,66 +202,80 @@ func (s *State) Guild(guildID string) (*Guild, error) {
 	s.RLock()
 	defer s.RUnlock()
 
-	if g, ok := s.guildMap[guildID]; ok {
-		return g, nil
+	return s.store().Guild(guildID)
+}
+
+// guildMemberCountAdd adds n to the member count of a guild.
+func (s *State) guildMemberCountAdd(guildID string, n int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	guild, err := s.store().Guild(guildID)
+	if err != nil {
+		return err
 	}
 
-	return nil, ErrStateNotFound
+	guild.MemberCount += n
+	return s.store().GuildPut(guild)
 }
 
 func (s *State) presenceAdd(guildID string, presence *Presence) error {
-	guild, ok := s.guildMap[guildID]
-	if !ok {
-		return ErrStateNotFound
-	}
-
-	for _, savedPresence := range guild.Presences {
-		if savedPresence.User.ID != presence.User.ID {
-			continue
+	savedPresence, err := s.store().Presence(guildID, presence.User.ID)
+	if errors.Is(err, ErrStateNotFound) {
+		if err = s.store().PresencePut(guildID, presence); err != nil {
+			return err
 		}
 
-		// Update status
-		savedPresence.Activities = presence.Activities
-		if presence.Status != "" {
-			savedPresence.Status = presence.Status
-		}
-		if presence.ClientStatus.Desktop != "" {
-			savedPresence.ClientStatus.Desktop = presence.ClientStatus.Desktop
-		}
-		if presence.ClientStatus.Mobile != "" {
-			savedPresence.ClientStatus.Mobile = presence.ClientStatus.Mobile
-		}
-		if presence.ClientStatus.Web != "" {
-			savedPresence.ClientStatus.Web = presence.ClientStatus.Web
-		}
-		if presence.ClientStatus.Embedded != "" {
-			savedPresence.ClientStatus.Embedded = presence.ClientStatus.Embedded
-		}
+		s.memberSeen(guildID, presence.User.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 
-		// Update the optionally sent user information
-		// ID Is a mandatory field so you should not need to check if it is empty
-		savedPresence.User.ID = presence.User.ID
+	// Update status
+	savedPresence.Activities = presence.Activities
+	if presence.Status != "" {
+		savedPresence.Status = presence.Status
+	}
+	if presence.ClientStatus.Desktop != "" {
+		savedPresence.ClientStatus.Desktop = presence.ClientStatus.Desktop
+	}
+	if presence.ClientStatus.Mobile != "" {
+		savedPresence.ClientStatus.Mobile = presence.ClientStatus.Mobile
+	}
+	if presence.ClientStatus.Web != "" {
+		savedPresence.ClientStatus.Web = presence.ClientStatus.Web
+	}
+	if presence.ClientStatus.Embedded != "" {
+		savedPresence.ClientStatus.Embedded = presence.ClientStatus.Embedded
+	}
 
-		if presence.User.Avatar != "" {
-			savedPresence.User.Avatar = presence.User.Avatar
-		}
-		if presence.User.Discriminator != "" {
-			savedPresence.User.Discriminator = presence.User.Discriminator
-		}
-		if presence.User.Email != "" {
-			savedPresence.User.Email = presence.User.Email
-		}
-		if presence.User.Token != "" {
-			savedPresence.User.Token = presence.User.Token
-		}
-		if presence.User.Username != "" {
-			savedPresence.User.Username = presence.User.Username
-		}
+	// Update the optionally sent user information
+	// ID Is a mandatory field so you should not need to check if it is empty
+	savedPresence.User.ID = presence.User.ID
 
-		return nil
+	if presence.User.Avatar != "" {
+		savedPresence.User.Avatar = presence.User.Avatar
+	}
+	if presence.User.Discriminator != "" {
+		savedPresence.User.Discriminator = presence.User.Discriminator
+	}
+	if presence.User.Email != "" {
+		savedPresence.User.Email = presence.User.Email
+	}
+	if presence.User.Token != "" {
+		savedPresence.User.Token = presence.User.Token
+	}
+	if presence.User.Username != "" {
+		savedPresence.User.Username = presence.User.Username
 	}
 
-	guild.Presences = append(guild.Presences, presence)
+	if err = s.store().PresencePut(guildID, savedPresence); err != nil {
+		return err
+	}
+
+	s.memberSeen(guildID, presence.User.ID)
 	return nil
 }
 
@@ -270,22 +298,15 @@ func (s *State) PresenceRemove(guildID string, presence *Presence) error {
 		return ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	for i, p := range guild.Presences {
-		if p.User.ID == presence.User.ID {
-			guild.Presences = append(guild.Presences[:i], guild.Presences[i+1:]...)
-			return nil
-		}
+	if err := s.store().PresenceDelete(guildID, presence.User.ID); err != nil {
+		return err
 	}
 
-	return ErrStateNotFound
+	s.memberForgotten(guildID, presence.User.ID)
+	return nil
 }
 
 // Presence gets a presence by ID from a guild.
@@ -294,18 +315,10 @@ func (s *State) Presence(guildID, userID string) (*Presence, error) {
 		return nil, ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, p := range guild.Presences {
-		if p.User.ID == userID {
-			return p, nil
-		}
-	}
+	s.RLock()
+	defer s.RUnlock()
 
-	return nil, ErrStateNotFound
+	return s.store().Presence(guildID, userID)
 }
 
 // GetUser gets a user by ID.
@@ -317,15 +330,18 @@ func (s *State) GetUser(userID string) (*User, error) {
 	s.RLock()
 	defer s.RUnlock()
 
-	if userID == s.User.ID {
+	if s.User != nil && userID == s.User.ID {
 		return s.User, nil
 	}
 
-	for _, guild := range s.guildMap {
-		for _, member := range guild.Members {
-			if member.User.ID == userID {
-				return member.User, nil
-			}
+	guilds, err := s.store().Guilds()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, guild := range guilds {
+		if member, err := s.store().Member(guild.ID, userID); err == nil {
+			return member.User, nil
 		}
 	}
 	return nil, ErrStateNotFound
@@ -334,28 +350,22 @@ func (s *State) GetUser(userID string) (*User, error) {
 // TODO: Consider moving Guild state update methods onto *Guild.
 
 func (s *State) memberAdd(member *Member) error {
-	guild, ok := s.guildMap[member.GuildID]
-	if !ok {
-		return ErrStateNotFound
-	}
-
-	members, ok := s.memberMap[member.GuildID]
-	if !ok {
-		return ErrStateNotFound
-	}
-
-	m, ok := members[member.User.ID]
-	if !ok {
-		members[member.User.ID] = member
-		guild.Members = append(guild.Members, member)
-	} else {
+	m, err := s.store().Member(member.GuildID, member.User.ID)
+	if err == nil {
 		// We are about to replace `m` in the state with `member`, but first we need to
 		// make sure we preserve any fields that the `member` doesn't contain from `m`.
 		if member.JoinedAt.IsZero() {
 			member.JoinedAt = m.JoinedAt
 		}
-		*m = *member
+	} else if !errors.Is(err, ErrStateNotFound) {
+		return err
 	}
+
+	if err = s.store().MemberPut(member); err != nil {
+		return err
+	}
+
+	s.memberSeen(member.GuildID, member.User.ID)
 	return nil
 }
 
@@ -378,33 +388,15 @@ func (s *State) MemberRemove(member *Member) error {
 		return ErrNilState
 	}
 
-	guild, err := s.Guild(member.GuildID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	members, ok := s.memberMap[member.GuildID]
-	if !ok {
-		return ErrStateNotFound
-	}
-
-	_, ok = members[member.User.ID]
-	if !ok {
-		return ErrStateNotFound
-	}
-	delete(members, member.User.ID)
-
-	for i, m := range guild.Members {
-		if m.User.ID == member.User.ID {
-			guild.Members = append(guild.Members[:i], guild.Members[i+1:]...)
-			return nil
-		}
+	if err := s.store().MemberDelete(member.GuildID, member.User.ID); err != nil {
+		return err
 	}
 
-	return ErrStateNotFound
+	s.memberForgotten(member.GuildID, member.User.ID)
+	return nil
 }
 
 // Member gets a member by ID from a guild.
@@ -416,25 +408,13 @@ func (s *State) Member(guildID, userID string) (*Member, error) {
 	s.RLock()
 	defer s.RUnlock()
 
-	members, ok := s.memberMap[guildID]
-	if !ok {
-		return nil, ErrStateNotFound
-	}
-
-	g, ok := s.guildMap[guildID]
-	if !ok {
-		return nil, ErrStateNotFound
-	}
-
-	m, ok := members[userID]
-	if !ok {
-		return nil, ErrStateNotFound
+	m, err := s.store().Member(guildID, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, p := range g.Presences {
-		if p.User.ID == userID {
-			m.Presence = p
-		}
+	if p, err := s.store().Presence(guildID, userID); err == nil {
+		m.Presence = p
 	}
 
 	return m, nil
@@ -447,23 +427,10 @@ func (s *State) RoleAdd(guildID string, role *Role) error {
 		return ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	for i, r := range guild.Roles {
-		if r.ID == role.ID {
-			guild.Roles[i] = role
-			return nil
-		}
-	}
-
-	guild.Roles = append(guild.Roles, role)
-	return nil
+	return s.store().RolePut(guildID, role)
 }
 
 // RoleRemove removes a role from current world state by ID.
@@ -472,22 +439,10 @@ func (s *State) RoleRemove(guildID, roleID string) error {
 		return ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	for i, r := range guild.Roles {
-		if r.ID == roleID {
-			guild.Roles = append(guild.Roles[:i], guild.Roles[i+1:]...)
-			return nil
-		}
-	}
-
-	return ErrStateNotFound
+	return s.store().RoleDelete(guildID, roleID)
 }
 
 // Role gets a role by ID from a guild.
@@ -496,21 +451,10 @@ func (s *State) Role(guildID, roleID string) (*Role, error) {
 		return nil, ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return nil, err
-	}
-
 	s.RLock()
 	defer s.RUnlock()
 
-	for _, r := range guild.Roles {
-		if r.ID == roleID {
-			return r, nil
-		}
-	}
-
-	return nil, ErrStateNotFound
+	return s.store().Role(guildID, roleID)
 }
 
 // ChannelAdd adds a channel to the current world state, or
@@ -526,7 +470,8 @@ func (s *State) ChannelAdd(channel *Channel) error {
 	defer s.Unlock()
 
 	// If the channel exists, replace it
-	if c, ok := s.channelMap[channel.ID]; ok {
+	c, err := s.store().Channel(channel.ID)
+	if err == nil {
 		if channel.Messages == nil {
 			channel.Messages = c.Messages
 		}
@@ -536,31 +481,11 @@ func (s *State) ChannelAdd(channel *Channel) error {
 		if channel.ThreadMetadata == nil {
 			channel.ThreadMetadata = c.ThreadMetadata
 		}
-
-		*c = *channel
-		return nil
-	}
-
-	if channel.Type == ChannelTypeDM || channel.Type == ChannelTypeGroupDM {
-		s.PrivateChannels = append(s.PrivateChannels, channel)
-		s.channelMap[channel.ID] = channel
-		return nil
-	}
-
-	guild, ok := s.guildMap[channel.GuildID]
-	if !ok {
-		return ErrStateNotFound
-	}
-
-	if channel.IsThread() {
-		guild.Threads = append(guild.Threads, channel)
-	} else {
-		guild.Channels = append(guild.Channels, channel)
+	} else if !errors.Is(err, ErrStateNotFound) {
+		return err
 	}
 
-	s.channelMap[channel.ID] = channel
-
-	return nil
+	return s.store().ChannelPut(channel)
 }
 
 // ChannelRemove removes a channel from current world state.
@@ -569,92 +494,59 @@ func (s *State) ChannelRemove(channel *Channel) error {
 		return ErrNilState
 	}
 
-	_, err := s.Channel(channel.ID)
-	if err != nil {
-		return err
-	}
-
-	if channel.Type == ChannelTypeDM || channel.Type == ChannelTypeGroupDM {
-		s.Lock()
-		defer s.Unlock()
-
-		for i, c := range s.PrivateChannels {
-			if c.ID == channel.ID {
-				s.PrivateChannels = append(s.PrivateChannels[:i], s.PrivateChannels[i+1:]...)
-				break
-			}
-		}
-		delete(s.channelMap, channel.ID)
-		return nil
-	}
-
-	guild, err := s.Guild(channel.GuildID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	if channel.IsThread() {
-		for i, t := range guild.Threads {
-			if t.ID == channel.ID {
-				guild.Threads = append(guild.Threads[:i], guild.Threads[i+1:]...)
-				break
-			}
-		}
-	} else {
-		for i, c := range guild.Channels {
-			if c.ID == channel.ID {
-				guild.Channels = append(guild.Channels[:i], guild.Channels[i+1:]...)
-				break
-			}
-		}
+	if err := s.store().ChannelDelete(channel.ID); err != nil {
+		return err
 	}
 
-	delete(s.channelMap, channel.ID)
-
+	s.messageLRU.removeParent(channel.ID)
 	return nil
 }
 
 // ThreadListSync syncs guild threads with provided ones.
 func (s *State) ThreadListSync(tls *ThreadListSync) error {
-	guild, err := s.Guild(tls.GuildID)
-	if err != nil {
-		return err
+	if s == nil {
+		return ErrNilState
 	}
 
 	s.Lock()
 	defer s.Unlock()
 
+	guild, err := s.store().Guild(tls.GuildID)
+	if err != nil {
+		return err
+	}
+
 	// This algorithm filters out archived or
 	// threads which are children of channels in channelIDs
 	// and then it adds all synced threads to guild threads and cache
-	index := 0
-outer:
+	var removed []string
 	for _, t := range guild.Threads {
-		if !t.ThreadMetadata.Archived && tls.ChannelIDs != nil {
-			for _, v := range tls.ChannelIDs {
-				if t.ParentID == v {
-					delete(s.channelMap, t.ID)
-					continue outer
-				}
-			}
-			guild.Threads[index] = t
-			index++
-		} else {
-			delete(s.channelMap, t.ID)
+		if t.ThreadMetadata.Archived || tls.ChannelIDs == nil || slices.Contains(tls.ChannelIDs, t.ParentID) {
+			removed = append(removed, t.ID)
+		}
+	}
+	for _, id := range removed {
+		if err = s.store().ChannelDelete(id); err != nil {
+			return err
 		}
+		s.messageLRU.removeParent(id)
 	}
-	guild.Threads = guild.Threads[:index]
+
 	for _, t := range tls.Threads {
-		s.channelMap[t.ID] = t
-		guild.Threads = append(guild.Threads, t)
+		if err = s.store().ChannelPut(t); err != nil {
+			return err
+		}
 	}
 
 	for _, m := range tls.Members {
-		if c, ok := s.channelMap[m.ID]; ok {
+		if c, err := s.store().Channel(m.ID); err == nil {
 			c.Member = m
+			if err = s.store().ChannelPut(c); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -663,24 +555,27 @@ outer:
 
 // ThreadMembersUpdate updates thread members list
 func (s *State) ThreadMembersUpdate(tmu *ThreadMembersUpdate) error {
-	thread, err := s.Channel(tmu.ID)
-	if err != nil {
-		return err
+	if s == nil {
+		return ErrNilState
 	}
+
 	s.Lock()
 	defer s.Unlock()
 
-	for idx, member := range thread.Members {
-		for _, removedMember := range tmu.RemovedMembers {
-			if member.ID == removedMember {
-				thread.Members = append(thread.Members[:idx], thread.Members[idx+1:]...)
-				break
-			}
+	thread, err := s.store().Channel(tmu.ID)
+	if err != nil {
+		return err
+	}
+
+	var members []*ThreadMember
+	for _, member := range thread.Members {
+		if !slices.Contains(tmu.RemovedMembers, member.UserID) {
+			members = append(members, member)
 		}
 	}
 
 	for _, addedMember := range tmu.AddedMembers {
-		thread.Members = append(thread.Members, addedMember.ThreadMember)
+		members = append(members, addedMember.ThreadMember)
 		if addedMember.Member != nil {
 			err = s.memberAdd(addedMember.Member)
 			if err != nil {
@@ -694,20 +589,28 @@ func (s *State) ThreadMembersUpdate(tmu *ThreadMembersUpdate) error {
 			}
 		}
 	}
+	thread.Members = members
 	thread.MemberCount = tmu.MemberCount
 
-	return nil
+	return s.store().ChannelPut(thread)
 }
 
 // ThreadMemberUpdate sets or updates member data for the current user.
 func (s *State) ThreadMemberUpdate(mu *ThreadMemberUpdate) error {
-	thread, err := s.Channel(mu.ID)
+	if s == nil {
+		return ErrNilState
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	thread, err := s.store().Channel(mu.ID)
 	if err != nil {
 		return err
 	}
 
 	thread.Member = mu.ThreadMember
-	return nil
+	return s.store().ChannelPut(thread)
 }
 
 // Channel gets a channel by ID, it will look in all guilds and private channels.
@@ -719,11 +622,7 @@ func (s *State) Channel(channelID string) (*Channel, error) {
 	s.RLock()
 	defer s.RUnlock()
 
-	if c, ok := s.channelMap[channelID]; ok {
-		return c, nil
-	}
-
-	return nil, ErrStateNotFound
+	return s.store().Channel(channelID)
 }
 
 // Emoji returns an emoji for a guild and emoji id.
@@ -732,21 +631,10 @@ func (s *State) Emoji(guildID, emojiID string) (*Emoji, error) {
 		return nil, ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return nil, err
-	}
-
 	s.RLock()
 	defer s.RUnlock()
 
-	for _, e := range guild.Emojis {
-		if e.ID == emojiID {
-			return e, nil
-		}
-	}
-
-	return nil, ErrStateNotFound
+	return s.store().Emoji(guildID, emojiID)
 }
 
 // EmojiAdd adds an emoji to the current world state.
@@ -755,23 +643,10 @@ func (s *State) EmojiAdd(guildID string, emoji *Emoji) error {
 		return ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	for i, e := range guild.Emojis {
-		if e.ID == emoji.ID {
-			guild.Emojis[i] = emoji
-			return nil
-		}
-	}
-
-	guild.Emojis = append(guild.Emojis, emoji)
-	return nil
+	return s.store().EmojiPut(guildID, emoji)
 }
 
 // EmojisAdd adds multiple emojis to the world state.
@@ -795,49 +670,70 @@ func (s *State) MessageAdd(message *Message) error {
 	s.Lock()
 	defer s.Unlock()
 
-	c, ok := s.channelMap[message.ChannelID]
-	if !ok {
-		return ErrStateNotFound
-	}
-
 	// If the message exists, merge in the new message contents.
-	for _, m := range c.Messages {
-		if m.ID == message.ID {
-			if message.Content != "" {
-				m.Content = message.Content
-			}
-			if message.EditedTimestamp != nil {
-				m.EditedTimestamp = message.EditedTimestamp
-			}
-			if message.Mentions != nil {
-				m.Mentions = message.Mentions
-			}
-			if message.Embeds != nil {
-				m.Embeds = message.Embeds
-			}
-			if message.Attachments != nil {
-				m.Attachments = message.Attachments
-			}
-			if !message.Timestamp.IsZero() {
-				m.Timestamp = message.Timestamp
-			}
-			if message.Author != nil {
-				m.Author = message.Author
-			}
-			if message.Components != nil {
-				m.Components = message.Components
-			}
+	m, err := s.store().Message(message.ChannelID, message.ID)
+	if err == nil {
+		if message.Content != "" {
+			m.Content = message.Content
+		}
+		if message.EditedTimestamp != nil {
+			m.EditedTimestamp = message.EditedTimestamp
+		}
+		if message.Mentions != nil {
+			m.Mentions = message.Mentions
+		}
+		if message.Embeds != nil {
+			m.Embeds = message.Embeds
+		}
+		if message.Attachments != nil {
+			m.Attachments = message.Attachments
+		}
+		if !message.Timestamp.IsZero() {
+			m.Timestamp = message.Timestamp
+		}
+		if message.Author != nil {
+			m.Author = message.Author
+		}
+		if message.Components != nil {
+			m.Components = message.Components
+		}
 
-			return nil
+		if err = s.store().MessagePut(m); err != nil {
+			return err
 		}
+
+		s.messageLRU.resize(cacheKey{m.ChannelID, m.ID}, messageSize(m))
+		s.evictMessages(time.Now())
+		return nil
+	}
+	if !errors.Is(err, ErrStateNotFound) {
+		return err
 	}
 
-	c.Messages = append(c.Messages, message)
+	if err = s.store().MessagePut(message); err != nil {
+		return err
+	}
+
+	messages, err := s.store().Messages(message.ChannelID)
+	if err != nil {
+		return err
+	}
 
-	if len(c.Messages) > s.MaxMessageCount {
-		c.Messages = c.Messages[len(c.Messages)-s.MaxMessageCount:]
+	// The oldest messages are removed past MaxMessageCount.
+	var removed []string
+	for i := 0; i < len(messages)-s.MaxMessageCount; i++ {
+		removed = append(removed, messages[i].ID)
+	}
+	for _, id := range removed {
+		if err = s.store().MessageDelete(message.ChannelID, id); err != nil {
+			return err
+		}
+		s.messageLRU.remove(cacheKey{message.ChannelID, id})
 	}
 
+	now := time.Now()
+	s.messageLRU.touch(cacheKey{message.ChannelID, message.ID}, now, messageSize(message))
+	s.evictMessages(now)
 	return nil
 }
 
@@ -852,53 +748,41 @@ func (s *State) MessageRemove(message *Message) error {
 
 // messageRemoveByID removes a message by channelID and messageID from the world state.
 func (s *State) messageRemoveByID(channelID, messageID string) error {
-	c, err := s.Channel(channelID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
-	for i, m := range c.Messages {
-		if m.ID == messageID {
-			c.Messages = append(c.Messages[:i], c.Messages[i+1:]...)
-
-			return nil
-		}
+	if err := s.store().MessageDelete(channelID, messageID); err != nil {
+		return err
 	}
 
-	return ErrStateNotFound
+	s.messageLRU.remove(cacheKey{channelID, messageID})
+	return nil
 }
 
 func (s *State) voiceStateUpdate(update *VoiceStateUpdate) error {
-	guild, err := s.Guild(update.GuildID)
-	if err != nil {
-		return err
-	}
-
 	s.Lock()
 	defer s.Unlock()
 
+	if _, err := s.store().Guild(update.GuildID); err != nil {
+		return err
+	}
+
 	// Handle Leaving Channel
 	if update.ChannelID == "" {
-		for i, state := range guild.VoiceStates {
-			if state.UserID == update.UserID {
-				guild.VoiceStates = append(guild.VoiceStates[:i], guild.VoiceStates[i+1:]...)
-				return nil
-			}
+		err := s.store().VoiceStateDelete(update.GuildID, update.UserID)
+		if errors.Is(err, ErrStateNotFound) {
+			return nil
 		}
-	} else {
-		for i, state := range guild.VoiceStates {
-			if state.UserID == update.UserID {
-				guild.VoiceStates[i] = update.VoiceState
-				return nil
-			}
+		if err == nil {
+			s.memberKeepChanged(update.GuildID, update.UserID)
 		}
-
-		guild.VoiceStates = append(guild.VoiceStates, update.VoiceState)
+		return err
 	}
 
+	if err := s.store().VoiceStatePut(update.GuildID, update.VoiceState); err != nil {
+		return err
+	}
+	s.memberKeepChanged(update.GuildID, update.UserID)
 	return nil
 }
 
@@ -908,18 +792,10 @@ func (s *State) VoiceState(guildID, userID string) (*VoiceState, error) {
 		return nil, ErrNilState
 	}
 
-	guild, err := s.Guild(guildID)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, state := range guild.VoiceStates {
-		if state.UserID == userID {
-			return state, nil
-		}
-	}
+	s.RLock()
+	defer s.RUnlock()
 
-	return nil, ErrStateNotFound
+	return s.store().VoiceState(guildID, userID)
 }
 
 // Message gets a message by channel and message ID.
@@ -928,21 +804,10 @@ func (s *State) Message(channelID, messageID string) (*Message, error) {
 		return nil, ErrNilState
 	}
 
-	c, err := s.Channel(channelID)
-	if err != nil {
-		return nil, err
-	}
-
 	s.RLock()
 	defer s.RUnlock()
 
-	for _, m := range c.Messages {
-		if m.ID == messageID {
-			return m, nil
-		}
-	}
-
-	return nil, ErrStateNotFound
+	return s.store().Message(channelID, messageID)
 }
 
 // OnReady takes a Ready event and updates all internal state.
@@ -954,6 +819,9 @@ func (s *State) onReady(se *Session, r *Ready) (err error) {
 	s.Lock()
 	defer s.Unlock()
 
+	// A new session replaces the session of a restored snapshot.
+	s.restored = false
+
 	// We must track at least the current user for Voice, even
 	// if state is disabled, store the bare essentials.
 	if !se.StateEnabled {
@@ -969,20 +837,27 @@ func (s *State) onReady(se *Session, r *Ready) (err error) {
 	}
 
 	s.Ready = *r
+	s.indexReadStates()
 
-	for _, g := range s.Guilds {
-		s.guildMap[g.ID] = g
-		s.createMemberMap(g)
+	// A new session starts over, so nothing cached for a previous one is
+	// kept.
+	if err = s.store().Reset(); err != nil {
+		return err
+	}
 
-		for _, c := range g.Channels {
-			s.channelMap[c.ID] = c
+	for _, g := range r.Guilds {
+		if err = s.store().GuildPut(g); err != nil {
+			return err
 		}
 	}
 
-	for _, c := range s.PrivateChannels {
-		s.channelMap[c.ID] = c
+	for _, c := range r.PrivateChannels {
+		if err = s.store().ChannelPut(c); err != nil {
+			return err
+		}
 	}
 
+	s.resetCaches(r.Guilds)
 	return nil
 }
 
@@ -1016,13 +891,11 @@ func (s *State) OnInterface(se *Session, i any) (err error) {
 
 		err = s.GuildRemove(&t.Guild)
 	case *GuildMemberAdd:
-		var guild *Guild
 		// Updates the MemberCount of the guild.
-		guild, err = s.Guild(t.Member.GuildID)
+		err = s.guildMemberCountAdd(t.Member.GuildID, 1)
 		if err != nil {
 			return err
 		}
-		guild.MemberCount++
 
 		// Caches member if tracking is enabled.
 		if s.TrackMembers {
@@ -1040,13 +913,11 @@ func (s *State) OnInterface(se *Session, i any) (err error) {
 			err = s.MemberAdd(t.Member)
 		}
 	case *GuildMemberRemove:
-		var guild *Guild
 		// Updates the MemberCount of the guild.
-		guild, err = s.Guild(t.Member.GuildID)
+		err = s.guildMemberCountAdd(t.Member.GuildID, -1)
 		if err != nil {
 			return err
 		}
-		guild.MemberCount--
 
 		// Removes member from the cache if tracking is enabled.
 		if s.TrackMembers {
@@ -1080,14 +951,9 @@ func (s *State) OnInterface(se *Session, i any) (err error) {
 		}
 	case *GuildEmojisUpdate:
 		if s.TrackEmojis {
-			var guild *Guild
-			guild, err = s.Guild(t.GuildID)
-			if err != nil {
-				return err
-			}
 			s.Lock()
-			defer s.Unlock()
-			guild.Emojis = t.Emojis
+			err = s.store().EmojisPut(t.GuildID, t.Emojis)
+			s.Unlock()
 		}
 	case *ChannelCreate:
 		if s.TrackChannels {
@@ -1131,8 +997,23 @@ func (s *State) OnInterface(se *Session, i any) (err error) {
 			err = s.ThreadListSync(t)
 		}
 	case *MessageCreate:
+		if s.TrackReadStates {
+			err = s.onMessageCreateReadState(t.Message)
+		}
 		if s.MaxMessageCount != 0 {
-			err = s.MessageAdd(t.Message)
+			err = errors.Join(err, s.MessageAdd(t.Message))
+		}
+	case *MessageAck:
+		if s.TrackReadStates {
+			s.messageAck(t.ChannelID, t.MessageID, t.MentionCount)
+		}
+	case *ChannelPinsAck:
+		if s.TrackReadStates {
+			s.pinsAck(t.ChannelID, t.Timestamp)
+		}
+	case *ChannelPinsUpdate:
+		if s.TrackChannels {
+			err = s.onChannelPinsUpdate(t)
 		}
 	case *MessageUpdate:
 		if s.MaxMessageCount != 0 {
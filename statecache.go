@@ -0,0 +1,352 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the eviction policies of the message and member caches
+// of a State, which bound the memory used by long running sessions.
+
+package discordgo
+
+import (
+	"container/list"
+	"slices"
+	"time"
+)
+
+// MessageCachePolicy bounds the messages cached by a State, across all
+// channels. The oldest cached messages are evicted first. A zero field is no
+// bound, and State.MaxMessageCount still bounds the messages per channel.
+type MessageCachePolicy struct {
+	// MaxCount is the number of messages cached.
+	MaxCount int
+
+	// MaxBytes is the approximate size of the messages cached.
+	MaxBytes int64
+
+	// TTL is how long a message is cached.
+	TTL time.Duration
+}
+
+// MemberCachePolicy bounds the members and presences cached by a State,
+// across all guilds. The member and the presence of a user in a guild are
+// evicted together, least recently seen first: a member is seen when an
+// event adds or updates it or its presence.
+type MemberCachePolicy struct {
+	// MaxCount is the number of members, or presences without a member,
+	// cached. Kept members count toward it.
+	MaxCount int
+
+	// TTL is how long a member is cached after it was last seen, or after
+	// it stopped being kept.
+	TTL time.Duration
+
+	// KeepInVoice keeps the members in a voice channel of their guild.
+	KeepInVoice bool
+
+	// KeepRoles keeps the members with any of these roles.
+	KeepRoles []string
+}
+
+// StateCacheStats are the counters of the caches of a State.
+type StateCacheStats struct {
+	// Messages is the number of messages cached, and MessageBytes their
+	// approximate size.
+	Messages     int
+	MessageBytes int64
+
+	// Members is the number of members, or presences without a member,
+	// cached.
+	Members int
+
+	// EvictedMessages and EvictedMembers are the number of messages and
+	// members evicted by the cache policies.
+	EvictedMessages uint64
+	EvictedMembers  uint64
+}
+
+// CacheStats returns the counters of the caches of the state.
+func (s *State) CacheStats() StateCacheStats {
+	if s == nil {
+		return StateCacheStats{}
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return StateCacheStats{
+		Messages:        s.messageLRU.len(),
+		MessageBytes:    s.messageLRU.bytes,
+		Members:         s.memberLRU.len() + len(s.keptMembers),
+		EvictedMessages: s.evictedMessages,
+		EvictedMembers:  s.evictedMembers,
+	}
+}
+
+// Evict evicts the messages and members past the cache policies of the
+// state. They are evicted as the state is updated, but a state which is not
+// updated keeps them past their TTL until Evict is called.
+func (s *State) Evict() {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	s.evictMessages(now)
+	s.evictMembers(now)
+}
+
+// evictMessages evicts the messages past MessageCache. s must be locked.
+func (s *State) evictMessages(now time.Time) {
+	p := s.MessageCache
+	for {
+		e := s.messageLRU.oldest()
+		if e == nil {
+			return
+		}
+		if !(p.MaxCount > 0 && s.messageLRU.len() > p.MaxCount ||
+			p.MaxBytes > 0 && s.messageLRU.bytes > p.MaxBytes ||
+			p.TTL > 0 && now.Sub(e.used) > p.TTL) {
+			return
+		}
+
+		s.messageLRU.remove(e.key)
+		s.store().MessageDelete(e.key.parentID, e.key.id)
+		s.evictedMessages++
+	}
+}
+
+// evictMembers evicts the members past MemberCache. The members it keeps
+// count toward MaxCount, but are not tracked by memberLRU, so only the others
+// are evicted. s must be locked.
+func (s *State) evictMembers(now time.Time) {
+	p := s.MemberCache
+	for {
+		e := s.memberLRU.oldest()
+		if e == nil {
+			return
+		}
+		if !(p.MaxCount > 0 && s.memberLRU.len()+len(s.keptMembers) > p.MaxCount ||
+			p.TTL > 0 && now.Sub(e.used) > p.TTL) {
+			return
+		}
+
+		s.memberLRU.remove(e.key)
+		s.store().MemberDelete(e.key.parentID, e.key.id)
+		s.store().PresenceDelete(e.key.parentID, e.key.id)
+		s.evictedMembers++
+	}
+}
+
+// keepMember reports whether the member of a guild must not be evicted.
+// s must be locked.
+func (s *State) keepMember(guildID, userID string) bool {
+	if s.User != nil && s.User.ID == userID {
+		return true
+	}
+
+	if s.MemberCache.KeepInVoice {
+		if _, err := s.store().VoiceState(guildID, userID); err == nil {
+			return true
+		}
+	}
+
+	if len(s.MemberCache.KeepRoles) > 0 {
+		if m, err := s.store().Member(guildID, userID); err == nil {
+			for _, roleID := range m.Roles {
+				if slices.Contains(s.MemberCache.KeepRoles, roleID) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// trackMember tracks the member of a user in a guild as seen at the given
+// time, or as kept when MemberCache keeps it. s must be locked.
+func (s *State) trackMember(key cacheKey, now time.Time) {
+	if s.keepMember(key.parentID, key.id) {
+		s.memberLRU.remove(key)
+		if s.keptMembers == nil {
+			s.keptMembers = make(map[cacheKey]struct{})
+		}
+		s.keptMembers[key] = struct{}{}
+		return
+	}
+
+	delete(s.keptMembers, key)
+	s.memberLRU.touch(key, now, 0)
+}
+
+// memberSeen records that the member or the presence of a user in a guild
+// was seen, and evicts the members past MemberCache. s must be locked.
+func (s *State) memberSeen(guildID, userID string) {
+	now := time.Now()
+	s.trackMember(cacheKey{guildID, userID}, now)
+	s.evictMembers(now)
+}
+
+// memberKeepChanged checks again whether MemberCache keeps the member of a
+// user in a guild, after its voice state changed. A member which is no
+// longer kept is tracked as seen now. s must be locked.
+func (s *State) memberKeepChanged(guildID, userID string) {
+	key := cacheKey{guildID, userID}
+	_, kept := s.keptMembers[key]
+	if kept == s.keepMember(guildID, userID) {
+		return
+	}
+	if _, tracked := s.memberLRU.index[key]; !kept && !tracked {
+		return
+	}
+
+	now := time.Now()
+	s.trackMember(key, now)
+	s.evictMembers(now)
+}
+
+// membersSeen records that members of a guild were seen, and evicts the
+// members past MemberCache. s must be locked.
+func (s *State) membersSeen(guildID string, members []*Member) {
+	// All are tracked before evicting, as evicting changes the slice of
+	// members of the guild, which members may be.
+	now := time.Now()
+	for _, m := range members {
+		if m.User != nil {
+			s.trackMember(cacheKey{guildID, m.User.ID}, now)
+		}
+	}
+	s.evictMembers(now)
+}
+
+// memberForgotten stops tracking the member of a user in a guild, once
+// neither its member nor its presence is cached. s must be locked.
+func (s *State) memberForgotten(guildID, userID string) {
+	if _, err := s.store().Member(guildID, userID); err == nil {
+		return
+	}
+	if _, err := s.store().Presence(guildID, userID); err == nil {
+		return
+	}
+	s.memberLRU.remove(cacheKey{guildID, userID})
+	delete(s.keptMembers, cacheKey{guildID, userID})
+}
+
+// resetCaches stops tracking everything, and tracks the members of guilds.
+// s must be locked.
+func (s *State) resetCaches(guilds []*Guild) {
+	s.messageLRU = cacheLRU{}
+	s.memberLRU = cacheLRU{}
+	s.keptMembers = nil
+
+	for _, g := range guilds {
+		s.membersSeen(g.ID, g.Members)
+	}
+}
+
+// messageOverhead is the approximate size of a message without its content,
+// embeds and attachments.
+const messageOverhead = 512
+
+// messageSize returns the approximate size of a message in memory.
+func messageSize(m *Message) int64 {
+	size := messageOverhead + len(m.Content)
+	for _, e := range m.Embeds {
+		size += len(e.URL) + len(e.Title) + len(e.Description)
+		for _, f := range e.Fields {
+			size += len(f.Name) + len(f.Value)
+		}
+	}
+	for _, a := range m.Attachments {
+		size += len(a.URL) + len(a.ProxyURL) + len(a.Filename)
+	}
+	return int64(size)
+}
+
+// cacheKey is the key of a cached entity: the IDs of its channel and its
+// own for messages, and the IDs of its guild and user for members.
+type cacheKey struct {
+	parentID, id string
+}
+
+// cacheEntry is an entity tracked by a cacheLRU.
+type cacheEntry struct {
+	key  cacheKey
+	used time.Time
+	size int64
+}
+
+// cacheLRU orders cached entities from the least to the most recently used,
+// and sums their sizes. The zero value is empty and ready to use.
+type cacheLRU struct {
+	entries list.List
+	index   map[cacheKey]*list.Element
+	bytes   int64
+}
+
+func (c *cacheLRU) len() int {
+	return c.entries.Len()
+}
+
+// oldest returns the least recently used entry, or nil.
+func (c *cacheLRU) oldest() *cacheEntry {
+	if e := c.entries.Front(); e != nil {
+		return e.Value.(*cacheEntry)
+	}
+	return nil
+}
+
+// touch marks an entity as used at the given time, with the given size,
+// and adds it when it is not tracked.
+func (c *cacheLRU) touch(key cacheKey, used time.Time, size int64) {
+	if e, ok := c.index[key]; ok {
+		entry := e.Value.(*cacheEntry)
+		c.bytes += size - entry.size
+		entry.used, entry.size = used, size
+		c.entries.MoveToBack(e)
+		return
+	}
+
+	if c.index == nil {
+		c.index = make(map[cacheKey]*list.Element)
+	}
+	c.index[key] = c.entries.PushBack(&cacheEntry{key, used, size})
+	c.bytes += size
+}
+
+// resize changes the size of a tracked entity, without marking it as used.
+func (c *cacheLRU) resize(key cacheKey, size int64) {
+	if e, ok := c.index[key]; ok {
+		entry := e.Value.(*cacheEntry)
+		c.bytes += size - entry.size
+		entry.size = size
+	}
+}
+
+// remove stops tracking an entity.
+func (c *cacheLRU) remove(key cacheKey) {
+	if e, ok := c.index[key]; ok {
+		c.bytes -= e.Value.(*cacheEntry).size
+		c.entries.Remove(e)
+		delete(c.index, key)
+	}
+}
+
+// removeParent stops tracking the entities of a channel or guild.
+func (c *cacheLRU) removeParent(parentID string) {
+	for e := c.entries.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*cacheEntry); entry.key.parentID == parentID {
+			c.bytes -= entry.size
+			c.entries.Remove(e)
+			delete(c.index, entry.key)
+		}
+		e = next
+	}
+}
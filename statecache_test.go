@@ -0,0 +1,119 @@
+package discordgo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStateMessageCache(t *testing.T) {
+	state := NewState()
+	state.MaxMessageCount = 10
+	state.MessageCache = MessageCachePolicy{MaxCount: 2}
+	se := &Session{StateEnabled: true, State: state}
+
+	if err := state.OnInterface(se, testReady()); err != nil {
+		t.Fatalf("OnInterface(Ready) returned error: %+v", err)
+	}
+
+	messages := []*Message{
+		{ID: "30", ChannelID: "20", Content: "a"},
+		{ID: "31", ChannelID: "21", Content: "b"},
+		{ID: "32", ChannelID: "20", Content: "c"},
+	}
+	for _, m := range messages {
+		if err := state.MessageAdd(m); err != nil {
+			t.Fatalf("MessageAdd returned error: %+v", err)
+		}
+	}
+
+	if _, err := state.Message("20", "30"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("expected the oldest message to be evicted, got %v", err)
+	}
+	if _, err := state.Message("21", "31"); err != nil {
+		t.Errorf("Message returned error: %+v", err)
+	}
+	stats := state.CacheStats()
+	if stats.Messages != 2 || stats.EvictedMessages != 1 || stats.MessageBytes != 2*(messageOverhead+1) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	state.MessageCache = MessageCachePolicy{TTL: time.Nanosecond}
+	time.Sleep(time.Millisecond)
+	state.Evict()
+	if stats = state.CacheStats(); stats.Messages != 0 || stats.MessageBytes != 0 || stats.EvictedMessages != 3 {
+		t.Errorf("unexpected stats after the TTL %+v", stats)
+	}
+	if c, _ := state.Channel("20"); len(c.Messages) != 0 {
+		t.Errorf("expected no messages, got %+v", c.Messages)
+	}
+}
+
+func TestStateMemberCache(t *testing.T) {
+	state := NewState()
+	state.MemberCache = MemberCachePolicy{MaxCount: 2, KeepInVoice: true, KeepRoles: []string{"99"}}
+	se := &Session{StateEnabled: true, State: state}
+
+	if err := state.OnInterface(se, testReady()); err != nil {
+		t.Fatalf("OnInterface(Ready) returned error: %+v", err)
+	}
+
+	add := func(userID string, roles ...string) {
+		t.Helper()
+		if err := state.MemberAdd(&Member{GuildID: "10", User: &User{ID: userID}, Roles: roles}); err != nil {
+			t.Fatalf("MemberAdd returned error: %+v", err)
+		}
+	}
+	cached := func(userIDs ...string) {
+		t.Helper()
+		g, _ := state.Guild("10")
+		if len(g.Members) != len(userIDs) {
+			t.Errorf("expected members %v, got %d members", userIDs, len(g.Members))
+		}
+		for _, id := range userIDs {
+			if _, err := state.Member("10", id); err != nil {
+				t.Errorf("expected member %s to be cached, got %v", id, err)
+			}
+		}
+	}
+
+	add("3", "99")
+	add("4")
+	cached("3", "4")
+
+	// Member 3 has a kept role, so member 4 is evicted.
+	add("5")
+	cached("3", "5")
+
+	// Member 5 is in a voice channel, so the newest member is evicted.
+	if err := state.voiceStateUpdate(&VoiceStateUpdate{VoiceState: &VoiceState{GuildID: "10", ChannelID: "20", UserID: "5"}}); err != nil {
+		t.Fatalf("voiceStateUpdate returned error: %+v", err)
+	}
+	add("6")
+	cached("3", "5")
+
+	// Presences are evicted with their member.
+	if err := state.PresenceAdd("10", &Presence{User: &User{ID: "7"}}); err != nil {
+		t.Fatalf("PresenceAdd returned error: %+v", err)
+	}
+	if _, err := state.Presence("10", "7"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("expected the presence to be evicted, got %v", err)
+	}
+
+	// Member 5 leaves the voice channel, so it is evicted before newer
+	// members.
+	if err := state.voiceStateUpdate(&VoiceStateUpdate{VoiceState: &VoiceState{GuildID: "10", UserID: "5"}}); err != nil {
+		t.Fatalf("voiceStateUpdate returned error: %+v", err)
+	}
+	add("8")
+	cached("3", "8")
+
+	// Kept members are not tracked as seen.
+	if _, ok := state.memberLRU.index[cacheKey{"10", "3"}]; ok {
+		t.Error("expected member 3 not to be in the LRU")
+	}
+
+	if stats := state.CacheStats(); stats.Members != 2 || stats.EvictedMembers != 5 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
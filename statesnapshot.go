@@ -0,0 +1,255 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the snapshots of a State, which are saved so that a
+// restarted process starts with a warm cache and resumes its gateway
+// session.
+
+package discordgo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/lb-selfbot/discordgo/protos"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrInvalidStateSnapshot is returned by State.Restore when the snapshot is
+// corrupt, or was written by an unsupported version.
+var ErrInvalidStateSnapshot = errors.New("invalid state snapshot")
+
+// stateSnapshotMagic starts every state snapshot, followed by the
+// stateSnapshotVersion byte and the zstd compressed gob encoding of a
+// stateSnapshot.
+const stateSnapshotMagic = "DGSTATE"
+
+// stateSnapshotVersion is the version of the snapshot encoding. It must be
+// increased when stateSnapshot changes incompatibly.
+const stateSnapshotVersion = 1
+
+// stateSnapshot is what a State snapshot holds. Messages, presences and voice
+// states are not kept, as they are stale by the time a snapshot is restored.
+type stateSnapshot struct {
+	Version          int
+	SessionID        string
+	ResumeGatewayURL string
+	Sequence         int64
+
+	User              *User
+	Guilds            []*Guild
+	PrivateChannels   []*Channel
+	ReadState         []*ReadState
+	UserGuildSettings []*UserGuildSettings
+	Relationships     []*Relationship
+
+	// UserSettings is the protobuf encoding of the user settings.
+	UserSettings []byte
+}
+
+// Snapshot writes a snapshot of the state to w: its guilds, with their
+// channels, members, roles and emojis, the private channels, read states and
+// relationships, and the gateway session the state was last updated by. It
+// is consistent with the events applied to the state, so it may be taken
+// while the session is open.
+//
+// A snapshot is restored with Restore.
+func (s *State) Snapshot(w io.Writer) error {
+	if s == nil {
+		return ErrNilState
+	}
+
+	// The state is encoded under its lock, and compressed afterwards.
+	var buf bytes.Buffer
+	if err := s.snapshot(&buf); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, stateSnapshotMagic); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{stateSnapshotVersion}); err != nil {
+		return err
+	}
+
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	if _, err = buf.WriteTo(zw); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// snapshot writes the gob encoding of a stateSnapshot of the state to w.
+func (s *State) snapshot(w io.Writer) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	guilds, err := s.store().Guilds()
+	if err != nil {
+		return err
+	}
+	privateChannels, err := s.store().PrivateChannels()
+	if err != nil {
+		return err
+	}
+
+	snap := stateSnapshot{
+		Version:           s.Version,
+		SessionID:         s.SessionID,
+		ResumeGatewayURL:  s.ResumeGatewayURL,
+		Sequence:          s.sequence,
+		User:              s.User,
+		ReadState:         s.ReadState,
+		UserGuildSettings: s.UserGuildSettings,
+		Relationships:     s.Relationships,
+	}
+
+	for _, g := range guilds {
+		guild := *g
+		guild.Presences = nil
+		guild.VoiceStates = nil
+		guild.Channels = snapshotChannels(g.Channels)
+		guild.Threads = snapshotChannels(g.Threads)
+		snap.Guilds = append(snap.Guilds, &guild)
+	}
+	snap.PrivateChannels = snapshotChannels(privateChannels)
+
+	if s.UserSettings != nil {
+		snap.UserSettings, err = proto.Marshal(s.UserSettings)
+		if err != nil {
+			return err
+		}
+	}
+
+	return gob.NewEncoder(w).Encode(&snap)
+}
+
+// snapshotChannels returns copies of channels without their messages.
+func snapshotChannels(channels []*Channel) []*Channel {
+	snap := make([]*Channel, len(channels))
+	for i, c := range channels {
+		channel := *c
+		channel.Messages = nil
+		snap[i] = &channel
+	}
+	return snap
+}
+
+// Restore replaces the state with a snapshot written by Snapshot. The gateway
+// session of the snapshot is resumed by the next Open of a session which has
+// none, so the events missed since the snapshot was taken are replayed.
+//
+// Restore must be called before the session is opened.
+func (s *State) Restore(r io.Reader) error {
+	if s == nil {
+		return ErrNilState
+	}
+
+	header := make([]byte, len(stateSnapshotMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidStateSnapshot, err)
+	}
+	if string(header[:len(stateSnapshotMagic)]) != stateSnapshotMagic {
+		return ErrInvalidStateSnapshot
+	}
+	if v := header[len(stateSnapshotMagic)]; v != stateSnapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidStateSnapshot, v)
+	}
+
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	var snap stateSnapshot
+	if err = gob.NewDecoder(zr).Decode(&snap); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidStateSnapshot, err)
+	}
+
+	var userSettings *protos.PreloadedUserSettings
+	if snap.UserSettings != nil {
+		userSettings = &protos.PreloadedUserSettings{}
+		if err = proto.Unmarshal(snap.UserSettings, userSettings); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidStateSnapshot, err)
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.Ready = Ready{
+		Version:           snap.Version,
+		SessionID:         snap.SessionID,
+		ResumeGatewayURL:  snap.ResumeGatewayURL,
+		User:              snap.User,
+		ReadState:         snap.ReadState,
+		PrivateChannels:   []*Channel{},
+		Guilds:            []*Guild{},
+		UserGuildSettings: snap.UserGuildSettings,
+		Relationships:     snap.Relationships,
+		UserSettings:      userSettings,
+	}
+
+	if err = s.store().Reset(); err != nil {
+		return err
+	}
+	for _, g := range snap.Guilds {
+		if err = s.store().GuildPut(g); err != nil {
+			return err
+		}
+	}
+	for _, c := range snap.PrivateChannels {
+		if err = s.store().ChannelPut(c); err != nil {
+			return err
+		}
+	}
+
+	s.indexReadStates()
+	s.resetCaches(snap.Guilds)
+	s.sequence = snap.Sequence
+	s.restored = snap.SessionID != ""
+	return nil
+}
+
+// setSequence records the sequence number of the last gateway event applied
+// to the state.
+func (s *State) setSequence(sequence int64) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	s.sequence = sequence
+	s.Unlock()
+}
+
+// restoredSession returns the gateway session of the snapshot the state was
+// restored from, if it was not resumed yet. It is only returned once.
+func (s *State) restoredSession() (sessionID, resumeGatewayURL string, sequence int64, ok bool) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.restored {
+		return
+	}
+	s.restored = false
+
+	return s.SessionID, s.ResumeGatewayURL, s.sequence, true
+}
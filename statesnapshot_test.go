@@ -0,0 +1,83 @@
+package discordgo
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestStateSnapshot(t *testing.T) {
+	state := NewState()
+	state.MaxMessageCount = 10
+	se := &Session{StateEnabled: true, State: state}
+
+	ready := testReady()
+	ready.SessionID = "session"
+	ready.ResumeGatewayURL = "wss://resume"
+	ready.ReadState = []*ReadState{{ID: "20", LastMessageID: "30"}}
+	ready.Relationships = []*Relationship{{ID: "4", Type: 1, User: &User{ID: "4"}}}
+	if err := state.OnInterface(se, ready); err != nil {
+		t.Fatalf("OnInterface(Ready) returned error: %+v", err)
+	}
+	if err := state.OnInterface(se, &MessageCreate{&Message{ID: "30", ChannelID: "20"}}); err != nil {
+		t.Fatalf("OnInterface(MessageCreate) returned error: %+v", err)
+	}
+	state.setSequence(42)
+
+	var buf bytes.Buffer
+	if err := state.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot returned error: %+v", err)
+	}
+	if c, _ := state.Channel("20"); len(c.Messages) != 1 {
+		t.Errorf("Snapshot changed the state, messages %+v", c.Messages)
+	}
+
+	restored := NewState()
+	if err := restored.Restore(&buf); err != nil {
+		t.Fatalf("Restore returned error: %+v", err)
+	}
+
+	if len(restored.Guilds) != 1 || len(restored.PrivateChannels) != 1 {
+		t.Fatalf("expected 1 guild and 1 private channel, got %d and %d", len(restored.Guilds), len(restored.PrivateChannels))
+	}
+	if c, err := restored.Channel("20"); err != nil || c.GuildID != "10" || len(c.Messages) != 0 {
+		t.Errorf("Channel returned %+v, %v", c, err)
+	}
+	if m, err := restored.Member("10", "2"); err != nil || m.User.ID != "2" {
+		t.Errorf("Member returned %+v, %v", m, err)
+	}
+	if perms, err := restored.UserChannelPermissions("2", "20"); err != nil || perms&PermissionViewChannel == 0 {
+		t.Errorf("UserChannelPermissions returned %d, %v", perms, err)
+	}
+	if restored.User.ID != "1" || len(restored.ReadState) != 1 || len(restored.Relationships) != 1 {
+		t.Errorf("unexpected ready %+v", restored.Ready)
+	}
+
+	sessionID, gateway, sequence, ok := restored.restoredSession()
+	if !ok || sessionID != "session" || gateway != "wss://resume" || sequence != 42 {
+		t.Errorf("restoredSession returned %q, %q, %d, %t", sessionID, gateway, sequence, ok)
+	}
+	if _, _, _, ok = restored.restoredSession(); ok {
+		t.Error("expected the restored session to be returned once")
+	}
+}
+
+func TestStateRestoreInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewState().Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot returned error: %+v", err)
+	}
+	snapshot := buf.Bytes()
+
+	tests := map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("NOTSTATE"), snapshot[8:]...),
+		"version":   append(append([]byte(stateSnapshotMagic), stateSnapshotVersion+1), snapshot[8:]...),
+		"truncated": snapshot[:len(snapshot)/2],
+	}
+	for name, data := range tests {
+		if err := NewState().Restore(bytes.NewReader(data)); !errors.Is(err, ErrInvalidStateSnapshot) {
+			t.Errorf("%s: expected %v, got %v", name, ErrInvalidStateSnapshot, err)
+		}
+	}
+}
@@ -0,0 +1,524 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the StateStore interface, which stores the entities
+// tracked by a State, and its default in-memory implementation.
+
+package discordgo
+
+import (
+	"slices"
+)
+
+// A StateStore stores the guilds, channels, members, roles, emojis, messages,
+// presences and voice states tracked by a State. The State decides what is
+// stored, e.g. it merges updates with the stored entities, and the store only
+// keeps them.
+//
+// The getters return ErrStateNotFound when nothing is stored for the given
+// IDs, as do the setters when the guild or channel an entity belongs to is
+// not stored. A guild is returned with its channels, threads, members, roles,
+// emojis, presences and voice states, and a channel with its messages.
+//
+// A store may return the values it holds rather than copies. The State
+// changes them only while it holds its write lock, and always puts them back
+// afterwards, so a store which copies values sees every change.
+//
+// A State calls its store with its lock held: the write lock when it changes
+// the store, and the read lock otherwise. The getters of a store may thus be
+// called concurrently, but never concurrently with a setter.
+type StateStore interface {
+	// Reset removes everything from the store.
+	Reset() error
+
+	Guild(guildID string) (*Guild, error)
+	Guilds() ([]*Guild, error)
+	// GuildPut stores a guild, along with its channels, threads and
+	// members, replacing the guild with the same ID.
+	GuildPut(guild *Guild) error
+	GuildDelete(guildID string) error
+
+	Channel(channelID string) (*Channel, error)
+	// PrivateChannels returns the DM and group DM channels.
+	PrivateChannels() ([]*Channel, error)
+	// ChannelPut stores a channel, replacing the channel with the same ID.
+	// The guild of a guild channel must be stored.
+	ChannelPut(channel *Channel) error
+	ChannelDelete(channelID string) error
+
+	Member(guildID, userID string) (*Member, error)
+	Members(guildID string) ([]*Member, error)
+	MemberPut(member *Member) error
+	MemberDelete(guildID, userID string) error
+
+	Role(guildID, roleID string) (*Role, error)
+	RolePut(guildID string, role *Role) error
+	RoleDelete(guildID, roleID string) error
+
+	Emoji(guildID, emojiID string) (*Emoji, error)
+	EmojiPut(guildID string, emoji *Emoji) error
+	// EmojisPut replaces all the emojis of a guild.
+	EmojisPut(guildID string, emojis []*Emoji) error
+
+	Message(channelID, messageID string) (*Message, error)
+	// Messages returns the messages of a channel, oldest first.
+	Messages(channelID string) ([]*Message, error)
+	MessagePut(message *Message) error
+	MessageDelete(channelID, messageID string) error
+
+	Presence(guildID, userID string) (*Presence, error)
+	PresencePut(guildID string, presence *Presence) error
+	PresenceDelete(guildID, userID string) error
+
+	VoiceState(guildID, userID string) (*VoiceState, error)
+	VoiceStatePut(guildID string, state *VoiceState) error
+	VoiceStateDelete(guildID, userID string) error
+}
+
+// memoryStateStore is the default StateStore, which keeps everything in
+// memory. Roles, emojis, presences and voice states are kept in the slices
+// of their guild, and messages in the slice of their channel. The guilds and
+// private channels are kept in the slices of a Ready, so that State.Guilds
+// and State.PrivateChannels are up to date.
+//
+// Stored values are changed in place when they are replaced, so the pointers
+// handed out stay valid.
+type memoryStateStore struct {
+	ready *Ready
+
+	guilds   map[string]*Guild
+	channels map[string]*Channel
+	members  map[string]map[string]*Member
+}
+
+// newMemoryStateStore returns an empty memoryStateStore keeping its guilds
+// and private channels in ready.
+func newMemoryStateStore(ready *Ready) *memoryStateStore {
+	m := &memoryStateStore{ready: ready}
+	m.Reset()
+	return m
+}
+
+// removeFirst removes the first element of s for which match returns true,
+// and reports whether there was one.
+func removeFirst[T any](s []T, match func(T) bool) ([]T, bool) {
+	i := slices.IndexFunc(s, match)
+	if i < 0 {
+		return s, false
+	}
+	return append(s[:i], s[i+1:]...), true
+}
+
+func (m *memoryStateStore) Reset() error {
+	m.guilds = make(map[string]*Guild)
+	m.channels = make(map[string]*Channel)
+	m.members = make(map[string]map[string]*Member)
+	m.ready.Guilds = []*Guild{}
+	m.ready.PrivateChannels = []*Channel{}
+	return nil
+}
+
+func (m *memoryStateStore) Guild(guildID string) (*Guild, error) {
+	if g, ok := m.guilds[guildID]; ok {
+		return g, nil
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) Guilds() ([]*Guild, error) {
+	return m.ready.Guilds, nil
+}
+
+func (m *memoryStateStore) GuildPut(guild *Guild) error {
+	g, ok := m.guilds[guild.ID]
+	if ok && g == guild {
+		// The stored guild was changed in place.
+		return nil
+	}
+
+	for _, c := range guild.Channels {
+		m.channels[c.ID] = c
+	}
+	for _, t := range guild.Threads {
+		m.channels[t.ID] = t
+	}
+
+	// A new member slice needs a new member map, so the pointers stay valid.
+	if guild.Members != nil {
+		members := make(map[string]*Member, len(guild.Members))
+		for _, member := range guild.Members {
+			members[member.User.ID] = member
+		}
+		m.members[guild.ID] = members
+	} else if _, ok := m.members[guild.ID]; !ok {
+		m.members[guild.ID] = make(map[string]*Member)
+	}
+
+	if ok {
+		*g = *guild
+		return nil
+	}
+
+	m.ready.Guilds = append(m.ready.Guilds, guild)
+	m.guilds[guild.ID] = guild
+	return nil
+}
+
+func (m *memoryStateStore) GuildDelete(guildID string) error {
+	if _, ok := m.guilds[guildID]; !ok {
+		return ErrStateNotFound
+	}
+	delete(m.guilds, guildID)
+
+	m.ready.Guilds, _ = removeFirst(m.ready.Guilds, func(g *Guild) bool { return g.ID == guildID })
+	return nil
+}
+
+func (m *memoryStateStore) Channel(channelID string) (*Channel, error) {
+	if c, ok := m.channels[channelID]; ok {
+		return c, nil
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) PrivateChannels() ([]*Channel, error) {
+	return m.ready.PrivateChannels, nil
+}
+
+func (m *memoryStateStore) ChannelPut(channel *Channel) error {
+	if c, ok := m.channels[channel.ID]; ok {
+		if c != channel {
+			*c = *channel
+		}
+		return nil
+	}
+
+	if channel.Type == ChannelTypeDM || channel.Type == ChannelTypeGroupDM {
+		m.ready.PrivateChannels = append(m.ready.PrivateChannels, channel)
+		m.channels[channel.ID] = channel
+		return nil
+	}
+
+	guild, ok := m.guilds[channel.GuildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	if channel.IsThread() {
+		guild.Threads = append(guild.Threads, channel)
+	} else {
+		guild.Channels = append(guild.Channels, channel)
+	}
+	m.channels[channel.ID] = channel
+	return nil
+}
+
+func (m *memoryStateStore) ChannelDelete(channelID string) error {
+	c, ok := m.channels[channelID]
+	if !ok {
+		return ErrStateNotFound
+	}
+	delete(m.channels, channelID)
+
+	match := func(c *Channel) bool { return c.ID == channelID }
+	if c.Type == ChannelTypeDM || c.Type == ChannelTypeGroupDM {
+		m.ready.PrivateChannels, _ = removeFirst(m.ready.PrivateChannels, match)
+		return nil
+	}
+
+	if guild, ok := m.guilds[c.GuildID]; ok {
+		if c.IsThread() {
+			guild.Threads, _ = removeFirst(guild.Threads, match)
+		} else {
+			guild.Channels, _ = removeFirst(guild.Channels, match)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStateStore) Member(guildID, userID string) (*Member, error) {
+	if member, ok := m.members[guildID][userID]; ok {
+		return member, nil
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) Members(guildID string) ([]*Member, error) {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	return guild.Members, nil
+}
+
+func (m *memoryStateStore) MemberPut(member *Member) error {
+	guild, ok := m.guilds[member.GuildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+	members, ok := m.members[member.GuildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	if old, ok := members[member.User.ID]; ok {
+		if old != member {
+			*old = *member
+		}
+		return nil
+	}
+
+	members[member.User.ID] = member
+	guild.Members = append(guild.Members, member)
+	return nil
+}
+
+func (m *memoryStateStore) MemberDelete(guildID, userID string) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+	members, ok := m.members[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+	if _, ok := members[userID]; !ok {
+		return ErrStateNotFound
+	}
+	delete(members, userID)
+
+	guild.Members, _ = removeFirst(guild.Members, func(member *Member) bool { return member.User.ID == userID })
+	return nil
+}
+
+func (m *memoryStateStore) Role(guildID, roleID string) (*Role, error) {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	for _, r := range guild.Roles {
+		if r.ID == roleID {
+			return r, nil
+		}
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) RolePut(guildID string, role *Role) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	for i, r := range guild.Roles {
+		if r.ID == role.ID {
+			guild.Roles[i] = role
+			return nil
+		}
+	}
+	guild.Roles = append(guild.Roles, role)
+	return nil
+}
+
+func (m *memoryStateStore) RoleDelete(guildID, roleID string) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	var removed bool
+	guild.Roles, removed = removeFirst(guild.Roles, func(r *Role) bool { return r.ID == roleID })
+	if !removed {
+		return ErrStateNotFound
+	}
+	return nil
+}
+
+func (m *memoryStateStore) Emoji(guildID, emojiID string) (*Emoji, error) {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	for _, e := range guild.Emojis {
+		if e.ID == emojiID {
+			return e, nil
+		}
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) EmojiPut(guildID string, emoji *Emoji) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	for i, e := range guild.Emojis {
+		if e.ID == emoji.ID {
+			guild.Emojis[i] = emoji
+			return nil
+		}
+	}
+	guild.Emojis = append(guild.Emojis, emoji)
+	return nil
+}
+
+func (m *memoryStateStore) EmojisPut(guildID string, emojis []*Emoji) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	guild.Emojis = emojis
+	return nil
+}
+
+func (m *memoryStateStore) Message(channelID, messageID string) (*Message, error) {
+	c, ok := m.channels[channelID]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	for _, message := range c.Messages {
+		if message.ID == messageID {
+			return message, nil
+		}
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) Messages(channelID string) ([]*Message, error) {
+	c, ok := m.channels[channelID]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	return c.Messages, nil
+}
+
+func (m *memoryStateStore) MessagePut(message *Message) error {
+	c, ok := m.channels[message.ChannelID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	for _, old := range c.Messages {
+		if old.ID == message.ID {
+			if old != message {
+				*old = *message
+			}
+			return nil
+		}
+	}
+	c.Messages = append(c.Messages, message)
+	return nil
+}
+
+func (m *memoryStateStore) MessageDelete(channelID, messageID string) error {
+	c, ok := m.channels[channelID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	var removed bool
+	c.Messages, removed = removeFirst(c.Messages, func(message *Message) bool { return message.ID == messageID })
+	if !removed {
+		return ErrStateNotFound
+	}
+	return nil
+}
+
+func (m *memoryStateStore) Presence(guildID, userID string) (*Presence, error) {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	for _, p := range guild.Presences {
+		if p.User.ID == userID {
+			return p, nil
+		}
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) PresencePut(guildID string, presence *Presence) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	for _, p := range guild.Presences {
+		if p.User.ID == presence.User.ID {
+			if p != presence {
+				*p = *presence
+			}
+			return nil
+		}
+	}
+	guild.Presences = append(guild.Presences, presence)
+	return nil
+}
+
+func (m *memoryStateStore) PresenceDelete(guildID, userID string) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	var removed bool
+	guild.Presences, removed = removeFirst(guild.Presences, func(p *Presence) bool { return p.User.ID == userID })
+	if !removed {
+		return ErrStateNotFound
+	}
+	return nil
+}
+
+func (m *memoryStateStore) VoiceState(guildID, userID string) (*VoiceState, error) {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	for _, vs := range guild.VoiceStates {
+		if vs.UserID == userID {
+			return vs, nil
+		}
+	}
+	return nil, ErrStateNotFound
+}
+
+func (m *memoryStateStore) VoiceStatePut(guildID string, state *VoiceState) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	for i, vs := range guild.VoiceStates {
+		if vs.UserID == state.UserID {
+			guild.VoiceStates[i] = state
+			return nil
+		}
+	}
+	guild.VoiceStates = append(guild.VoiceStates, state)
+	return nil
+}
+
+func (m *memoryStateStore) VoiceStateDelete(guildID, userID string) error {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return ErrStateNotFound
+	}
+
+	var removed bool
+	guild.VoiceStates, removed = removeFirst(guild.VoiceStates, func(vs *VoiceState) bool { return vs.UserID == userID })
+	if !removed {
+		return ErrStateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package discordgo
+
+import (
+	"errors"
+	"testing"
+)
+
+// countingStore is a StateStore counting the guilds and members put into it.
+type countingStore struct {
+	StateStore
+	guilds, members int
+}
+
+func (s *countingStore) GuildPut(guild *Guild) error {
+	s.guilds++
+	return s.StateStore.GuildPut(guild)
+}
+
+func (s *countingStore) MemberPut(member *Member) error {
+	s.members++
+	return s.StateStore.MemberPut(member)
+}
+
+// testReady returns a Ready with a guild, which has a channel and a member.
+func testReady() *Ready {
+	return &Ready{
+		User: &User{ID: "1"},
+		Guilds: []*Guild{{
+			ID:       "10",
+			Roles:    []*Role{{ID: "10", Permissions: PermissionViewChannel}},
+			Channels: []*Channel{{ID: "20", GuildID: "10", Type: ChannelTypeGuildText}},
+			Members:  []*Member{{GuildID: "10", User: &User{ID: "2"}}},
+		}},
+		PrivateChannels: []*Channel{{ID: "21", Type: ChannelTypeDM}},
+	}
+}
+
+func TestStateMemoryStore(t *testing.T) {
+	state := NewState()
+	state.MaxMessageCount = 2
+	se := &Session{StateEnabled: true, State: state}
+
+	if err := state.OnInterface(se, testReady()); err != nil {
+		t.Fatalf("OnInterface(Ready) returned error: %+v", err)
+	}
+	if len(state.Guilds) != 1 || len(state.PrivateChannels) != 1 {
+		t.Fatalf("expected 1 guild and 1 private channel, got %d and %d", len(state.Guilds), len(state.PrivateChannels))
+	}
+
+	if err := state.OnInterface(se, &GuildMemberAdd{&Member{GuildID: "10", User: &User{ID: "3"}}}); err != nil {
+		t.Fatalf("OnInterface(GuildMemberAdd) returned error: %+v", err)
+	}
+	if u, err := state.GetUser("3"); err != nil || u.ID != "3" {
+		t.Errorf("GetUser returned %+v, %v", u, err)
+	}
+	if g, _ := state.Guild("10"); g.MemberCount != 1 || len(g.Members) != 2 {
+		t.Errorf("expected a member count of 1 and 2 members, got %d and %d", g.MemberCount, len(g.Members))
+	}
+
+	for _, id := range []string{"30", "31", "32"} {
+		if err := state.OnInterface(se, &MessageCreate{&Message{ID: id, ChannelID: "20"}}); err != nil {
+			t.Fatalf("OnInterface(MessageCreate) returned error: %+v", err)
+		}
+	}
+	if c, _ := state.Channel("20"); len(c.Messages) != 2 || c.Messages[0].ID != "31" {
+		t.Errorf("expected messages 31 and 32, got %+v", c.Messages)
+	}
+
+	if err := state.ChannelRemove(&Channel{ID: "20"}); err != nil {
+		t.Fatalf("ChannelRemove returned error: %+v", err)
+	}
+	if g, _ := state.Guild("10"); len(g.Channels) != 0 {
+		t.Errorf("expected the channel to be removed from the guild, got %+v", g.Channels)
+	}
+
+	if err := state.GuildRemove(&Guild{ID: "10"}); err != nil {
+		t.Fatalf("GuildRemove returned error: %+v", err)
+	}
+	if _, err := state.Guild("10"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("expected %v, got %v", ErrStateNotFound, err)
+	}
+	if len(state.Guilds) != 0 {
+		t.Errorf("expected no guilds, got %+v", state.Guilds)
+	}
+}
+
+func TestStateCustomStore(t *testing.T) {
+	store := &countingStore{StateStore: newMemoryStateStore(&Ready{})}
+	state := NewState()
+	state.Store = store
+	se := &Session{StateEnabled: true, State: state}
+
+	if err := state.OnInterface(se, testReady()); err != nil {
+		t.Fatalf("OnInterface(Ready) returned error: %+v", err)
+	}
+	if err := state.OnInterface(se, &GuildMemberUpdate{Member: &Member{GuildID: "10", User: &User{ID: "2"}, Nick: "nick"}}); err != nil {
+		t.Fatalf("OnInterface(GuildMemberUpdate) returned error: %+v", err)
+	}
+	if store.guilds != 1 || store.members != 1 {
+		t.Errorf("expected 1 guild and 1 member put, got %d and %d", store.guilds, store.members)
+	}
+
+	if m, err := state.Member("10", "2"); err != nil || m.Nick != "nick" {
+		t.Errorf("Member returned %+v, %v", m, err)
+	}
+	if perms, err := state.UserChannelPermissions("2", "20"); err != nil || perms&PermissionViewChannel == 0 {
+		t.Errorf("UserChannelPermissions returned %d, %v", perms, err)
+	}
+	if _, err := state.Channel("21"); err != nil {
+		t.Errorf("Channel returned error: %+v", err)
+	}
+}
@@ -12,11 +12,15 @@
 package discordgo
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/goccy/go-json"
@@ -50,6 +54,13 @@ type Session struct {
 	// Should the session reconnect the websocket on errors.
 	ShouldReconnectOnError bool
 
+	// Decides how long to wait before each attempt to reconnect, and when
+	// to give up. DefaultReconnectPolicy is used when it is nil.
+	ReconnectPolicy ReconnectPolicy
+
+	// When set, reconnecting is aborted once it is done.
+	ReconnectContext context.Context
+
 	// Should the session retry requests when rate limited.
 	ShouldRetryOnRateLimit bool
 
@@ -61,6 +72,13 @@ type Session struct {
 	// Should the session request compressed websocket data.
 	Compress bool
 
+	// The transport compression used for the gateway connection. Payload
+	// compression (Compress) is not requested when it is set.
+	GatewayCompression GatewayCompression
+
+	// The encoding of the gateway payloads. JSON is used when it is not set.
+	GatewayEncoding GatewayEncoding
+
 	// Sharding
 	ShardID    int
 	ShardCount int
@@ -80,6 +98,14 @@ type Session struct {
 	// e.g. false = launch event handlers in their own goroutines.
 	SyncEvents bool
 
+	// When set, and SyncEvents is false, event handlers are run by the
+	// Dispatcher instead of their own goroutines.
+	Dispatcher *Dispatcher
+
+	// Event middleware, in the order they see each event before it is
+	// dispatched to event handlers.
+	EventMiddleware []EventMiddleware
+
 	// Exposed but should not be modified by User.
 
 	// Whether the Data Websocket is ready
@@ -119,6 +145,11 @@ type Session struct {
 	// used to deal with rate limits
 	Ratelimiter *RateLimiter
 
+	// REST middleware, in the order they see each request.
+	// DefaultRESTMiddleware is used when it is nil; set an empty slice to
+	// use no middleware.
+	RESTMiddleware []RESTMiddleware
+
 	ErrorHandler func(session *Session, err any)
 
 	ForceSubscribeGuildIDs []string
@@ -134,15 +165,35 @@ type Session struct {
 	// When nil, the session is not listening.
 	listening chan any
 
+	// Closed by CloseWithCode, to stop reconnecting. It is created by Open.
+	closed chan struct{}
+
+	// Tracks the goroutines started by Open, and the event handlers running
+	// in their own goroutines, so Shutdown can wait for them.
+	goroutines sync.WaitGroup
+	handlersWg sync.WaitGroup
+
+	// Decompresses the gateway connection, when transport compression is used.
+	decompressor gatewayDecompressor
+
+	// The encoding of the open gateway connection, guarded by wsMutex.
+	encoding GatewayEncoding
+
 	// sequence tracks the current gateway api websocket sequence number
 	sequence *int64
 
+	// The ConnectionState of the gateway connection.
+	status atomic.Int32
+
 	// stores sessions current Discord Gateway
 	gateway string
 
 	// stores session ID of current Gateway connection
 	sessionID string
 
+	// stores the URL to resume the current session against
+	resumeGatewayURL string
+
 	// used to make sure gateway websocket writes do not happen concurrently
 	wsMutex sync.Mutex
 
@@ -1090,6 +1141,23 @@ func (g *Guild) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	g.applyProperties()
+	return nil
+}
+
+func (g *Guild) unmarshalETF(d *etfDecoder, h etfHeader) error {
+	type guild Guild
+	if err := d.decode(h, (*guild)(g)); err != nil {
+		return err
+	}
+
+	g.applyProperties()
+	return nil
+}
+
+// applyProperties copies the guild properties Discord sends in
+// GUILD_CREATE and READY events into the guild.
+func (g *Guild) applyProperties() {
 	if g.Properties != nil {
 		g.VerificationLevel = g.Properties.VerificationLevel
 		g.VanityURLCode = g.Properties.VanityURLCode
@@ -1124,7 +1192,6 @@ func (g *Guild) UnmarshalJSON(data []byte) error {
 		g.AfkChannelID = g.Properties.AfkChannelID
 	}
 
-	return nil
 }
 
 // A GuildPreview holds data related to a specific public Discord Guild, even if the user is not in the guild.
@@ -1741,6 +1808,24 @@ type ReadState struct {
 	ID               string     `json:"id"`
 }
 
+// rawReady is the READY event as Discord sends it.
+type rawReady struct {
+	Version           int                    `json:"v"`
+	SessionID         string                 `json:"session_id"`
+	ResumeGatewayURL  string                 `json:"resume_gateway_url"`
+	CountryCode       string                 `json:"country_code"`
+	User              *User                  `json:"user"`
+	Users             []*User                `json:"users"`
+	Guilds            []*Guild               `json:"guilds"`
+	Sessions          []*GatewaySession      `json:"sessions"`
+	ReadState         *ReadStateData         `json:"read_state"`
+	Relationships     []*Relationship        `json:"relationships"`
+	PrivateChannels   []*Channel             `json:"private_channels"`
+	UserGuildSettings *UserGuildSettingsData `json:"user_guild_settings"`
+	UserSettingsProto string                 `json:"user_settings_proto"`
+	ConnectedAccounts []*UserConnection      `json:"connected_accounts"`
+}
+
 // ReadStateData is sent in the READY event
 type ReadStateData struct {
 	Version int          `json:"version"`
@@ -2228,10 +2313,124 @@ type GuildMemberAddParams struct {
 
 // An APIErrorMessage is an api error message returned from discord
 type APIErrorMessage struct {
-	Code    int    `json:"code"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+
+	// Errors holds the per-field validation errors, usually returned
+	// along with ErrCodeInvalidFormBody. Errors may be nil.
+	Errors *APIFieldErrors `json:"errors,omitempty"`
+}
+
+// An APIFieldError is a single validation error for a field of a request.
+type APIFieldError struct {
+	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
+// APIFieldErrors is a tree of validation errors which mirrors the structure
+// of the request body, e.g. the errors for the title of the first embed of
+// a message are found at Fields["embeds"].Fields["0"].Fields["title"].
+type APIFieldErrors struct {
+	// Errors for this field itself.
+	Errors []*APIFieldError
+
+	// Errors for the nested fields of this field, keyed by field name or
+	// array index.
+	Fields map[string]*APIFieldErrors
+}
+
+// UnmarshalJSON is a helper function to unmarshal the nested error objects,
+// where the errors of a field are held in the "_errors" key.
+func (e *APIFieldErrors) UnmarshalJSON(data []byte) error {
+	var v map[string]json.RawMessage
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	for k, raw := range v {
+		if k == "_errors" {
+			if err := json.Unmarshal(raw, &e.Errors); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var field *APIFieldErrors
+		if err := json.Unmarshal(raw, &field); err != nil {
+			return err
+		}
+
+		if e.Fields == nil {
+			e.Fields = make(map[string]*APIFieldErrors)
+		}
+		e.Fields[k] = field
+	}
+
+	return nil
+}
+
+// Get returns the errors of the field at the given path, e.g.
+// Get("embeds", "0", "title"). It returns nil if the field has no errors.
+func (e *APIFieldErrors) Get(path ...string) []*APIFieldError {
+	for _, p := range path {
+		if e == nil {
+			return nil
+		}
+		e = e.Fields[p]
+	}
+
+	if e == nil {
+		return nil
+	}
+	return e.Errors
+}
+
+// Flatten returns the errors of every field in the tree, keyed by the dot
+// separated path of the field, e.g. "embeds.0.title".
+func (e *APIFieldErrors) Flatten() map[string][]*APIFieldError {
+	m := make(map[string][]*APIFieldError)
+	e.flatten("", m)
+	return m
+}
+
+func (e *APIFieldErrors) flatten(prefix string, m map[string][]*APIFieldError) {
+	if e == nil {
+		return
+	}
+
+	if len(e.Errors) > 0 {
+		m[prefix] = e.Errors
+	}
+
+	for k, field := range e.Fields {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		field.flatten(k, m)
+	}
+}
+
+// String returns all field errors in the tree, one per line and sorted
+// by field path.
+func (e *APIFieldErrors) String() string {
+	flat := e.Flatten()
+
+	paths := make([]string, 0, len(flat))
+	for path := range flat {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var lines []string
+	for _, path := range paths {
+		for _, fe := range flat[path] {
+			lines = append(lines, path+": "+fe.Message+" ("+fe.Code+")")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // MessageReaction stores the data for a message reaction.
 type MessageReaction struct {
 	UserID    string `json:"user_id"`
@@ -2646,169 +2845,237 @@ const (
 		PermissionManageEmojis
 )
 
+// ErrorCode is a Discord JSON error code, as returned in the code field of
+// an API error response.
+// It implements error so that a RESTError can be matched against one
+// using errors.Is, e.g. errors.Is(err, ErrCodeUnknownMessage).
+type ErrorCode int
+
+// Error returns a description of the error code.
+func (c ErrorCode) Error() string {
+	return "Discord JSON error code " + strconv.Itoa(int(c))
+}
+
 // Block contains Discord JSON Error Response codes
 const (
-	ErrCodeGeneralError = 0
-
-	ErrCodeUnknownAccount                        = 10001
-	ErrCodeUnknownApplication                    = 10002
-	ErrCodeUnknownChannel                        = 10003
-	ErrCodeUnknownGuild                          = 10004
-	ErrCodeUnknownIntegration                    = 10005
-	ErrCodeUnknownInvite                         = 10006
-	ErrCodeUnknownMember                         = 10007
-	ErrCodeUnknownMessage                        = 10008
-	ErrCodeUnknownOverwrite                      = 10009
-	ErrCodeUnknownProvider                       = 10010
-	ErrCodeUnknownRole                           = 10011
-	ErrCodeUnknownToken                          = 10012
-	ErrCodeUnknownUser                           = 10013
-	ErrCodeUnknownEmoji                          = 10014
-	ErrCodeUnknownWebhook                        = 10015
-	ErrCodeUnknownWebhookService                 = 10016
-	ErrCodeUnknownSession                        = 10020
-	ErrCodeUnknownBan                            = 10026
-	ErrCodeUnknownSKU                            = 10027
-	ErrCodeUnknownStoreListing                   = 10028
-	ErrCodeUnknownEntitlement                    = 10029
-	ErrCodeUnknownBuild                          = 10030
-	ErrCodeUnknownLobby                          = 10031
-	ErrCodeUnknownBranch                         = 10032
-	ErrCodeUnknownStoreDirectoryLayout           = 10033
-	ErrCodeUnknownRedistributable                = 10036
-	ErrCodeUnknownGiftCode                       = 10038
-	ErrCodeUnknownStream                         = 10049
-	ErrCodeUnknownPremiumServerSubscribeCooldown = 10050
-	ErrCodeUnknownGuildTemplate                  = 10057
-	ErrCodeUnknownDiscoveryCategory              = 10059
-	ErrCodeUnknownSticker                        = 10060
-	ErrCodeUnknownInteraction                    = 10062
-	ErrCodeUnknownApplicationCommand             = 10063
-	ErrCodeUnknownApplicationCommandPermissions  = 10066
-	ErrCodeUnknownStageInstance                  = 10067
-	ErrCodeUnknownGuildMemberVerificationForm    = 10068
-	ErrCodeUnknownGuildWelcomeScreen             = 10069
-	ErrCodeUnknownGuildScheduledEvent            = 10070
-	ErrCodeUnknownGuildScheduledEventUser        = 10071
-	ErrUnknownTag                                = 10087
-
-	ErrCodeBotsCannotUseEndpoint                                            = 20001
-	ErrCodeOnlyBotsCanUseEndpoint                                           = 20002
-	ErrCodeExplicitContentCannotBeSentToTheDesiredRecipients                = 20009
-	ErrCodeYouAreNotAuthorizedToPerformThisActionOnThisApplication          = 20012
-	ErrCodeThisActionCannotBePerformedDueToSlowmodeRateLimit                = 20016
-	ErrCodeOnlyTheOwnerOfThisAccountCanPerformThisAction                    = 20018
-	ErrCodeMessageCannotBeEditedDueToAnnouncementRateLimits                 = 20022
-	ErrCodeChannelHasHitWriteRateLimit                                      = 20028
-	ErrCodeTheWriteActionYouArePerformingOnTheServerHasHitTheWriteRateLimit = 20029
-	ErrCodeStageTopicContainsNotAllowedWordsForPublicStages                 = 20031
-	ErrCodeGuildPremiumSubscriptionLevelTooLow                              = 20035
-
-	ErrCodeMaximumGuildsReached                                     = 30001
-	ErrCodeMaximumPinsReached                                       = 30003
-	ErrCodeMaximumNumberOfRecipientsReached                         = 30004
-	ErrCodeMaximumGuildRolesReached                                 = 30005
-	ErrCodeMaximumNumberOfWebhooksReached                           = 30007
-	ErrCodeMaximumNumberOfEmojisReached                             = 30008
-	ErrCodeTooManyReactions                                         = 30010
-	ErrCodeMaximumNumberOfGuildChannelsReached                      = 30013
-	ErrCodeMaximumNumberOfAttachmentsInAMessageReached              = 30015
-	ErrCodeMaximumNumberOfInvitesReached                            = 30016
-	ErrCodeMaximumNumberOfAnimatedEmojisReached                     = 30018
-	ErrCodeMaximumNumberOfServerMembersReached                      = 30019
-	ErrCodeMaximumNumberOfGuildDiscoverySubcategoriesReached        = 30030
-	ErrCodeGuildAlreadyHasATemplate                                 = 30031
-	ErrCodeMaximumNumberOfThreadParticipantsReached                 = 30033
-	ErrCodeMaximumNumberOfBansForNonGuildMembersHaveBeenExceeded    = 30035
-	ErrCodeMaximumNumberOfBansFetchesHasBeenReached                 = 30037
-	ErrCodeMaximumNumberOfUncompletedGuildScheduledEventsReached    = 30038
-	ErrCodeMaximumNumberOfStickersReached                           = 30039
-	ErrCodeMaximumNumberOfPruneRequestsHasBeenReached               = 30040
-	ErrCodeMaximumNumberOfGuildWidgetSettingsUpdatesHasBeenReached  = 30042
-	ErrCodeMaximumNumberOfEditsToMessagesOlderThanOneHourReached    = 30046
-	ErrCodeMaximumNumberOfPinnedThreadsInForumChannelHasBeenReached = 30047
-	ErrCodeMaximumNumberOfTagsInForumChannelHasBeenReached          = 30048
-
-	ErrCodeUnauthorized                           = 40001
-	ErrCodeActionRequiredVerifiedAccount          = 40002
-	ErrCodeOpeningDirectMessagesTooFast           = 40003
-	ErrCodeSendMessagesHasBeenTemporarilyDisabled = 40004
-	ErrCodeRequestEntityTooLarge                  = 40005
-	ErrCodeFeatureTemporarilyDisabledServerSide   = 40006
-	ErrCodeUserIsBannedFromThisGuild              = 40007
-	ErrCodeTargetIsNotConnectedToVoice            = 40032
-	ErrCodeMessageAlreadyCrossposted              = 40033
-	ErrCodeAnApplicationWithThatNameAlreadyExists = 40041
-	ErrCodeInteractionHasAlreadyBeenAcknowledged  = 40060
-	ErrCodeTagNamesMustBeUnique                   = 40061
-
-	ErrCodeMissingAccess                                                = 50001
-	ErrCodeInvalidAccountType                                           = 50002
-	ErrCodeCannotExecuteActionOnDMChannel                               = 50003
-	ErrCodeEmbedDisabled                                                = 50004
-	ErrCodeGuildWidgetDisabled                                          = 50004
-	ErrCodeCannotEditFromAnotherUser                                    = 50005
-	ErrCodeCannotSendEmptyMessage                                       = 50006
-	ErrCodeCannotSendMessagesToThisUser                                 = 50007
-	ErrCodeCannotSendMessagesInVoiceChannel                             = 50008
-	ErrCodeChannelVerificationLevelTooHigh                              = 50009
-	ErrCodeOAuth2ApplicationDoesNotHaveBot                              = 50010
-	ErrCodeOAuth2ApplicationLimitReached                                = 50011
-	ErrCodeInvalidOAuthState                                            = 50012
-	ErrCodeMissingPermissions                                           = 50013
-	ErrCodeInvalidAuthenticationToken                                   = 50014
-	ErrCodeTooFewOrTooManyMessagesToDelete                              = 50016
-	ErrCodeCanOnlyPinMessageToOriginatingChannel                        = 50019
-	ErrCodeInviteCodeWasEitherInvalidOrTaken                            = 50020
-	ErrCodeCannotExecuteActionOnSystemMessage                           = 50021
-	ErrCodeCannotExecuteActionOnThisChannelType                         = 50024
-	ErrCodeInvalidOAuth2AccessTokenProvided                             = 50025
-	ErrCodeMissingRequiredOAuth2Scope                                   = 50026
-	ErrCodeInvalidWebhookTokenProvided                                  = 50027
-	ErrCodeInvalidRole                                                  = 50028
-	ErrCodeInvalidRecipients                                            = 50033
-	ErrCodeMessageProvidedTooOldForBulkDelete                           = 50034
-	ErrCodeInvalidFormBody                                              = 50035
-	ErrCodeInviteAcceptedToGuildApplicationsBotNotIn                    = 50036
-	ErrCodeInvalidAPIVersionProvided                                    = 50041
-	ErrCodeFileUploadedExceedsTheMaximumSize                            = 50045
-	ErrCodeInvalidFileUploaded                                          = 50046
-	ErrCodeInvalidGuild                                                 = 50055
-	ErrCodeInvalidMessageType                                           = 50068
-	ErrCodeCannotDeleteAChannelRequiredForCommunityGuilds               = 50074
-	ErrCodeInvalidStickerSent                                           = 50081
-	ErrCodePerformedOperationOnArchivedThread                           = 50083
-	ErrCodeBeforeValueIsEarlierThanThreadCreationDate                   = 50085
-	ErrCodeCommunityServerChannelsMustBeTextChannels                    = 50086
-	ErrCodeThisServerIsNotAvailableInYourLocation                       = 50095
-	ErrCodeThisServerNeedsMonetizationEnabledInOrderToPerformThisAction = 50097
-	ErrCodeThisServerNeedsMoreBoostsToPerformThisAction                 = 50101
-	ErrCodeTheRequestBodyContainsInvalidJSON                            = 50109
-
-	ErrCodeNoUsersWithDiscordTagExist = 80004
-
-	ErrCodeReactionBlocked = 90001
-
-	ErrCodeAPIResourceIsCurrentlyOverloaded = 130000
-
-	ErrCodeTheStageIsAlreadyOpen = 150006
-
-	ErrCodeCannotReplyWithoutPermissionToReadMessageHistory = 160002
-	ErrCodeThreadAlreadyCreatedForThisMessage               = 160004
-	ErrCodeThreadIsLocked                                   = 160005
-	ErrCodeMaximumNumberOfActiveThreadsReached              = 160006
-	ErrCodeMaximumNumberOfActiveAnnouncementThreadsReached  = 160007
-
-	ErrCodeInvalidJSONForUploadedLottieFile                    = 170001
-	ErrCodeUploadedLottiesCannotContainRasterizedImages        = 170002
-	ErrCodeStickerMaximumFramerateExceeded                     = 170003
-	ErrCodeStickerFrameCountExceedsMaximumOfOneThousandFrames  = 170004
-	ErrCodeLottieAnimationMaximumDimensionsExceeded            = 170005
-	ErrCodeStickerFrameRateOutOfRange                          = 170006
-	ErrCodeStickerAnimationDurationExceedsMaximumOfFiveSeconds = 170007
-
-	ErrCodeCannotUpdateAFinishedEvent             = 180000
-	ErrCodeFailedToCreateStageNeededForStageEvent = 180002
+	ErrCodeGeneralError ErrorCode = 0
+
+	ErrCodeUnknownAccount                        ErrorCode = 10001
+	ErrCodeUnknownApplication                    ErrorCode = 10002
+	ErrCodeUnknownChannel                        ErrorCode = 10003
+	ErrCodeUnknownGuild                          ErrorCode = 10004
+	ErrCodeUnknownIntegration                    ErrorCode = 10005
+	ErrCodeUnknownInvite                         ErrorCode = 10006
+	ErrCodeUnknownMember                         ErrorCode = 10007
+	ErrCodeUnknownMessage                        ErrorCode = 10008
+	ErrCodeUnknownOverwrite                      ErrorCode = 10009
+	ErrCodeUnknownProvider                       ErrorCode = 10010
+	ErrCodeUnknownRole                           ErrorCode = 10011
+	ErrCodeUnknownToken                          ErrorCode = 10012
+	ErrCodeUnknownUser                           ErrorCode = 10013
+	ErrCodeUnknownEmoji                          ErrorCode = 10014
+	ErrCodeUnknownWebhook                        ErrorCode = 10015
+	ErrCodeUnknownWebhookService                 ErrorCode = 10016
+	ErrCodeUnknownSession                        ErrorCode = 10020
+	ErrCodeUnknownAsset                          ErrorCode = 10021
+	ErrCodeUnknownBan                            ErrorCode = 10026
+	ErrCodeUnknownSKU                            ErrorCode = 10027
+	ErrCodeUnknownStoreListing                   ErrorCode = 10028
+	ErrCodeUnknownEntitlement                    ErrorCode = 10029
+	ErrCodeUnknownBuild                          ErrorCode = 10030
+	ErrCodeUnknownLobby                          ErrorCode = 10031
+	ErrCodeUnknownBranch                         ErrorCode = 10032
+	ErrCodeUnknownStoreDirectoryLayout           ErrorCode = 10033
+	ErrCodeUnknownRedistributable                ErrorCode = 10036
+	ErrCodeUnknownGiftCode                       ErrorCode = 10038
+	ErrCodeUnknownStream                         ErrorCode = 10049
+	ErrCodeUnknownPremiumServerSubscribeCooldown ErrorCode = 10050
+	ErrCodeUnknownGuildTemplate                  ErrorCode = 10057
+	ErrCodeUnknownDiscoveryCategory              ErrorCode = 10059
+	ErrCodeUnknownSticker                        ErrorCode = 10060
+	ErrCodeUnknownStickerPack                    ErrorCode = 10061
+	ErrCodeUnknownInteraction                    ErrorCode = 10062
+	ErrCodeUnknownApplicationCommand             ErrorCode = 10063
+	ErrCodeUnknownVoiceState                     ErrorCode = 10065
+	ErrCodeUnknownApplicationCommandPermissions  ErrorCode = 10066
+	ErrCodeUnknownStageInstance                  ErrorCode = 10067
+	ErrCodeUnknownGuildMemberVerificationForm    ErrorCode = 10068
+	ErrCodeUnknownGuildWelcomeScreen             ErrorCode = 10069
+	ErrCodeUnknownGuildScheduledEvent            ErrorCode = 10070
+	ErrCodeUnknownGuildScheduledEventUser        ErrorCode = 10071
+	ErrUnknownTag                                ErrorCode = 10087
+
+	ErrCodeBotsCannotUseEndpoint                                            ErrorCode = 20001
+	ErrCodeOnlyBotsCanUseEndpoint                                           ErrorCode = 20002
+	ErrCodeExplicitContentCannotBeSentToTheDesiredRecipients                ErrorCode = 20009
+	ErrCodeYouAreNotAuthorizedToPerformThisActionOnThisApplication          ErrorCode = 20012
+	ErrCodeThisActionCannotBePerformedDueToSlowmodeRateLimit                ErrorCode = 20016
+	ErrCodeOnlyTheOwnerOfThisAccountCanPerformThisAction                    ErrorCode = 20018
+	ErrCodeMessageCannotBeEditedDueToAnnouncementRateLimits                 ErrorCode = 20022
+	ErrCodeUnderMinimumAge                                                  ErrorCode = 20024
+	ErrCodeChannelHasHitWriteRateLimit                                      ErrorCode = 20028
+	ErrCodeTheWriteActionYouArePerformingOnTheServerHasHitTheWriteRateLimit ErrorCode = 20029
+	ErrCodeStageTopicContainsNotAllowedWordsForPublicStages                 ErrorCode = 20031
+	ErrCodeGuildPremiumSubscriptionLevelTooLow                              ErrorCode = 20035
+
+	ErrCodeMaximumGuildsReached                                     ErrorCode = 30001
+	ErrCodeMaximumFriendsReached                                    ErrorCode = 30002
+	ErrCodeMaximumPinsReached                                       ErrorCode = 30003
+	ErrCodeMaximumNumberOfRecipientsReached                         ErrorCode = 30004
+	ErrCodeMaximumGuildRolesReached                                 ErrorCode = 30005
+	ErrCodeMaximumNumberOfWebhooksReached                           ErrorCode = 30007
+	ErrCodeMaximumNumberOfEmojisReached                             ErrorCode = 30008
+	ErrCodeTooManyReactions                                         ErrorCode = 30010
+	ErrCodeMaximumNumberOfGroupDMsReached                           ErrorCode = 30011
+	ErrCodeMaximumNumberOfGuildChannelsReached                      ErrorCode = 30013
+	ErrCodeMaximumNumberOfAttachmentsInAMessageReached              ErrorCode = 30015
+	ErrCodeMaximumNumberOfInvitesReached                            ErrorCode = 30016
+	ErrCodeMaximumNumberOfAnimatedEmojisReached                     ErrorCode = 30018
+	ErrCodeMaximumNumberOfServerMembersReached                      ErrorCode = 30019
+	ErrCodeMaximumNumberOfGuildDiscoverySubcategoriesReached        ErrorCode = 30030
+	ErrCodeGuildAlreadyHasATemplate                                 ErrorCode = 30031
+	ErrCodeMaximumNumberOfApplicationCommandsReached                ErrorCode = 30032
+	ErrCodeMaximumNumberOfThreadParticipantsReached                 ErrorCode = 30033
+	ErrCodeMaximumNumberOfDailyApplicationCommandCreatesReached     ErrorCode = 30034
+	ErrCodeMaximumNumberOfBansForNonGuildMembersHaveBeenExceeded    ErrorCode = 30035
+	ErrCodeMaximumNumberOfBansFetchesHasBeenReached                 ErrorCode = 30037
+	ErrCodeMaximumNumberOfUncompletedGuildScheduledEventsReached    ErrorCode = 30038
+	ErrCodeMaximumNumberOfStickersReached                           ErrorCode = 30039
+	ErrCodeMaximumNumberOfPruneRequestsHasBeenReached               ErrorCode = 30040
+	ErrCodeMaximumNumberOfGuildWidgetSettingsUpdatesHasBeenReached  ErrorCode = 30042
+	ErrCodeMaximumNumberOfEditsToMessagesOlderThanOneHourReached    ErrorCode = 30046
+	ErrCodeMaximumNumberOfPinnedThreadsInForumChannelHasBeenReached ErrorCode = 30047
+	ErrCodeMaximumNumberOfTagsInForumChannelHasBeenReached          ErrorCode = 30048
+	ErrCodeBitrateIsTooHighForChannelOfThisType                     ErrorCode = 30052
+	ErrCodeMaximumNumberOfPremiumEmojisReached                      ErrorCode = 30056
+	ErrCodeMaximumNumberOfWebhooksPerGuildReached                   ErrorCode = 30058
+	ErrCodeMaximumNumberOfChannelPermissionOverwritesReached        ErrorCode = 30060
+	ErrCodeTheChannelsForThisGuildAreTooLarge                       ErrorCode = 30061
+
+	ErrCodeUnauthorized                           ErrorCode = 40001
+	ErrCodeActionRequiredVerifiedAccount          ErrorCode = 40002
+	ErrCodeOpeningDirectMessagesTooFast           ErrorCode = 40003
+	ErrCodeSendMessagesHasBeenTemporarilyDisabled ErrorCode = 40004
+	ErrCodeRequestEntityTooLarge                  ErrorCode = 40005
+	ErrCodeFeatureTemporarilyDisabledServerSide   ErrorCode = 40006
+	ErrCodeUserIsBannedFromThisGuild              ErrorCode = 40007
+	ErrCodeConnectionHasBeenRevoked               ErrorCode = 40012
+	ErrCodeTargetIsNotConnectedToVoice            ErrorCode = 40032
+	ErrCodeMessageAlreadyCrossposted              ErrorCode = 40033
+	ErrCodeAnApplicationWithThatNameAlreadyExists ErrorCode = 40041
+	ErrCodeApplicationInteractionFailedToSend     ErrorCode = 40043
+	ErrCodeCannotSendAMessageInAForumChannel      ErrorCode = 40058
+	ErrCodeInteractionHasAlreadyBeenAcknowledged  ErrorCode = 40060
+	ErrCodeTagNamesMustBeUnique                   ErrorCode = 40061
+	ErrCodeServiceResourceIsBeingRateLimited      ErrorCode = 40062
+	ErrCodeNoTagsAvailableForNonModerators        ErrorCode = 40066
+	ErrCodeTagRequiredToCreateAForumPost          ErrorCode = 40067
+	ErrCodeMaximumNumberOfFollowUpMessagesReached ErrorCode = 40094
+	ErrCodeCloudflareIsBlockingYourRequest        ErrorCode = 40333
+
+	ErrCodeMissingAccess                                                ErrorCode = 50001
+	ErrCodeInvalidAccountType                                           ErrorCode = 50002
+	ErrCodeCannotExecuteActionOnDMChannel                               ErrorCode = 50003
+	ErrCodeEmbedDisabled                                                ErrorCode = 50004
+	ErrCodeGuildWidgetDisabled                                          ErrorCode = 50004
+	ErrCodeCannotEditFromAnotherUser                                    ErrorCode = 50005
+	ErrCodeCannotSendEmptyMessage                                       ErrorCode = 50006
+	ErrCodeCannotSendMessagesToThisUser                                 ErrorCode = 50007
+	ErrCodeCannotSendMessagesInVoiceChannel                             ErrorCode = 50008
+	ErrCodeChannelVerificationLevelTooHigh                              ErrorCode = 50009
+	ErrCodeOAuth2ApplicationDoesNotHaveBot                              ErrorCode = 50010
+	ErrCodeOAuth2ApplicationLimitReached                                ErrorCode = 50011
+	ErrCodeInvalidOAuthState                                            ErrorCode = 50012
+	ErrCodeMissingPermissions                                           ErrorCode = 50013
+	ErrCodeInvalidAuthenticationToken                                   ErrorCode = 50014
+	ErrCodeNoteWasTooLong                                               ErrorCode = 50015
+	ErrCodeTooFewOrTooManyMessagesToDelete                              ErrorCode = 50016
+	ErrCodeInvalidMFALevel                                              ErrorCode = 50017
+	ErrCodeCanOnlyPinMessageToOriginatingChannel                        ErrorCode = 50019
+	ErrCodeInviteCodeWasEitherInvalidOrTaken                            ErrorCode = 50020
+	ErrCodeCannotExecuteActionOnSystemMessage                           ErrorCode = 50021
+	ErrCodeCannotExecuteActionOnThisChannelType                         ErrorCode = 50024
+	ErrCodeInvalidOAuth2AccessTokenProvided                             ErrorCode = 50025
+	ErrCodeMissingRequiredOAuth2Scope                                   ErrorCode = 50026
+	ErrCodeInvalidWebhookTokenProvided                                  ErrorCode = 50027
+	ErrCodeInvalidRole                                                  ErrorCode = 50028
+	ErrCodeInvalidRecipients                                            ErrorCode = 50033
+	ErrCodeMessageProvidedTooOldForBulkDelete                           ErrorCode = 50034
+	ErrCodeInvalidFormBody                                              ErrorCode = 50035
+	ErrCodeInviteAcceptedToGuildApplicationsBotNotIn                    ErrorCode = 50036
+	ErrCodeInvalidActivityAction                                        ErrorCode = 50039
+	ErrCodeInvalidAPIVersionProvided                                    ErrorCode = 50041
+	ErrCodeFileUploadedExceedsTheMaximumSize                            ErrorCode = 50045
+	ErrCodeInvalidFileUploaded                                          ErrorCode = 50046
+	ErrCodeCannotSelfRedeemThisGift                                     ErrorCode = 50054
+	ErrCodeInvalidGuild                                                 ErrorCode = 50055
+	ErrCodeInvalidSKU                                                   ErrorCode = 50057
+	ErrCodeInvalidRequestOrigin                                         ErrorCode = 50067
+	ErrCodeInvalidMessageType                                           ErrorCode = 50068
+	ErrCodePaymentSourceRequiredToRedeemGift                            ErrorCode = 50070
+	ErrCodeCannotModifyASystemWebhook                                   ErrorCode = 50073
+	ErrCodeCannotDeleteAChannelRequiredForCommunityGuilds               ErrorCode = 50074
+	ErrCodeCannotEditStickersWithinAMessage                             ErrorCode = 50080
+	ErrCodeInvalidStickerSent                                           ErrorCode = 50081
+	ErrCodePerformedOperationOnArchivedThread                           ErrorCode = 50083
+	ErrCodeInvalidThreadNotificationSettings                            ErrorCode = 50084
+	ErrCodeBeforeValueIsEarlierThanThreadCreationDate                   ErrorCode = 50085
+	ErrCodeCommunityServerChannelsMustBeTextChannels                    ErrorCode = 50086
+	ErrCodeThisServerIsNotAvailableInYourLocation                       ErrorCode = 50095
+	ErrCodeThisServerNeedsMonetizationEnabledInOrderToPerformThisAction ErrorCode = 50097
+	ErrCodeThisServerNeedsMoreBoostsToPerformThisAction                 ErrorCode = 50101
+	ErrCodeTheRequestBodyContainsInvalidJSON                            ErrorCode = 50109
+	ErrCodeOwnerCannotBePendingMember                                   ErrorCode = 50131
+	ErrCodeOwnershipCannotBeTransferredToABotUser                       ErrorCode = 50132
+	ErrCodeFailedToResizeAssetBelowTheMaximumSize                       ErrorCode = 50138
+	ErrCodeUploadedFileNotFound                                         ErrorCode = 50146
+	ErrCodeVoiceMessagesDoNotSupportAdditionalContent                   ErrorCode = 50159
+	ErrCodeVoiceMessagesMustHaveASingleAudioAttachment                  ErrorCode = 50160
+	ErrCodeVoiceMessagesMustHaveSupportingMetadata                      ErrorCode = 50161
+	ErrCodeVoiceMessagesCannotBeEdited                                  ErrorCode = 50162
+	ErrCodeCannotSendVoiceMessagesInThisChannel                         ErrorCode = 50173
+	ErrCodeUserAccountMustFirstBeVerified                               ErrorCode = 50178
+	ErrCodeNoPermissionToSendThisSticker                                ErrorCode = 50600
+
+	ErrCodeTwoFactorIsRequiredForThisOperation ErrorCode = 60003
+
+	ErrCodeNoUsersWithDiscordTagExist ErrorCode = 80004
+
+	ErrCodeReactionBlocked             ErrorCode = 90001
+	ErrCodeUserCannotUseBurstReactions ErrorCode = 90002
+
+	ErrCodeApplicationNotYetAvailable ErrorCode = 110001
+
+	ErrCodeAPIResourceIsCurrentlyOverloaded ErrorCode = 130000
+
+	ErrCodeTheStageIsAlreadyOpen ErrorCode = 150006
+
+	ErrCodeCannotReplyWithoutPermissionToReadMessageHistory ErrorCode = 160002
+	ErrCodeThreadAlreadyCreatedForThisMessage               ErrorCode = 160004
+	ErrCodeThreadIsLocked                                   ErrorCode = 160005
+	ErrCodeMaximumNumberOfActiveThreadsReached              ErrorCode = 160006
+	ErrCodeMaximumNumberOfActiveAnnouncementThreadsReached  ErrorCode = 160007
+
+	ErrCodeInvalidJSONForUploadedLottieFile                    ErrorCode = 170001
+	ErrCodeUploadedLottiesCannotContainRasterizedImages        ErrorCode = 170002
+	ErrCodeStickerMaximumFramerateExceeded                     ErrorCode = 170003
+	ErrCodeStickerFrameCountExceedsMaximumOfOneThousandFrames  ErrorCode = 170004
+	ErrCodeLottieAnimationMaximumDimensionsExceeded            ErrorCode = 170005
+	ErrCodeStickerFrameRateOutOfRange                          ErrorCode = 170006
+	ErrCodeStickerAnimationDurationExceedsMaximumOfFiveSeconds ErrorCode = 170007
+
+	ErrCodeCannotUpdateAFinishedEvent             ErrorCode = 180000
+	ErrCodeFailedToCreateStageNeededForStageEvent ErrorCode = 180002
+
+	ErrCodeMessageWasBlockedByAutomaticModeration ErrorCode = 200000
+	ErrCodeTitleWasBlockedByAutomaticModeration   ErrorCode = 200001
+
+	ErrCodeWebhooksPostedToForumChannelsMustHaveAThreadNameOrThreadID        ErrorCode = 220001
+	ErrCodeWebhooksPostedToForumChannelsCannotHaveBothAThreadNameAndThreadID ErrorCode = 220002
+	ErrCodeWebhooksCanOnlyCreateThreadsInForumChannels                       ErrorCode = 220003
+	ErrCodeWebhookServicesCannotBeUsedInForumChannels                        ErrorCode = 220004
+
+	ErrCodeMessageBlockedByHarmfulLinksFilter ErrorCode = 240000
 )
 
 // Intent is the type of a Gateway Intent
@@ -56,6 +56,8 @@ func handlerForInterface(handler any) EventHandler {
     return interfaceEventHandler(v){{range .}}
   case func(*Session, *{{.}}):
     return {{privateName .}}EventHandler(v){{end}}
+  case EventHandler:
+    return v
   }
 
   return nil
@@ -104,7 +106,7 @@ func constCase(name string) string {
 
 func isDiscordEvent(name string) bool {
 	switch {
-	case name == "Connect", name == "Disconnect", name == "Event", name == "RateLimit", name == "Interface":
+	case name == "Connect", name == "Disconnect", name == "Event", name == "RateLimit", name == "StateChange", name == "ReconnectFailed", name == "VoiceReconnectFailed", name == "Interface":
 		return false
 	default:
 		return true
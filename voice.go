@@ -11,6 +11,7 @@ package discordgo
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
@@ -21,7 +22,6 @@ import (
 	"github.com/goccy/go-json"
 
 	"github.com/gorilla/websocket"
-	"golang.org/x/crypto/nacl/secretbox"
 )
 
 // ------------------------------------------------------------------------------------------------
@@ -64,6 +64,18 @@ type VoiceConnection struct {
 	op4 voiceOP4
 	op2 voiceOP2
 
+	// crypto encrypts and decrypts the voice packets with the mode and key
+	// of op4.
+	crypto *voiceCrypto
+
+	// dave negotiates the keys of the end-to-end encryption of the opus
+	// frames, when the voice server supports it.
+	dave *daveSession
+
+	// seqAck is the sequence number of the last message received on the
+	// voice websocket, acknowledged by heartbeats and resumes.
+	seqAck int
+
 	voiceSpeakingUpdateHandlers []VoiceSpeakingUpdateHandler
 }
 
@@ -120,7 +132,7 @@ func (v *VoiceConnection) ChangeChannel(channelID string, mute, deaf bool) (err
 
 	data := voiceChannelJoinOp{4, voiceChannelJoinData{&v.GuildID, &channelID, mute, deaf}}
 	v.session.wsMutex.Lock()
-	err = v.session.wsConn.WriteJSON(data)
+	err = v.session.writeGateway(v.session.wsConn, data)
 	v.session.wsMutex.Unlock()
 	if err != nil {
 		return
@@ -142,7 +154,7 @@ func (v *VoiceConnection) Disconnect() (err error) {
 	if v.sessionID != "" {
 		data := voiceChannelJoinOp{4, voiceChannelJoinData{&v.GuildID, nil, true, true}}
 		v.session.wsMutex.Lock()
-		err = v.session.wsConn.WriteJSON(data)
+		err = v.session.writeGateway(v.session.wsConn, data)
 		v.session.wsMutex.Unlock()
 		v.sessionID = ""
 	}
@@ -185,6 +197,8 @@ func (v *VoiceConnection) Close() {
 		}
 		v.udpConn = nil
 	}
+	v.crypto = nil
+	v.dave = nil
 
 	if v.wsConn != nil {
 		v.log(LogInformational, "sending close frame")
@@ -226,25 +240,65 @@ type VoiceSpeakingUpdate struct {
 	Speaking bool   `json:"speaking"`
 }
 
+// Voice close event codes.
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#voice-voice-close-event-codes
+const (
+	VoiceCloseUnknownOpcode         = 4001
+	VoiceCloseDecodeError           = 4002
+	VoiceCloseNotAuthenticated      = 4003
+	VoiceCloseAuthenticationFailed  = 4004
+	VoiceCloseAlreadyAuthenticated  = 4005
+	VoiceCloseSessionNoLongerValid  = 4006
+	VoiceCloseSessionTimeout        = 4009
+	VoiceCloseServerNotFound        = 4011
+	VoiceCloseUnknownProtocol       = 4012
+	VoiceCloseDisconnected          = 4014
+	VoiceCloseServerCrashed         = 4015
+	VoiceCloseUnknownEncryptionMode = 4016
+)
+
+// voiceGatewayVersion is the version of the voice gateway protocol.
+const voiceGatewayVersion = "8"
+
+// voiceCloseResumable reports whether a voice session can be resumed after
+// its websocket was closed with code. The others must be joined again.
+func voiceCloseResumable(code int) bool {
+	return code < 4000 || code == VoiceCloseServerCrashed
+}
+
 // ------------------------------------------------------------------------------------------------
 // Unexported Internal Functions Below.
 // ------------------------------------------------------------------------------------------------
 
 // A voiceOP4 stores the data for the voice operation 4 websocket event
-// which provides us with the NaCl SecretBox encryption key
+// which provides us with the encryption mode and key
 type voiceOP4 struct {
-	SecretKey [32]byte `json:"secret_key"`
-	Mode      string   `json:"mode"`
+	SecretKey           [32]byte `json:"secret_key"`
+	Mode                string   `json:"mode"`
+	DAVEProtocolVersion int      `json:"dave_protocol_version"`
 }
 
 // A voiceOP2 stores the data for the voice operation 2 websocket event
 // which is sort of like the voice READY packet
 type voiceOP2 struct {
-	SSRC              uint32        `json:"ssrc"`
-	Port              int           `json:"port"`
-	Modes             []string      `json:"modes"`
-	HeartbeatInterval time.Duration `json:"heartbeat_interval"`
-	IP                string        `json:"ip"`
+	SSRC  uint32   `json:"ssrc"`
+	Port  int      `json:"port"`
+	Modes []string `json:"modes"`
+	IP    string   `json:"ip"`
+}
+
+// A voiceOP8 stores the data for the voice operation 8 websocket event
+// which is the voice HELLO packet
+type voiceOP8 struct {
+	HeartbeatInterval float64 `json:"heartbeat_interval"` // In milliseconds
+}
+
+// A voiceEvent is a message received on the voice websocket. Seq is set
+// for the messages which are replayed on resume.
+type voiceEvent struct {
+	Operation int             `json:"op"`
+	Seq       *int            `json:"seq"`
+	RawData   json.RawMessage `json:"d"`
 }
 
 // WaitUntilConnected waits for the Voice Connection to
@@ -278,19 +332,14 @@ func (v *VoiceConnection) open() (err error) {
 
 	v.log(LogInformational, "called")
 
-	v.Lock()
-	defer v.Unlock()
-
-	// Don't open a websocket if one is already open
-	if v.wsConn != nil {
-		v.log(LogWarning, "refusing to overwrite non-nil websocket")
-		return
-	}
-
 	// TODO temp? loop to wait for the SessionID
+	// The lock is not held, as the SessionID is set under it.
 	i := 0
 	for {
-		if v.sessionID != "" {
+		v.RLock()
+		sessionID := v.sessionID
+		v.RUnlock()
+		if sessionID != "" {
 			break
 		}
 		if i > 20 { // only loop for up to 1 second total
@@ -300,33 +349,35 @@ func (v *VoiceConnection) open() (err error) {
 		i++
 	}
 
-	// Connect to VoiceConnection Websocket
-	vg := "wss://" + strings.TrimSuffix(v.endpoint, ":80")
-	v.log(LogInformational, "connecting to voice endpoint %s", vg)
-	v.wsConn, _, err = v.session.Dialer.Dial(vg, nil)
-	if err != nil {
-		v.log(LogWarning, "error connecting to voice endpoint %s, %s", vg, err)
-		v.log(LogDebug, "voice struct: %#v\n", v)
+	v.Lock()
+	defer v.Unlock()
+
+	// Don't open a websocket if one is already open
+	if v.wsConn != nil {
+		v.log(LogWarning, "refusing to overwrite non-nil websocket")
 		return
 	}
 
 	type voiceHandshakeData struct {
-		ServerID  string `json:"server_id"`
-		UserID    string `json:"user_id"`
-		SessionID string `json:"session_id"`
-		Token     string `json:"token"`
+		ServerID               string `json:"server_id"`
+		UserID                 string `json:"user_id"`
+		SessionID              string `json:"session_id"`
+		Token                  string `json:"token"`
+		MaxDAVEProtocolVersion int    `json:"max_dave_protocol_version"`
 	}
 	type voiceHandshakeOp struct {
 		Op   int                `json:"op"` // Always 0
 		Data voiceHandshakeData `json:"d"`
 	}
-	data := voiceHandshakeOp{0, voiceHandshakeData{v.GuildID, v.UserID, v.sessionID, v.token}}
+	data := voiceHandshakeOp{0, voiceHandshakeData{v.GuildID, v.UserID, v.sessionID, v.token, daveProtocolVersion}}
 
-	v.wsMutex.Lock()
-	err = v.wsConn.WriteJSON(data)
-	v.wsMutex.Unlock()
+	// A new voice session starts without any message to acknowledge, nor
+	// MLS group.
+	v.seqAck = -1
+	v.dave = newDAVESession(v.UserID, v.ChannelID)
+
+	err = v.dial(data)
 	if err != nil {
-		v.log(LogWarning, "error sending init packet, %s", err)
 		return
 	}
 
@@ -344,6 +395,80 @@ func (v *VoiceConnection) open() (err error) {
 	return
 }
 
+// dial connects to the voice websocket, and sends the given identify or
+// resume packet. v must be locked.
+func (v *VoiceConnection) dial(data any) (err error) {
+
+	// Connect to VoiceConnection Websocket
+	vg := "wss://" + strings.TrimSuffix(v.endpoint, ":80") + "/?v=" + voiceGatewayVersion
+	v.log(LogInformational, "connecting to voice endpoint %s", vg)
+	v.wsConn, _, err = v.session.Dialer.Dial(vg, nil)
+	if err != nil {
+		v.log(LogWarning, "error connecting to voice endpoint %s, %s", vg, err)
+		v.log(LogDebug, "voice struct: %#v\n", v)
+		return
+	}
+
+	v.wsMutex.Lock()
+	err = v.wsConn.WriteJSON(data)
+	v.wsMutex.Unlock()
+	if err != nil {
+		v.log(LogWarning, "error sending init packet, %s", err)
+		v.wsConn.Close()
+		v.wsConn = nil
+		return
+	}
+
+	return
+}
+
+type voiceResumeData struct {
+	ServerID  string `json:"server_id"`
+	SessionID string `json:"session_id"`
+	Token     string `json:"token"`
+	SeqAck    int    `json:"seq_ack"`
+}
+
+type voiceResumeOp struct {
+	Op   int             `json:"op"` // Always 7
+	Data voiceResumeData `json:"d"`
+}
+
+// resume reopens the voice websocket closed with a resumable code, and
+// resumes the voice session, which replays the messages missed since the
+// last one acknowledged. The UDP connection is kept open. When the websocket
+// cannot be reopened, the voice channel is joined again.
+func (v *VoiceConnection) resume(wsConn *websocket.Conn) {
+
+	v.log(LogInformational, "called")
+
+	v.Lock()
+
+	// The connection was closed or replaced meanwhile.
+	if v.wsConn != wsConn || v.close == nil {
+		v.Unlock()
+		return
+	}
+	wsConn.Close()
+
+	data := voiceResumeOp{7, voiceResumeData{v.GuildID, v.sessionID, v.token, v.seqAck}}
+	err := v.dial(data)
+	if err == nil {
+		wsConn, close := v.wsConn, v.close
+		go func() {
+			defer v.session.ErrorChecker()
+
+			v.wsListen(wsConn, close)
+		}()
+	}
+	v.Unlock()
+
+	if err != nil {
+		v.log(LogError, "error resuming voice session, %s", err)
+		v.reconnect()
+	}
+}
+
 // wsListen listens on the voice websocket for messages and passes them
 // to the voice event handler.  This is automatically called by the Open func
 func (v *VoiceConnection) wsListen(wsConn *websocket.Conn, close <-chan struct{}) {
@@ -351,7 +476,7 @@ func (v *VoiceConnection) wsListen(wsConn *websocket.Conn, close <-chan struct{}
 	v.log(LogInformational, "called")
 
 	for {
-		_, message, err := v.wsConn.ReadMessage()
+		messageType, message, err := wsConn.ReadMessage()
 		if err != nil {
 			// 4014 indicates a manual disconnection by someone in the guild;
 			// we shouldn't reconnect.
@@ -401,21 +526,41 @@ func (v *VoiceConnection) wsListen(wsConn *websocket.Conn, close <-chan struct{}
 
 				v.log(LogError, "voice endpoint %s websocket closed unexpectantly, %s", v.endpoint, err)
 
-				// Start reconnect goroutine then exit.
-				go func() {
-					defer v.session.ErrorChecker()
-			
-					v.reconnect()
-				}()
+				// Resume the voice session when possible, or join the
+				// channel again, then exit.
+				var closeErr *websocket.CloseError
+				if errors.As(err, &closeErr) && !voiceCloseResumable(closeErr.Code) {
+					go func() {
+						defer v.session.ErrorChecker()
+
+						v.reconnect()
+					}()
+				} else {
+					go func() {
+						defer v.session.ErrorChecker()
+
+						v.resume(wsConn)
+					}()
+				}
 			}
 			return
 		}
 
-		// Pass received message to voice event handler
+		// Pass received message to voice event handler. DAVE messages are
+		// handled in order, as each depends on the previous ones.
 		select {
 		case <-close:
 			return
 		default:
+			if messageType == websocket.BinaryMessage {
+				v.onDAVEBinary(message)
+				continue
+			}
+			if isDAVEEvent(message) {
+				v.onEvent(message)
+				continue
+			}
+
 			go func() {
 				defer v.session.ErrorChecker()
 		
@@ -431,12 +576,20 @@ func (v *VoiceConnection) onEvent(message []byte) {
 
 	v.log(LogDebug, "received: %s", string(message))
 
-	var e Event
+	var e voiceEvent
 	if err := json.Unmarshal(message, &e); err != nil {
 		v.log(LogError, "unmarshall error, %s", err)
 		return
 	}
 
+	if e.Seq != nil {
+		v.Lock()
+		if *e.Seq > v.seqAck {
+			v.seqAck = *e.Seq
+		}
+		v.Unlock()
+	}
+
 	switch e.Operation {
 
 	case 2: // READY
@@ -446,14 +599,6 @@ func (v *VoiceConnection) onEvent(message []byte) {
 			return
 		}
 
-		// Start the voice websocket heartbeat to keep the connection alive
-		go func() {
-			defer v.session.ErrorChecker()
-	
-			v.wsHeartbeat(v.wsConn, v.close, v.op2.HeartbeatInterval)
-		}()
-		// TODO monitor a chan/bool to verify this was successful
-
 		// Start the UDP connection
 		err := v.udpOpen()
 		if err != nil {
@@ -463,60 +608,104 @@ func (v *VoiceConnection) onEvent(message []byte) {
 
 		// Start the opusSender.
 		// TODO: Should we allow 48000/960 values to be user defined?
+		v.Lock()
 		if v.OpusSend == nil {
 			v.OpusSend = make(chan []byte, 2)
 		}
+		if !v.deaf && v.OpusRecv == nil {
+			v.OpusRecv = make(chan *Packet, 2)
+		}
+		udpConn, close, opusSend, opusRecv, deaf := v.udpConn, v.close, v.OpusSend, v.OpusRecv, v.deaf
+		v.Unlock()
+
 		go func() {
 			defer v.session.ErrorChecker()
-	
-			v.opusSender(v.udpConn, v.close, v.OpusSend, 48000, 960)
+
+			v.opusSender(udpConn, close, opusSend, 48000, 960)
 		}()
 
 		// Start the opusReceiver
-		if !v.deaf {
-			if v.OpusRecv == nil {
-				v.OpusRecv = make(chan *Packet, 2)
-			}
-
+		if !deaf {
 			go func() {
 				defer v.session.ErrorChecker()
-		
-				v.opusReceiver(v.udpConn, v.close, v.OpusRecv)
+
+				v.opusReceiver(udpConn, close, opusRecv)
 			}()
 		}
 
 		return
 
-	case 3: // HEARTBEAT response
+	case 6: // HEARTBEAT ACK
 		// add code to use this to track latency?
 		return
 
+	case 8: // HELLO
+		var op8 voiceOP8
+		if err := json.Unmarshal(e.RawData, &op8); err != nil {
+			v.log(LogError, "OP8 unmarshall error, %s, %s", err, string(e.RawData))
+			return
+		}
+
+		// Start the voice websocket heartbeat to keep the connection alive
+		v.RLock()
+		wsConn, close := v.wsConn, v.close
+		v.RUnlock()
+		go func() {
+			defer v.session.ErrorChecker()
+
+			v.wsHeartbeat(wsConn, close, time.Duration(op8.HeartbeatInterval*float64(time.Millisecond)))
+		}()
+		return
+
+	case 9: // RESUMED
+		v.log(LogInformational, "resumed voice session on %s", v.endpoint)
+		return
+
 	case 4: // udp encryption secret key
 		v.Lock()
-		defer v.Unlock()
-
 		v.op4 = voiceOP4{}
 		if err := json.Unmarshal(e.RawData, &v.op4); err != nil {
+			v.Unlock()
 			v.log(LogError, "OP4 unmarshall error, %s, %s", err, string(e.RawData))
 			return
 		}
-		return
 
-	case 5:
-		if len(v.voiceSpeakingUpdateHandlers) == 0 {
+		crypto, err := newVoiceCrypto(v.op4.Mode, v.op4.SecretKey)
+		if err != nil {
+			v.Unlock()
+			v.log(LogError, "OP4 encryption error, %s", err)
 			return
 		}
+		v.crypto = crypto
+		daveVersion := v.op4.DAVEProtocolVersion
+		v.Unlock()
+
+		v.onDAVESessionDescription(daveVersion)
+		return
 
+	case 5:
 		voiceSpeakingUpdate := &VoiceSpeakingUpdate{}
 		if err := json.Unmarshal(e.RawData, voiceSpeakingUpdate); err != nil {
 			v.log(LogError, "OP5 unmarshall error, %s, %s", err, string(e.RawData))
 			return
 		}
 
+		// The SSRC identifies the sender of the frames to decrypt.
+		if _, d := v.daveSession(); d != nil {
+			if userID, err := strconv.ParseUint(voiceSpeakingUpdate.UserID, 10, 64); err == nil {
+				d.Lock()
+				d.ssrcs[uint32(voiceSpeakingUpdate.SSRC)] = userID
+				d.Unlock()
+			}
+		}
+
 		for _, h := range v.voiceSpeakingUpdateHandlers {
 			h(v, voiceSpeakingUpdate)
 		}
 
+	case voiceOpClientsConnect, voiceOpClientDisconnect, voiceOpDAVEPrepareTransition, voiceOpDAVEExecuteTransition, voiceOpDAVEPrepareEpoch:
+		v.onDAVEEvent(&e)
+
 	default:
 		v.log(LogDebug, "unknown voice operation, %d, %s", e.Operation, string(e.RawData))
 	}
@@ -524,9 +713,14 @@ func (v *VoiceConnection) onEvent(message []byte) {
 	return
 }
 
+type voiceHeartbeatData struct {
+	Nonce  int64 `json:"t"`
+	SeqAck int   `json:"seq_ack"`
+}
+
 type voiceHeartbeatOp struct {
-	Op   int `json:"op"` // Always 3
-	Data int `json:"d"`
+	Op   int                `json:"op"` // Always 3
+	Data voiceHeartbeatData `json:"d"`
 }
 
 // NOTE :: When a guild voice server changes how do we shut this down
@@ -542,12 +736,21 @@ func (v *VoiceConnection) wsHeartbeat(wsConn *websocket.Conn, close <-chan struc
 	}
 
 	var err error
-	ticker := time.NewTicker(i * time.Millisecond)
+	ticker := time.NewTicker(i)
 	defer ticker.Stop()
 	for {
+		// Stop once the websocket was replaced by a resume.
+		v.RLock()
+		current := v.wsConn == wsConn
+		seqAck := v.seqAck
+		v.RUnlock()
+		if !current {
+			return
+		}
+
 		v.log(LogDebug, "sending heartbeat packet")
 		v.wsMutex.Lock()
-		err = wsConn.WriteJSON(voiceHeartbeatOp{3, int(time.Now().Unix())})
+		err = wsConn.WriteJSON(voiceHeartbeatOp{3, voiceHeartbeatData{time.Now().UnixMilli(), seqAck}})
 		v.wsMutex.Unlock()
 		if err != nil {
 			v.log(LogError, "error sending heartbeat to voice endpoint %s, %s", v.endpoint, err)
@@ -570,7 +773,7 @@ func (v *VoiceConnection) wsHeartbeat(wsConn *websocket.Conn, close <-chan struc
 type voiceUDPData struct {
 	Address string `json:"address"` // Public IP of machine running this code
 	Port    uint16 `json:"port"`    // UDP Port of machine running this code
-	Mode    string `json:"mode"`    // always "xsalsa20_poly1305"
+	Mode    string `json:"mode"`    // Encryption mode selected from the OP2 modes
 }
 
 type voiceUDPD struct {
@@ -608,6 +811,12 @@ func (v *VoiceConnection) udpOpen() (err error) {
 		return fmt.Errorf("empty endpoint")
 	}
 
+	mode, err := selectVoiceMode(v.op2.Modes)
+	if err != nil {
+		v.log(LogWarning, "error selecting encryption mode, %s", err)
+		return
+	}
+
 	host := v.op2.IP + ":" + strconv.Itoa(v.op2.Port)
 	addr, err := net.ResolveUDPAddr("udp", host)
 	if err != nil {
@@ -665,7 +874,7 @@ func (v *VoiceConnection) udpOpen() (err error) {
 
 	// Take the data from above and send it back to Discord to finalize
 	// the UDP connection handshake.
-	data := voiceUDPOp{1, voiceUDPD{"udp", voiceUDPData{ip, port, "xsalsa20_poly1305"}}}
+	data := voiceUDPOp{1, voiceUDPD{"udp", voiceUDPData{ip, port, mode}}}
 
 	v.wsMutex.Lock()
 	err = v.wsConn.WriteJSON(data)
@@ -744,17 +953,17 @@ func (v *VoiceConnection) opusSender(udpConn *net.UDPConn, close <-chan struct{}
 	var timestamp uint32
 	var recvbuf []byte
 	var ok bool
+	var nonce uint32
 	udpHeader := make([]byte, 12)
-	var nonce [24]byte
 
 	// build the parts that don't change in the udpHeader
 	udpHeader[0] = 0x80
 	udpHeader[1] = 0x78
 	binary.BigEndian.PutUint32(udpHeader[8:], v.op2.SSRC)
 
-	// start a send loop that loops until buf chan is closed
-	ticker := time.NewTicker(time.Millisecond * time.Duration(size/(rate/1000)))
-	defer ticker.Stop()
+	// start a send loop that loops until buf chan is closed. Packets are
+	// sent one after the other, at the pace of their duration.
+	var next time.Time
 	for {
 
 		// Get data from chan.  If chan is closed, return.
@@ -778,24 +987,41 @@ func (v *VoiceConnection) opusSender(udpConn *net.UDPConn, close <-chan struct{}
 			}
 		}
 
+		samples := opusPacketSamples(recvbuf)
+		if samples == 0 {
+			samples = size
+		}
+
 		// Add sequence and timestamp to udpPacket
 		binary.BigEndian.PutUint16(udpHeader[2:], sequence)
 		binary.BigEndian.PutUint32(udpHeader[4:], timestamp)
 
-		// encrypt the opus data
-		copy(nonce[:], udpHeader)
+		// encrypt the opus data, end-to-end then for the transport
 		v.RLock()
-		sendbuf := secretbox.Seal(udpHeader, recvbuf, &nonce, &v.op4.SecretKey)
+		crypto, dave := v.crypto, v.dave
 		v.RUnlock()
+		if crypto == nil {
+			v.log(LogWarning, "dropping opus frame, encryption key not received yet")
+			continue
+		}
+		if dave != nil {
+			recvbuf = dave.encrypt(recvbuf)
+		}
+		sendbuf := crypto.seal(nil, udpHeader, recvbuf, nonce)
+		nonce++
 
 		// block here until we're exactly at the right time :)
 		// Then send rtp audio packet to Discord over UDP
+		if now := time.Now(); next.Before(now) {
+			next = now
+		}
 		select {
 		case <-close:
 			return
-		case <-ticker.C:
+		case <-time.After(time.Until(next)):
 			// continue
 		}
+		next = next.Add(time.Duration(samples) * time.Second / time.Duration(rate))
 		_, err := udpConn.Write(sendbuf)
 
 		if err != nil {
@@ -810,10 +1036,10 @@ func (v *VoiceConnection) opusSender(udpConn *net.UDPConn, close <-chan struct{}
 			sequence++
 		}
 
-		if (timestamp + uint32(size)) < timestamp {
+		if (timestamp + uint32(samples)) < timestamp {
 			timestamp = 0
 		} else {
-			timestamp += uint32(size)
+			timestamp += uint32(samples)
 		}
 	}
 }
@@ -838,7 +1064,6 @@ func (v *VoiceConnection) opusReceiver(udpConn *net.UDPConn, close <-chan struct
 	}
 
 	recvbuf := make([]byte, 1024)
-	var nonce [24]byte
 
 	for {
 		rlen, err := udpConn.Read(recvbuf)
@@ -881,23 +1106,24 @@ func (v *VoiceConnection) opusReceiver(udpConn *net.UDPConn, close <-chan struct
 		p.Sequence = binary.BigEndian.Uint16(recvbuf[2:4])
 		p.Timestamp = binary.BigEndian.Uint32(recvbuf[4:8])
 		p.SSRC = binary.BigEndian.Uint32(recvbuf[8:12])
-		// decrypt opus data
-		copy(nonce[:], recvbuf[0:12])
+		// decrypt opus data, without the header extension
+		v.RLock()
+		crypto, dave := v.crypto, v.dave
+		v.RUnlock()
+		if crypto == nil {
+			continue
+		}
 
-		if opus, ok := secretbox.Open(nil, recvbuf[12:rlen], &nonce, &v.op4.SecretKey); ok {
+		if opus, ok := crypto.open(recvbuf[:rlen]); ok {
 			p.Opus = opus
 		} else {
 			continue
 		}
-
-		// extension bit set, and not a RTCP packet
-		if ((recvbuf[0] & 0x10) == 0x10) && ((recvbuf[1] & 0x80) == 0) {
-			// get extended header length
-			extlen := binary.BigEndian.Uint16(p.Opus[2:4])
-			// 4 bytes (ext header header) + 4*extlen (ext header data)
-			shift := int(4 + 4*extlen)
-			if len(p.Opus) > shift {
-				p.Opus = p.Opus[shift:]
+		if dave != nil {
+			if opus, ok := dave.decrypt(p.SSRC, p.Opus); ok {
+				p.Opus = opus
+			} else {
+				continue
 			}
 		}
 
@@ -938,13 +1164,29 @@ func (v *VoiceConnection) reconnect() {
 	// Close any currently open connections
 	v.Close()
 
-	wait := time.Duration(1)
-	for {
+	v.session.RLock()
+	closed := v.session.closed
+	v.session.RUnlock()
+
+	policy := v.session.reconnectPolicy()
+
+	var err error
+	for attempt := 1; ; attempt++ {
 
-		<-time.After(wait * time.Second)
-		wait *= 2
-		if wait > 600 {
-			wait = 600
+		delay, ok := policy.Backoff(attempt, err)
+		if !ok {
+			v.log(LogError, "giving up reconnecting to channel %s after %d attempts", v.ChannelID, attempt-1)
+			v.reconnectFailed(attempt-1, err)
+			return
+		}
+		if werr := v.session.reconnectWait(delay, closed); werr != nil {
+			// The session was closed while waiting to reconnect.
+			if werr == ErrSessionClosed {
+				return
+			}
+			v.log(LogInformational, "reconnecting to channel %s aborted, %s", v.ChannelID, werr)
+			v.reconnectFailed(attempt-1, werr)
+			return
 		}
 
 		if !v.session.DataReady || v.session.wsConn == nil {
@@ -954,7 +1196,7 @@ func (v *VoiceConnection) reconnect() {
 
 		v.log(LogInformational, "trying to reconnect to channel %s", v.ChannelID)
 
-		_, err := v.session.ChannelVoiceJoin(v.GuildID, v.ChannelID, v.mute, v.deaf)
+		_, err = v.session.ChannelVoiceJoin(v.GuildID, v.ChannelID, v.mute, v.deaf)
 		if err == nil {
 			v.log(LogInformational, "successfully reconnected to channel %s", v.ChannelID)
 			return
@@ -967,7 +1209,7 @@ func (v *VoiceConnection) reconnect() {
 		// Send a OP4 with a nil channel to disconnect
 		data := voiceChannelJoinOp{4, voiceChannelJoinData{&v.GuildID, nil, true, true}}
 		v.session.wsMutex.Lock()
-		err = v.session.wsConn.WriteJSON(data)
+		err = v.session.writeGateway(v.session.wsConn, data)
 		v.session.wsMutex.Unlock()
 		if err != nil {
 			v.log(LogError, "error sending disconnect packet, %s", err)
@@ -975,3 +1217,14 @@ func (v *VoiceConnection) reconnect() {
 
 	}
 }
+
+// reconnectFailed emits a VoiceReconnectFailed event after the voice
+// connection gave up reconnecting.
+func (v *VoiceConnection) reconnectFailed(attempts int, err error) {
+	v.session.handleEvent(voiceReconnectFailedEventType, &VoiceReconnectFailed{
+		GuildID:   v.GuildID,
+		ChannelID: v.ChannelID,
+		Attempts:  attempts,
+		Err:       err,
+	})
+}
@@ -0,0 +1,165 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the transport encryption modes of voice connections,
+// which encrypt the RTP packets sent to and received from the voice server.
+
+package discordgo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Voice encryption modes.
+const (
+	VoiceModeAES256GCMRTPSize         = "aead_aes256_gcm_rtpsize"
+	VoiceModeXChaCha20Poly1305RTPSize = "aead_xchacha20_poly1305_rtpsize"
+
+	// VoiceModeXSalsa20Poly1305 is deprecated by Discord, and only used
+	// when the voice server supports no other mode.
+	VoiceModeXSalsa20Poly1305 = "xsalsa20_poly1305"
+)
+
+// voiceModes are the supported voice encryption modes, from the most to the
+// least preferred.
+var voiceModes = []string{
+	VoiceModeAES256GCMRTPSize,
+	VoiceModeXChaCha20Poly1305RTPSize,
+	VoiceModeXSalsa20Poly1305,
+}
+
+// ErrVoiceModeUnsupported is returned when a voice server supports none of
+// the voice encryption modes.
+var ErrVoiceModeUnsupported = errors.New("no supported voice encryption mode")
+
+// selectVoiceMode returns the preferred voice encryption mode of those
+// supported by a voice server.
+func selectVoiceMode(modes []string) (string, error) {
+	for _, mode := range voiceModes {
+		if slices.Contains(modes, mode) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %v", ErrVoiceModeUnsupported, modes)
+}
+
+// rtpHeaderSize is the size of an RTP header without CSRCs nor extension.
+const rtpHeaderSize = 12
+
+// A voiceCrypto encrypts and decrypts the RTP packets of a voice connection
+// with the mode and secret key of the voice operation 4 websocket event.
+type voiceCrypto struct {
+	key [32]byte
+
+	// aead is nil for xsalsa20_poly1305.
+	aead cipher.AEAD
+}
+
+// newVoiceCrypto returns a voiceCrypto for a voice encryption mode.
+func newVoiceCrypto(mode string, key [32]byte) (*voiceCrypto, error) {
+	c := &voiceCrypto{key: key}
+
+	var err error
+	switch mode {
+	case VoiceModeAES256GCMRTPSize:
+		var block cipher.Block
+		if block, err = aes.NewCipher(key[:]); err == nil {
+			c.aead, err = cipher.NewGCM(block)
+		}
+	case VoiceModeXChaCha20Poly1305RTPSize:
+		c.aead, err = chacha20poly1305.NewX(key[:])
+	case VoiceModeXSalsa20Poly1305:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrVoiceModeUnsupported, mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// seal appends the RTP packet of the given header and opus payload to dst,
+// and returns it. The AEAD modes use the 32 bit nonce, appended to the
+// packet, which must be incremented for every packet.
+func (c *voiceCrypto) seal(dst, header, opus []byte, nonce uint32) []byte {
+	if c.aead == nil {
+		var n [24]byte
+		copy(n[:], header)
+		return secretbox.Seal(append(dst, header...), opus, &n, &c.key)
+	}
+
+	n := make([]byte, c.aead.NonceSize())
+	binary.BigEndian.PutUint32(n, nonce)
+
+	// The header is authenticated but not encrypted.
+	dst = c.aead.Seal(append(dst, header...), n, opus, header)
+	return append(dst, n[:4]...)
+}
+
+// open decrypts an RTP packet, and returns its opus payload without the
+// header extension.
+func (c *voiceCrypto) open(packet []byte) ([]byte, bool) {
+	if len(packet) < rtpHeaderSize {
+		return nil, false
+	}
+
+	// The header of the rtpsize modes includes the CSRCs, and the profile and
+	// length of the extension, while its data is encrypted.
+	headerSize := rtpHeaderSize + 4*int(packet[0]&0x0F)
+	extension := packet[0]&0x10 != 0
+	if extension {
+		headerSize += 4
+	}
+
+	var payload []byte
+	if c.aead == nil {
+		var n [24]byte
+		copy(n[:], packet[:rtpHeaderSize])
+		var ok bool
+		if payload, ok = secretbox.Open(nil, packet[rtpHeaderSize:], &n, &c.key); !ok {
+			return nil, false
+		}
+		// The whole extension is encrypted.
+		if extension && len(payload) >= 4 {
+			payload = stripHeaderExtension(payload[4:], binary.BigEndian.Uint16(payload[2:4]))
+		}
+		return payload, true
+	}
+
+	if len(packet) < headerSize+c.aead.Overhead()+4 {
+		return nil, false
+	}
+	n := make([]byte, c.aead.NonceSize())
+	copy(n, packet[len(packet)-4:])
+
+	header := packet[:headerSize]
+	payload, err := c.aead.Open(nil, n, packet[headerSize:len(packet)-4], header)
+	if err != nil {
+		return nil, false
+	}
+	if extension {
+		payload = stripHeaderExtension(payload, binary.BigEndian.Uint16(header[headerSize-2:]))
+	}
+	return payload, true
+}
+
+// stripHeaderExtension removes the data of an RTP header extension of the
+// given length, in 32 bit words, from the start of a payload.
+func stripHeaderExtension(payload []byte, length uint16) []byte {
+	if size := 4 * int(length); len(payload) > size {
+		return payload[size:]
+	}
+	return payload
+}
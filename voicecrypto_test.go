@@ -0,0 +1,101 @@
+package discordgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestSelectVoiceMode(t *testing.T) {
+	tests := []struct {
+		modes []string
+		want  string
+	}{
+		{[]string{"xsalsa20_poly1305", "aead_xchacha20_poly1305_rtpsize", "aead_aes256_gcm_rtpsize"}, VoiceModeAES256GCMRTPSize},
+		{[]string{"xsalsa20_poly1305_lite", "aead_xchacha20_poly1305_rtpsize"}, VoiceModeXChaCha20Poly1305RTPSize},
+		{[]string{"xsalsa20_poly1305_suffix", "xsalsa20_poly1305"}, VoiceModeXSalsa20Poly1305},
+	}
+	for _, tt := range tests {
+		mode, err := selectVoiceMode(tt.modes)
+		if err != nil || mode != tt.want {
+			t.Errorf("selectVoiceMode(%v) = %q, %v, want %q", tt.modes, mode, err, tt.want)
+		}
+	}
+
+	if _, err := selectVoiceMode([]string{"xsalsa20_poly1305_lite"}); !errors.Is(err, ErrVoiceModeUnsupported) {
+		t.Errorf("expected ErrVoiceModeUnsupported, got %v", err)
+	}
+}
+
+func TestVoiceCrypto(t *testing.T) {
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	header := []byte{0x80, 0x78, 0, 1, 0, 0, 3, 192, 0, 0, 0, 42}
+	opus := []byte("opus frame")
+
+	for _, mode := range voiceModes {
+		c, err := newVoiceCrypto(mode, key)
+		if err != nil {
+			t.Fatalf("newVoiceCrypto(%s) returned error: %+v", mode, err)
+		}
+
+		packet := c.seal(nil, header, opus, 7)
+		if !bytes.Equal(packet[:12], header) {
+			t.Errorf("%s: expected the header to be sent in the clear, got %x", mode, packet[:12])
+		}
+		if c.aead != nil && binary.BigEndian.Uint32(packet[len(packet)-4:]) != 7 {
+			t.Errorf("%s: expected the nonce to be appended, got %x", mode, packet[len(packet)-4:])
+		}
+
+		got, ok := c.open(packet)
+		if !ok || !bytes.Equal(got, opus) {
+			t.Errorf("%s: open = %q, %t, want %q", mode, got, ok, opus)
+		}
+
+		packet[3]++
+		if _, ok = c.open(packet); ok {
+			t.Errorf("%s: expected a packet with a tampered header to be rejected", mode)
+		}
+	}
+
+	if _, err := newVoiceCrypto("xsalsa20_poly1305_lite", key); !errors.Is(err, ErrVoiceModeUnsupported) {
+		t.Errorf("expected ErrVoiceModeUnsupported, got %v", err)
+	}
+}
+
+func TestVoiceCryptoHeaderExtension(t *testing.T) {
+	var key [32]byte
+	opus := []byte("opus frame")
+
+	// An RTP header with the extension bit, and a one word extension.
+	header := []byte{0x90, 0x78, 0, 1, 0, 0, 3, 192, 0, 0, 0, 42, 0xBE, 0xDE, 0, 1}
+	extension := []byte{0x10, 0xFF, 0, 0}
+
+	for _, mode := range voiceModes[:2] {
+		c, err := newVoiceCrypto(mode, key)
+		if err != nil {
+			t.Fatalf("newVoiceCrypto(%s) returned error: %+v", mode, err)
+		}
+
+		// The profile and length of the extension are authenticated, and its
+		// data is encrypted with the payload.
+		packet := c.seal(nil, header, append(extension, opus...), 1)
+		got, ok := c.open(packet)
+		if !ok || !bytes.Equal(got, opus) {
+			t.Errorf("%s: open = %q, %t, want %q", mode, got, ok, opus)
+		}
+	}
+
+	c, err := newVoiceCrypto(VoiceModeXSalsa20Poly1305, key)
+	if err != nil {
+		t.Fatalf("newVoiceCrypto returned error: %+v", err)
+	}
+	packet := c.seal(nil, header[:12], append(append(header[12:], extension...), opus...), 0)
+	got, ok := c.open(packet)
+	if !ok || !bytes.Equal(got, opus) {
+		t.Errorf("%s: open = %q, %t, want %q", VoiceModeXSalsa20Poly1305, got, ok, opus)
+	}
+}
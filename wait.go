@@ -0,0 +1,154 @@
+// Discordgo - Discord bindings for Go
+// Available at https://github.com/lb-selfbot/discordgo
+
+// Copyright 2015-2016 Bruce Marriner <bruce@sqls.net>.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains helpers to wait for events, and to receive them from
+// channels.
+
+package discordgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WaitFor waits for the next event of type T for which match returns true,
+// and returns it. Any event of type T is returned when match is nil. The
+// error of ctx is returned when it is done first, e.g.
+//
+//	m, err := discordgo.WaitFor(ctx, s, func(m *discordgo.MessageCreate) bool {
+//		return m.ChannelID == channelID && m.Author.ID == userID
+//	})
+func WaitFor[T any](ctx context.Context, s *Session, match func(*T) bool) (*T, error) {
+	events := make(chan *T, 1)
+	handler := func(_ *Session, t *T) {
+		events <- t
+	}
+
+	eh := handlerForInterface(handler)
+	if eh == nil {
+		return nil, fmt.Errorf("%T is not an event type", new(T))
+	}
+
+	var filters []EventFilter
+	if match != nil {
+		filters = append(filters, Match(match))
+	}
+	remove := s.addEventHandlerOnce(eh, filters...)
+	defer remove()
+
+	select {
+	case t := <-events:
+		return t, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Subscribe returns a channel receiving the events of type T which pass all
+// filters, and a function cancelling the subscription, which closes the
+// channel. The channel is unbuffered: events wait, in the goroutines of their
+// handlers, until they are received or the subscription is cancelled. With
+// Session.SyncEvents, that is the goroutine reading the gateway.
+//
+//	chunks, cancel := discordgo.Subscribe[discordgo.GuildMembersChunk](s)
+//	defer cancel()
+func Subscribe[T any](s *Session, filters ...EventFilter) (<-chan *T, func()) {
+	events := make(chan *T)
+	done := make(chan struct{})
+
+	var (
+		mu        sync.Mutex
+		cancelled bool
+		sending   sync.WaitGroup
+	)
+	handler := func(_ *Session, t *T) {
+		mu.Lock()
+		if cancelled {
+			mu.Unlock()
+			return
+		}
+		sending.Add(1)
+		mu.Unlock()
+		defer sending.Done()
+
+		select {
+		case events <- t:
+		case <-done:
+		}
+	}
+
+	eh := handlerForInterface(handler)
+	if eh == nil {
+		s.log(LogError, "%T is not an event type, the subscription will never receive events", new(T))
+		close(events)
+		return events, func() {}
+	}
+
+	remove := s.addEventHandler(eh, filters...)
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			mu.Lock()
+			cancelled = true
+			mu.Unlock()
+
+			// Pending sends give up before the handler is removed, as a
+			// handler run synchronously blocks removing handlers.
+			close(done)
+			remove()
+			sending.Wait()
+			close(events)
+		})
+	}
+}
+
+// MessageConfirmTimeout is the longest time ChannelMessageSendComplex waits
+// for the MESSAGE_CREATE event of a message sent with MessageSend.Confirm.
+var MessageConfirmTimeout = 10 * time.Second
+
+// messageConfirmation waits for the MESSAGE_CREATE event of a sent message.
+type messageConfirmation struct {
+	created <-chan *MessageCreate
+	cancel  func()
+}
+
+// awaitMessage starts waiting for the MESSAGE_CREATE event of the message
+// sent in a channel with the given nonce. The returned confirmation must be
+// cancelled. The event is kept until it is waited for, so its handler never
+// blocks the dispatch of events.
+func (s *Session) awaitMessage(channelID, nonce string) *messageConfirmation {
+	created := make(chan *MessageCreate, 1)
+	handler := func(_ *Session, m *MessageCreate) {
+		select {
+		case created <- m:
+		default:
+		}
+	}
+
+	cancel := s.addEventHandlerOnce(messageCreateEventHandler(handler), InChannel(channelID), Match(func(m *MessageCreate) bool {
+		return m.Nonce == nonce
+	}))
+	return &messageConfirmation{created, cancel}
+}
+
+// wait returns the message of the MESSAGE_CREATE event, once it is received.
+// When ctx is done, or MessageConfirmTimeout elapsed, first, sent is returned
+// with an ErrMessageNotConfirmed error.
+func (c *messageConfirmation) wait(ctx context.Context, sent *Message) (*Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, MessageConfirmTimeout)
+	defer cancel()
+
+	select {
+	case m := <-c.created:
+		return m.Message, nil
+	case <-ctx.Done():
+		return sent, fmt.Errorf("%w: %w", ErrMessageNotConfirmed, ctx.Err())
+	}
+}
@@ -0,0 +1,152 @@
+package discordgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitFor(t *testing.T) {
+	s := &Session{State: NewState()}
+
+	go func() {
+		// Wait for WaitFor to add its handler.
+		for {
+			s.handlersMu.RLock()
+			n := len(s.onceHandlers[messageCreateEventType])
+			s.handlersMu.RUnlock()
+			if n > 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: "1", Content: "other"}})
+		s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: "2", Content: "nonce"}})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := WaitFor(ctx, s, func(m *MessageCreate) bool { return m.Content == "nonce" })
+	if err != nil {
+		t.Fatalf("WaitFor returned error: %+v", err)
+	}
+	if m.ID != "2" {
+		t.Errorf("expected message 2, got %s", m.ID)
+	}
+
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+	if n := len(s.onceHandlers[messageCreateEventType]); n != 0 {
+		t.Errorf("expected the handler to be removed, got %d handlers", n)
+	}
+}
+
+func TestWaitForContext(t *testing.T) {
+	s := &Session{State: NewState()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := WaitFor[GuildMembersChunk](ctx, s, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if n := len(s.onceHandlers[guildMembersChunkEventType]); n != 0 {
+		t.Errorf("expected the handler to be removed, got %d handlers", n)
+	}
+
+	if _, err := WaitFor[Message](ctx, s, nil); err == nil {
+		t.Error("expected an error waiting for a type which is not an event")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	s := &Session{State: NewState()}
+
+	events, cancel := Subscribe[MessageCreate](s, InChannel("1"))
+
+	for _, channelID := range []string{"1", "2", "1"} {
+		s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ChannelID: channelID}})
+	}
+
+	for range 2 {
+		select {
+		case m := <-events:
+			if m.ChannelID != "1" {
+				t.Errorf("expected a message in channel 1, got %s", m.ChannelID)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("expected the channel to be closed")
+	}
+	if n := len(s.handlers[messageCreateEventType]); n != 0 {
+		t.Errorf("expected the handler to be removed, got %d handlers", n)
+	}
+
+	// Events sent after cancelling are dropped.
+	s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ChannelID: "1"}})
+	cancel()
+}
+
+func TestSubscribeSyncEvents(t *testing.T) {
+	s := &Session{State: NewState(), SyncEvents: true}
+
+	_, cancel := Subscribe[MessageCreate](s)
+
+	// The handler blocks the dispatch of the event, until the subscription
+	// is cancelled.
+	dispatched := make(chan struct{})
+	go func() {
+		s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ChannelID: "1"}})
+		close(dispatched)
+	}()
+	for s.handlersMu.TryLock() {
+		s.handlersMu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelled := make(chan struct{})
+	go func() {
+		cancel()
+		close(cancelled)
+	}()
+	for _, c := range []chan struct{}{cancelled, dispatched} {
+		select {
+		case <-c:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out cancelling the subscription")
+		}
+	}
+}
+
+func TestAwaitMessageSyncEvents(t *testing.T) {
+	s := &Session{State: NewState(), SyncEvents: true}
+
+	c := s.awaitMessage("1", "nonce")
+
+	// The event is dispatched before it is waited for, without blocking.
+	dispatched := make(chan struct{})
+	go func() {
+		s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: "2", ChannelID: "1", Nonce: "other"}})
+		s.handleEvent(messageCreateEventType, &MessageCreate{&Message{ID: "3", ChannelID: "1", Nonce: "nonce"}})
+		close(dispatched)
+	}()
+	select {
+	case <-dispatched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out dispatching the events")
+	}
+
+	m, err := c.wait(context.Background(), nil)
+	c.cancel()
+	if err != nil || m.ID != "3" {
+		t.Errorf("expected message 3, got %+v and %v", m, err)
+	}
+}
@@ -13,6 +13,7 @@ package discordgo
 import (
 	"bytes"
 	"compress/zlib"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -38,6 +39,49 @@ var ErrWSNotFound = errors.New("no websocket connection exists")
 // more than the total shard count
 var ErrWSShardBounds = errors.New("ShardID must be less than ShardCount")
 
+// Gateway close event codes.
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#gateway-gateway-close-event-codes
+const (
+	GatewayCloseUnknownError         = 4000
+	GatewayCloseUnknownOpcode        = 4001
+	GatewayCloseDecodeError          = 4002
+	GatewayCloseNotAuthenticated     = 4003
+	GatewayCloseAuthenticationFailed = 4004
+	GatewayCloseAlreadyAuthenticated = 4005
+	GatewayCloseInvalidSeq           = 4007
+	GatewayCloseRateLimited          = 4008
+	GatewayCloseSessionTimedOut      = 4009
+	GatewayCloseInvalidShard         = 4010
+	GatewayCloseShardingRequired     = 4011
+	GatewayCloseInvalidAPIVersion    = 4012
+	GatewayCloseInvalidIntents       = 4013
+	GatewayCloseDisallowedIntents    = 4014
+)
+
+// gatewayCloseResumable reports whether a session can be resumed after its
+// connection was closed with code.
+func gatewayCloseResumable(code int) bool {
+	switch code {
+	case websocket.CloseNormalClosure, websocket.CloseGoingAway,
+		GatewayCloseAuthenticationFailed, GatewayCloseInvalidSeq, GatewayCloseSessionTimedOut,
+		GatewayCloseInvalidShard, GatewayCloseShardingRequired, GatewayCloseInvalidAPIVersion,
+		GatewayCloseInvalidIntents, GatewayCloseDisallowedIntents:
+		return false
+	}
+	return true
+}
+
+// gatewayCloseFatal reports whether a connection closed with code must not
+// be reopened, as a new connection would be closed for the same reason.
+func gatewayCloseFatal(code int) bool {
+	switch code {
+	case GatewayCloseAuthenticationFailed, GatewayCloseInvalidShard, GatewayCloseShardingRequired,
+		GatewayCloseInvalidAPIVersion, GatewayCloseInvalidIntents, GatewayCloseDisallowedIntents:
+		return true
+	}
+	return false
+}
+
 type resumePacket struct {
 	Op   int `json:"op"`
 	Data struct {
@@ -50,6 +94,27 @@ type resumePacket struct {
 // Open creates a websocket connection to Discord.
 // See: https://discord.com/developers/docs/topics/gateway#connecting
 func (s *Session) Open() error {
+	return s.OpenContext(context.Background())
+}
+
+// OpenContext creates a websocket connection to Discord, like Open. Fetching
+// the gateway URL, dialing and the handshake are aborted when ctx is done, in
+// which case the error of ctx is returned. Once open, the connection is not
+// bound to ctx.
+func (s *Session) OpenContext(ctx context.Context) error {
+	err := s.open(ctx)
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil && err != ErrWSAlreadyOpen {
+		s.setStatus(ConnectionStateDisconnected, err)
+	}
+	return err
+}
+
+// open connects to the gateway, and identifies or resumes the session. The
+// caller sets the state of a connection which failed to open.
+func (s *Session) open(ctx context.Context) error {
 	s.log(LogInformational, "called")
 
 	var err error
@@ -64,26 +129,69 @@ func (s *Session) Open() error {
 		return ErrWSAlreadyOpen
 	}
 
-	// Get the gateway to use for the Websocket connection
-	if s.gateway == "" {
-		s.gateway, err = s.Gateway()
-		if err != nil {
-			return err
+	s.setStatus(ConnectionStateConnecting, nil)
+
+	if s.closed == nil {
+		s.closed = make(chan struct{})
+	}
+
+	// The session of a restored state snapshot is resumed.
+	if s.sessionID == "" {
+		if sessionID, gateway, sequence, ok := s.State.restoredSession(); ok {
+			s.sessionID, s.resumeGatewayURL = sessionID, gateway
+			atomic.StoreInt64(s.sequence, sequence)
+		}
+	}
+
+	// A session is resumed against the URL it was given in its Ready
+	// event, and new sessions are identified on the gateway URL.
+	resuming := s.sessionID != ""
+	gateway := s.resumeGatewayURL
+	if !resuming || gateway == "" {
+		// Get the gateway to use for the Websocket connection
+		if s.gateway == "" {
+			s.gateway, err = s.Gateway(WithContext(ctx))
+			if err != nil {
+				return err
+			}
 		}
+		gateway = s.gateway
+	}
+
+	// Every connection starts a new compression context.
+	s.decompressor, err = newGatewayDecompressor(s.GatewayCompression)
+	if err != nil {
+		return err
+	}
+
+	encoding := s.GatewayEncoding
+	switch encoding {
+	case "":
+		encoding = GatewayEncodingJSON
+	case GatewayEncodingJSON, GatewayEncodingETF:
+	default:
+		return fmt.Errorf("unknown gateway encoding %q", encoding)
+	}
+	s.wsMutex.Lock()
+	s.encoding = encoding
+	s.wsMutex.Unlock()
 
-		// Add the version and encoding to the URL
-		s.gateway = s.gateway + "?v=" + APIVersion + "&encoding=json"
+	// Add the version, encoding and compression to the URL
+	gateway += "?v=" + APIVersion + "&encoding=" + string(encoding)
+	if s.GatewayCompression != GatewayCompressionNone {
+		gateway += "&compress=" + string(s.GatewayCompression)
 	}
 
 	// Connect to the Gateway
-	s.log(LogInformational, "connecting to gateway %s", s.gateway)
+	s.log(LogInformational, "connecting to gateway %s", gateway)
 	header := nethttp.Header{}
 	header.Add("accept-encoding", "zlib")
-	s.wsConn, _, err = s.Dialer.Dial(s.gateway, header)
+	s.wsConn, _, err = s.Dialer.DialContext(ctx, gateway, header)
 	if err != nil {
-		s.log(LogError, "error connecting to gateway %s, %s", s.gateway, err)
-		s.gateway = "" // clear cached gateway
-		s.wsConn = nil // Just to be safe.
+		s.log(LogError, "error connecting to gateway %s, %s", gateway, err)
+		s.gateway = ""          // clear cached gateway
+		s.resumeGatewayURL = "" // resume on the gateway URL next time
+		s.wsConn = nil          // Just to be safe.
 		return err
 	}
 
@@ -101,13 +209,16 @@ func (s *Session) Open() error {
 		}
 	}()
 
+	// Abort the handshake when ctx is done, by failing the pending read.
+	handshakeConn := s.wsConn
+	stopHandshake := context.AfterFunc(ctx, func() {
+		handshakeConn.SetReadDeadline(time.Now())
+	})
+	defer stopHandshake()
+
 	// The first response from Discord should be an Op 10 (Hello) Packet.
 	// When processed by onEvent the heartbeat goroutine will be started.
-	mt, m, err := s.wsConn.ReadMessage()
-	if err != nil {
-		return err
-	}
-	e, err := s.onEvent(mt, m)
+	e, err := s.readEvent()
 	if err != nil {
 		return err
 	}
@@ -126,9 +237,10 @@ func (s *Session) Open() error {
 	// Now we send either an Op 2 Identity if this is a brand new
 	// connection or Op 6 Resume if we are resuming an existing connection.
 	sequence := atomic.LoadInt64(s.sequence)
-	if s.sessionID == "" && sequence == 0 {
+	if !resuming {
 
 		// Send Op 2 Identity Packet
+		s.setStatus(ConnectionStateIdentifying, nil)
 		err = s.identify()
 		if err != nil {
 			err = fmt.Errorf("error sending identify packet to gateway, %s, %s", s.gateway, err)
@@ -145,8 +257,9 @@ func (s *Session) Open() error {
 		p.Data.Sequence = sequence
 
 		s.log(LogInformational, "sending resume packet to gateway")
+		s.setStatus(ConnectionStateResuming, nil)
 		s.wsMutex.Lock()
-		err = s.wsConn.WriteJSON(p)
+		err = s.writeGateway(s.wsConn, p)
 		s.wsMutex.Unlock()
 		if err != nil {
 			err = fmt.Errorf("error sending gateway resume packet, %s, %s", s.gateway, err)
@@ -170,11 +283,7 @@ func (s *Session) Open() error {
 	}
 
 	// Now Discord should send us a READY or RESUMED packet.
-	mt, m, err = s.wsConn.ReadMessage()
-	if err != nil {
-		return err
-	}
-	e, err = s.onEvent(mt, m)
+	e, err = s.readEvent()
 	if err != nil {
 		return err
 	}
@@ -184,8 +293,14 @@ func (s *Session) Open() error {
 	}
 	// s.log(LogInformational, "First Packet:\n%#v\n", e)
 
+	// The read deadline may be set once ctx is done.
+	if !stopHandshake() {
+		err = ctx.Err()
+		return err
+	}
+
 	s.log(LogInformational, "We are now connected to Discord, emitting connect event")
-	s.handleEvent(connectEventType, &Connect{})
+	s.handleEvent(connectEventType, &Connect{Resumed: e.Type == `RESUMED`})
 
 	// A VoiceConnections map is a hard requirement for Voice.
 	// XXX: can this be moved to when opening a voice connection?
@@ -199,23 +314,32 @@ func (s *Session) Open() error {
 	// go rountines.
 	s.listening = make(chan any)
 
+	// The goroutines get their own copies, as Close may reset the session
+	// fields before they start.
+	wsConn, listening := s.wsConn, s.listening
+
 	// Start sending heartbeats and reading messages from Discord.
+	s.goroutines.Add(2)
 	go func() {
+		defer s.goroutines.Done()
 		defer s.ErrorChecker()
 
-		s.heartbeat(s.wsConn, s.listening, h.HeartbeatInterval)
+		s.heartbeat(wsConn, listening, h.HeartbeatInterval)
 	}()
 	go func() {
+		defer s.goroutines.Done()
 		defer s.ErrorChecker()
 
-		s.listen(s.wsConn, s.listening)
+		s.listen(wsConn, listening)
 	}()
 
 	if s.ShouldSubscribeGuilds {
+		s.goroutines.Add(1)
 		go func() {
+			defer s.goroutines.Done()
 			defer s.ErrorChecker()
 
-			s.subscribeGuilds(s.wsConn, s.listening)
+			s.subscribeGuilds(wsConn, listening)
 		}()
 	}
 
@@ -223,6 +347,22 @@ func (s *Session) Open() error {
 	return nil
 }
 
+// readEvent reads messages from the websocket until a whole payload has been
+// received, and passes it to onEvent.
+func (s *Session) readEvent() (*Event, error) {
+	for {
+		messageType, message, err := s.wsConn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := s.onEvent(messageType, message)
+		if e != nil || err != nil {
+			return e, err
+		}
+	}
+}
+
 func (s *Session) subscribeGuilds(wsConn *websocket.Conn, listening <-chan any) {
 	s.log(LogInformational, "subscribing to guilds")
 
@@ -272,7 +412,12 @@ func (s *Session) subscribeGuilds(wsConn *websocket.Conn, listening <-chan any)
 		// Subscribe to guild
 		s.RequestLazyGuild(data)
 
-		time.Sleep(2 * time.Second)
+		select {
+		case <-time.After(2 * time.Second):
+		case <-listening:
+			s.log(LogInformational, "stopped subscribing to guilds")
+			return
+		}
 	}
 
 	s.log(LogInformational, "subscribed to guilds")
@@ -298,15 +443,7 @@ func (s *Session) listen(wsConn *websocket.Conn, listening <-chan any) {
 			if sameConnection {
 
 				s.log(LogWarning, "error reading from gateway %s websocket, %s", s.gateway, err)
-				// There has been an error reading, close the websocket so that
-				// OnDisconnect event is emitted.
-				err := s.Close()
-				if err != nil {
-					s.log(LogWarning, "error closing session connection, %s", err)
-				}
-
-				s.log(LogInformational, "calling reconnect() now")
-				s.reconnect()
+				s.dropConnection(err)
 			}
 
 			return
@@ -363,16 +500,25 @@ func (s *Session) heartbeat(wsConn *websocket.Conn, listening <-chan any, heartb
 		s.log(LogDebug, "sending gateway websocket heartbeat seq %d", sequence)
 		s.wsMutex.Lock()
 		s.LastHeartbeatSent = time.Now().UTC()
-		err = wsConn.WriteJSON(heartbeatOp{1, sequence})
+		err = s.writeGateway(wsConn, heartbeatOp{1, sequence})
 		s.wsMutex.Unlock()
 		if err != nil || time.Now().UTC().Sub(last) > (heartbeatIntervalMsec*FailedHeartbeatAcks) {
+			// The connection may already be closing, in which case the
+			// listen goroutine handles it.
+			s.RLock()
+			sameConnection := s.wsConn == wsConn
+			s.RUnlock()
+			if !sameConnection {
+				return
+			}
+
 			if err != nil {
 				s.log(LogError, "error sending heartbeat to gateway %s, %s", s.gateway, err)
 			} else {
 				s.log(LogError, "haven't gotten a heartbeat ACK in %v, triggering a reconnection", time.Now().UTC().Sub(last))
+				err = ErrHeartbeatACKTimeout
 			}
-			s.Close()
-			s.reconnect()
+			s.dropConnection(err)
 			return
 		}
 		s.Lock()
@@ -479,7 +625,7 @@ func (s *Session) UpdateStatusComplex(usd UpdateStatusData) (err error) {
 	}
 
 	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(updateStatusOp{3, usd})
+	err = s.writeGateway(s.wsConn, updateStatusOp{3, usd})
 	s.wsMutex.Unlock()
 
 	return
@@ -497,11 +643,25 @@ func (s *Session) SendWsData(data any) (err error) {
 	s.wsMutex.Lock()
 	defer s.wsMutex.Unlock()
 
-	err = s.wsConn.WriteJSON(data)
+	err = s.writeGateway(s.wsConn, data)
 
 	return
 }
 
+// writeGateway sends a payload over a gateway connection, in the encoding
+// of the connection. s.wsMutex must be held.
+func (s *Session) writeGateway(wsConn *websocket.Conn, v any) error {
+	if s.encoding != GatewayEncodingETF {
+		return wsConn.WriteJSON(v)
+	}
+
+	data, err := MarshalETF(v)
+	if err != nil {
+		return err
+	}
+	return wsConn.WriteMessage(websocket.BinaryMessage, data)
+}
+
 type requestGuildMembersData struct {
 	GuildID   string    `json:"guild_id"`
 	Query     *string   `json:"query,omitempty"`
@@ -590,7 +750,7 @@ func (s *Session) requestGuildMembers(data requestGuildMembersData) (err error)
 	}
 
 	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(requestGuildMembersOp{8, data})
+	err = s.writeGateway(s.wsConn, requestGuildMembersOp{8, data})
 	s.wsMutex.Unlock()
 
 	return
@@ -623,7 +783,7 @@ func (s *Session) RequestLazyGuild(data RequestLazyGuildData) (err error) {
 	}
 
 	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(requestLazyGuildOp{14, data})
+	err = s.writeGateway(s.wsConn, requestLazyGuildOp{14, data})
 	s.wsMutex.Unlock()
 
 	return
@@ -637,15 +797,29 @@ func (s *Session) RequestLazyGuild(data RequestLazyGuildData) (err error) {
 //
 // If you use the AddHandler() function to register a handler for the
 // "OnEvent" event then all events will be passed to that handler.
+//
+// When transport compression is used, a payload may span several messages,
+// in which case onEvent returns a nil Event until the last one is received.
 func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 	var err error
-	var reader io.Reader
-	reader = bytes.NewBuffer(message)
 
-	// If this is a compressed message, uncompress it.
-	if messageType == websocket.BinaryMessage {
+	if s.decompressor != nil {
+		message, err = s.decompressor.Decompress(message)
+		if err != nil {
+			s.log(LogError, "error uncompressing websocket message, %s", err)
+			return nil, err
+		}
+		if message == nil {
+			return nil, nil
+		}
+		messageType = websocket.TextMessage
+	}
 
-		z, err2 := zlib.NewReader(reader)
+	// If this is a compressed message, uncompress it. Uncompressed ETF
+	// payloads are binary messages too, but start with the version byte.
+	if messageType == websocket.BinaryMessage && (len(message) == 0 || message[0] != etfVersion) {
+
+		z, err2 := zlib.NewReader(bytes.NewReader(message))
 		if err2 != nil {
 			s.log(LogError, "error uncompressing websocket message, %s", err)
 			return nil, err2
@@ -658,13 +832,21 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 			}
 		}()
 
-		reader = z
+		message, err = io.ReadAll(z)
+		if err != nil {
+			s.log(LogError, "error uncompressing websocket message, %s", err)
+			return nil, err
+		}
 	}
 
 	// Decode the event into an Event struct.
 	var e *Event
-	decoder := json.NewDecoder(reader)
-	if err = decoder.Decode(&e); err != nil {
+	if len(message) > 0 && message[0] == etfVersion {
+		e, err = decodeETFEvent(message)
+	} else {
+		err = json.Unmarshal(message, &e)
+	}
+	if err != nil {
 		s.log(LogError, "error decoding websocket message, %s", err)
 		return e, err
 	}
@@ -676,7 +858,7 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 	if e.Operation == 1 {
 		s.log(LogInformational, "sending heartbeat in response to Op1")
 		s.wsMutex.Lock()
-		err = s.wsConn.WriteJSON(heartbeatOp{1, atomic.LoadInt64(s.sequence)})
+		err = s.writeGateway(s.wsConn, heartbeatOp{1, atomic.LoadInt64(s.sequence)})
 		s.wsMutex.Unlock()
 		if err != nil {
 			s.log(LogError, "error sending heartbeat in response to Op1")
@@ -690,15 +872,29 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 	// Must immediately disconnect from gateway and reconnect to new gateway.
 	if e.Operation == 7 {
 		s.log(LogInformational, "Closing and reconnecting in response to Op7")
-		s.CloseWithCode(websocket.CloseServiceRestart)
-		s.reconnect()
+		s.dropConnection(ErrGatewayReconnect)
 		return e, nil
 	}
 
 	// Invalid Session
-	// Must respond with a Identify packet.
+	// Must reconnect and resume when the session is resumable, otherwise
+	// must respond with a Identify packet for a new session.
 	if e.Operation == 9 {
 
+		var resumable bool
+		if err = json.Unmarshal(e.RawData, &resumable); err != nil {
+			s.log(LogWarning, "error unmarshalling Op9, %s", err)
+		}
+
+		if resumable {
+			s.log(LogInformational, "Closing and resuming in response to Op9")
+			s.dropConnection(ErrGatewayInvalidSession)
+			return e, nil
+		}
+
+		s.forgetSession()
+		s.setStatus(ConnectionStateIdentifying, ErrGatewayInvalidSession)
+
 		s.log(LogInformational, "sending identify packet to gateway in response to Op9")
 
 		err = s.identify()
@@ -743,11 +939,12 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 		e.Struct = eh.New()
 
 		// Attempt to unmarshal our event.
-		if err = json.Unmarshal(e.RawData, e.Struct); err != nil {
+		if err = e.unmarshalData(e.Struct); err != nil {
 			s.log(LogError, "error unmarshalling %s event, %s", e.Type, err)
 		}
 
 		e.RawData = nil
+		e.etfData = nil
 
 		if update, ok := e.Struct.(*GuildMemberListUpdate); ok {
 			hasSync := false
@@ -760,6 +957,9 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 
 			isActive, ok := s.activeGuildSubscriptions[update.GuildID]
 			if !hasSync || !ok || !isActive {
+				// The event is dropped, but a snapshot still resumes after
+				// it.
+				s.State.setSequence(e.Sequence)
 				e.Struct = nil
 				return e, nil
 			}
@@ -773,10 +973,17 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 		// TODO: Think about that decision :)
 		// Either way, READY events must fire, even with errors.
 		s.handleEvent(e.Type, e.Struct)
+
+		if e.Type == "READY" || e.Type == "RESUMED" {
+			s.setStatus(ConnectionStateReady, nil)
+		}
 	} else {
 		s.log(LogWarning, "unknown event: Op: %d, Seq: %d, Type: %s, Data: %s", e.Operation, e.Sequence, e.Type, string(e.RawData))
 	}
 
+	// The event was applied to the state, so a snapshot resumes after it.
+	s.State.setSequence(e.Sequence)
+
 	// For legacy reasons, we send the raw event also, this could be useful for handling unknown events.
 	s.handleEvent(eventEventType, e)
 
@@ -864,7 +1071,7 @@ func (s *Session) ChannelVoiceJoinManual(gID, cID string, mute, deaf bool) (err
 	// Send the request to Discord that we want to join the voice channel
 	data := voiceChannelJoinOp{4, voiceChannelJoinData{&gID, channelID, mute, deaf}}
 	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(data)
+	err = s.writeGateway(s.wsConn, data)
 	s.wsMutex.Unlock()
 	return
 }
@@ -943,7 +1150,7 @@ func (s *Session) identify() error {
 
 	// TODO: This is a temporary block of code to help
 	// maintain backwards compatibility
-	if !s.Compress {
+	if !s.Compress || s.GatewayCompression != GatewayCompressionNone {
 		s.Identify.Compress = false
 	}
 
@@ -957,7 +1164,7 @@ func (s *Session) identify() error {
 	op := identifyOp{2, s.Identify}
 	s.log(LogDebug, "Identify Packet: \n%#v", op)
 	s.wsMutex.Lock()
-	err := s.wsConn.WriteJSON(op)
+	err := s.writeGateway(s.wsConn, op)
 	s.wsMutex.Unlock()
 
 	return err
@@ -966,59 +1173,152 @@ func (s *Session) identify() error {
 func (s *Session) reconnect() {
 	s.log(LogInformational, "called")
 
-	var err error
+	if !s.ShouldReconnectOnError {
+		return
+	}
 
-	if s.ShouldReconnectOnError {
+	s.RLock()
+	closed := s.closed
+	s.RUnlock()
 
-		wait := time.Duration(1)
+	// Aborting reconnecting aborts connecting too.
+	ctx := s.ReconnectContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-		for {
-			s.log(LogInformational, "trying to reconnect to gateway")
+	policy := s.reconnectPolicy()
 
-			err = s.Open()
-			if err == nil {
-				s.log(LogInformational, "successfully reconnected to gateway")
+	var err error
+	for attempt := 1; ; attempt++ {
+		delay, ok := policy.Backoff(attempt, err)
+		if !ok {
+			s.log(LogError, "giving up reconnecting to gateway after %d attempts", attempt-1)
+			s.reconnectFailed(attempt-1, err)
+			return
+		}
 
-				// I'm not sure if this is actually needed.
-				// if the gw reconnect works properly, voice should stay alive
-				// However, there seems to be cases where something "weird"
-				// happens.  So we're doing this for now just to improve
-				// stability in those edge cases.
-				s.RLock()
-				defer s.RUnlock()
-				for _, v := range s.VoiceConnections {
+		s.log(LogInformational, "waiting %v before reconnect attempt %d", delay, attempt)
+		if werr := s.reconnectWait(delay, closed); werr != nil {
+			// The session was closed while waiting to reconnect.
+			if werr == ErrSessionClosed {
+				return
+			}
+			s.log(LogInformational, "reconnecting to gateway aborted, %s", werr)
+			s.reconnectFailed(attempt-1, werr)
+			return
+		}
 
-					s.log(LogInformational, "reconnecting voice connection to guild %s", v.GuildID)
-					go func() {
-						defer s.ErrorChecker()
+		select {
+		case <-closed:
+			return
+		default:
+		}
 
-						v.reconnect()
-					}()
+		s.log(LogInformational, "trying to reconnect to gateway")
 
-					// This is here just to prevent violently spamming the
-					// voice reconnects
-					time.Sleep(1 * time.Second)
+		err = s.open(ctx)
+		if err == nil {
+			s.log(LogInformational, "successfully reconnected to gateway")
 
-				}
-				return
+			// I'm not sure if this is actually needed.
+			// if the gw reconnect works properly, voice should stay alive
+			// However, there seems to be cases where something "weird"
+			// happens.  So we're doing this for now just to improve
+			// stability in those edge cases.
+			s.RLock()
+			voice := make([]*VoiceConnection, 0, len(s.VoiceConnections))
+			for _, v := range s.VoiceConnections {
+				voice = append(voice, v)
 			}
+			s.RUnlock()
 
-			// Certain race conditions can call reconnect() twice. If this happens, we
-			// just break out of the reconnect loop
-			if err == ErrWSAlreadyOpen {
-				s.log(LogInformational, "Websocket already exists, no need to reconnect")
-				return
+			// The voice connections wait for the reconnect policy before
+			// their first attempt, so they are not reconnected at once.
+			for _, v := range voice {
+				s.log(LogInformational, "reconnecting voice connection to guild %s", v.GuildID)
+				s.goroutines.Add(1)
+				go func() {
+					defer s.goroutines.Done()
+					defer s.ErrorChecker()
+
+					v.reconnect()
+				}()
 			}
+			return
+		}
 
-			s.log(LogError, "error reconnecting to gateway, %s", err)
+		// Certain race conditions can call reconnect() twice. If this happens, we
+		// just break out of the reconnect loop
+		if err == ErrWSAlreadyOpen {
+			s.log(LogInformational, "Websocket already exists, no need to reconnect")
+			return
+		}
 
-			<-time.After(wait * time.Second)
-			wait *= 2
-			if wait > 600 {
-				wait = 600
-			}
+		if !s.onGatewayClose(err) {
+			s.reconnectFailed(attempt, err)
+			return
 		}
+
+		s.log(LogError, "error reconnecting to gateway, %s", err)
+		s.setStatus(ConnectionStateReconnecting, err)
+	}
+}
+
+// reconnectFailed disconnects the session after it gave up reconnecting,
+// and emits a ReconnectFailed event.
+func (s *Session) reconnectFailed(attempts int, err error) {
+	s.setStatus(ConnectionStateDisconnected, err)
+	s.handleEvent(reconnectFailedEventType, &ReconnectFailed{Attempts: attempts, Err: err})
+}
+
+// dropConnection closes a gateway connection which failed with cause, or
+// which the gateway asked to reopen, and reconnects when it can be reopened.
+// It is not closed normally, as that would end the session.
+func (s *Session) dropConnection(cause error) {
+	reconnect := s.onGatewayClose(cause) && s.ShouldReconnectOnError
+	if reconnect {
+		s.setStatus(ConnectionStateReconnecting, cause)
+	} else {
+		s.setStatus(ConnectionStateDisconnected, cause)
+	}
+
+	// Close the websocket so that the Disconnect event is emitted.
+	if err := s.closeConnection(websocket.CloseServiceRestart); err != nil {
+		s.log(LogWarning, "error closing session connection, %s", err)
+	}
+
+	if reconnect {
+		s.log(LogInformational, "calling reconnect() now")
+		s.reconnect()
+	}
+}
+
+// forgetSession discards the gateway session, so the next connection
+// identifies instead of resuming. It is called with s locked, or by the
+// goroutine reading the gateway connection.
+func (s *Session) forgetSession() {
+	s.sessionID = ""
+	s.resumeGatewayURL = ""
+	atomic.StoreInt64(s.sequence, 0)
+}
+
+// onGatewayClose updates the session for the error its gateway connection
+// failed with, and reports whether the connection should be reopened.
+func (s *Session) onGatewayClose(err error) bool {
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return true
 	}
+
+	if !gatewayCloseResumable(closeErr.Code) {
+		s.forgetSession()
+	}
+	if gatewayCloseFatal(closeErr.Code) {
+		s.log(LogError, "gateway closed the connection with code %d, not reconnecting", closeErr.Code)
+		return false
+	}
+	return true
 }
 
 // Close closes a websocket and stops all listening/heartbeat goroutines.
@@ -1027,15 +1327,82 @@ func (s *Session) Close() error {
 	return s.CloseWithCode(websocket.CloseNormalClosure)
 }
 
+// Shutdown gracefully closes the session. It disconnects all voice
+// connections, closes the websocket normally, which ends the gateway session,
+// then waits until the goroutines started by Open and the event handlers
+// running in their own goroutines have returned, or until ctx is done, in
+// which case the error of ctx is returned.
+//
+// Shutdown must not be called from an event handler, which it would wait for.
+func (s *Session) Shutdown(ctx context.Context) error {
+	s.log(LogInformational, "called")
+
+	s.RLock()
+	voice := make([]*VoiceConnection, 0, len(s.VoiceConnections))
+	for _, v := range s.VoiceConnections {
+		voice = append(voice, v)
+	}
+	s.RUnlock()
+
+	// Voice connections are left over the gateway, so before closing it.
+	for _, v := range voice {
+		if err := v.Disconnect(); err != nil {
+			s.log(LogWarning, "error disconnecting voice connection to guild %s, %s", v.GuildID, err)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.goroutines.Wait()
+		s.handlersWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.log(LogInformational, "shut down")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // CloseWithCode closes a websocket using the provided closeCode and stops all
 // listening/heartbeat goroutines.
 // TODO: Add support for Voice WS/UDP connections
 func (s *Session) CloseWithCode(closeCode int) (err error) {
+	s.setStatus(ConnectionStateClosed, nil)
+
+	// Stop reconnecting. Open creates a new channel.
+	s.Lock()
+	if s.closed != nil {
+		close(s.closed)
+		s.closed = nil
+	}
+	s.Unlock()
+
+	return s.closeConnection(closeCode)
+}
+
+// closeConnection closes the websocket using the provided closeCode and stops
+// all listening/heartbeat goroutines, without changing the state of the
+// connection.
+func (s *Session) closeConnection(closeCode int) (err error) {
 	s.log(LogInformational, "called")
 	s.Lock()
 
 	s.DataReady = false
 
+	// Discord ends the session of a connection closed normally, so the next
+	// connection identifies instead of resuming.
+	if closeCode == websocket.CloseNormalClosure || closeCode == websocket.CloseGoingAway {
+		s.forgetSession()
+	}
+
 	if s.listening != nil {
 		s.log(LogInformational, "closing listening channel")
 		close(s.listening)
@@ -0,0 +1,87 @@
+package discordgo
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestOpenContextHandshake(t *testing.T) {
+	// The gateway accepts the connection, but never says Hello.
+	done := make(chan struct{})
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	s, err := New("token")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+	s.gateway = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = s.OpenContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if s.wsConn != nil {
+		t.Error("expected the connection to be closed")
+	}
+	if st := s.Status(); st != ConnectionStateDisconnected {
+		t.Errorf("expected the session to be Disconnected, got %s", st)
+	}
+}
+
+func TestOpenContextCanceled(t *testing.T) {
+	s, err := New("token")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+	s.gateway = "ws://127.0.0.1:1"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err = s.OpenContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestOnEventFilteredSequence(t *testing.T) {
+	s, err := New("token")
+	if err != nil {
+		t.Fatalf("New returned error: %+v", err)
+	}
+
+	// A member list update of a guild which is not subscribed is dropped,
+	// but is still recorded as the last event.
+	message := `{"op":0,"s":42,"t":"GUILD_MEMBER_LIST_UPDATE","d":{"guild_id":"1","ops":[{"op":"INSERT"}]}}`
+	e, err := s.onEvent(websocket.TextMessage, []byte(message))
+	if err != nil {
+		t.Fatalf("onEvent returned error: %+v", err)
+	}
+	if e.Struct != nil {
+		t.Errorf("expected the event to be dropped, got %+v", e.Struct)
+	}
+
+	s.State.RLock()
+	defer s.State.RUnlock()
+	if s.State.sequence != 42 {
+		t.Errorf("expected sequence 42, got %d", s.State.sequence)
+	}
+}